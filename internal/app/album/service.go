@@ -0,0 +1,342 @@
+package album
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"jointrip/internal/domain/album"
+	"jointrip/internal/domain/blob"
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+)
+
+// PhotoLinkTTL is how long the links to a photo's files stay valid
+const PhotoLinkTTL = time.Hour
+
+// PhotoMaker re-encodes uploaded photos
+type PhotoMaker interface {
+	// Photos decodes an image, refusing any whose dimensions are too large
+	// to decode safely, and returns it scaled down to fit within a square of
+	// each of the sides and encoded as a JPEG without metadata
+	Photos(data []byte, sides []int) ([][]byte, error)
+}
+
+// Album is the part of a trip's album a user can see
+type Album struct {
+	Photos []*album.Photo `json:"photos"`
+	// Cover is shown for the album, nil when there is nothing to show
+	Cover *album.Photo `json:"cover"`
+	// CanUpload is true for the trip's members
+	CanUpload bool `json:"can_upload"`
+}
+
+// Archive is the part of a trip's album a user can download as one file
+type Archive struct {
+	TripTitle string
+	Photos    []*album.Photo
+	fileStore blob.Store
+}
+
+// Open returns the stored file of one of the archive's photos
+func (a *Archive) Open(ctx context.Context, photo *album.Photo) (io.ReadCloser, error) {
+	return a.fileStore.Open(ctx, photo.StorageKey)
+}
+
+// Service provides trip photo album business logic. The organizer and
+// approved participants add photos and see all of them; anyone who can see
+// a public trip sees the photos its members made public.
+type Service struct {
+	photoRepo       album.Repository
+	tripRepo        trip.Repository
+	participantRepo trip.ParticipantRepository
+	fileStore       blob.Store
+	photoMaker      PhotoMaker
+}
+
+// NewService creates a new album service
+func NewService(
+	photoRepo album.Repository,
+	tripRepo trip.Repository,
+	participantRepo trip.ParticipantRepository,
+	fileStore blob.Store,
+	photoMaker PhotoMaker,
+) *Service {
+	return &Service{
+		photoRepo:       photoRepo,
+		tripRepo:        tripRepo,
+		participantRepo: participantRepo,
+		fileStore:       fileStore,
+		photoMaker:      photoMaker,
+	}
+}
+
+// GetAlbum returns the photos of a trip the user can see, in album order
+func (s *Service) GetAlbum(ctx context.Context, tripID, userID uuid.UUID) (*Album, error) {
+	_, member, err := s.getAlbumTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	photos, err := s.photoRepo.ListByTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	photos = album.VisibleTo(photos, member)
+	for _, photo := range photos {
+		if err := s.setLinks(ctx, photo); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Album{Photos: photos, Cover: album.Cover(photos), CanUpload: member}, nil
+}
+
+// UploadPhoto adds a photo to the end of a trip's album. The upload is
+// checked by its magic bytes, decoded and stored re-encoded with a preview,
+// so nothing but the pixels is kept.
+func (s *Service) UploadPhoto(ctx context.Context, tripID, userID uuid.UUID, caption string, visibility album.Visibility, content []byte) (*album.Photo, error) {
+	if _, err := s.getMemberTrip(ctx, tripID, userID); err != nil {
+		return nil, err
+	}
+
+	// Spare processing the photo when the album is already full; the
+	// repository checks again when the photo is stored
+	existing, err := s.photoRepo.ListByTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= album.MaxPhotosPerTrip {
+		return nil, album.ErrAlbumFull
+	}
+
+	if err := album.CheckPhoto(content[:min(len(content), blob.SniffLength)], int64(len(content))); err != nil {
+		return nil, err
+	}
+
+	files, err := s.photoMaker.Photos(content, []int{album.PhotoSide, album.ThumbnailSide})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", album.ErrInvalidPhoto, err)
+	}
+	full, thumbnail := files[0], files[1]
+
+	photo, err := album.NewPhoto(tripID, userID, caption, visibility, int64(len(full)))
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.fileStore.Put(ctx, photo.StorageKey, bytes.NewReader(full), photo.Size, blob.ContentTypeJPEG)
+	if err == nil {
+		err = s.fileStore.Put(ctx, photo.ThumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), blob.ContentTypeJPEG)
+	}
+	if err == nil {
+		err = s.photoRepo.Create(ctx, photo)
+	}
+	if err != nil {
+		s.deletePhotoFiles(ctx, photo)
+		return nil, err
+	}
+
+	if err := s.setLinks(ctx, photo); err != nil {
+		return nil, err
+	}
+	return photo, nil
+}
+
+// UpdatePhoto changes a photo's caption and visibility. Nil fields are left
+// unchanged. Only whoever uploaded the photo and the organizer can change it.
+func (s *Service) UpdatePhoto(ctx context.Context, tripID, photoID, userID uuid.UUID, caption *string, visibility *album.Visibility) (*album.Photo, error) {
+	t, err := s.getMemberTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	photo, err := s.getTripPhoto(ctx, tripID, photoID)
+	if err != nil {
+		return nil, err
+	}
+	if !photo.CanModify(userID, t.CreatorID) {
+		return nil, album.ErrNotPhotoOwner
+	}
+
+	if err := photo.Update(caption, visibility); err != nil {
+		return nil, err
+	}
+	if err := s.photoRepo.Update(ctx, photo); err != nil {
+		return nil, err
+	}
+
+	if err := s.setLinks(ctx, photo); err != nil {
+		return nil, err
+	}
+	return photo, nil
+}
+
+// ReorderPhotos places the album's photos in the order given by their IDs,
+// which must list every photo. Only the organizer arranges the album.
+func (s *Service) ReorderPhotos(ctx context.Context, tripID, userID uuid.UUID, order []uuid.UUID) (*Album, error) {
+	photos, err := s.getArrangedPhotos(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	photos, err = album.Reorder(photos, order)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.photoRepo.UpdateArrangement(ctx, photos); err != nil {
+		return nil, err
+	}
+
+	return s.GetAlbum(ctx, tripID, userID)
+}
+
+// SetCover makes a photo the cover of the album. Only the organizer
+// arranges the album.
+func (s *Service) SetCover(ctx context.Context, tripID, photoID, userID uuid.UUID) (*Album, error) {
+	photos, err := s.getArrangedPhotos(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := album.SetCover(photos, photoID); err != nil {
+		return nil, err
+	}
+	if err := s.photoRepo.UpdateArrangement(ctx, photos); err != nil {
+		return nil, err
+	}
+
+	return s.GetAlbum(ctx, tripID, userID)
+}
+
+// DeletePhoto removes a photo and its files. Only whoever uploaded the
+// photo and the organizer can remove it.
+func (s *Service) DeletePhoto(ctx context.Context, tripID, photoID, userID uuid.UUID) error {
+	t, err := s.getMemberTrip(ctx, tripID, userID)
+	if err != nil {
+		return err
+	}
+
+	photo, err := s.getTripPhoto(ctx, tripID, photoID)
+	if err != nil {
+		return err
+	}
+	if !photo.CanModify(userID, t.CreatorID) {
+		return album.ErrNotPhotoOwner
+	}
+
+	if err := s.photoRepo.Delete(ctx, photo.ID); err != nil {
+		return err
+	}
+
+	s.deletePhotoFiles(ctx, photo)
+	return nil
+}
+
+// GetArchive returns the photos of a trip the user can see, in album order,
+// ready to be written out as one file
+func (s *Service) GetArchive(ctx context.Context, tripID, userID uuid.UUID) (*Archive, error) {
+	t, member, err := s.getAlbumTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	photos, err := s.photoRepo.ListByTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Archive{
+		TripTitle: t.Title,
+		Photos:    album.VisibleTo(photos, member),
+		fileStore: s.fileStore,
+	}, nil
+}
+
+// getAlbumTrip returns a trip whose album the user can see and whether the
+// user is one of its members. Others only see the albums of published
+// public trips.
+func (s *Service) getAlbumTrip(ctx context.Context, tripID, userID uuid.UUID) (*trip.Trip, bool, error) {
+	t, err := s.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	access, err := trip.CheckAccess(ctx, s.participantRepo, t, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if !access.Visible {
+		return nil, false, trip.ErrTripNotFound
+	}
+
+	return t, access.Member, nil
+}
+
+// getMemberTrip returns a trip the user is a member of
+func (s *Service) getMemberTrip(ctx context.Context, tripID, userID uuid.UUID) (*trip.Trip, error) {
+	t, member, err := s.getAlbumTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, album.ErrNotTripMember
+	}
+
+	return t, nil
+}
+
+// getArrangedPhotos returns all the photos of a trip's album for its
+// organizer to arrange
+func (s *Service) getArrangedPhotos(ctx context.Context, tripID, userID uuid.UUID) ([]*album.Photo, error) {
+	t, err := s.getMemberTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !t.IsOrganizer(userID) {
+		return nil, album.ErrNotAlbumOrganizer
+	}
+
+	return s.photoRepo.ListByTrip(ctx, tripID)
+}
+
+// getTripPhoto returns a photo of the trip's album
+func (s *Service) getTripPhoto(ctx context.Context, tripID, photoID uuid.UUID) (*album.Photo, error) {
+	photo, err := s.photoRepo.GetByID(ctx, photoID)
+	if err != nil {
+		return nil, err
+	}
+	if photo.TripID != tripID {
+		return nil, album.ErrPhotoNotFound
+	}
+
+	return photo, nil
+}
+
+// setLinks gives a photo fresh links to download its files with
+func (s *Service) setLinks(ctx context.Context, photo *album.Photo) error {
+	expiresAt := time.Now().Add(PhotoLinkTTL)
+
+	url, err := s.fileStore.SignedURL(ctx, photo.StorageKey, expiresAt)
+	if err != nil {
+		return err
+	}
+	thumbnailURL, err := s.fileStore.SignedURL(ctx, photo.ThumbnailKey, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	photo.URL, photo.ThumbnailURL = url, thumbnailURL
+	return nil
+}
+
+// deletePhotoFiles removes a photo's files from storage. Failures are
+// ignored: the photo is gone either way and a stray file does no harm.
+func (s *Service) deletePhotoFiles(ctx context.Context, photo *album.Photo) {
+	s.fileStore.Delete(ctx, photo.StorageKey)
+	s.fileStore.Delete(ctx, photo.ThumbnailKey)
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"jointrip/internal/domain/blob"
+	"jointrip/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// ProfilePhotoLinkTTL is how long a link to download a profile photo stays valid
+const ProfilePhotoLinkTTL = time.Hour
+
+// legacyProfilePhotoPrefix is where profile photos were served from when
+// they were written to the local uploads directory
+const legacyProfilePhotoPrefix = "/uploads/profile_photos/"
+
+// AvatarMaker re-encodes uploaded photos as square JPEGs
+type AvatarMaker interface {
+	// Avatars decodes an image, refusing any whose dimensions are too large
+	// to decode safely, and returns it cropped to a square and encoded
+	// without metadata at each of the sizes
+	Avatars(data []byte, sizes []int) ([][]byte, error)
+}
+
+// UploadProfilePhoto sets a new profile photo for the user. The upload is
+// checked by its magic bytes, decoded and stored re-encoded at every
+// profile photo size, so nothing but the pixels is kept. The photo it
+// replaces is removed.
+func (s *Service) UploadProfilePhoto(ctx context.Context, u *user.User, content []byte) error {
+	if err := user.CheckProfilePhoto(content[:min(len(content), blob.SniffLength)], int64(len(content))); err != nil {
+		return err
+	}
+
+	avatars, err := s.avatarMaker.Avatars(content, user.ProfilePhotoSizes)
+	if err != nil {
+		return fmt.Errorf("%w: %v", user.ErrInvalidProfilePhoto, err)
+	}
+
+	// Name the files after the largest variant, which the others derive from
+	hash := sha256.Sum256(avatars[len(avatars)-1])
+	key := user.ProfilePhotoKey(u.ID, hex.EncodeToString(hash[:16]))
+	if key == u.ProfilePhotoKey {
+		return nil
+	}
+
+	var stored []string
+	for i, size := range user.ProfilePhotoSizes {
+		variantKey := user.ProfilePhotoVariantKey(key, size)
+		err = s.fileStore.Put(ctx, variantKey, bytes.NewReader(avatars[i]), int64(len(avatars[i])), blob.ContentTypeJPEG)
+		if err != nil {
+			break
+		}
+		stored = append(stored, variantKey)
+	}
+
+	previous := u.ProfilePhotoFiles()
+	if err == nil {
+		u.SetProfilePhoto(key, func(size int) string {
+			return profilePhotoURL(u.ID, key, size)
+		})
+		err = s.userRepo.Update(ctx, u)
+	}
+	if err != nil {
+		s.deleteFiles(ctx, stored)
+		return err
+	}
+
+	s.deleteFiles(ctx, previous)
+	return nil
+}
+
+// ProfilePhotoLink returns a short-lived link to download the user's
+// uploaded profile photo, in the smallest size at least size pixels wide
+func (s *Service) ProfilePhotoLink(ctx context.Context, userID uuid.UUID, size int) (string, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := u.ProfilePhotoFile(size)
+	if err != nil {
+		return "", err
+	}
+
+	return s.fileStore.SignedURL(ctx, file, time.Now().Add(ProfilePhotoLinkTTL))
+}
+
+// ImportLegacyProfilePhotos moves profile photos that were written to the
+// local uploads directory, given as files, into file storage and returns
+// how many it moved. Users whose old photo is missing or unusable get
+// their Google photo back, as nothing serves the old one anymore.
+func (s *Service) ImportLegacyProfilePhotos(ctx context.Context, files fs.FS) (int, error) {
+	const pageSize = 100
+
+	imported := 0
+	var errs []error
+	for offset := 0; ; offset += pageSize {
+		users, err := s.userRepo.List(ctx, pageSize, offset)
+		if err != nil {
+			return imported, err
+		}
+
+		for _, u := range users {
+			name, ok := strings.CutPrefix(u.ProfilePhotoURL, legacyProfilePhotoPrefix)
+			if !ok || u.ProfilePhotoKey != "" {
+				continue
+			}
+
+			content, err := readLegacyProfilePhoto(files, name)
+			if err == nil {
+				if err = s.UploadProfilePhoto(ctx, u, content); err == nil {
+					imported++
+					continue
+				}
+			}
+			if errors.Is(err, fs.ErrNotExist) || errors.Is(err, user.ErrInvalidProfilePhoto) ||
+				errors.Is(err, user.ErrProfilePhotoTooLarge) {
+				u.ProfilePhotoURL = u.GooglePhotoURL
+				err = s.userRepo.Update(ctx, u)
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		if len(users) < pageSize {
+			return imported, errors.Join(errs...)
+		}
+	}
+}
+
+// readLegacyProfilePhoto reads a photo from the old uploads directory
+func readLegacyProfilePhoto(files fs.FS, name string) ([]byte, error) {
+	file, err := files.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(io.LimitReader(file, user.MaxProfilePhotoSize+1))
+}
+
+// deleteFiles removes stored files. A file left behind only takes up
+// space, so failures are ignored.
+func (s *Service) deleteFiles(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		s.fileStore.Delete(ctx, key)
+	}
+}
+
+// profilePhotoURL is where clients load a size of an uploaded profile
+// photo from. It redirects to a fresh signed link and changes with every
+// photo, so that browsers never show a stale one.
+func profilePhotoURL(userID uuid.UUID, key string, size int) string {
+	return fmt.Sprintf("/api/v1/users/%s/photo?size=%d&v=%s", userID, size, path.Base(key))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"jointrip/internal/domain/blob"
 	"jointrip/internal/domain/session"
 	"jointrip/internal/domain/user"
 
@@ -57,6 +58,8 @@ type Service struct {
 	googleClient GoogleOAuthClient
 	jwtManager   JWTManager
 	maxSessions  int
+	fileStore    blob.Store
+	avatarMaker  AvatarMaker
 }
 
 // NewService creates a new authentication service
@@ -66,6 +69,8 @@ func NewService(
 	googleClient GoogleOAuthClient,
 	jwtManager JWTManager,
 	maxSessions int,
+	fileStore blob.Store,
+	avatarMaker AvatarMaker,
 ) *Service {
 	return &Service{
 		userRepo:     userRepo,
@@ -73,6 +78,8 @@ func NewService(
 		googleClient: googleClient,
 		jwtManager:   jwtManager,
 		maxSessions:  maxSessions,
+		fileStore:    fileStore,
+		avatarMaker:  avatarMaker,
 	}
 }
 
@@ -0,0 +1,134 @@
+package calendar
+
+import (
+	"context"
+
+	"jointrip/internal/domain/calendar"
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+)
+
+// TripSchedule is a trip together with its itinerary
+type TripSchedule struct {
+	Trip  *trip.Trip
+	Items []*trip.ItineraryItem
+}
+
+// Service provides calendar export business logic
+type Service struct {
+	feedRepo        calendar.Repository
+	tripRepo        trip.Repository
+	participantRepo trip.ParticipantRepository
+	itineraryRepo   trip.ItineraryRepository
+}
+
+// NewService creates a new calendar service
+func NewService(
+	feedRepo calendar.Repository,
+	tripRepo trip.Repository,
+	participantRepo trip.ParticipantRepository,
+	itineraryRepo trip.ItineraryRepository,
+) *Service {
+	return &Service{
+		feedRepo:        feedRepo,
+		tripRepo:        tripRepo,
+		participantRepo: participantRepo,
+		itineraryRepo:   itineraryRepo,
+	}
+}
+
+// ExportTrip returns a trip and its itinerary for calendar export.
+// Private trips and drafts are only visible to their organizer and approved
+// participants.
+func (s *Service) ExportTrip(ctx context.Context, tripID, userID uuid.UUID) (*TripSchedule, error) {
+	t, err := s.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := trip.CheckAccess(ctx, s.participantRepo, t, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.Visible {
+		return nil, trip.ErrTripNotFound
+	}
+
+	items, err := s.itineraryRepo.ListByTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TripSchedule{Trip: t, Items: items}, nil
+}
+
+// GetFeed resolves a feed secret and returns every trip its owner is approved on
+func (s *Service) GetFeed(ctx context.Context, secret string) ([]*TripSchedule, error) {
+	if secret == "" {
+		return nil, calendar.ErrFeedTokenNotFound
+	}
+
+	token, err := s.feedRepo.GetActiveByHash(ctx, calendar.HashToken(secret))
+	if err != nil {
+		return nil, err
+	}
+
+	trips, err := s.tripRepo.ListByParticipant(ctx, token.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*TripSchedule, 0, len(trips))
+	byTrip := make(map[uuid.UUID]*TripSchedule, len(trips))
+	tripIDs := make([]uuid.UUID, 0, len(trips))
+	for _, t := range trips {
+		schedule := &TripSchedule{Trip: t}
+		schedules = append(schedules, schedule)
+		byTrip[t.ID] = schedule
+		tripIDs = append(tripIDs, t.ID)
+	}
+
+	if len(tripIDs) > 0 {
+		items, err := s.itineraryRepo.ListByTrips(ctx, tripIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if schedule, ok := byTrip[item.TripID]; ok {
+				schedule.Items = append(schedule.Items, item)
+			}
+		}
+	}
+
+	if err := s.feedRepo.MarkUsed(ctx, token.ID); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// GetFeedToken returns the user's active feed token
+func (s *Service) GetFeedToken(ctx context.Context, userID uuid.UUID) (*calendar.FeedToken, error) {
+	return s.feedRepo.GetActiveByUser(ctx, userID)
+}
+
+// RotateFeedToken issues a new feed secret for the user, revoking the previous one.
+// The returned secret is the only copy; it is not stored in plaintext.
+func (s *Service) RotateFeedToken(ctx context.Context, userID uuid.UUID) (*calendar.FeedToken, string, error) {
+	token, secret, err := calendar.NewFeedToken(userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := s.feedRepo.Rotate(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	return token, secret, nil
+}
+
+// RevokeFeedToken disables the user's feed URL
+func (s *Service) RevokeFeedToken(ctx context.Context, userID uuid.UUID) error {
+	return s.feedRepo.RevokeByUser(ctx, userID)
+}
@@ -0,0 +1,341 @@
+package comment
+
+import (
+	"context"
+	"errors"
+
+	"jointrip/internal/domain/comment"
+	"jointrip/internal/domain/event"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// Pagination limits
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Page is one page of a comment thread
+type Page struct {
+	Comments   []*comment.Comment `json:"comments"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+// Service provides trip comment business logic. Everyone who can see a trip
+// can read and write its comments: anyone for public trips, the organizer and
+// approved participants for private ones.
+type Service struct {
+	commentRepo     comment.Repository
+	tripRepo        trip.Repository
+	participantRepo trip.ParticipantRepository
+	userRepo        user.Repository
+	publisher       event.Publisher
+}
+
+// NewService creates a new comment service
+func NewService(
+	commentRepo comment.Repository,
+	tripRepo trip.Repository,
+	participantRepo trip.ParticipantRepository,
+	userRepo user.Repository,
+	publisher event.Publisher,
+) *Service {
+	return &Service{
+		commentRepo:     commentRepo,
+		tripRepo:        tripRepo,
+		participantRepo: participantRepo,
+		userRepo:        userRepo,
+		publisher:       publisher,
+	}
+}
+
+// ListComments returns a page of a trip's top-level comments, newest first
+func (s *Service) ListComments(ctx context.Context, tripID, userID uuid.UUID, cursor string, limit int) (*Page, error) {
+	t, err := s.getVisibleTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.listThread(ctx, t, userID, nil, cursor, limit)
+}
+
+// ListReplies returns a page of the direct replies to a comment, oldest first
+func (s *Service) ListReplies(ctx context.Context, tripID, commentID, userID uuid.UUID, cursor string, limit int) (*Page, error) {
+	t, err := s.getVisibleTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getTripComment(ctx, tripID, commentID); err != nil {
+		return nil, err
+	}
+
+	return s.listThread(ctx, t, userID, &commentID, cursor, limit)
+}
+
+// ListRevisions returns the earlier versions of an edited comment, newest first
+func (s *Service) ListRevisions(ctx context.Context, tripID, commentID, userID uuid.UUID) ([]*comment.Revision, error) {
+	t, err := s.getVisibleTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.getTripComment(ctx, tripID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if !c.CanSeeContent(userID, t.CreatorID) {
+		return []*comment.Revision{}, nil
+	}
+
+	return s.commentRepo.ListRevisions(ctx, commentID)
+}
+
+// CreateComment posts a comment on a trip, or a reply when a parent is given,
+// and notifies the users it mentions
+func (s *Service) CreateComment(ctx context.Context, tripID, userID uuid.UUID, content string, parentID *uuid.UUID) (*comment.Comment, error) {
+	t, err := s.getVisibleTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var parent *comment.Comment
+	if parentID != nil {
+		parent, err = s.getTripComment(ctx, tripID, *parentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c, err := comment.NewComment(tripID, userID, content, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	mentioned, err := s.resolveMentions(ctx, t, c)
+	if err != nil {
+		return nil, err
+	}
+	c.MentionedUserIDs = mentioned
+
+	if err := s.commentRepo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+
+	s.publishMentions(ctx, c, nil)
+	return c, nil
+}
+
+// EditComment changes the content of the user's comment, keeping the previous
+// version in its history, and notifies users who are newly mentioned
+func (s *Service) EditComment(ctx context.Context, tripID, commentID, userID uuid.UUID, content string) (*comment.Comment, error) {
+	t, err := s.getVisibleTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.getTripComment(ctx, tripID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := c.Edit(userID, content)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return c, nil
+	}
+
+	previous := c.MentionedUserIDs
+	mentioned, err := s.resolveMentions(ctx, t, c)
+	if err != nil {
+		return nil, err
+	}
+	c.MentionedUserIDs = mentioned
+
+	if err := s.commentRepo.Update(ctx, c, revision); err != nil {
+		return nil, err
+	}
+
+	s.publishMentions(ctx, c, previous)
+	return c, nil
+}
+
+// DeleteComment removes the content of the user's comment. Its replies stay.
+func (s *Service) DeleteComment(ctx context.Context, tripID, commentID, userID uuid.UUID) error {
+	if _, err := s.getVisibleTrip(ctx, tripID, userID); err != nil {
+		return err
+	}
+
+	c, err := s.getTripComment(ctx, tripID, commentID)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Delete(userID); err != nil {
+		return err
+	}
+
+	return s.commentRepo.Delete(ctx, c)
+}
+
+// SetCommentHidden hides a comment on the organizer's trip from everyone but
+// its author, or shows it again
+func (s *Service) SetCommentHidden(ctx context.Context, tripID, commentID, organizerID uuid.UUID, hidden bool) (*comment.Comment, error) {
+	t, err := s.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if !t.IsOrganizer(organizerID) {
+		return nil, trip.ErrNotOrganizer
+	}
+
+	c, err := s.getTripComment(ctx, tripID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if hidden {
+		c.Hide()
+	} else {
+		c.Unhide()
+	}
+
+	if err := s.commentRepo.Update(ctx, c, nil); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// listThread returns a page of one thread with hidden content redacted for the viewer
+func (s *Service) listThread(ctx context.Context, t *trip.Trip, userID uuid.UUID, parentID *uuid.UUID, cursor string, limit int) (*Page, error) {
+	after, err := comment.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit = clampPageSize(limit)
+	comments, err := s.commentRepo.List(ctx, t.ID, comment.ListFilter{
+		ParentID: parentID,
+		After:    after,
+		Limit:    limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{Comments: comments}
+	if len(comments) > limit {
+		page.Comments = comments[:limit]
+		last := page.Comments[limit-1]
+		page.NextCursor = comment.Cursor{At: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	for _, c := range page.Comments {
+		c.RedactFor(userID, t.CreatorID)
+	}
+
+	return page, nil
+}
+
+// getVisibleTrip returns the trip if the user can see it. Drafts and private
+// trips are only visible to their organizer and approved participants; other
+// users get ErrTripNotFound.
+func (s *Service) getVisibleTrip(ctx context.Context, tripID, userID uuid.UUID) (*trip.Trip, error) {
+	t, err := s.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := trip.CheckAccess(ctx, s.participantRepo, t, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.Visible {
+		return nil, trip.ErrTripNotFound
+	}
+
+	return t, nil
+}
+
+// getTripComment returns a comment of the trip
+func (s *Service) getTripComment(ctx context.Context, tripID, commentID uuid.UUID) (*comment.Comment, error) {
+	c, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if c.TripID != tripID {
+		return nil, comment.ErrCommentNotFound
+	}
+	return c, nil
+}
+
+// resolveMentions looks up the users the comment mentions by username.
+// Unknown usernames, the author and users who cannot see the trip are left
+// out, so mentions never reveal a private trip.
+func (s *Service) resolveMentions(ctx context.Context, t *trip.Trip, c *comment.Comment) ([]uuid.UUID, error) {
+	usernames, err := comment.ParseMentions(c.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	mentioned := []uuid.UUID{}
+	for _, username := range usernames {
+		u, err := s.userRepo.GetByUsername(ctx, username)
+		if errors.Is(err, user.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if u.ID == c.UserID {
+			continue
+		}
+
+		access, err := trip.CheckAccess(ctx, s.participantRepo, t, u.ID)
+		if err != nil {
+			return nil, err
+		}
+		if access.Visible {
+			mentioned = append(mentioned, u.ID)
+		}
+	}
+
+	return mentioned, nil
+}
+
+// publishMentions announces the users the comment mentions that were not
+// mentioned before
+func (s *Service) publishMentions(ctx context.Context, c *comment.Comment, previous []uuid.UUID) {
+	known := make(map[uuid.UUID]bool, len(previous))
+	for _, id := range previous {
+		known[id] = true
+	}
+
+	var added []uuid.UUID
+	for _, id := range c.MentionedUserIDs {
+		if !known[id] {
+			added = append(added, id)
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	s.publisher.Publish(ctx, comment.UsersMentioned{Comment: c, UserIDs: added})
+}
+
+// clampPageSize applies the default and maximum page sizes
+func clampPageSize(limit int) int {
+	if limit <= 0 {
+		return DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return limit
+}
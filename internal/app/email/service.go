@@ -0,0 +1,229 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"jointrip/internal/domain/email"
+	"jointrip/internal/domain/event"
+	"jointrip/internal/domain/notification"
+	"jointrip/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// Outbox delivery settings
+const (
+	// DeliveryBatchSize is how many queued emails one delivery run sends
+	DeliveryBatchSize = 50
+	// DeliveryLease is how long a claimed email stays hidden from other
+	// workers; a worker that dies mid-run leaves it to be retried afterwards
+	DeliveryLease = 5 * time.Minute
+
+	// DigestBatchSize is how many users' digests one digest run prepares
+	DigestBatchSize = 100
+	// DigestLease is how long a claimed digest stays hidden from other workers
+	DigestLease = 15 * time.Minute
+	// DigestMaxItems is how many notifications a digest lists
+	DigestMaxItems = 50
+)
+
+// Mailer defines the interface for sending emails
+type Mailer interface {
+	Send(ctx context.Context, message *email.Message) error
+}
+
+// Renderer defines the interface for rendering notification emails in the
+// recipient's language
+type Renderer interface {
+	RenderNotification(recipient *user.User, n *notification.Notification) (*email.Message, error)
+	RenderDigest(recipient *user.User, prefs *notification.Preferences, notifications []*notification.Notification, more bool, now time.Time) (*email.Message, error)
+}
+
+// Service turns notifications into emails. Emails are queued in an outbox
+// when a notification is created, or batched into a digest by SendDigests,
+// and sent asynchronously by DeliverDue, so a slow or unavailable mail server
+// never holds up the action that caused them.
+type Service struct {
+	outboxRepo       email.OutboxRepository
+	notificationRepo notification.Repository
+	userRepo         user.Repository
+	preferenceRepo   notification.PreferenceRepository
+	renderer         Renderer
+	mailer           Mailer
+	now              func() time.Time
+}
+
+// NewService creates a new email service
+func NewService(
+	outboxRepo email.OutboxRepository,
+	notificationRepo notification.Repository,
+	userRepo user.Repository,
+	preferenceRepo notification.PreferenceRepository,
+	renderer Renderer,
+	mailer Mailer,
+) *Service {
+	return &Service{
+		outboxRepo:       outboxRepo,
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		preferenceRepo:   preferenceRepo,
+		renderer:         renderer,
+		mailer:           mailer,
+		now:              time.Now,
+	}
+}
+
+// RegisterHandlers subscribes the service to new notifications
+func (s *Service) RegisterHandlers(subscriber event.Subscriber) {
+	subscriber.Subscribe(notification.EventCreated, s.onNotificationCreated)
+}
+
+// onNotificationCreated queues an email about a notification delivered on
+// the email channel. During the recipient's quiet hours the email is held
+// back until they end.
+func (s *Service) onNotificationCreated(ctx context.Context, e event.Event) error {
+	created, ok := e.(notification.Created)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", e)
+	}
+	n := created.Notification
+	if !n.HasChannel(notification.ChannelEmail) {
+		return nil
+	}
+
+	recipient, err := s.userRepo.GetByID(ctx, n.UserID)
+	if err != nil {
+		return err
+	}
+	if !recipient.IsActive {
+		return nil
+	}
+
+	prefs, err := s.preferenceRepo.GetPreferences(ctx, recipient.ID)
+	if err != nil {
+		return err
+	}
+
+	message, err := s.renderer.RenderNotification(recipient, n)
+	if err != nil {
+		return err
+	}
+
+	entry, err := email.NewOutboxEntry(recipient.ID, &n.ID, *message)
+	if err != nil {
+		return err
+	}
+	entry.HoldUntil(prefs.QuietUntil(s.now().UTC()))
+
+	return s.outboxRepo.Enqueue(ctx, entry)
+}
+
+// DeliverDue sends the queued emails whose next attempt is due and returns
+// how many were sent. Failed emails are rescheduled with backoff.
+func (s *Service) DeliverDue(ctx context.Context) (int, error) {
+	entries, err := s.outboxRepo.ClaimDue(ctx, s.now().UTC(), DeliveryBatchSize, DeliveryLease)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for _, entry := range entries {
+		if err := s.mailer.Send(ctx, &entry.Message); err != nil {
+			entry.MarkFailed(s.now().UTC(), err)
+		} else {
+			entry.MarkSent(s.now().UTC())
+			sent++
+		}
+
+		if err := s.outboxRepo.Update(ctx, entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// SendDigests queues the digests that are due at the given time and returns
+// how many were queued. A digest lists the notifications on the digest channel
+// since the previous one that are still unread, so what users saw in the app
+// is left out; when nothing is left, no email is sent. Digests that fall in
+// the user's quiet hours are held back until they end.
+func (s *Service) SendDigests(ctx context.Context, now time.Time) (int, error) {
+	userIDs, err := s.preferenceRepo.ClaimDueDigests(ctx, now, DigestBatchSize, DigestLease)
+	if err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	var errs []error
+	for _, userID := range userIDs {
+		sent, err := s.sendDigest(ctx, userID, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("digest for user %s: %w", userID, err))
+			continue
+		}
+		if sent {
+			queued++
+		}
+	}
+
+	return queued, errors.Join(errs...)
+}
+
+// sendDigest queues one user's digest and schedules the next one
+func (s *Service) sendDigest(ctx context.Context, userID uuid.UUID, now time.Time) (bool, error) {
+	prefs, err := s.preferenceRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	recipient, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	var items []*notification.Notification
+	more := false
+	if recipient.IsActive && prefs.Digest != notification.DigestImmediate {
+		var since time.Time
+		if prefs.LastDigestAt != nil {
+			since = *prefs.LastDigestAt
+		}
+
+		unread, err := s.notificationRepo.ListUnreadSince(ctx, userID, since, DigestMaxItems+1)
+		if err != nil {
+			return false, err
+		}
+		if len(unread) > DigestMaxItems {
+			unread, more = unread[:DigestMaxItems], true
+		}
+		items = unread
+	}
+
+	if len(items) > 0 {
+		message, err := s.renderer.RenderDigest(recipient, prefs, items, more, now)
+		if err != nil {
+			return false, err
+		}
+
+		entry, err := email.NewOutboxEntry(recipient.ID, nil, *message)
+		if err != nil {
+			return false, err
+		}
+		entry.HoldUntil(prefs.QuietUntil(now))
+		if err := s.outboxRepo.Enqueue(ctx, entry); err != nil {
+			return false, err
+		}
+	}
+
+	prefs.DigestSent(now)
+	if err := s.preferenceRepo.SavePreferences(ctx, prefs); err != nil {
+		return false, err
+	}
+
+	return len(items) > 0, nil
+}
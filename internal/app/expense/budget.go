@@ -0,0 +1,130 @@
+package expense
+
+import (
+	"context"
+	"time"
+
+	"jointrip/internal/domain/event"
+	"jointrip/internal/domain/expense"
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+)
+
+// GetBudgetPlan returns the trip's budget plan, or the member's own plan
+// when personal is set. Personal plans are private to their member.
+func (s *Service) GetBudgetPlan(ctx context.Context, tripID, userID uuid.UUID, personal bool) ([]*expense.BudgetLine, error) {
+	if _, _, err := s.getMemberTrip(ctx, tripID, userID); err != nil {
+		return nil, err
+	}
+
+	lines, err := s.budgetRepo.ListByTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	return expense.PlanOf(lines, budgetOwner(userID, personal)), nil
+}
+
+// SetBudgetPlan replaces the trip's budget plan, which only the organizer
+// can plan, or the member's own plan when personal is set. Amounts are in
+// the trip's currency.
+func (s *Service) SetBudgetPlan(ctx context.Context, tripID, userID uuid.UUID, personal bool, inputs []expense.BudgetLineInput) ([]*expense.BudgetLine, error) {
+	t, _, err := s.getMemberTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !personal && t.CreatorID != userID {
+		return nil, expense.ErrNotBudgetOwner
+	}
+
+	all, err := s.budgetRepo.ListByTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := budgetOwner(userID, personal)
+	lines, err := expense.NewBudgetPlan(tripID, owner, t.Currency, inputs, expense.PlanOf(all, owner), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.budgetRepo.ReplacePlan(ctx, tripID, owner, lines); err != nil {
+		return nil, err
+	}
+
+	s.checkBudgets(ctx, t)
+	return lines, nil
+}
+
+// CompareBudget sets the trip's budget plan, or the member's own plan when
+// personal is set, against the recorded expenses, with a burn-down over the
+// trip's dates
+func (s *Service) CompareBudget(ctx context.Context, tripID, userID uuid.UUID, personal bool) (*expense.BudgetComparison, error) {
+	t, _, err := s.getMemberTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := s.budgetRepo.ListByTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	expenses, err := s.expenseRepo.ListByTrip(ctx, tripID, expense.ListFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	owner := budgetOwner(userID, personal)
+	return expense.CompareBudget(tripID, owner, t.Currency, expense.PlanOf(lines, owner), expenses,
+		t.StartDate, t.EndDate, time.Now()), nil
+}
+
+// checkBudgets updates the alert levels of the trip's budget lines after its
+// expenses or plans changed, and publishes an alert for each line that
+// reached a new level. Failures are ignored: the change itself succeeded and
+// the next one checks the budgets again.
+func (s *Service) checkBudgets(ctx context.Context, t *trip.Trip) {
+	lines, err := s.budgetRepo.ListByTrip(ctx, t.ID)
+	if err != nil || len(lines) == 0 {
+		return
+	}
+
+	expenses, err := s.expenseRepo.ListByTrip(ctx, t.ID, expense.ListFilter{})
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	var changed []*expense.BudgetLine
+	var alerts []event.Event
+	for _, line := range lines {
+		spent := line.Spent(expenses)
+		lineChanged, alert := line.UpdateAlert(spent)
+		if !lineChanged {
+			continue
+		}
+		changed = append(changed, line)
+		if alert {
+			alerts = append(alerts, expense.BudgetAlerted{Line: line, Spent: spent, OccurredAt: now})
+		}
+	}
+
+	if len(changed) == 0 {
+		return
+	}
+	// Save the levels first, so a failure cannot announce an alert twice
+	if err := s.budgetRepo.UpdateAlertLevels(ctx, changed); err != nil {
+		return
+	}
+	s.publisher.Publish(ctx, alerts...)
+}
+
+// budgetOwner returns the member whose plan is meant: nil for the trip's plan
+func budgetOwner(userID uuid.UUID, personal bool) *uuid.UUID {
+	if !personal {
+		return nil
+	}
+	return &userID
+}
@@ -0,0 +1,162 @@
+package expense
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"jointrip/internal/domain/blob"
+	"jointrip/internal/domain/expense"
+
+	"github.com/google/uuid"
+)
+
+// ThumbnailSize is the largest side, in pixels, of receipt previews
+const ThumbnailSize = 320
+
+// Thumbnailer makes small JPEG previews of images
+type Thumbnailer interface {
+	Thumbnail(data []byte) ([]byte, error)
+}
+
+// ListReceipts returns the receipts attached to an expense
+func (s *Service) ListReceipts(ctx context.Context, tripID, expenseID, userID uuid.UUID) ([]*expense.Receipt, error) {
+	if _, _, err := s.getMemberTrip(ctx, tripID, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.getTripExpense(ctx, tripID, expenseID); err != nil {
+		return nil, err
+	}
+
+	return s.receiptRepo.ListByExpense(ctx, expenseID)
+}
+
+// UploadReceipt attaches a receipt file to an expense. Any trip member can
+// attach receipts, to settled expenses too. Images get a thumbnail, which
+// also proves they can be decoded.
+func (s *Service) UploadReceipt(ctx context.Context, tripID, expenseID, userID uuid.UUID, filename string, content []byte) (*expense.Receipt, error) {
+	if _, _, err := s.getMemberTrip(ctx, tripID, userID); err != nil {
+		return nil, err
+	}
+
+	e, err := s.getTripExpense(ctx, tripID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.receiptRepo.ListByExpense(ctx, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= expense.MaxReceiptsPerExpense {
+		return nil, expense.ErrTooManyReceipts
+	}
+
+	receipt, err := expense.NewReceipt(e, userID, filename, content[:min(len(content), blob.SniffLength)], int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	var thumbnail []byte
+	if receipt.IsImage() {
+		thumbnail, err = s.thumbnailer.Thumbnail(content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", expense.ErrInvalidReceipt, err)
+		}
+		receipt.SetThumbnail()
+	}
+
+	if err := s.blobStore.Put(ctx, receipt.StorageKey, bytes.NewReader(content), receipt.Size, receipt.ContentType); err != nil {
+		return nil, err
+	}
+	if thumbnail != nil {
+		err = s.blobStore.Put(ctx, receipt.ThumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), blob.ContentTypeJPEG)
+	}
+	if err == nil {
+		err = s.receiptRepo.Create(ctx, receipt)
+	}
+	if err != nil {
+		s.deleteReceiptFiles(ctx, receipt)
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// OpenReceipt returns a receipt and its file, or its thumbnail, for a trip member
+func (s *Service) OpenReceipt(ctx context.Context, tripID, expenseID, receiptID, userID uuid.UUID, thumbnail bool) (*expense.Receipt, io.ReadCloser, error) {
+	if _, _, err := s.getMemberTrip(ctx, tripID, userID); err != nil {
+		return nil, nil, err
+	}
+
+	_, receipt, err := s.getExpenseReceipt(ctx, tripID, expenseID, receiptID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := receipt.StorageKey
+	if thumbnail {
+		if !receipt.HasThumbnail {
+			return nil, nil, expense.ErrReceiptNotFound
+		}
+		key = receipt.ThumbnailKey
+	}
+
+	file, err := s.blobStore.Open(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return receipt, file, nil
+}
+
+// DeleteReceipt removes a receipt and its files
+func (s *Service) DeleteReceipt(ctx context.Context, tripID, expenseID, receiptID, userID uuid.UUID) error {
+	t, _, err := s.getMemberTrip(ctx, tripID, userID)
+	if err != nil {
+		return err
+	}
+
+	e, receipt, err := s.getExpenseReceipt(ctx, tripID, expenseID, receiptID)
+	if err != nil {
+		return err
+	}
+	if !receipt.CanDelete(e, userID, t.CreatorID) {
+		return expense.ErrNotReceiptOwner
+	}
+
+	if err := s.receiptRepo.Delete(ctx, receipt.ID); err != nil {
+		return err
+	}
+
+	s.deleteReceiptFiles(ctx, receipt)
+	return nil
+}
+
+// getExpenseReceipt returns an expense of the trip and one of its receipts
+func (s *Service) getExpenseReceipt(ctx context.Context, tripID, expenseID, receiptID uuid.UUID) (*expense.Expense, *expense.Receipt, error) {
+	e, err := s.getTripExpense(ctx, tripID, expenseID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	receipt, err := s.receiptRepo.GetByID(ctx, receiptID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if receipt.ExpenseID != e.ID {
+		return nil, nil, expense.ErrReceiptNotFound
+	}
+
+	return e, receipt, nil
+}
+
+// deleteReceiptFiles removes a receipt's files from storage. Failures are
+// ignored: the receipt is gone either way and a stray file does no harm.
+func (s *Service) deleteReceiptFiles(ctx context.Context, receipt *expense.Receipt) {
+	s.blobStore.Delete(ctx, receipt.StorageKey)
+	if receipt.ThumbnailKey != "" {
+		s.blobStore.Delete(ctx, receipt.ThumbnailKey)
+	}
+}
@@ -0,0 +1,251 @@
+package expense
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"jointrip/internal/domain/expense"
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+)
+
+// RecurringInput holds the data needed to set up or change a recurring
+// expense. Empty fields fall back to defaults: the current user pays, in the
+// trip's currency, under "accommodation", every night of the trip.
+type RecurringInput struct {
+	PayerID   *uuid.UUID
+	Title     string
+	Amount    int64
+	Currency  string
+	Category  expense.Category
+	Notes     string
+	Frequency expense.Frequency
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// ListRecurringExpenses returns a trip's recurring expenses
+func (s *Service) ListRecurringExpenses(ctx context.Context, tripID, userID uuid.UUID) ([]*expense.RecurringExpense, error) {
+	if _, _, err := s.getMemberTrip(ctx, tripID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.recurringRepo.ListByTrip(ctx, tripID)
+}
+
+// CreateRecurringExpense sets up a cost that is recorded as an expense every
+// period. Only the organizer can manage recurring expenses.
+func (s *Service) CreateRecurringExpense(ctx context.Context, tripID, userID uuid.UUID, input RecurringInput) (*expense.RecurringExpense, error) {
+	t, members, err := s.getOrganizerTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := resolveRecurringInput(t, userID, members, input)
+	if err != nil {
+		return nil, err
+	}
+
+	recurring, err := expense.NewRecurringExpense(tripID, userID, details)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recurringRepo.Create(ctx, recurring); err != nil {
+		return nil, err
+	}
+
+	return recurring, nil
+}
+
+// UpdateRecurringExpense changes a recurring expense. Periods already
+// recorded keep their expenses; changes apply to the next ones.
+func (s *Service) UpdateRecurringExpense(ctx context.Context, tripID, recurringID, userID uuid.UUID, input RecurringInput) (*expense.RecurringExpense, error) {
+	t, members, err := s.getOrganizerTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	recurring, err := s.getTripRecurringExpense(ctx, tripID, recurringID)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := resolveRecurringInput(t, userID, members, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recurring.Update(details); err != nil {
+		return nil, err
+	}
+
+	if err := s.recurringRepo.Update(ctx, recurring); err != nil {
+		return nil, err
+	}
+
+	return recurring, nil
+}
+
+// DeleteRecurringExpense stops a recurring expense. The expenses it already
+// recorded remain.
+func (s *Service) DeleteRecurringExpense(ctx context.Context, tripID, recurringID, userID uuid.UUID) error {
+	if _, _, err := s.getOrganizerTrip(ctx, tripID, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.getTripRecurringExpense(ctx, tripID, recurringID); err != nil {
+		return err
+	}
+
+	return s.recurringRepo.Delete(ctx, recurringID)
+}
+
+// RecordRecurringExpenses records an expense for every period of a
+// recurring expense that is over, split by the nights the members stayed
+// during it, and returns how many it recorded. A failing recurring expense
+// does not hold up the others.
+func (s *Service) RecordRecurringExpenses(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.recurringRepo.ListDue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	recorded := 0
+	var errs []error
+	for _, recurring := range due {
+		n, err := s.recordRecurringExpense(ctx, recurring, now)
+		recorded += n
+		if err != nil && !errors.Is(err, expense.ErrRecurringExpenseNotFound) {
+			errs = append(errs, err)
+		}
+	}
+
+	return recorded, errors.Join(errs...)
+}
+
+// recordRecurringExpense records the periods of a recurring expense that
+// are over
+func (s *Service) recordRecurringExpense(ctx context.Context, recurring *expense.RecurringExpense, now time.Time) (int, error) {
+	t, err := s.tripRepo.GetByID(ctx, recurring.TripID)
+	if err != nil {
+		return 0, err
+	}
+
+	participants, err := s.participantRepo.ListByTrip(ctx, t.ID)
+	if err != nil {
+		return 0, err
+	}
+	stays := tripStays(t, participants)
+
+	recorded := 0
+	for recurring.IsDue(now) {
+		previous := recurring.NextDate
+		e, err := recurring.NextOccurrence(stays)
+		if err != nil {
+			return recorded, err
+		}
+		if err := s.applyRate(ctx, t, e); err != nil {
+			return recorded, err
+		}
+		if err := s.recurringRepo.Record(ctx, recurring, previous, e); err != nil {
+			return recorded, err
+		}
+		recorded++
+	}
+
+	if recorded > 0 {
+		s.checkBudgets(ctx, t)
+	}
+	return recorded, nil
+}
+
+// getOrganizerTrip returns the trip and its current members if the user
+// organizes it
+func (s *Service) getOrganizerTrip(ctx context.Context, tripID, userID uuid.UUID) (*trip.Trip, map[uuid.UUID]bool, error) {
+	t, members, err := s.getMemberTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.CreatorID != userID {
+		return nil, nil, expense.ErrNotRecurringOwner
+	}
+	return t, members, nil
+}
+
+// getTripRecurringExpense returns a recurring expense of the trip
+func (s *Service) getTripRecurringExpense(ctx context.Context, tripID, recurringID uuid.UUID) (*expense.RecurringExpense, error) {
+	recurring, err := s.recurringRepo.GetByID(ctx, recurringID)
+	if err != nil {
+		return nil, err
+	}
+	if recurring.TripID != tripID {
+		return nil, expense.ErrRecurringExpenseNotFound
+	}
+	return recurring, nil
+}
+
+// tripStays returns the nights each member spends on the trip: the organizer
+// throughout, participants from their approval until they left. Participants
+// who left before leave dates were kept have no stay.
+func tripStays(t *trip.Trip, participants []*trip.Participant) []expense.Stay {
+	stays := []expense.Stay{{UserID: t.CreatorID}}
+	for _, p := range participants {
+		if p.UserID == t.CreatorID || p.JoinDate == nil {
+			continue
+		}
+		switch {
+		case p.IsApproved():
+			stays = append(stays, expense.Stay{UserID: p.UserID, From: *p.JoinDate})
+		case p.LeftDate != nil:
+			stays = append(stays, expense.Stay{UserID: p.UserID, From: *p.JoinDate, To: p.LeftDate})
+		}
+	}
+	return stays
+}
+
+// resolveRecurringInput applies the defaults to the input and checks that
+// the payer is a trip member
+func resolveRecurringInput(t *trip.Trip, userID uuid.UUID, members map[uuid.UUID]bool, input RecurringInput) (expense.RecurringDetails, error) {
+	details := expense.RecurringDetails{
+		PayerID:   userID,
+		Title:     input.Title,
+		Amount:    input.Amount,
+		Currency:  input.Currency,
+		Category:  input.Category,
+		Notes:     input.Notes,
+		Frequency: input.Frequency,
+		StartDate: t.StartDate,
+		EndDate:   t.EndDate,
+	}
+	if input.PayerID != nil {
+		details.PayerID = *input.PayerID
+	}
+	if details.Currency == "" {
+		details.Currency = t.Currency
+	}
+	if details.Category == "" {
+		details.Category = expense.CategoryAccommodation
+	}
+	if details.Frequency == "" {
+		details.Frequency = expense.FrequencyDaily
+	}
+	// The last night of a trip is the one before its last day
+	if t.EndDate.After(t.StartDate) {
+		details.EndDate = t.EndDate.AddDate(0, 0, -1)
+	}
+	if input.StartDate != nil {
+		details.StartDate = *input.StartDate
+	}
+	if input.EndDate != nil {
+		details.EndDate = *input.EndDate
+	}
+
+	if !members[details.PayerID] {
+		return expense.RecurringDetails{}, expense.ErrNotTripMember
+	}
+
+	return details, nil
+}
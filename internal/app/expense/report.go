@@ -0,0 +1,114 @@
+package expense
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"jointrip/internal/domain/expense"
+	"jointrip/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// ReportLinkTTL is how long a report download link stays valid
+const ReportLinkTTL = 10 * time.Minute
+
+// ReportSigner defines the interface for signing report download tokens
+type ReportSigner interface {
+	Sign(claims expense.ReportClaims) (string, error)
+	Verify(token string, now time.Time) (expense.ReportClaims, error)
+}
+
+// ReportLink is a signed, short-lived token to download a report with
+type ReportLink struct {
+	Token     string               `json:"token"`
+	Format    expense.ReportFormat `json:"format"`
+	ExpiresAt time.Time            `json:"expires_at"`
+}
+
+// CreateReportLink issues a link to download the trip's expense report.
+// The report is produced when the link is used, from the data at that time.
+func (s *Service) CreateReportLink(ctx context.Context, tripID, userID uuid.UUID, format expense.ReportFormat, filter expense.ListFilter) (*ReportLink, error) {
+	if _, _, err := s.getMemberTrip(ctx, tripID, userID); err != nil {
+		return nil, err
+	}
+
+	claims, err := expense.NewReportClaims(tripID, userID, format, filter, time.Now(), ReportLinkTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.reportSigner.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReportLink{Token: token, Format: format, ExpiresAt: claims.ExpiresAt}, nil
+}
+
+// GetReport produces the report a link grants, as long as the user it was
+// issued to is still a member of the trip
+func (s *Service) GetReport(ctx context.Context, token string) (*expense.Report, expense.ReportFormat, error) {
+	claims, err := s.reportSigner.Verify(token, time.Now())
+	if err != nil {
+		return nil, "", err
+	}
+
+	t, _, err := s.getMemberTrip(ctx, claims.TripID, claims.UserID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filter := claims.Filter()
+	expenses, err := s.expenseRepo.ListByTrip(ctx, t.ID, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	names, err := s.displayNames(ctx, expenses)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return expense.NewReport(t.Title, filter, expenses, names, time.Now()), claims.Format, nil
+}
+
+// displayNames returns the names of the payers and participants of expenses.
+// Deleted users are left out.
+func (s *Service) displayNames(ctx context.Context, expenses []*expense.Expense) (map[uuid.UUID]string, error) {
+	names := make(map[uuid.UUID]string)
+	lookup := func(userID uuid.UUID) error {
+		if _, ok := names[userID]; ok {
+			return nil
+		}
+		u, err := s.userRepo.GetByID(ctx, userID)
+		if errors.Is(err, user.ErrUserNotFound) {
+			names[userID] = ""
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimSpace(u.FirstName + " " + u.LastName)
+		if name == "" {
+			name = u.Username
+		}
+		names[userID] = name
+		return nil
+	}
+
+	for _, e := range expenses {
+		if err := lookup(e.PayerID); err != nil {
+			return nil, err
+		}
+		for _, share := range e.Shares {
+			if err := lookup(share.UserID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return names, nil
+}
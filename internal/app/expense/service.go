@@ -0,0 +1,331 @@
+package expense
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"jointrip/internal/domain/blob"
+	"jointrip/internal/domain/currency"
+	"jointrip/internal/domain/event"
+	"jointrip/internal/domain/expense"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// ExpenseInput holds the data needed to record or change an expense. Empty
+// fields fall back to defaults: the current user pays, in the trip's
+// currency, today, under "other", split equally among all trip members.
+type ExpenseInput struct {
+	PayerID     *uuid.UUID
+	Title       string
+	Amount      int64
+	Currency    string
+	Category    expense.Category
+	Date        *time.Time
+	Notes       string
+	SplitMethod expense.SplitMethod
+	Split       []expense.Portion
+}
+
+// RateProvider looks up the exchange rate between two currencies on a day
+type RateProvider interface {
+	Rate(ctx context.Context, base, quote string, on time.Time) (currency.Rate, error)
+}
+
+// Service provides shared expense business logic. Only trip members, the
+// organizer and approved participants, can see and record expenses.
+type Service struct {
+	expenseRepo     expense.Repository
+	receiptRepo     expense.ReceiptRepository
+	budgetRepo      expense.BudgetRepository
+	recurringRepo   expense.RecurringRepository
+	tripRepo        trip.Repository
+	participantRepo trip.ParticipantRepository
+	userRepo        user.Repository
+	rates           RateProvider
+	reportSigner    ReportSigner
+	blobStore       blob.Store
+	thumbnailer     Thumbnailer
+	publisher       event.Publisher
+}
+
+// NewService creates a new expense service
+func NewService(
+	expenseRepo expense.Repository,
+	receiptRepo expense.ReceiptRepository,
+	budgetRepo expense.BudgetRepository,
+	recurringRepo expense.RecurringRepository,
+	tripRepo trip.Repository,
+	participantRepo trip.ParticipantRepository,
+	userRepo user.Repository,
+	rates RateProvider,
+	reportSigner ReportSigner,
+	blobStore blob.Store,
+	thumbnailer Thumbnailer,
+	publisher event.Publisher,
+) *Service {
+	return &Service{
+		expenseRepo:     expenseRepo,
+		receiptRepo:     receiptRepo,
+		budgetRepo:      budgetRepo,
+		recurringRepo:   recurringRepo,
+		tripRepo:        tripRepo,
+		participantRepo: participantRepo,
+		userRepo:        userRepo,
+		rates:           rates,
+		reportSigner:    reportSigner,
+		blobStore:       blobStore,
+		thumbnailer:     thumbnailer,
+		publisher:       publisher,
+	}
+}
+
+// ListExpenses returns a trip's expenses matching the filter, most recent first
+func (s *Service) ListExpenses(ctx context.Context, tripID, userID uuid.UUID, filter expense.ListFilter) ([]*expense.Expense, error) {
+	if _, _, err := s.getMemberTrip(ctx, tripID, userID); err != nil {
+		return nil, err
+	}
+
+	if filter.Category != "" && !filter.Category.IsValid() {
+		return nil, expense.ErrInvalidCategory
+	}
+
+	return s.expenseRepo.ListByTrip(ctx, tripID, filter)
+}
+
+// GetExpense returns an expense of the trip
+func (s *Service) GetExpense(ctx context.Context, tripID, expenseID, userID uuid.UUID) (*expense.Expense, error) {
+	if _, _, err := s.getMemberTrip(ctx, tripID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.getTripExpense(ctx, tripID, expenseID)
+}
+
+// CreateExpense records an expense on the trip
+func (s *Service) CreateExpense(ctx context.Context, tripID, userID uuid.UUID, input ExpenseInput) (*expense.Expense, error) {
+	t, members, err := s.getMemberTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	details, method, portions, err := resolveInput(t, userID, members, input)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := expense.NewExpense(tripID, userID, details, method, portions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyRate(ctx, t, e); err != nil {
+		return nil, err
+	}
+
+	if err := s.expenseRepo.Create(ctx, e); err != nil {
+		return nil, err
+	}
+
+	s.checkBudgets(ctx, t)
+	return e, nil
+}
+
+// UpdateExpense changes an expense and its split. The payer, the member who
+// recorded it and the organizer can change it until a share is settled.
+func (s *Service) UpdateExpense(ctx context.Context, tripID, expenseID, userID uuid.UUID, input ExpenseInput) (*expense.Expense, error) {
+	t, members, err := s.getMemberTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := s.getTripExpense(ctx, tripID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	if !e.CanModify(userID, t.CreatorID) {
+		return nil, expense.ErrNotExpenseOwner
+	}
+
+	details, method, portions, err := resolveInput(t, userID, members, input)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := *e
+	if err := e.Update(details, method, portions); err != nil {
+		return nil, err
+	}
+
+	// Keep the rate snapshot unless what it depends on changed
+	if e.Currency == previous.Currency && e.Date.Equal(previous.Date) && previous.BaseCurrency == t.Currency {
+		err = e.ApplyRate(previous.ExchangeRate)
+	} else {
+		err = s.applyRate(ctx, t, e)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.expenseRepo.Update(ctx, e); err != nil {
+		return nil, err
+	}
+
+	s.checkBudgets(ctx, t)
+	return e, nil
+}
+
+// DeleteExpense deletes an expense that has no settled shares, with its receipts
+func (s *Service) DeleteExpense(ctx context.Context, tripID, expenseID, userID uuid.UUID) error {
+	t, _, err := s.getMemberTrip(ctx, tripID, userID)
+	if err != nil {
+		return err
+	}
+
+	e, err := s.getTripExpense(ctx, tripID, expenseID)
+	if err != nil {
+		return err
+	}
+	if !e.CanModify(userID, t.CreatorID) {
+		return expense.ErrNotExpenseOwner
+	}
+	if e.HasSettledShares() {
+		return expense.ErrExpenseSettled
+	}
+
+	receipts, err := s.receiptRepo.ListByExpense(ctx, e.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.expenseRepo.Delete(ctx, e.ID); err != nil {
+		return err
+	}
+
+	for _, receipt := range receipts {
+		s.deleteReceiptFiles(ctx, receipt)
+	}
+	s.checkBudgets(ctx, t)
+	return nil
+}
+
+// applyRate snapshots the rate from the expense currency into the trip's
+// currency on the day of the expense
+func (s *Service) applyRate(ctx context.Context, t *trip.Trip, e *expense.Expense) error {
+	rate, err := s.rates.Rate(ctx, e.Currency, t.Currency, e.Date)
+	if err != nil {
+		return err
+	}
+	return e.ApplyRate(rate)
+}
+
+// getMemberTrip returns the trip and its current members if the user is one
+// of them. Non-members get ErrTripNotFound for trips they cannot see and
+// ErrNotTripMember for public ones.
+func (s *Service) getMemberTrip(ctx context.Context, tripID, userID uuid.UUID) (*trip.Trip, map[uuid.UUID]bool, error) {
+	t, err := s.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	access, err := trip.CheckAccess(ctx, s.participantRepo, t, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !access.Visible {
+		return nil, nil, trip.ErrTripNotFound
+	}
+	if !access.Member {
+		return nil, nil, expense.ErrNotTripMember
+	}
+
+	members, err := s.members(ctx, t)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return t, members, nil
+}
+
+// members returns the IDs of the trip's organizer and approved participants
+func (s *Service) members(ctx context.Context, t *trip.Trip) (map[uuid.UUID]bool, error) {
+	participants, err := s.participantRepo.ListByTrip(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := map[uuid.UUID]bool{t.CreatorID: true}
+	for _, p := range participants {
+		if p.IsApproved() {
+			members[p.UserID] = true
+		}
+	}
+	return members, nil
+}
+
+// getTripExpense returns an expense of the trip
+func (s *Service) getTripExpense(ctx context.Context, tripID, expenseID uuid.UUID) (*expense.Expense, error) {
+	e, err := s.expenseRepo.GetByID(ctx, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	if e.TripID != tripID {
+		return nil, expense.ErrExpenseNotFound
+	}
+	return e, nil
+}
+
+// resolveInput applies the defaults to the input and checks that the payer
+// and everyone in the split are trip members
+func resolveInput(t *trip.Trip, userID uuid.UUID, members map[uuid.UUID]bool, input ExpenseInput) (expense.Details, expense.SplitMethod, []expense.Portion, error) {
+	details := expense.Details{
+		PayerID:  userID,
+		Title:    input.Title,
+		Amount:   input.Amount,
+		Currency: input.Currency,
+		Category: input.Category,
+		Date:     time.Now().UTC(),
+		Notes:    input.Notes,
+	}
+	if input.PayerID != nil {
+		details.PayerID = *input.PayerID
+	}
+	if details.Currency == "" {
+		details.Currency = t.Currency
+	}
+	if details.Category == "" {
+		details.Category = expense.CategoryOther
+	}
+	if input.Date != nil {
+		details.Date = *input.Date
+	}
+
+	method := input.SplitMethod
+	if method == "" {
+		method = expense.SplitEqual
+	}
+
+	portions := input.Split
+	if len(portions) == 0 && method == expense.SplitEqual {
+		for memberID := range members {
+			portions = append(portions, expense.Portion{UserID: memberID})
+		}
+		sort.Slice(portions, func(i, j int) bool {
+			return portions[i].UserID.String() < portions[j].UserID.String()
+		})
+	}
+
+	if !members[details.PayerID] {
+		return expense.Details{}, "", nil, expense.ErrNotTripMember
+	}
+	for _, portion := range portions {
+		if !members[portion.UserID] {
+			return expense.Details{}, "", nil, expense.ErrNotTripMember
+		}
+	}
+
+	return details, method, portions, nil
+}
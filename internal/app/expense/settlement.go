@@ -0,0 +1,188 @@
+package expense
+
+import (
+	"context"
+	"time"
+
+	"jointrip/internal/domain/currency"
+	"jointrip/internal/domain/expense"
+
+	"github.com/google/uuid"
+)
+
+// BalanceSheet holds a trip's open balances and the transfers that settle them
+type BalanceSheet struct {
+	Balances  []expense.Balance  `json:"balances"`
+	Transfers []expense.Transfer `json:"transfers"`
+}
+
+// Settlement is a payment that squared up two members, with the shares it settled
+type Settlement struct {
+	Transfer expense.Transfer `json:"transfer"`
+	Shares   []*expense.Share `json:"shares"`
+}
+
+// GetBalances returns every member's net balance and a settle-up plan with as
+// few transfers as possible, per currency paid in or in the trip's base
+// currency. With preferred set, balances and transfers also carry the amount
+// in the preferred currency of the member they belong to, or of the payer,
+// at today's rate.
+func (s *Service) GetBalances(ctx context.Context, tripID, userID uuid.UUID, basis expense.Basis, preferred bool) (*BalanceSheet, error) {
+	if _, _, err := s.getMemberTrip(ctx, tripID, userID); err != nil {
+		return nil, err
+	}
+
+	expenses, err := s.expenseRepo.ListByTrip(ctx, tripID, expense.ListFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	balances := expense.ComputeBalances(expenses, basis)
+	sheet := &BalanceSheet{
+		Balances:  balances,
+		Transfers: expense.SettleUp(balances),
+	}
+
+	if preferred {
+		if err := s.convertToPreferred(ctx, sheet); err != nil {
+			return nil, err
+		}
+	}
+
+	return sheet, nil
+}
+
+// SettleShare records that a share was paid back
+func (s *Service) SettleShare(ctx context.Context, tripID, expenseID, shareID, userID uuid.UUID, notes string) (*expense.Expense, error) {
+	return s.updateShare(ctx, tripID, expenseID, shareID, userID, func(e *expense.Expense, share *expense.Share) error {
+		return e.SettleShare(share, notes, time.Now())
+	})
+}
+
+// UnsettleShare reopens a share that was settled by mistake
+func (s *Service) UnsettleShare(ctx context.Context, tripID, expenseID, shareID, userID uuid.UUID) (*expense.Expense, error) {
+	return s.updateShare(ctx, tripID, expenseID, shareID, userID, func(e *expense.Expense, share *expense.Share) error {
+		return e.UnsettleShare(share)
+	})
+}
+
+// SettleBetween records that two members squared up in a currency, settling
+// every open share between them. Either of them or the organizer can record it.
+func (s *Service) SettleBetween(ctx context.Context, tripID, userID, fromUserID, toUserID uuid.UUID, code, notes string) (*Settlement, error) {
+	t, members, err := s.getMemberTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != fromUserID && userID != toUserID && !t.IsOrganizer(userID) {
+		return nil, expense.ErrNotSettlementParty
+	}
+	if !members[fromUserID] || !members[toUserID] {
+		return nil, expense.ErrNotTripMember
+	}
+
+	if code == "" {
+		code = t.Currency
+	}
+	code, err = currency.Normalize(code)
+	if err != nil {
+		return nil, err
+	}
+
+	expenses, err := s.expenseRepo.ListByTrip(ctx, tripID, expense.ListFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	shares, transfer, err := expense.SettleBetween(expenses, fromUserID, toUserID, code, notes, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.expenseRepo.UpdateSettlements(ctx, shares); err != nil {
+		return nil, err
+	}
+
+	return &Settlement{Transfer: transfer, Shares: shares}, nil
+}
+
+// convertToPreferred fills in the converted amounts of a balance sheet.
+// Members without a preferred currency are left as they are.
+func (s *Service) convertToPreferred(ctx context.Context, sheet *BalanceSheet) error {
+	now := time.Now().UTC()
+	preferred := make(map[uuid.UUID]string)
+	convert := func(userID uuid.UUID, code string, amount int64) (*currency.Money, error) {
+		target, ok := preferred[userID]
+		if !ok {
+			u, err := s.userRepo.GetByID(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+			target = u.PreferredCurrency
+			preferred[userID] = target
+		}
+		if target == "" {
+			return nil, nil
+		}
+
+		rate, err := s.rates.Rate(ctx, code, target, now)
+		if err != nil {
+			return nil, err
+		}
+		converted, err := rate.Convert(amount)
+		if err != nil {
+			return nil, err
+		}
+		return &currency.Money{Currency: target, Amount: converted}, nil
+	}
+
+	for i, b := range sheet.Balances {
+		money, err := convert(b.UserID, b.Currency, b.Net)
+		if err != nil {
+			return err
+		}
+		sheet.Balances[i].Converted = money
+	}
+	for i, t := range sheet.Transfers {
+		money, err := convert(t.FromUserID, t.Currency, t.Amount)
+		if err != nil {
+			return err
+		}
+		sheet.Transfers[i].Converted = money
+	}
+	return nil
+}
+
+// updateShare applies a settlement change to one share of an expense
+func (s *Service) updateShare(
+	ctx context.Context,
+	tripID, expenseID, shareID, userID uuid.UUID,
+	update func(e *expense.Expense, share *expense.Share) error,
+) (*expense.Expense, error) {
+	t, _, err := s.getMemberTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := s.getTripExpense(ctx, tripID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+
+	share, err := e.Share(shareID)
+	if err != nil {
+		return nil, err
+	}
+	if !e.CanSettle(share, userID, t.CreatorID) {
+		return nil, expense.ErrNotSettlementParty
+	}
+
+	if err := update(e, share); err != nil {
+		return nil, err
+	}
+
+	if err := s.expenseRepo.UpdateSettlements(ctx, []*expense.Share{share}); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
@@ -0,0 +1,404 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"jointrip/internal/domain/event"
+	"jointrip/internal/domain/messaging"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// Page size limits for conversation and message listings
+const (
+	DefaultPageSize = 30
+	MaxPageSize     = 100
+)
+
+// ConversationPage is one page of a user's inbox
+type ConversationPage struct {
+	Conversations []*messaging.ConversationSummary `json:"conversations"`
+	NextCursor    string                           `json:"next_cursor,omitempty"`
+}
+
+// MessagePage is one page of a conversation's messages, newest first
+type MessagePage struct {
+	Messages   []*messaging.Message `json:"messages"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// Service provides direct messaging and trip group chat business logic
+type Service struct {
+	messagingRepo   messaging.Repository
+	userRepo        user.Repository
+	blockRepo       user.BlockRepository
+	tripRepo        trip.Repository
+	participantRepo trip.ParticipantRepository
+	publisher       event.Publisher
+}
+
+// NewService creates a new messaging service
+func NewService(
+	messagingRepo messaging.Repository,
+	userRepo user.Repository,
+	blockRepo user.BlockRepository,
+	tripRepo trip.Repository,
+	participantRepo trip.ParticipantRepository,
+	publisher event.Publisher,
+) *Service {
+	return &Service{
+		messagingRepo:   messagingRepo,
+		userRepo:        userRepo,
+		blockRepo:       blockRepo,
+		tripRepo:        tripRepo,
+		participantRepo: participantRepo,
+		publisher:       publisher,
+	}
+}
+
+// StartConversation returns the direct conversation between two users, creating it
+// if needed, and posts the optional first message. The boolean reports whether the
+// conversation was created by this call.
+func (s *Service) StartConversation(ctx context.Context, senderID, recipientID uuid.UUID, body string) (*messaging.Conversation, *messaging.Message, bool, error) {
+	if senderID == recipientID {
+		return nil, nil, false, messaging.ErrCannotMessageSelf
+	}
+
+	if err := s.checkCanMessage(ctx, senderID, recipientID); err != nil {
+		return nil, nil, false, err
+	}
+
+	conversation, created, err := s.getOrCreateDirect(ctx, senderID, recipientID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	if body == "" {
+		return conversation, nil, created, nil
+	}
+
+	message, err := s.postMessage(ctx, conversation, senderID, body)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return conversation, message, created, nil
+}
+
+// ListConversations returns a page of the user's conversations by latest activity
+func (s *Service) ListConversations(ctx context.Context, userID uuid.UUID, cursor string, limit int) (*ConversationPage, error) {
+	after, err := messaging.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit = clampPageSize(limit)
+	summaries, err := s.messagingRepo.ListConversations(ctx, userID, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ConversationPage{Conversations: summaries}
+	if len(summaries) > limit {
+		page.Conversations = summaries[:limit]
+		last := page.Conversations[limit-1]
+		page.NextCursor = messaging.Cursor{At: last.LastActivityAt, ID: last.ID}.Encode()
+	}
+
+	return page, nil
+}
+
+// GetConversation returns a conversation the user takes part in
+func (s *Service) GetConversation(ctx context.Context, conversationID, userID uuid.UUID) (*messaging.Conversation, error) {
+	conversation, _, err := s.getMemberConversation(ctx, conversationID, userID)
+	return conversation, err
+}
+
+// SendMessage posts a message to a conversation the sender currently takes part in.
+// Direct messages are subject to blocking and the recipient's privacy settings;
+// trip chats are open to every current member.
+func (s *Service) SendMessage(ctx context.Context, conversationID, senderID uuid.UUID, body string) (*messaging.Message, error) {
+	conversation, member, err := s.getMemberConversation(ctx, conversationID, senderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !member.IsCurrent() {
+		return nil, messaging.ErrNotCurrentMember
+	}
+
+	if !conversation.IsTripChat() {
+		for _, recipientID := range conversation.OtherMembers(senderID) {
+			if err := s.checkCanMessage(ctx, senderID, recipientID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return s.postMessage(ctx, conversation, senderID, body)
+}
+
+// ListMessages returns a page of a conversation's messages, newest first.
+// Members who left only see messages posted before their departure, and
+// members who rejoined do not see what was posted while they were away.
+func (s *Service) ListMessages(ctx context.Context, conversationID, userID uuid.UUID, cursor string, limit int) (*MessagePage, error) {
+	_, member, err := s.getMemberConversation(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := messaging.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit = clampPageSize(limit)
+	messages, err := s.messagingRepo.ListMessages(ctx, conversationID, messaging.MessageFilter{
+		After:  after,
+		Until:  member.LeftAt,
+		Viewer: member.UserID,
+		Limit:  limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &MessagePage{Messages: messages}
+	if len(messages) > limit {
+		page.Messages = messages[:limit]
+		last := page.Messages[limit-1]
+		page.NextCursor = messaging.Cursor{At: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	return page, nil
+}
+
+// MarkRead moves the user's read marker up to the given message, or to the
+// latest message they can see when no message is given
+func (s *Service) MarkRead(ctx context.Context, conversationID, userID uuid.UUID, messageID *uuid.UUID) (*messaging.Member, error) {
+	_, member, err := s.getMemberConversation(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var message *messaging.Message
+	if messageID != nil {
+		message, err = s.getVisibleMessage(ctx, conversationID, *messageID, member)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		latest, err := s.messagingRepo.ListMessages(ctx, conversationID, messaging.MessageFilter{
+			Until:  member.LeftAt,
+			Viewer: member.UserID,
+			Limit:  1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(latest) == 0 {
+			return member, nil
+		}
+		message = latest[0]
+	}
+
+	if !member.MarkRead(message) {
+		return member, nil
+	}
+
+	if err := s.messagingRepo.UpdateMember(ctx, member); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// NotifyTyping tells the other current members of a conversation that the user
+// is writing a message
+func (s *Service) NotifyTyping(ctx context.Context, conversationID, userID uuid.UUID) error {
+	conversation, member, err := s.getMemberConversation(ctx, conversationID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !member.IsCurrent() {
+		return messaging.ErrNotCurrentMember
+	}
+
+	recipientIDs := conversation.OtherMembers(userID)
+	if len(recipientIDs) == 0 {
+		return nil
+	}
+
+	s.publisher.Publish(ctx, messaging.Typing{
+		ConversationID: conversationID,
+		UserID:         userID,
+		RecipientIDs:   recipientIDs,
+		OccurredAt:     time.Now().UTC(),
+	})
+	return nil
+}
+
+// BlockUser stops another user from messaging the blocker and vice versa
+func (s *Service) BlockUser(ctx context.Context, blockerID, blockedID uuid.UUID) (*user.Block, error) {
+	block, err := user.NewBlock(blockerID, blockedID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.blockRepo.Create(ctx, block); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// UnblockUser lifts a block
+func (s *Service) UnblockUser(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	return s.blockRepo.Delete(ctx, blockerID, blockedID)
+}
+
+// ListBlockedUsers returns the blocks the user has placed
+func (s *Service) ListBlockedUsers(ctx context.Context, userID uuid.UUID) ([]*user.Block, error) {
+	return s.blockRepo.ListByBlocker(ctx, userID)
+}
+
+// checkCanMessage enforces blocking and the recipient's privacy settings: users
+// who do not accept messages from strangers can only be reached by people they
+// share a trip with
+func (s *Service) checkCanMessage(ctx context.Context, senderID, recipientID uuid.UUID) error {
+	recipient, err := s.userRepo.GetByID(ctx, recipientID)
+	if err != nil {
+		return err
+	}
+	if !recipient.IsActive {
+		return user.ErrUserNotFound
+	}
+
+	blocked, err := s.blockRepo.ExistsBetween(ctx, senderID, recipientID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return messaging.ErrUserBlocked
+	}
+
+	if recipient.AcceptsMessagesFromStrangers() {
+		return nil
+	}
+
+	shares, err := s.participantRepo.SharesTrip(ctx, senderID, recipientID)
+	if err != nil {
+		return err
+	}
+	if !shares {
+		return messaging.ErrMessagingNotAllowed
+	}
+
+	return nil
+}
+
+// getOrCreateDirect loads the direct conversation between two users or creates it
+func (s *Service) getOrCreateDirect(ctx context.Context, userID, otherID uuid.UUID) (*messaging.Conversation, bool, error) {
+	key := messaging.DirectKey(userID, otherID)
+	conversation, err := s.messagingRepo.GetDirectConversation(ctx, key)
+	if err == nil {
+		return conversation, false, nil
+	}
+	if !errors.Is(err, messaging.ErrConversationNotFound) {
+		return nil, false, err
+	}
+
+	conversation, err = messaging.NewDirectConversation(userID, otherID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := s.messagingRepo.CreateConversation(ctx, conversation); err != nil {
+		if !errors.Is(err, messaging.ErrConversationExists) {
+			return nil, false, err
+		}
+		// Lost a race with the other user starting the same conversation
+		conversation, err = s.messagingRepo.GetDirectConversation(ctx, key)
+		if err != nil {
+			return nil, false, err
+		}
+		return conversation, false, nil
+	}
+
+	return conversation, true, nil
+}
+
+// postMessage stores a new message in a conversation
+func (s *Service) postMessage(ctx context.Context, conversation *messaging.Conversation, senderID uuid.UUID, body string) (*messaging.Message, error) {
+	message, err := messaging.NewMessage(conversation.ID, senderID, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.messagingRepo.CreateMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	conversation.LastActivityAt = message.CreatedAt
+	if member := conversation.Member(senderID); member != nil {
+		member.MarkRead(message)
+	}
+
+	s.publishPosted(ctx, conversation, message)
+	return message, nil
+}
+
+// publishPosted announces a new message to the conversation's current members
+func (s *Service) publishPosted(ctx context.Context, conversation *messaging.Conversation, message *messaging.Message) {
+	s.publisher.Publish(ctx, messaging.MessagePosted{
+		Message:      message,
+		TripID:       conversation.TripID,
+		RecipientIDs: conversation.CurrentMembers(),
+	})
+}
+
+// getMemberConversation loads a conversation and the user's membership, hiding
+// the conversation from non-members
+func (s *Service) getMemberConversation(ctx context.Context, conversationID, userID uuid.UUID) (*messaging.Conversation, *messaging.Member, error) {
+	conversation, err := s.messagingRepo.GetConversation(ctx, conversationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	member := conversation.Member(userID)
+	if member == nil {
+		return nil, nil, messaging.ErrConversationNotFound
+	}
+
+	return conversation, member, nil
+}
+
+// getVisibleMessage loads a message of the conversation that the member can see
+func (s *Service) getVisibleMessage(ctx context.Context, conversationID, messageID uuid.UUID, member *messaging.Member) (*messaging.Message, error) {
+	message, err := s.messagingRepo.GetMessage(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if message.ConversationID != conversationID || !member.CanSee(message) {
+		return nil, messaging.ErrMessageNotFound
+	}
+
+	return message, nil
+}
+
+// clampPageSize applies the default and maximum page sizes
+func clampPageSize(limit int) int {
+	if limit <= 0 {
+		return DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return limit
+}
@@ -0,0 +1,62 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+
+	"jointrip/internal/domain/messaging"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/domain/user"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Stubs implement only the calls made before a conversation is created;
+// any other call panics on the nil embedded interface.
+
+type stubUserRepo struct {
+	user.Repository
+	users map[uuid.UUID]*user.User
+}
+
+func (r *stubUserRepo) GetByID(_ context.Context, id uuid.UUID) (*user.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, user.ErrUserNotFound
+	}
+	return u, nil
+}
+
+type stubBlockRepo struct {
+	user.BlockRepository
+}
+
+func (stubBlockRepo) ExistsBetween(context.Context, uuid.UUID, uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+type stubParticipantRepo struct {
+	trip.ParticipantRepository
+	sharesTrip bool
+}
+
+func (r stubParticipantRepo) SharesTrip(context.Context, uuid.UUID, uuid.UUID) (bool, error) {
+	return r.sharesTrip, nil
+}
+
+func TestService_StartConversation_PrivateRecipientRejectsStranger(t *testing.T) {
+	sender, err := user.NewUser("google-sender", "sender@example.com", "Ana", "Sender", "")
+	require.NoError(t, err)
+	recipient, err := user.NewUser("google-recipient", "recipient@example.com", "Bo", "Recipient", "")
+	require.NoError(t, err)
+	recipient.ProfileVisibility = user.PrivacyLevelPrivate
+
+	users := &stubUserRepo{users: map[uuid.UUID]*user.User{sender.ID: sender, recipient.ID: recipient}}
+	service := NewService(nil, users, stubBlockRepo{}, nil, stubParticipantRepo{}, nil)
+
+	_, _, _, err = service.StartConversation(context.Background(), sender.ID, recipient.ID, "Hello!")
+
+	assert.ErrorIs(t, err, messaging.ErrMessagingNotAllowed)
+}
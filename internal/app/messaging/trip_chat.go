@@ -0,0 +1,300 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"jointrip/internal/domain/event"
+	"jointrip/internal/domain/messaging"
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+)
+
+// chatDateLayout formats trip dates in system messages
+const chatDateLayout = "2 Jan 2006"
+
+// RegisterHandlers subscribes the trip group chats to the trip events that keep
+// their membership in sync and generate system messages
+func (s *Service) RegisterHandlers(subscriber event.Subscriber) {
+	subscriber.Subscribe(trip.EventParticipantJoined, s.onParticipantJoined)
+	subscriber.Subscribe(trip.EventParticipantLeft, s.onParticipantLeft)
+	subscriber.Subscribe(trip.EventTripUpdated, s.onTripUpdated)
+}
+
+// GetTripConversation returns the group chat of a trip, creating it on first use.
+// Approved participants have full access; former participants keep read access
+// to the history up to their departure.
+func (s *Service) GetTripConversation(ctx context.Context, tripID, userID uuid.UUID) (*messaging.Conversation, error) {
+	if _, err := s.tripRepo.GetByID(ctx, tripID); err != nil {
+		return nil, err
+	}
+
+	conversation, err := s.ensureTripConversation(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	member := conversation.Member(userID)
+	if member != nil && member.IsCurrent() {
+		return conversation, nil
+	}
+
+	// Heal a membership whose join event was missed
+	participant, err := s.participantRepo.GetByTripAndUser(ctx, tripID, userID)
+	if err != nil && !errors.Is(err, trip.ErrParticipantNotFound) {
+		return nil, err
+	}
+	if participant != nil && participant.IsApproved() {
+		if _, err := s.addTripMember(ctx, conversation, userID); err != nil {
+			return nil, err
+		}
+		return conversation, nil
+	}
+
+	if member == nil {
+		return nil, messaging.ErrNotTripMember
+	}
+
+	return conversation, nil
+}
+
+// ListPinnedMessages returns the pinned messages of a trip chat, most recent first
+func (s *Service) ListPinnedMessages(ctx context.Context, conversationID, userID uuid.UUID) ([]*messaging.Message, error) {
+	conversation, member, err := s.getMemberConversation(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !conversation.IsTripChat() {
+		return nil, messaging.ErrNotTripChat
+	}
+
+	return s.messagingRepo.ListMessages(ctx, conversationID, messaging.MessageFilter{
+		Until:      member.LeftAt,
+		Viewer:     member.UserID,
+		PinnedOnly: true,
+		Limit:      MaxPageSize,
+	})
+}
+
+// PinMessage pins a message of a trip chat on behalf of the trip organizer
+func (s *Service) PinMessage(ctx context.Context, conversationID, messageID, userID uuid.UUID) (*messaging.Message, error) {
+	message, err := s.getPinnableMessage(ctx, conversationID, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	message.Pin(userID)
+	if err := s.messagingRepo.UpdatePin(ctx, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// UnpinMessage unpins a message of a trip chat on behalf of the trip organizer
+func (s *Service) UnpinMessage(ctx context.Context, conversationID, messageID, userID uuid.UUID) (*messaging.Message, error) {
+	message, err := s.getPinnableMessage(ctx, conversationID, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	message.Unpin()
+	if err := s.messagingRepo.UpdatePin(ctx, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// onParticipantJoined adds the new participant to the trip chat and announces them
+func (s *Service) onParticipantJoined(ctx context.Context, e event.Event) error {
+	joined, ok := e.(trip.ParticipantJoined)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", e)
+	}
+
+	conversation, err := s.ensureTripConversation(ctx, joined.TripID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.addTripMember(ctx, conversation, joined.UserID); err != nil {
+		return err
+	}
+
+	return s.postSystemMessage(ctx, conversation, s.displayName(ctx, joined.UserID)+" joined the trip")
+}
+
+// onParticipantLeft announces the departure and closes the member's access to
+// later messages
+func (s *Service) onParticipantLeft(ctx context.Context, e event.Event) error {
+	left, ok := e.(trip.ParticipantLeft)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", e)
+	}
+
+	conversation, err := s.messagingRepo.GetTripConversation(ctx, left.TripID)
+	if errors.Is(err, messaging.ErrConversationNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	member := conversation.Member(left.UserID)
+	if member == nil || !member.IsCurrent() {
+		return nil
+	}
+
+	name := s.displayName(ctx, left.UserID)
+	text := name + " left the trip"
+	if left.Removed {
+		text = name + " was removed from the trip"
+	}
+
+	message := messaging.NewSystemMessage(conversation.ID, text)
+	if err := s.messagingRepo.CreateMessage(ctx, message); err != nil {
+		return err
+	}
+	s.publishPosted(ctx, conversation, message)
+
+	// The departure notice is the last message the member can see
+	member.Leave(message.CreatedAt)
+	return s.messagingRepo.UpdateMember(ctx, member)
+}
+
+// onTripUpdated announces changes to the trip in its chat
+func (s *Service) onTripUpdated(ctx context.Context, e event.Event) error {
+	updated, ok := e.(trip.TripUpdated)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", e)
+	}
+
+	conversation, err := s.messagingRepo.GetTripConversation(ctx, updated.TripID)
+	if errors.Is(err, messaging.ErrConversationNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !updated.DatesChanged {
+		return s.postSystemMessage(ctx, conversation, "The trip details were updated")
+	}
+
+	t, err := s.tripRepo.GetByID(ctx, updated.TripID)
+	if err != nil {
+		return err
+	}
+
+	return s.postSystemMessage(ctx, conversation, fmt.Sprintf("The trip dates changed to %s – %s",
+		t.StartDate.Format(chatDateLayout), t.EndDate.Format(chatDateLayout)))
+}
+
+// ensureTripConversation loads the group chat of a trip, creating it with the
+// trip's approved participants if it does not exist yet
+func (s *Service) ensureTripConversation(ctx context.Context, tripID uuid.UUID) (*messaging.Conversation, error) {
+	conversation, err := s.messagingRepo.GetTripConversation(ctx, tripID)
+	if err == nil {
+		return conversation, nil
+	}
+	if !errors.Is(err, messaging.ErrConversationNotFound) {
+		return nil, err
+	}
+
+	participants, err := s.participantRepo.ListByTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	var memberIDs []uuid.UUID
+	for _, p := range participants {
+		if p.IsApproved() {
+			memberIDs = append(memberIDs, p.UserID)
+		}
+	}
+
+	conversation = messaging.NewTripConversation(tripID, memberIDs)
+	if err := s.messagingRepo.CreateConversation(ctx, conversation); err != nil {
+		if !errors.Is(err, messaging.ErrConversationExists) {
+			return nil, err
+		}
+		return s.messagingRepo.GetTripConversation(ctx, tripID)
+	}
+
+	return conversation, nil
+}
+
+// addTripMember makes the user a current member of the trip chat. It reports
+// whether the membership changed.
+func (s *Service) addTripMember(ctx context.Context, conversation *messaging.Conversation, userID uuid.UUID) (bool, error) {
+	member := conversation.Member(userID)
+	if member != nil && member.IsCurrent() {
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+	if member == nil {
+		member = &messaging.Member{
+			ConversationID: conversation.ID,
+			UserID:         userID,
+			JoinedAt:       now,
+		}
+		conversation.Members = append(conversation.Members, member)
+	}
+	member.Return(now)
+
+	if err := s.messagingRepo.AddMember(ctx, member); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// postSystemMessage posts a generated message to a conversation
+func (s *Service) postSystemMessage(ctx context.Context, conversation *messaging.Conversation, text string) error {
+	message := messaging.NewSystemMessage(conversation.ID, text)
+	if err := s.messagingRepo.CreateMessage(ctx, message); err != nil {
+		return err
+	}
+
+	conversation.LastActivityAt = message.CreatedAt
+	s.publishPosted(ctx, conversation, message)
+	return nil
+}
+
+// getPinnableMessage loads a trip chat message that the user may pin or unpin
+func (s *Service) getPinnableMessage(ctx context.Context, conversationID, messageID, userID uuid.UUID) (*messaging.Message, error) {
+	conversation, member, err := s.getMemberConversation(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !conversation.IsTripChat() {
+		return nil, messaging.ErrNotTripChat
+	}
+
+	t, err := s.tripRepo.GetByID(ctx, *conversation.TripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !t.IsOrganizer(userID) {
+		return nil, messaging.ErrCannotPinMessage
+	}
+
+	return s.getVisibleMessage(ctx, conversationID, messageID, member)
+}
+
+// displayName returns the name shown for a user in system messages
+func (s *Service) displayName(ctx context.Context, userID uuid.UUID) string {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || u.FirstName == "" {
+		return "A participant"
+	}
+	return u.FirstName
+}
@@ -0,0 +1,288 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"jointrip/internal/domain/comment"
+	"jointrip/internal/domain/currency"
+	"jointrip/internal/domain/event"
+	"jointrip/internal/domain/expense"
+	"jointrip/internal/domain/messaging"
+	"jointrip/internal/domain/notification"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// notificationDateLayout formats trip dates in notifications
+const notificationDateLayout = "2 Jan 2006"
+
+// RegisterHandlers subscribes the notification center to the domain events
+// that users are notified about
+func (s *Service) RegisterHandlers(subscriber event.Subscriber) {
+	subscriber.Subscribe(trip.EventJoinRequested, s.onJoinRequested)
+	subscriber.Subscribe(trip.EventJoinReviewed, s.onJoinReviewed)
+	subscriber.Subscribe(trip.EventTripUpdated, s.onTripUpdated)
+	subscriber.Subscribe(messaging.EventMessagePosted, s.onMessagePosted)
+	subscriber.Subscribe(user.EventRatingReceived, s.onRatingReceived)
+	subscriber.Subscribe(comment.EventUsersMentioned, s.onUsersMentioned)
+	subscriber.Subscribe(expense.EventBudgetAlerted, s.onBudgetAlerted)
+}
+
+// onJoinRequested tells the organizer that a join request awaits review
+func (s *Service) onJoinRequested(ctx context.Context, e event.Event) error {
+	requested, ok := e.(trip.JoinRequested)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", e)
+	}
+
+	t, err := s.tripRepo.GetByID(ctx, requested.TripID)
+	if err != nil {
+		return err
+	}
+
+	actor := s.displayName(ctx, requested.UserID)
+	return s.notify(ctx, t.CreatorID, notification.TypeJoinRequest,
+		fmt.Sprintf("%s wants to join %s", actor, t.Title),
+		"Review the request in the trip's participant list.",
+		notification.EntityTrip, t.ID, t.ID,
+		map[string]string{notification.DataActorName: actor, notification.DataTripTitle: t.Title})
+}
+
+// onJoinReviewed tells the requester about the organizer's decision
+func (s *Service) onJoinReviewed(ctx context.Context, e event.Event) error {
+	reviewed, ok := e.(trip.JoinReviewed)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", e)
+	}
+
+	t, err := s.tripRepo.GetByID(ctx, reviewed.TripID)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]string{notification.DataTripTitle: t.Title}
+	if reviewed.Approved {
+		return s.notify(ctx, reviewed.UserID, notification.TypeJoinApproved,
+			fmt.Sprintf("Your request to join %s was approved", t.Title),
+			"You now have a seat on this trip.",
+			notification.EntityTrip, t.ID, t.ID, data)
+	}
+
+	return s.notify(ctx, reviewed.UserID, notification.TypeJoinRejected,
+		fmt.Sprintf("Your request to join %s was declined", t.Title),
+		"",
+		notification.EntityTrip, t.ID, t.ID, data)
+}
+
+// onTripUpdated tells the participants, except the organizer who made the
+// change, that a trip changed
+func (s *Service) onTripUpdated(ctx context.Context, e event.Event) error {
+	updated, ok := e.(trip.TripUpdated)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", e)
+	}
+
+	t, err := s.tripRepo.GetByID(ctx, updated.TripID)
+	if err != nil {
+		return err
+	}
+
+	participants, err := s.participantRepo.ListByTrip(ctx, updated.TripID)
+	if err != nil {
+		return err
+	}
+
+	content := "The organizer changed the trip details."
+	data := map[string]string{notification.DataTripTitle: t.Title}
+	if updated.DatesChanged {
+		content = fmt.Sprintf("The trip now runs from %s to %s.",
+			t.StartDate.Format(notificationDateLayout), t.EndDate.Format(notificationDateLayout))
+		data[notification.DataStartDate] = t.StartDate.Format(notification.DataDateLayout)
+		data[notification.DataEndDate] = t.EndDate.Format(notification.DataDateLayout)
+	}
+
+	var errs []error
+	for _, p := range participants {
+		if !p.IsApproved() || p.UserID == updated.ActorID {
+			continue
+		}
+		errs = append(errs, s.notify(ctx, p.UserID, notification.TypeTripChanged,
+			fmt.Sprintf("%s was updated", t.Title), content,
+			notification.EntityTrip, t.ID, t.ID, data))
+	}
+
+	return errors.Join(errs...)
+}
+
+// onMessagePosted tells the recipients of a user message about it. While an
+// earlier message notification for the conversation is unread, no new one is
+// added, so busy conversations do not flood the notification center.
+func (s *Service) onMessagePosted(ctx context.Context, e event.Event) error {
+	posted, ok := e.(messaging.MessagePosted)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", e)
+	}
+
+	message := posted.Message
+	if message.Kind != messaging.MessageKindUser || message.SenderID == nil {
+		return nil
+	}
+
+	sender := s.displayName(ctx, *message.SenderID)
+	title := "New message from " + sender
+	data := map[string]string{notification.DataActorName: sender}
+	tripID := uuid.Nil
+	if posted.TripID != nil {
+		tripID = *posted.TripID
+	}
+
+	var errs []error
+	for _, recipientID := range posted.RecipientIDs {
+		if recipientID == *message.SenderID {
+			continue
+		}
+
+		pending, err := s.notificationRepo.HasUnread(ctx, recipientID, notification.TypeNewMessage,
+			notification.EntityConversation, message.ConversationID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if pending {
+			continue
+		}
+
+		errs = append(errs, s.notify(ctx, recipientID, notification.TypeNewMessage, title, message.Body,
+			notification.EntityConversation, message.ConversationID, tripID, data))
+	}
+
+	return errors.Join(errs...)
+}
+
+// onRatingReceived tells a user that a fellow traveler rated them
+func (s *Service) onRatingReceived(ctx context.Context, e event.Event) error {
+	rated, ok := e.(user.RatingReceived)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", e)
+	}
+
+	rater := s.displayName(ctx, rated.RaterID)
+	tripID := uuid.Nil
+	if rated.TripID != nil {
+		tripID = *rated.TripID
+	}
+	return s.notify(ctx, rated.RatedUserID, notification.TypeNewRating,
+		fmt.Sprintf("%s rated you %d/5", rater, rated.Rating),
+		"",
+		notification.EntityUser, rated.RaterID, tripID,
+		map[string]string{notification.DataActorName: rater, notification.DataRating: strconv.Itoa(rated.Rating)})
+}
+
+// onUsersMentioned tells users that a trip comment mentions them
+func (s *Service) onUsersMentioned(ctx context.Context, e event.Event) error {
+	mentioned, ok := e.(comment.UsersMentioned)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", e)
+	}
+
+	c := mentioned.Comment
+	t, err := s.tripRepo.GetByID(ctx, c.TripID)
+	if err != nil {
+		return err
+	}
+
+	author := s.displayName(ctx, c.UserID)
+	title := fmt.Sprintf("%s mentioned you on %s", author, t.Title)
+	data := map[string]string{
+		notification.DataActorName: author,
+		notification.DataTripTitle: t.Title,
+		notification.DataCommentID: c.ID.String(),
+	}
+
+	var errs []error
+	for _, userID := range mentioned.UserIDs {
+		errs = append(errs, s.notify(ctx, userID, notification.TypeMention, title, c.Content,
+			notification.EntityTrip, t.ID, t.ID, data))
+	}
+
+	return errors.Join(errs...)
+}
+
+// onBudgetAlerted tells the members planning a budget line that spending in
+// its category reached the alert threshold or went over the plan: the member
+// for personal lines, everyone on the trip for the trip's lines
+func (s *Service) onBudgetAlerted(ctx context.Context, e event.Event) error {
+	alerted, ok := e.(expense.BudgetAlerted)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", e)
+	}
+
+	line := alerted.Line
+	t, err := s.tripRepo.GetByID(ctx, line.TripID)
+	if err != nil {
+		return err
+	}
+
+	recipients := []uuid.UUID{t.CreatorID}
+	if line.IsPersonal() {
+		recipients = []uuid.UUID{*line.UserID}
+	} else {
+		participants, err := s.participantRepo.ListByTrip(ctx, t.ID)
+		if err != nil {
+			return err
+		}
+		for _, p := range participants {
+			if p.IsApproved() && p.UserID != t.CreatorID {
+				recipients = append(recipients, p.UserID)
+			}
+		}
+	}
+
+	spent := currency.Money{Currency: line.Currency, Amount: alerted.Spent}.String()
+	budget := currency.Money{Currency: line.Currency, Amount: line.Amount}.String()
+	percent := strconv.FormatInt(alerted.Spent*100/line.Amount, 10)
+
+	subject := "The " + string(line.Category) + " budget"
+	if line.IsPersonal() {
+		subject = "Your " + string(line.Category) + " budget"
+	}
+	title := fmt.Sprintf("%s for %s is at %s%%", subject, t.Title, percent)
+	if line.AlertLevel == expense.AlertExceeded {
+		title = fmt.Sprintf("%s for %s is overspent", subject, t.Title)
+	}
+	content := fmt.Sprintf("%s of the %s planned has been spent.", spent, budget)
+
+	data := map[string]string{
+		notification.DataTripTitle: t.Title,
+		notification.DataCategory:  string(line.Category),
+		notification.DataSpent:     spent,
+		notification.DataBudget:    budget,
+		notification.DataPercent:   percent,
+		notification.DataStatus:    line.AlertLevel.String(),
+	}
+	if line.IsPersonal() {
+		data[notification.DataPersonal] = "true"
+	}
+
+	var errs []error
+	for _, userID := range recipients {
+		errs = append(errs, s.notify(ctx, userID, notification.TypeBudgetAlert, title, content,
+			notification.EntityTrip, t.ID, t.ID, data))
+	}
+
+	return errors.Join(errs...)
+}
+
+// displayName returns the name shown for a user in notifications
+func (s *Service) displayName(ctx context.Context, userID uuid.UUID) string {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || u.FirstName == "" {
+		return "Someone"
+	}
+	return u.FirstName
+}
@@ -0,0 +1,220 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"jointrip/internal/domain/event"
+	"jointrip/internal/domain/notification"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// Page is one page of a user's notifications
+type Page struct {
+	Notifications []*notification.Notification `json:"notifications"`
+	UnreadCount   int                          `json:"unread_count"`
+	Limit         int                          `json:"limit"`
+	Offset        int                          `json:"offset"`
+}
+
+// Service provides the notification center. Notifications are created in
+// response to domain events, so producers never call this service directly.
+type Service struct {
+	notificationRepo notification.Repository
+	preferenceRepo   notification.PreferenceRepository
+	userRepo         user.Repository
+	tripRepo         trip.Repository
+	participantRepo  trip.ParticipantRepository
+	publisher        event.Publisher
+	now              func() time.Time
+}
+
+// NewService creates a new notification service
+func NewService(
+	notificationRepo notification.Repository,
+	preferenceRepo notification.PreferenceRepository,
+	userRepo user.Repository,
+	tripRepo trip.Repository,
+	participantRepo trip.ParticipantRepository,
+	publisher event.Publisher,
+) *Service {
+	return &Service{
+		notificationRepo: notificationRepo,
+		preferenceRepo:   preferenceRepo,
+		userRepo:         userRepo,
+		tripRepo:         tripRepo,
+		participantRepo:  participantRepo,
+		publisher:        publisher,
+		now:              time.Now,
+	}
+}
+
+// List returns a page of the user's notifications, newest first
+func (s *Service) List(ctx context.Context, userID uuid.UUID, filter notification.ListFilter) (*Page, error) {
+	filter.Normalize()
+
+	notifications, err := s.notificationRepo.List(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Page{
+		Notifications: notifications,
+		UnreadCount:   unread,
+		Limit:         filter.Limit,
+		Offset:        filter.Offset,
+	}, nil
+}
+
+// UnreadCount returns the number of unread notifications of the user
+func (s *Service) UnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.notificationRepo.CountUnread(ctx, userID)
+}
+
+// MarkRead marks the given notifications of the user as read, or all of them
+// when no IDs are given. It returns how many notifications changed.
+func (s *Service) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	return s.notificationRepo.MarkRead(ctx, userID, ids, s.now().UTC())
+}
+
+// GetPreferences returns the user's notification preferences
+func (s *Service) GetPreferences(ctx context.Context, userID uuid.UUID) (*notification.Preferences, error) {
+	return s.preferenceRepo.GetPreferences(ctx, userID)
+}
+
+// PreferencesUpdate holds changes to a user's notification preferences.
+// Types, channels and settings that are left out keep their current value.
+type PreferencesUpdate struct {
+	Channels map[notification.Type]map[notification.Channel]bool
+	// Email sets the email channel of each type, as older clients do
+	Email      map[notification.Type]bool
+	Digest     *notification.DigestFrequency
+	Timezone   *string
+	QuietHours *QuietHoursUpdate
+}
+
+// QuietHoursUpdate sets quiet hours written as "15:04", or turns them off
+// when start and end are empty
+type QuietHoursUpdate struct {
+	Start string
+	End   string
+}
+
+// UpdatePreferences changes the user's notification preferences
+func (s *Service) UpdatePreferences(ctx context.Context, userID uuid.UUID, update PreferencesUpdate) (*notification.Preferences, error) {
+	prefs, err := s.preferenceRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for notificationType, enabled := range update.Email {
+		if err := prefs.Set(notificationType, notification.ChannelEmail, enabled); err != nil {
+			return nil, err
+		}
+	}
+	for notificationType, channels := range update.Channels {
+		for channel, enabled := range channels {
+			if err := prefs.Set(notificationType, channel, enabled); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if update.QuietHours != nil {
+		if err := prefs.SetQuietHours(update.QuietHours.Start, update.QuietHours.End); err != nil {
+			return nil, err
+		}
+	}
+
+	if update.Digest != nil || update.Timezone != nil {
+		digest, timezone := prefs.Digest, prefs.Timezone
+		if update.Digest != nil {
+			digest = *update.Digest
+		}
+		if update.Timezone != nil {
+			timezone = *update.Timezone
+		}
+		if err := prefs.SetDigest(digest, timezone, s.now().UTC()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.preferenceRepo.SavePreferences(ctx, prefs); err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+// SetChannelEnabled switches channels off for the whole account, types added
+// later included, or back on. Switching a channel back on restores the
+// per-type choices.
+func (s *Service) SetChannelEnabled(ctx context.Context, userID uuid.UUID, channels map[notification.Channel]bool) (*notification.Preferences, error) {
+	prefs, err := s.preferenceRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for channel, enabled := range channels {
+		if err := prefs.SetChannelEnabled(channel, enabled); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.preferenceRepo.SavePreferences(ctx, prefs); err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+// MuteTrip stops notifications about the trip reaching the user
+func (s *Service) MuteTrip(ctx context.Context, userID, tripID uuid.UUID) error {
+	if _, err := s.tripRepo.GetByID(ctx, tripID); err != nil {
+		return err
+	}
+	return s.preferenceRepo.MuteTrip(ctx, userID, tripID)
+}
+
+// UnmuteTrip lets notifications about the trip reach the user again
+func (s *Service) UnmuteTrip(ctx context.Context, userID, tripID uuid.UUID) error {
+	return s.preferenceRepo.UnmuteTrip(ctx, userID, tripID)
+}
+
+// notify stores a notification on the channels the recipient chose for its
+// type and announces it. Nothing is stored when the recipient wants none, or
+// muted the trip the notification is about; tripID is uuid.Nil otherwise.
+func (s *Service) notify(ctx context.Context, userID uuid.UUID, notificationType notification.Type, title, content string, entityType notification.EntityType, entityID, tripID uuid.UUID, data map[string]string) error {
+	prefs, err := s.preferenceRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+	channels := prefs.ChannelsFor(notificationType, tripID)
+	if len(channels) == 0 {
+		return nil
+	}
+
+	n, err := notification.NewNotification(userID, notificationType, title, content, entityType, entityID)
+	if err != nil {
+		return err
+	}
+	n.Channels = channels
+	for key, value := range data {
+		n.Data[key] = value
+	}
+
+	if err := s.notificationRepo.Create(ctx, n); err != nil {
+		return err
+	}
+
+	s.publisher.Publish(ctx, notification.Created{Notification: n})
+	return nil
+}
@@ -0,0 +1,269 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"jointrip/internal/domain/event"
+	"jointrip/internal/domain/notification"
+	"jointrip/internal/domain/push"
+	"jointrip/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// Delivery settings
+const (
+	// DeliveryBatchSize is how many queued messages one delivery run sends
+	DeliveryBatchSize = 100
+	// DeliveryLease is how long a claimed message stays hidden from other workers
+	DeliveryLease = 2 * time.Minute
+	// DeliveryRetention is how long finished deliveries are kept
+	DeliveryRetention = 24 * time.Hour
+)
+
+// Sender defines the interface for handing messages to push services
+type Sender interface {
+	// PublicKey returns the application server key browsers subscribe with
+	PublicKey() string
+
+	// Send delivers an encrypted payload to a subscription
+	Send(ctx context.Context, sub *push.Subscription, payload []byte, ttl time.Duration, urgency push.Urgency) error
+}
+
+// Payload is the message a service worker receives for a notification
+type Payload struct {
+	NotificationID uuid.UUID               `json:"notification_id"`
+	Type           notification.Type       `json:"type"`
+	Title          string                  `json:"title"`
+	Body           string                  `json:"body,omitempty"`
+	EntityType     notification.EntityType `json:"entity_type"`
+	EntityID       uuid.UUID               `json:"entity_id"`
+}
+
+// Service delivers notifications to users' devices through Web Push.
+// Messages are queued when a notification is created and sent by DeliverDue,
+// which retries failures and forgets subscriptions the push service reports
+// gone. Without a sender, push is disabled.
+type Service struct {
+	subscriptionRepo push.SubscriptionRepository
+	deliveryRepo     push.DeliveryRepository
+	userRepo         user.Repository
+	preferenceRepo   notification.PreferenceRepository
+	sender           Sender
+	now              func() time.Time
+}
+
+// NewService creates a new push service. sender may be nil when no VAPID keys
+// are configured.
+func NewService(
+	subscriptionRepo push.SubscriptionRepository,
+	deliveryRepo push.DeliveryRepository,
+	userRepo user.Repository,
+	preferenceRepo notification.PreferenceRepository,
+	sender Sender,
+) *Service {
+	return &Service{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+		userRepo:         userRepo,
+		preferenceRepo:   preferenceRepo,
+		sender:           sender,
+		now:              time.Now,
+	}
+}
+
+// PublicKey returns the VAPID public key browsers pass to PushManager.subscribe()
+func (s *Service) PublicKey() (string, error) {
+	if s.sender == nil {
+		return "", push.ErrPushNotConfigured
+	}
+	return s.sender.PublicKey(), nil
+}
+
+// Subscribe registers a device of the user. Registering a known endpoint
+// again refreshes its keys.
+func (s *Service) Subscribe(ctx context.Context, userID uuid.UUID, endpoint, p256dh, auth, userAgent string) (*push.Subscription, error) {
+	if s.sender == nil {
+		return nil, push.ErrPushNotConfigured
+	}
+
+	sub, err := push.NewSubscription(userID, endpoint, p256dh, auth, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.subscriptionRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	known := false
+	for _, e := range existing {
+		if e.Endpoint == endpoint {
+			known = true
+			break
+		}
+	}
+	if !known && len(existing) >= push.MaxSubscriptionsPerUser {
+		return nil, push.ErrTooManySubscriptions
+	}
+
+	if err := s.subscriptionRepo.Save(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// ListSubscriptions returns the user's registered devices
+func (s *Service) ListSubscriptions(ctx context.Context, userID uuid.UUID) ([]*push.Subscription, error) {
+	return s.subscriptionRepo.ListByUser(ctx, userID)
+}
+
+// Unsubscribe removes one of the user's devices
+func (s *Service) Unsubscribe(ctx context.Context, userID, subscriptionID uuid.UUID) error {
+	return s.subscriptionRepo.Delete(ctx, userID, subscriptionID)
+}
+
+// RegisterHandlers subscribes the service to new notifications
+func (s *Service) RegisterHandlers(subscriber event.Subscriber) {
+	subscriber.Subscribe(notification.EventCreated, s.onNotificationCreated)
+}
+
+// onNotificationCreated queues a notification delivered on the push channel
+// for each of the recipient's devices. During the recipient's quiet hours the
+// messages are held back until they end.
+func (s *Service) onNotificationCreated(ctx context.Context, e event.Event) error {
+	created, ok := e.(notification.Created)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", e)
+	}
+	n := created.Notification
+	if s.sender == nil || !n.HasChannel(notification.ChannelPush) {
+		return nil
+	}
+
+	recipient, err := s.userRepo.GetByID(ctx, n.UserID)
+	if err != nil {
+		return err
+	}
+	if !recipient.IsActive {
+		return nil
+	}
+
+	subs, err := s.subscriptionRepo.ListByUser(ctx, recipient.ID)
+	if err != nil || len(subs) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(Payload{
+		NotificationID: n.ID,
+		Type:           n.Type,
+		Title:          n.Title,
+		Body:           n.Content,
+		EntityType:     n.RelatedEntityType,
+		EntityID:       n.RelatedEntityID,
+	})
+	if err != nil {
+		return err
+	}
+
+	prefs, err := s.preferenceRepo.GetPreferences(ctx, recipient.ID)
+	if err != nil {
+		return err
+	}
+	quietUntil := prefs.QuietUntil(s.now().UTC())
+
+	var errs []error
+	for _, sub := range subs {
+		delivery, err := push.NewDelivery(sub.ID, payload, urgency(n.Type), push.DefaultTTL)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		delivery.HoldUntil(quietUntil)
+		errs = append(errs, s.deliveryRepo.Enqueue(ctx, delivery))
+	}
+
+	return errors.Join(errs...)
+}
+
+// DeliverDue sends the queued messages whose next attempt is due and returns
+// how many were delivered
+func (s *Service) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	if s.sender == nil {
+		return 0, nil
+	}
+
+	deliveries, err := s.deliveryRepo.ClaimDue(ctx, now, DeliveryBatchSize, DeliveryLease)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for _, delivery := range deliveries {
+		ok, err := s.deliver(ctx, delivery, now)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// deliver makes one delivery attempt and records its outcome
+func (s *Service) deliver(ctx context.Context, delivery *push.Delivery, now time.Time) (bool, error) {
+	sub, err := s.subscriptionRepo.GetByID(ctx, delivery.SubscriptionID)
+	if errors.Is(err, push.ErrSubscriptionNotFound) {
+		delivery.MarkFailed(now, err, false, 0)
+		return false, s.deliveryRepo.Update(ctx, delivery)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	err = s.sender.Send(ctx, sub, delivery.Payload, delivery.TTL(now), delivery.Urgency)
+
+	var deliveryErr *push.DeliveryError
+	switch {
+	case err == nil:
+		delivery.MarkSent()
+		if err := s.subscriptionRepo.Touch(ctx, sub.ID, now); err != nil {
+			return true, err
+		}
+	case errors.Is(err, push.ErrSubscriptionGone):
+		// The browser unsubscribed or the subscription expired; its queued
+		// messages go with it
+		return false, s.subscriptionRepo.DeleteByID(ctx, sub.ID)
+	case errors.As(err, &deliveryErr):
+		delivery.MarkFailed(now, err, deliveryErr.Retryable, deliveryErr.RetryAfter)
+	default:
+		delivery.MarkFailed(now, err, false, 0)
+	}
+
+	return delivery.Status == push.DeliveryStatusSent, s.deliveryRepo.Update(ctx, delivery)
+}
+
+// PruneDeliveries removes finished deliveries that are older than the retention
+func (s *Service) PruneDeliveries(ctx context.Context, now time.Time) error {
+	return s.deliveryRepo.DeleteFinishedBefore(ctx, now.Add(-DeliveryRetention))
+}
+
+// urgency returns how soon a notification type should reach the device
+func urgency(notificationType notification.Type) push.Urgency {
+	switch notificationType {
+	case notification.TypeNewMessage:
+		return push.UrgencyHigh
+	case notification.TypeNewRating:
+		return push.UrgencyLow
+	default:
+		return push.UrgencyNormal
+	}
+}
@@ -0,0 +1,102 @@
+package rating
+
+import (
+	"context"
+
+	"jointrip/internal/domain/event"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// Service provides user rating business logic
+type Service struct {
+	ratingRepo      user.RatingRepository
+	tripRepo        trip.Repository
+	participantRepo trip.ParticipantRepository
+	publisher       event.Publisher
+}
+
+// NewService creates a new rating service
+func NewService(
+	ratingRepo user.RatingRepository,
+	tripRepo trip.Repository,
+	participantRepo trip.ParticipantRepository,
+	publisher event.Publisher,
+) *Service {
+	return &Service{
+		ratingRepo:      ratingRepo,
+		tripRepo:        tripRepo,
+		participantRepo: participantRepo,
+		publisher:       publisher,
+	}
+}
+
+// RateUser stores a user's rating of a fellow traveler and lets the rated
+// user know about it. Both users must be members of the trip.
+func (s *Service) RateUser(ctx context.Context, raterID, ratedUserID, tripID uuid.UUID, score int, review string) (*user.Rating, error) {
+	rating, err := user.NewRating(raterID, ratedUserID, tripID, score, review)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCompanions(ctx, tripID, raterID, ratedUserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.ratingRepo.Create(ctx, rating); err != nil {
+		return nil, err
+	}
+
+	s.publisher.Publish(ctx, user.RatingReceived{
+		RaterID:     rating.RaterID,
+		RatedUserID: rating.RatedUserID,
+		TripID:      rating.TripID,
+		Rating:      rating.Rating,
+		OccurredAt:  rating.CreatedAt,
+	})
+
+	return rating, nil
+}
+
+// ListReceived returns the ratings a user received, newest first
+func (s *Service) ListReceived(ctx context.Context, userID uuid.UUID) ([]*user.Rating, error) {
+	return s.ratingRepo.ListByRatedUser(ctx, userID)
+}
+
+// ListGiven returns the ratings a user gave, newest first
+func (s *Service) ListGiven(ctx context.Context, userID uuid.UUID) ([]*user.Rating, error) {
+	return s.ratingRepo.ListByRater(ctx, userID)
+}
+
+// checkCompanions checks that the rater and the rated user are both the
+// organizer or approved participants of the trip. Trips the rater cannot
+// see are reported as not found.
+func (s *Service) checkCompanions(ctx context.Context, tripID, raterID, ratedUserID uuid.UUID) error {
+	t, err := s.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return err
+	}
+
+	raterAccess, err := trip.CheckAccess(ctx, s.participantRepo, t, raterID)
+	if err != nil {
+		return err
+	}
+	if !raterAccess.Visible {
+		return trip.ErrTripNotFound
+	}
+	if !raterAccess.Member {
+		return user.ErrNotTripCompanion
+	}
+
+	ratedAccess, err := trip.CheckAccess(ctx, s.participantRepo, t, ratedUserID)
+	if err != nil {
+		return err
+	}
+	if !ratedAccess.Member {
+		return user.ErrNotTripCompanion
+	}
+
+	return nil
+}
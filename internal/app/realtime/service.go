@@ -0,0 +1,119 @@
+package realtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"jointrip/internal/domain/event"
+	"jointrip/internal/domain/messaging"
+	"jointrip/internal/domain/notification"
+	"jointrip/internal/domain/realtime"
+
+	"github.com/google/uuid"
+)
+
+// EventLogRetention is how long events can be replayed to reconnecting clients
+const EventLogRetention = 24 * time.Hour
+
+// TypingPayload is the data of a typing event
+type TypingPayload struct {
+	ConversationID uuid.UUID `json:"conversation_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	At             time.Time `json:"at"`
+}
+
+// Service turns domain events into real-time updates for connected clients
+type Service struct {
+	broker   realtime.Broker
+	eventLog realtime.EventLog
+}
+
+// NewService creates a new real-time service
+func NewService(broker realtime.Broker, eventLog realtime.EventLog) *Service {
+	return &Service{
+		broker:   broker,
+		eventLog: eventLog,
+	}
+}
+
+// RegisterHandlers subscribes the service to the domain events that are pushed
+// to clients
+func (s *Service) RegisterHandlers(subscriber event.Subscriber) {
+	subscriber.Subscribe(messaging.EventMessagePosted, s.onMessagePosted)
+	subscriber.Subscribe(messaging.EventTyping, s.onTyping)
+	subscriber.Subscribe(notification.EventCreated, s.onNotificationCreated)
+}
+
+// Push sends an event to every connected client of the given users
+func (s *Service) Push(ctx context.Context, userIDs []uuid.UUID, eventType string, data any) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	e, err := realtime.NewEvent(eventType, data)
+	if err != nil {
+		return err
+	}
+
+	delivery := realtime.Delivery{UserIDs: userIDs, Event: e}
+	if e.IsReplayable() {
+		if err := s.eventLog.Append(ctx, &delivery); err != nil {
+			return err
+		}
+	}
+
+	return s.broker.Publish(ctx, delivery)
+}
+
+// EventsSince returns up to limit of the user's replayable events logged after
+// the given event ID, oldest first
+func (s *Service) EventsSince(ctx context.Context, userID uuid.UUID, afterID int64, limit int) ([]realtime.Event, error) {
+	if afterID < 0 {
+		return nil, realtime.ErrInvalidLastEventID
+	}
+	return s.eventLog.ListSince(ctx, userID, afterID, limit)
+}
+
+// PruneEventLog drops events that are too old to be replayed
+func (s *Service) PruneEventLog(ctx context.Context) error {
+	return s.eventLog.DeleteBefore(ctx, time.Now().Add(-EventLogRetention))
+}
+
+// onMessagePosted pushes a new message to the conversation's members
+func (s *Service) onMessagePosted(ctx context.Context, e event.Event) error {
+	posted, ok := e.(messaging.MessagePosted)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", e)
+	}
+
+	return s.Push(ctx, posted.RecipientIDs, realtime.TypeMessageCreated, posted.Message)
+}
+
+// onTyping pushes a typing indicator to the other members of a conversation
+func (s *Service) onTyping(ctx context.Context, e event.Event) error {
+	typing, ok := e.(messaging.Typing)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", e)
+	}
+
+	return s.Push(ctx, typing.RecipientIDs, realtime.TypeTyping, TypingPayload{
+		ConversationID: typing.ConversationID,
+		UserID:         typing.UserID,
+		At:             typing.OccurredAt,
+	})
+}
+
+// onNotificationCreated pushes a new in-app notification to its recipient
+func (s *Service) onNotificationCreated(ctx context.Context, e event.Event) error {
+	created, ok := e.(notification.Created)
+	if !ok {
+		return fmt.Errorf("unexpected event %T", e)
+	}
+
+	n := created.Notification
+	if !n.HasChannel(notification.ChannelInApp) {
+		return nil
+	}
+	return s.Push(ctx, []uuid.UUID{n.UserID}, realtime.TypeNotificationCreated, n)
+}
@@ -0,0 +1,196 @@
+package tag
+
+import (
+	"context"
+
+	"jointrip/internal/domain/tag"
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+)
+
+// Popular tag listing limits
+const (
+	DefaultPopularLimit = 10
+	MaxPopularLimit     = 50
+)
+
+// TagInput holds the editable fields of a tag
+type TagInput struct {
+	Name        string
+	Category    tag.Category
+	Description string
+	Color       string
+}
+
+// Service provides trip tag business logic
+type Service struct {
+	tagRepo  tag.Repository
+	tripRepo trip.Repository
+}
+
+// NewService creates a new tag service
+func NewService(tagRepo tag.Repository, tripRepo trip.Repository) *Service {
+	return &Service{
+		tagRepo:  tagRepo,
+		tripRepo: tripRepo,
+	}
+}
+
+// CreateTag adds a tag to the curated taxonomy
+func (s *Service) CreateTag(ctx context.Context, input TagInput) (*tag.Tag, error) {
+	t, err := tag.NewTag(input.Name, input.Category, input.Description, input.Color)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tagRepo.Create(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// UpdateTag changes a tag in the curated taxonomy
+func (s *Service) UpdateTag(ctx context.Context, tagID uuid.UUID, input TagInput) (*tag.Tag, error) {
+	t, err := s.tagRepo.GetByID(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.Update(input.Name, input.Category, input.Description, input.Color); err != nil {
+		return nil, err
+	}
+
+	if err := s.tagRepo.Update(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// SetTagActive activates or retires a tag
+func (s *Service) SetTagActive(ctx context.Context, tagID uuid.UUID, active bool) (*tag.Tag, error) {
+	t, err := s.tagRepo.GetByID(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	if active {
+		t.Activate()
+	} else {
+		t.Deactivate()
+	}
+
+	if err := s.tagRepo.Update(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// ListTags returns the taxonomy, optionally narrowed to one category
+func (s *Service) ListTags(ctx context.Context, filter tag.ListFilter) ([]*tag.Tag, error) {
+	if filter.Category != nil && !filter.Category.IsValid() {
+		return nil, tag.ErrInvalidCategory
+	}
+
+	return s.tagRepo.List(ctx, filter)
+}
+
+// ListPopularTags returns the most used tags with their usage counts
+func (s *Service) ListPopularTags(ctx context.Context, limit int) ([]*tag.Usage, error) {
+	if limit <= 0 {
+		limit = DefaultPopularLimit
+	}
+	if limit > MaxPopularLimit {
+		limit = MaxPopularLimit
+	}
+
+	return s.tagRepo.ListPopular(ctx, limit)
+}
+
+// GetTripTags returns the tags attached to a trip
+func (s *Service) GetTripTags(ctx context.Context, tripID uuid.UUID) ([]*tag.Tag, error) {
+	if _, err := s.tripRepo.GetByID(ctx, tripID); err != nil {
+		return nil, err
+	}
+
+	return s.tagRepo.ListByTrip(ctx, tripID)
+}
+
+// SetTripTags replaces the tags of a trip on behalf of its creator
+func (s *Service) SetTripTags(ctx context.Context, tripID, userID uuid.UUID, tagIDs []uuid.UUID) ([]*tag.Tag, error) {
+	if err := s.requireOrganizer(ctx, tripID, userID); err != nil {
+		return nil, err
+	}
+
+	tagIDs = uniqueIDs(tagIDs)
+	if len(tagIDs) > tag.MaxTagsPerTrip {
+		return nil, tag.ErrTooManyTags
+	}
+
+	tags := []*tag.Tag{}
+	if len(tagIDs) > 0 {
+		found, err := s.tagRepo.GetByIDs(ctx, tagIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) != len(tagIDs) {
+			return nil, tag.ErrTagNotFound
+		}
+		for _, t := range found {
+			if !t.IsActive {
+				return nil, tag.ErrTagInactive
+			}
+		}
+		tags = found
+	}
+
+	assignments := make([]*tag.Assignment, 0, len(tags))
+	for _, t := range tags {
+		assignments = append(assignments, tag.NewAssignment(tripID, t.ID, userID))
+	}
+
+	if err := s.tagRepo.ReplaceTripTags(ctx, tripID, assignments); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// RemoveTripTag detaches a single tag from a trip on behalf of its creator
+func (s *Service) RemoveTripTag(ctx context.Context, tripID, userID, tagID uuid.UUID) error {
+	if err := s.requireOrganizer(ctx, tripID, userID); err != nil {
+		return err
+	}
+
+	return s.tagRepo.RemoveFromTrip(ctx, tripID, tagID)
+}
+
+// requireOrganizer checks that the user created the trip
+func (s *Service) requireOrganizer(ctx context.Context, tripID, userID uuid.UUID) error {
+	t, err := s.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return err
+	}
+
+	if !t.IsOrganizer(userID) {
+		return trip.ErrNotOrganizer
+	}
+
+	return nil
+}
+
+// uniqueIDs removes duplicate IDs while preserving order
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,212 @@
+package template
+
+import (
+	"context"
+	"time"
+
+	"jointrip/internal/domain/tag"
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+)
+
+// Public template listing limits
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// Service provides trip cloning and template business logic
+type Service struct {
+	tripRepo      trip.Repository
+	itineraryRepo trip.ItineraryRepository
+	templateRepo  trip.TemplateRepository
+	tagRepo       tag.Repository
+}
+
+// NewService creates a new template service
+func NewService(
+	tripRepo trip.Repository,
+	itineraryRepo trip.ItineraryRepository,
+	templateRepo trip.TemplateRepository,
+	tagRepo tag.Repository,
+) *Service {
+	return &Service{
+		tripRepo:      tripRepo,
+		itineraryRepo: itineraryRepo,
+		templateRepo:  templateRepo,
+		tagRepo:       tagRepo,
+	}
+}
+
+// CloneTrip copies a trip's details, tags and itinerary into a new draft trip
+// whose dates are shifted by the given number of days
+func (s *Service) CloneTrip(ctx context.Context, tripID, organizerID uuid.UUID, offsetDays int) (*trip.Trip, error) {
+	snapshot, source, err := s.snapshotTrip(ctx, tripID, organizerID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.instantiate(ctx, snapshot, organizerID, source.StartDate.AddDate(0, 0, offsetDays))
+}
+
+// SaveTemplate saves a trip as a private template owned by its organizer
+func (s *Service) SaveTemplate(ctx context.Context, tripID, organizerID uuid.UUID, name string) (*trip.Template, error) {
+	tpl, _, err := s.snapshotTrip(ctx, tripID, organizerID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Create(ctx, tpl); err != nil {
+		return nil, err
+	}
+
+	return tpl, nil
+}
+
+// GetTemplate returns a template visible to the user
+func (s *Service) GetTemplate(ctx context.Context, templateID, userID uuid.UUID) (*trip.Template, error) {
+	tpl, err := s.templateRepo.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !tpl.IsVisibleTo(userID) {
+		return nil, trip.ErrTemplateNotFound
+	}
+
+	return tpl, nil
+}
+
+// ListPublicTemplates returns published templates
+func (s *Service) ListPublicTemplates(ctx context.Context, limit, offset int) ([]*trip.Template, error) {
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return s.templateRepo.ListPublic(ctx, limit, offset)
+}
+
+// ListMyTemplates returns the user's own templates
+func (s *Service) ListMyTemplates(ctx context.Context, userID uuid.UUID) ([]*trip.Template, error) {
+	return s.templateRepo.ListByOwner(ctx, userID)
+}
+
+// SetTemplatePublic publishes or unpublishes a template on behalf of its owner
+func (s *Service) SetTemplatePublic(ctx context.Context, templateID, userID uuid.UUID, public bool) (*trip.Template, error) {
+	tpl, err := s.getOwnedTemplate(ctx, templateID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tpl.SetPublic(public)
+
+	if err := s.templateRepo.Update(ctx, tpl); err != nil {
+		return nil, err
+	}
+
+	return tpl, nil
+}
+
+// DeleteTemplate removes a template on behalf of its owner
+func (s *Service) DeleteTemplate(ctx context.Context, templateID, userID uuid.UUID) error {
+	if _, err := s.getOwnedTemplate(ctx, templateID, userID); err != nil {
+		return err
+	}
+
+	return s.templateRepo.Delete(ctx, templateID)
+}
+
+// CreateTripFromTemplate starts a new draft trip from a template visible to the user
+func (s *Service) CreateTripFromTemplate(ctx context.Context, templateID, userID uuid.UUID, startDate time.Time) (*trip.Trip, error) {
+	tpl, err := s.GetTemplate(ctx, templateID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.instantiate(ctx, tpl, userID, startDate)
+}
+
+// snapshotTrip builds an unsaved template from a trip on behalf of its organizer
+func (s *Service) snapshotTrip(ctx context.Context, tripID, organizerID uuid.UUID, name string) (*trip.Template, *trip.Trip, error) {
+	source, err := s.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !source.IsOrganizer(organizerID) {
+		return nil, nil, trip.ErrNotOrganizer
+	}
+
+	tags, err := s.tagRepo.ListByTrip(ctx, tripID)
+	if err != nil {
+		return nil, nil, err
+	}
+	tagIDs := make([]uuid.UUID, 0, len(tags))
+	for _, t := range tags {
+		tagIDs = append(tagIDs, t.ID)
+	}
+
+	items, err := s.itineraryRepo.ListByTrip(ctx, tripID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tpl, err := trip.NewTemplateFromTrip(organizerID, name, source, tagIDs, items)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tpl, source, nil
+}
+
+// instantiate creates a draft trip from a template together with its itinerary
+// and still-active tags, so that a failure leaves no partial trip behind
+func (s *Service) instantiate(ctx context.Context, tpl *trip.Template, creatorID uuid.UUID, startDate time.Time) (*trip.Trip, error) {
+	t, items, err := tpl.Instantiate(creatorID, startDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var tagIDs []uuid.UUID
+	if len(tpl.TagIDs) > 0 {
+		tags, err := s.tagRepo.GetByIDs(ctx, tpl.TagIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, tg := range tags {
+			if tg.IsActive {
+				tagIDs = append(tagIDs, tg.ID)
+			}
+		}
+	}
+
+	if err := s.tripRepo.CreateWithItinerary(ctx, t, trip.NewCreatorParticipant(t.ID, creatorID), items, tagIDs); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// getOwnedTemplate loads a template and checks that the user owns it
+func (s *Service) getOwnedTemplate(ctx context.Context, templateID, userID uuid.UUID) (*trip.Template, error) {
+	tpl, err := s.templateRepo.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !tpl.IsOwner(userID) {
+		if !tpl.IsPublic {
+			return nil, trip.ErrTemplateNotFound
+		}
+		return nil, trip.ErrNotTemplateOwner
+	}
+
+	return tpl, nil
+}
@@ -0,0 +1,273 @@
+package trip
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+)
+
+// CreateInviteLinkInput holds the options of a new invite link
+type CreateInviteLinkInput struct {
+	TTL         time.Duration
+	MaxUses     *int
+	PreApproved bool
+}
+
+// IssuedInviteLink is an invite link together with its signed token
+type IssuedInviteLink struct {
+	*trip.InviteLink
+	Token string `json:"token"`
+}
+
+// InvitePreview describes the trip behind an invite link
+type InvitePreview struct {
+	Trip        *trip.Trip `json:"trip"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	PreApproved bool       `json:"pre_approved"`
+}
+
+// CreateInviteLink issues a new invite link for a trip on behalf of its organizer
+func (s *Service) CreateInviteLink(ctx context.Context, tripID, organizerID uuid.UUID, input CreateInviteLinkInput) (*IssuedInviteLink, error) {
+	if _, err := s.getOrganizedTrip(ctx, tripID, organizerID); err != nil {
+		return nil, err
+	}
+
+	link, err := trip.NewInviteLink(tripID, organizerID, input.TTL, input.MaxUses, input.PreApproved, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.inviteRepo.CreateLink(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return &IssuedInviteLink{InviteLink: link, Token: s.inviteSigner.Sign(link.ID)}, nil
+}
+
+// ListInviteLinks returns a trip's invite links on behalf of its organizer
+func (s *Service) ListInviteLinks(ctx context.Context, tripID, organizerID uuid.UUID) ([]*IssuedInviteLink, error) {
+	if _, err := s.getOrganizedTrip(ctx, tripID, organizerID); err != nil {
+		return nil, err
+	}
+
+	links, err := s.inviteRepo.ListLinksByTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	issued := make([]*IssuedInviteLink, 0, len(links))
+	for _, link := range links {
+		issued = append(issued, &IssuedInviteLink{InviteLink: link, Token: s.inviteSigner.Sign(link.ID)})
+	}
+
+	return issued, nil
+}
+
+// RevokeInviteLink disables an invite link on behalf of the trip organizer
+func (s *Service) RevokeInviteLink(ctx context.Context, tripID, organizerID, linkID uuid.UUID) error {
+	if _, err := s.getOrganizedTrip(ctx, tripID, organizerID); err != nil {
+		return err
+	}
+
+	link, err := s.inviteRepo.GetLink(ctx, linkID)
+	if err != nil {
+		return err
+	}
+	if link.TripID != tripID {
+		return trip.ErrInviteLinkNotFound
+	}
+
+	link.Revoke(s.now())
+	return s.inviteRepo.UpdateLink(ctx, link)
+}
+
+// PreviewInvite returns the trip an invite link leads to without redeeming it
+func (s *Service) PreviewInvite(ctx context.Context, token string) (*InvitePreview, error) {
+	link, err := s.resolveInviteLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := s.tripRepo.GetByID(ctx, link.TripID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InvitePreview{Trip: t, ExpiresAt: link.ExpiresAt, PreApproved: link.PreApproved}, nil
+}
+
+// RedeemInvite joins the trip behind an invite link through the regular participant workflow
+func (s *Service) RedeemInvite(ctx context.Context, token string, userID uuid.UUID, notes string) (*JoinResult, error) {
+	link, err := s.resolveInviteLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := s.tripRepo.GetByID(ctx, link.TripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.inviteRepo.ClaimLinkUse(ctx, link.ID, s.now()); err != nil {
+		return nil, err
+	}
+
+	result, err := s.join(ctx, t, userID, notes, link.PreApproved)
+	if err != nil {
+		if releaseErr := s.inviteRepo.ReleaseLinkUse(ctx, link.ID); releaseErr != nil {
+			return nil, errors.Join(err, releaseErr)
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// InviteUser sends a direct invitation on behalf of the trip organizer
+func (s *Service) InviteUser(ctx context.Context, tripID, organizerID, inviteeID uuid.UUID, message string, preApproved bool) (*trip.Invitation, error) {
+	t, err := s.getOrganizedTrip(ctx, tripID, organizerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !t.IsJoinable() {
+		return nil, trip.ErrTripNotJoinable
+	}
+
+	existing, err := s.participantRepo.GetByTripAndUser(ctx, tripID, inviteeID)
+	if err != nil && !errors.Is(err, trip.ErrParticipantNotFound) {
+		return nil, err
+	}
+	if existing != nil && existing.IsActive() {
+		return nil, trip.ErrAlreadyParticipant
+	}
+
+	invitation, err := trip.NewInvitation(tripID, organizerID, inviteeID, message, preApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.inviteRepo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// ListTripInvitations returns a trip's direct invitations on behalf of its organizer
+func (s *Service) ListTripInvitations(ctx context.Context, tripID, organizerID uuid.UUID) ([]*trip.Invitation, error) {
+	if _, err := s.getOrganizedTrip(ctx, tripID, organizerID); err != nil {
+		return nil, err
+	}
+
+	return s.inviteRepo.ListInvitationsByTrip(ctx, tripID)
+}
+
+// CancelInvitation withdraws a pending invitation on behalf of the trip organizer
+func (s *Service) CancelInvitation(ctx context.Context, tripID, organizerID, invitationID uuid.UUID) error {
+	if _, err := s.getOrganizedTrip(ctx, tripID, organizerID); err != nil {
+		return err
+	}
+
+	invitation, err := s.inviteRepo.GetInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.TripID != tripID {
+		return trip.ErrInvitationNotFound
+	}
+
+	if err := invitation.Cancel(s.now()); err != nil {
+		return err
+	}
+
+	return s.inviteRepo.UpdateInvitation(ctx, invitation)
+}
+
+// ListMyInvitations returns the user's pending invitations
+func (s *Service) ListMyInvitations(ctx context.Context, userID uuid.UUID) ([]*trip.Invitation, error) {
+	return s.inviteRepo.ListPendingInvitationsByInvitee(ctx, userID)
+}
+
+// AcceptInvitation joins the invited trip through the regular participant workflow
+func (s *Service) AcceptInvitation(ctx context.Context, invitationID, userID uuid.UUID, notes string) (*JoinResult, error) {
+	invitation, err := s.getReceivedInvitation(ctx, invitationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !invitation.IsPending() {
+		return nil, trip.ErrInvalidInvitationState
+	}
+
+	t, err := s.tripRepo.GetByID(ctx, invitation.TripID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.join(ctx, t, userID, notes, invitation.PreApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := invitation.Accept(s.now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.inviteRepo.UpdateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeclineInvitation turns down a pending invitation
+func (s *Service) DeclineInvitation(ctx context.Context, invitationID, userID uuid.UUID) error {
+	invitation, err := s.getReceivedInvitation(ctx, invitationID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := invitation.Decline(s.now()); err != nil {
+		return err
+	}
+
+	return s.inviteRepo.UpdateInvitation(ctx, invitation)
+}
+
+// resolveInviteLink verifies a token and loads its link if it can still be used
+func (s *Service) resolveInviteLink(ctx context.Context, token string) (*trip.InviteLink, error) {
+	linkID, err := s.inviteSigner.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.inviteRepo.GetLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := link.CheckUsable(s.now()); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// getReceivedInvitation loads an invitation addressed to the user
+func (s *Service) getReceivedInvitation(ctx context.Context, invitationID, userID uuid.UUID) (*trip.Invitation, error) {
+	invitation, err := s.inviteRepo.GetInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invitation.InviteeID != userID {
+		return nil, trip.ErrInvitationNotFound
+	}
+
+	return invitation, nil
+}
@@ -0,0 +1,120 @@
+package trip
+
+import (
+	"context"
+	"time"
+
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+)
+
+// ItineraryItemInput holds the editable fields of an itinerary item
+type ItineraryItemInput struct {
+	Title       string
+	Description string
+	Location    string
+	StartsAt    time.Time
+	EndsAt      *time.Time
+}
+
+// ListItinerary returns the itinerary of a trip the user can see, in
+// chronological order
+func (s *Service) ListItinerary(ctx context.Context, tripID, userID uuid.UUID) ([]*trip.ItineraryItem, error) {
+	if _, err := s.getVisibleTrip(ctx, tripID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.itineraryRepo.ListByTrip(ctx, tripID)
+}
+
+// AddItineraryItem adds an item to a trip's itinerary on behalf of its organizer
+func (s *Service) AddItineraryItem(ctx context.Context, tripID, organizerID uuid.UUID, input ItineraryItemInput) (*trip.ItineraryItem, error) {
+	t, err := s.getOrganizedTrip(ctx, tripID, organizerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !t.Covers(input.StartsAt) {
+		return nil, trip.ErrItemOutsideTrip
+	}
+
+	item, err := trip.NewItineraryItem(tripID, organizerID, input.Title, input.Description, input.Location, input.StartsAt, input.EndsAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.itineraryRepo.Create(ctx, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// UpdateItineraryItem changes an itinerary item on behalf of the trip organizer
+func (s *Service) UpdateItineraryItem(ctx context.Context, tripID, organizerID, itemID uuid.UUID, input ItineraryItemInput) (*trip.ItineraryItem, error) {
+	t, err := s.getOrganizedTrip(ctx, tripID, organizerID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.getTripItineraryItem(ctx, tripID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !t.Covers(input.StartsAt) {
+		return nil, trip.ErrItemOutsideTrip
+	}
+
+	if err := item.Update(input.Title, input.Description, input.Location, input.StartsAt, input.EndsAt); err != nil {
+		return nil, err
+	}
+
+	if err := s.itineraryRepo.Update(ctx, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// DeleteItineraryItem removes an itinerary item on behalf of the trip organizer
+func (s *Service) DeleteItineraryItem(ctx context.Context, tripID, organizerID, itemID uuid.UUID) error {
+	if _, err := s.getOrganizedTrip(ctx, tripID, organizerID); err != nil {
+		return err
+	}
+
+	if _, err := s.getTripItineraryItem(ctx, tripID, itemID); err != nil {
+		return err
+	}
+
+	return s.itineraryRepo.Delete(ctx, itemID)
+}
+
+// getOrganizedTrip loads a trip and checks that the user organizes it
+func (s *Service) getOrganizedTrip(ctx context.Context, tripID, organizerID uuid.UUID) (*trip.Trip, error) {
+	t, err := s.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !t.IsOrganizer(organizerID) {
+		return nil, trip.ErrNotOrganizer
+	}
+
+	return t, nil
+}
+
+// getTripItineraryItem loads an itinerary item and checks that it belongs to the trip
+func (s *Service) getTripItineraryItem(ctx context.Context, tripID, itemID uuid.UUID) (*trip.ItineraryItem, error) {
+	item, err := s.itineraryRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.TripID != tripID {
+		return nil, trip.ErrItineraryItemNotFound
+	}
+
+	return item, nil
+}
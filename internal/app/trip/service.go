@@ -0,0 +1,577 @@
+package trip
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"jointrip/internal/domain/currency"
+	"jointrip/internal/domain/event"
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+)
+
+// CreateTripInput holds the data needed to publish a trip
+type CreateTripInput struct {
+	Title              string
+	Description        string
+	DestinationCountry string
+	DestinationCity    string
+	StartDate          time.Time
+	EndDate            time.Time
+	MaxParticipants    int
+	EstimatedBudget    float64
+	Currency           string
+	TripType           string
+	IsPublic           bool
+	Draft              bool
+}
+
+// UpdateTripInput holds the editable details of a trip
+type UpdateTripInput struct {
+	Title              string
+	Description        string
+	DestinationCountry string
+	DestinationCity    string
+	StartDate          time.Time
+	EndDate            time.Time
+	EstimatedBudget    float64
+	TripType           string
+	IsPublic           bool
+}
+
+// JoinResult describes the outcome of a join request: either a pending
+// participation or, when the trip is full, a place on the waitlist
+type JoinResult struct {
+	Participant   *trip.Participant   `json:"participant,omitempty"`
+	WaitlistEntry *trip.WaitlistEntry `json:"waitlist_entry,omitempty"`
+}
+
+// Waitlisted returns true if the join request was queued on the waitlist
+func (r *JoinResult) Waitlisted() bool {
+	return r.WaitlistEntry != nil
+}
+
+// InviteSigner defines the interface for signing invite link tokens
+type InviteSigner interface {
+	Sign(linkID uuid.UUID) string
+	Verify(token string) (uuid.UUID, error)
+}
+
+// Service provides trip participation business logic
+type Service struct {
+	tripRepo        trip.Repository
+	participantRepo trip.ParticipantRepository
+	waitlistRepo    trip.WaitlistRepository
+	itineraryRepo   trip.ItineraryRepository
+	inviteRepo      trip.InviteRepository
+	inviteSigner    InviteSigner
+	publisher       event.Publisher
+	offerWindow     time.Duration
+	now             func() time.Time
+}
+
+// NewService creates a new trip service
+func NewService(
+	tripRepo trip.Repository,
+	participantRepo trip.ParticipantRepository,
+	waitlistRepo trip.WaitlistRepository,
+	itineraryRepo trip.ItineraryRepository,
+	inviteRepo trip.InviteRepository,
+	inviteSigner InviteSigner,
+	publisher event.Publisher,
+	offerWindow time.Duration,
+) *Service {
+	return &Service{
+		tripRepo:        tripRepo,
+		participantRepo: participantRepo,
+		waitlistRepo:    waitlistRepo,
+		itineraryRepo:   itineraryRepo,
+		inviteRepo:      inviteRepo,
+		inviteSigner:    inviteSigner,
+		publisher:       publisher,
+		offerWindow:     offerWindow,
+		now:             time.Now,
+	}
+}
+
+// CreateTrip publishes a new trip organized by the given user
+func (s *Service) CreateTrip(ctx context.Context, creatorID uuid.UUID, input CreateTripInput) (*trip.Trip, error) {
+	t, err := trip.NewTrip(
+		creatorID,
+		input.Title,
+		input.DestinationCountry,
+		input.DestinationCity,
+		input.StartDate,
+		input.EndDate,
+		input.MaxParticipants,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Description = input.Description
+	t.EstimatedBudget = input.EstimatedBudget
+	t.TripType = input.TripType
+	t.IsPublic = input.IsPublic
+	if input.Draft {
+		t.Status = trip.StatusDraft
+	}
+	if input.Currency != "" {
+		t.Currency, err = currency.Normalize(input.Currency)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.tripRepo.Create(ctx, t, trip.NewCreatorParticipant(t.ID, creatorID)); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// GetTrip retrieves a trip the user can see: any published public trip, and
+// private trips and drafts only for their organizer and approved participants
+func (s *Service) GetTrip(ctx context.Context, tripID, userID uuid.UUID) (*trip.Trip, error) {
+	return s.getVisibleTrip(ctx, tripID, userID)
+}
+
+// UpdateTrip changes a trip's details on behalf of its organizer
+func (s *Service) UpdateTrip(ctx context.Context, tripID, organizerID uuid.UUID, input UpdateTripInput) (*trip.Trip, error) {
+	t, err := s.getOrganizedTrip(ctx, tripID, organizerID)
+	if err != nil {
+		return nil, err
+	}
+
+	datesChanged, err := t.UpdateDetails(
+		input.Title,
+		input.Description,
+		input.DestinationCountry,
+		input.DestinationCity,
+		input.StartDate,
+		input.EndDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	t.EstimatedBudget = input.EstimatedBudget
+	t.TripType = input.TripType
+	t.IsPublic = input.IsPublic
+
+	if err := s.tripRepo.Update(ctx, t); err != nil {
+		return nil, err
+	}
+
+	s.publisher.Publish(ctx, trip.TripUpdated{
+		TripID:       t.ID,
+		ActorID:      organizerID,
+		DatesChanged: datesChanged,
+		OccurredAt:   s.now(),
+	})
+
+	return t, nil
+}
+
+// PublishTrip makes a draft trip visible in search and open for join requests
+func (s *Service) PublishTrip(ctx context.Context, tripID, organizerID uuid.UUID) (*trip.Trip, error) {
+	t, err := s.getOrganizedTrip(ctx, tripID, organizerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.Publish(); err != nil {
+		return nil, err
+	}
+
+	if err := s.tripRepo.Update(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// SearchTrips returns public trips matching the filter
+func (s *Service) SearchTrips(ctx context.Context, filter trip.SearchFilter) ([]*trip.Trip, error) {
+	filter.Normalize()
+	return s.tripRepo.Search(ctx, filter)
+}
+
+// ListParticipants returns every participation record of a trip
+func (s *Service) ListParticipants(ctx context.Context, tripID, userID uuid.UUID) ([]*trip.Participant, error) {
+	if _, err := s.getVisibleTrip(ctx, tripID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.participantRepo.ListByTrip(ctx, tripID)
+}
+
+// RequestJoin asks to join a trip. When the trip is full, or other users are
+// already queued for a seat, the request is placed on the waitlist instead.
+// Private trips can only be joined through an invitation.
+func (s *Service) RequestJoin(ctx context.Context, tripID, userID uuid.UUID, notes string) (*JoinResult, error) {
+	t, err := s.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !t.IsPublic {
+		return nil, trip.ErrInviteRequired
+	}
+
+	return s.join(ctx, t, userID, notes, false)
+}
+
+// join runs the participant workflow shared by public join requests and invitations.
+// Pre-approved joins take a seat right away, subject to the same capacity checks
+// as an organizer approval; if the seat is gone they stay a pending request.
+func (s *Service) join(ctx context.Context, t *trip.Trip, userID uuid.UUID, notes string, preApproved bool) (*JoinResult, error) {
+	if !t.IsJoinable() {
+		return nil, trip.ErrTripNotJoinable
+	}
+
+	existing, err := s.participantRepo.GetByTripAndUser(ctx, t.ID, userID)
+	if err != nil && !errors.Is(err, trip.ErrParticipantNotFound) {
+		return nil, err
+	}
+	if existing != nil && existing.IsActive() {
+		return nil, trip.ErrAlreadyParticipant
+	}
+
+	queued, err := s.waitlistRepo.CountActiveByTrip(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.IsFull() || queued > 0 {
+		entry, err := trip.NewWaitlistEntry(t.ID, userID, notes)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.waitlistRepo.Create(ctx, entry); err != nil {
+			return nil, err
+		}
+
+		return &JoinResult{WaitlistEntry: entry}, nil
+	}
+
+	participant := existing
+	if participant != nil {
+		if err := participant.Rejoin(notes); err != nil {
+			return nil, err
+		}
+		if err := s.participantRepo.Update(ctx, participant); err != nil {
+			return nil, err
+		}
+	} else {
+		participant, err = trip.NewJoinRequest(t.ID, userID, notes)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.participantRepo.Create(ctx, participant); err != nil {
+			return nil, err
+		}
+	}
+
+	if preApproved {
+		if err := participant.Approve(); err != nil {
+			return nil, err
+		}
+		err := s.participantRepo.Approve(ctx, participant)
+		if errors.Is(err, trip.ErrTripFull) {
+			// Someone took the last seat first; leave the request for the organizer
+			participant.Status = trip.ParticipantStatusRequested
+			participant.JoinDate = nil
+			s.publishRequested(ctx, participant)
+			return &JoinResult{Participant: participant}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.publishJoined(ctx, participant)
+	} else {
+		s.publishRequested(ctx, participant)
+	}
+
+	return &JoinResult{Participant: participant}, nil
+}
+
+// ApproveParticipant approves a pending join request if a seat is free
+func (s *Service) ApproveParticipant(ctx context.Context, tripID, organizerID, participantID uuid.UUID) (*trip.Participant, error) {
+	participant, err := s.getManagedParticipant(ctx, tripID, organizerID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := participant.Approve(); err != nil {
+		return nil, err
+	}
+
+	if err := s.participantRepo.Approve(ctx, participant); err != nil {
+		return nil, err
+	}
+
+	s.publishReviewed(ctx, participant, organizerID)
+	s.publishJoined(ctx, participant)
+	return participant, nil
+}
+
+// RejectParticipant rejects a pending join request
+func (s *Service) RejectParticipant(ctx context.Context, tripID, organizerID, participantID uuid.UUID) (*trip.Participant, error) {
+	participant, err := s.getManagedParticipant(ctx, tripID, organizerID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := participant.Reject(); err != nil {
+		return nil, err
+	}
+
+	if err := s.participantRepo.Update(ctx, participant); err != nil {
+		return nil, err
+	}
+
+	s.publishReviewed(ctx, participant, organizerID)
+	return participant, nil
+}
+
+// LeaveTrip withdraws a user from a trip and offers any freed seat to the waitlist
+func (s *Service) LeaveTrip(ctx context.Context, tripID, userID uuid.UUID) error {
+	participant, err := s.participantRepo.GetByTripAndUser(ctx, tripID, userID)
+	if err != nil {
+		return err
+	}
+
+	wasApproved := participant.IsApproved()
+	if err := participant.Leave(); err != nil {
+		return err
+	}
+
+	if err := s.participantRepo.Leave(ctx, participant); err != nil {
+		return err
+	}
+
+	if wasApproved {
+		s.publisher.Publish(ctx, trip.ParticipantLeft{
+			TripID:     tripID,
+			UserID:     userID,
+			OccurredAt: s.now(),
+		})
+	}
+
+	return s.offerFreeSeats(ctx, tripID)
+}
+
+// RemoveParticipant takes an approved participant off the trip on behalf of the
+// organizer and offers the freed seat to the waitlist
+func (s *Service) RemoveParticipant(ctx context.Context, tripID, organizerID, participantID uuid.UUID) (*trip.Participant, error) {
+	participant, err := s.getManagedParticipant(ctx, tripID, organizerID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := participant.Remove(); err != nil {
+		return nil, err
+	}
+
+	if err := s.participantRepo.Leave(ctx, participant); err != nil {
+		return nil, err
+	}
+
+	s.publisher.Publish(ctx, trip.ParticipantLeft{
+		TripID:     tripID,
+		UserID:     participant.UserID,
+		Removed:    true,
+		OccurredAt: s.now(),
+	})
+
+	if err := s.offerFreeSeats(ctx, tripID); err != nil {
+		return nil, err
+	}
+
+	return participant, nil
+}
+
+// GetWaitlist returns the active waitlist of a trip in queue order
+func (s *Service) GetWaitlist(ctx context.Context, tripID, organizerID uuid.UUID) ([]*trip.WaitlistEntry, error) {
+	t, err := s.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !t.IsOrganizer(organizerID) {
+		return nil, trip.ErrNotOrganizer
+	}
+
+	return s.waitlistRepo.ListActiveByTrip(ctx, tripID)
+}
+
+// GetWaitlistEntry returns the user's own active waitlist entry for a trip
+func (s *Service) GetWaitlistEntry(ctx context.Context, tripID, userID uuid.UUID) (*trip.WaitlistEntry, error) {
+	return s.waitlistRepo.GetActiveByTripAndUser(ctx, tripID, userID)
+}
+
+// LeaveWaitlist removes the user from a trip's waitlist, releasing any held offer
+func (s *Service) LeaveWaitlist(ctx context.Context, tripID, userID uuid.UUID) error {
+	entry, err := s.waitlistRepo.GetActiveByTripAndUser(ctx, tripID, userID)
+	if err != nil {
+		return err
+	}
+
+	heldOffer := entry.Status == trip.WaitlistStatusOffered
+	if err := entry.Cancel(s.now()); err != nil {
+		return err
+	}
+
+	if err := s.waitlistRepo.Update(ctx, entry); err != nil {
+		return err
+	}
+
+	if heldOffer {
+		return s.offerFreeSeats(ctx, tripID)
+	}
+	return nil
+}
+
+// AcceptWaitlistOffer takes the seat offered to the user
+func (s *Service) AcceptWaitlistOffer(ctx context.Context, tripID, userID uuid.UUID) (*trip.Participant, error) {
+	entry, err := s.waitlistRepo.GetActiveByTripAndUser(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := entry.Accept(s.now()); err != nil {
+		return nil, err
+	}
+
+	participant := trip.NewApprovedParticipant(tripID, userID, entry.Notes)
+
+	if err := s.waitlistRepo.AcceptOffer(ctx, entry, participant); err != nil {
+		return nil, err
+	}
+
+	s.publishJoined(ctx, participant)
+	return participant, nil
+}
+
+// DeclineWaitlistOffer turns down the seat offered to the user and passes it on
+func (s *Service) DeclineWaitlistOffer(ctx context.Context, tripID, userID uuid.UUID) error {
+	entry, err := s.waitlistRepo.GetActiveByTripAndUser(ctx, tripID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := entry.Decline(s.now()); err != nil {
+		return err
+	}
+
+	if err := s.waitlistRepo.Update(ctx, entry); err != nil {
+		return err
+	}
+
+	return s.offerFreeSeats(ctx, tripID)
+}
+
+// ExpireWaitlistOffers expires lapsed seat offers and passes them to the next users in line
+func (s *Service) ExpireWaitlistOffers(ctx context.Context) error {
+	tripIDs, err := s.waitlistRepo.ExpireOffers(ctx, s.now())
+	if err != nil {
+		return err
+	}
+
+	for _, tripID := range tripIDs {
+		if err := s.offerFreeSeats(ctx, tripID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// offerFreeSeats offers every unreserved free seat to the waitlist in queue order
+func (s *Service) offerFreeSeats(ctx context.Context, tripID uuid.UUID) error {
+	for {
+		_, err := s.waitlistRepo.OfferNext(ctx, tripID, s.now(), s.offerWindow)
+		if errors.Is(err, trip.ErrNoSeatAvailable) || errors.Is(err, trip.ErrWaitlistEmpty) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// publishRequested announces a join request awaiting the organizer's review
+func (s *Service) publishRequested(ctx context.Context, participant *trip.Participant) {
+	s.publisher.Publish(ctx, trip.JoinRequested{
+		TripID:        participant.TripID,
+		UserID:        participant.UserID,
+		ParticipantID: participant.ID,
+		OccurredAt:    s.now(),
+	})
+}
+
+// publishReviewed announces the organizer's decision on a join request
+func (s *Service) publishReviewed(ctx context.Context, participant *trip.Participant, reviewerID uuid.UUID) {
+	s.publisher.Publish(ctx, trip.JoinReviewed{
+		TripID:        participant.TripID,
+		UserID:        participant.UserID,
+		ParticipantID: participant.ID,
+		ReviewerID:    reviewerID,
+		Approved:      participant.IsApproved(),
+		OccurredAt:    s.now(),
+	})
+}
+
+// publishJoined announces that a participant took a seat on a trip
+func (s *Service) publishJoined(ctx context.Context, participant *trip.Participant) {
+	s.publisher.Publish(ctx, trip.ParticipantJoined{
+		TripID:     participant.TripID,
+		UserID:     participant.UserID,
+		OccurredAt: s.now(),
+	})
+}
+
+// getManagedParticipant loads a participant of a trip on behalf of its organizer
+func (s *Service) getManagedParticipant(ctx context.Context, tripID, organizerID, participantID uuid.UUID) (*trip.Participant, error) {
+	t, err := s.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !t.IsOrganizer(organizerID) {
+		return nil, trip.ErrNotOrganizer
+	}
+
+	participant, err := s.participantRepo.GetByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if participant.TripID != tripID {
+		return nil, trip.ErrParticipantNotFound
+	}
+
+	return participant, nil
+}
+
+// getVisibleTrip loads a trip the user can see. Trips hidden from the user
+// are reported as not found, so that their existence does not leak.
+func (s *Service) getVisibleTrip(ctx context.Context, tripID, userID uuid.UUID) (*trip.Trip, error) {
+	t, err := s.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := trip.CheckAccess(ctx, s.participantRepo, t, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.Visible {
+		return nil, trip.ErrTripNotFound
+	}
+
+	return t, nil
+}
@@ -0,0 +1,223 @@
+package album
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"jointrip/internal/domain/blob"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxPhotoSize is the largest photo file accepted, in bytes
+	MaxPhotoSize = 20 << 20
+	// MaxPhotosPerTrip limits how many photos one trip's album can hold
+	MaxPhotosPerTrip = 500
+	// MaxCaptionLength is the longest caption, in characters
+	MaxCaptionLength = 500
+	// PhotoSide is the largest side, in pixels, of the stored photos
+	PhotoSide = 2560
+	// ThumbnailSide is the largest side, in pixels, of photo previews
+	ThumbnailSide = 400
+)
+
+// photoContentTypes are the image types photos can be uploaded as
+var photoContentTypes = map[string]bool{
+	blob.ContentTypeJPEG: true,
+	blob.ContentTypePNG:  true,
+	blob.ContentTypeGIF:  true,
+}
+
+// Visibility controls who besides the trip's members can see a photo
+type Visibility string
+
+const (
+	// VisibilityParticipants shows the photo to the organizer and approved participants only
+	VisibilityParticipants Visibility = "participants"
+	// VisibilityPublic also shows the photo on the page of a public trip
+	VisibilityPublic Visibility = "public"
+)
+
+// IsValid returns true if the visibility is known
+func (v Visibility) IsValid() bool {
+	return v == VisibilityParticipants || v == VisibilityPublic
+}
+
+// Photo is a photo in a trip's album. The uploaded image is re-encoded as a
+// JPEG, without metadata, and kept with a preview in blob storage under the
+// keys recorded here.
+type Photo struct {
+	ID         uuid.UUID  `json:"id"`
+	TripID     uuid.UUID  `json:"trip_id"`
+	UploadedBy uuid.UUID  `json:"uploaded_by"`
+	Caption    string     `json:"caption"`
+	Position   int        `json:"position"`
+	Visibility Visibility `json:"visibility"`
+	IsCover    bool       `json:"is_cover"`
+	Size       int64      `json:"size"`
+	// StorageKey holds the photo and ThumbnailKey its preview
+	StorageKey   string `json:"-"`
+	ThumbnailKey string `json:"-"`
+	// URL and ThumbnailURL are short-lived links to the files, set when the
+	// photo is shown to someone
+	URL          string    `json:"url,omitempty"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CheckPhoto checks an uploaded photo by its size and its first bytes,
+// whatever its name or the uploader claim
+func CheckPhoto(head []byte, size int64) error {
+	if size > MaxPhotoSize {
+		return ErrPhotoTooLarge
+	}
+	if size <= 0 || !photoContentTypes[blob.DetectContentType(head)] {
+		return ErrInvalidPhoto
+	}
+	return nil
+}
+
+// NewPhoto creates a photo for a trip's album, placed after the photos
+// already in it. size is that of the stored JPEG. Photos are only shown to
+// the trip's members unless the visibility says otherwise.
+func NewPhoto(tripID, uploadedBy uuid.UUID, caption string, visibility Visibility, size int64) (*Photo, error) {
+	if visibility == "" {
+		visibility = VisibilityParticipants
+	}
+
+	id := uuid.New()
+	now := time.Now()
+	p := &Photo{
+		ID:           id,
+		TripID:       tripID,
+		UploadedBy:   uploadedBy,
+		Size:         size,
+		StorageKey:   fmt.Sprintf("trip-photos/%s/%s.jpg", tripID, id),
+		ThumbnailKey: fmt.Sprintf("trip-photos/%s/%s-thumb.jpg", tripID, id),
+		CreatedAt:    now,
+	}
+	if err := p.Update(&caption, &visibility); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Update changes the caption and visibility of the photo. Nil fields are
+// left unchanged.
+func (p *Photo) Update(caption *string, visibility *Visibility) error {
+	if caption != nil {
+		trimmed := strings.TrimSpace(*caption)
+		if utf8.RuneCountInString(trimmed) > MaxCaptionLength {
+			return ErrInvalidCaption
+		}
+		p.Caption = trimmed
+	}
+	if visibility != nil {
+		if !visibility.IsValid() {
+			return ErrInvalidVisibility
+		}
+		p.Visibility = *visibility
+	}
+
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsPublic returns true if the photo is shown on the public trip page
+func (p *Photo) IsPublic() bool {
+	return p.Visibility == VisibilityPublic
+}
+
+// CanModify returns true if the user may change or remove the photo:
+// whoever uploaded it and the trip's organizer
+func (p *Photo) CanModify(userID, tripCreatorID uuid.UUID) bool {
+	return userID == p.UploadedBy || userID == tripCreatorID
+}
+
+// VisibleTo returns the photos of an album that trip members, or anyone
+// else who can see the trip, are shown
+func VisibleTo(photos []*Photo, member bool) []*Photo {
+	if member {
+		return photos
+	}
+
+	visible := []*Photo{}
+	for _, p := range photos {
+		if p.IsPublic() {
+			visible = append(visible, p)
+		}
+	}
+	return visible
+}
+
+// Cover returns the photo chosen as the album's cover or, when it is not
+// among the photos, the first one. It returns nil for an empty album.
+func Cover(photos []*Photo) *Photo {
+	if len(photos) == 0 {
+		return nil
+	}
+	for _, p := range photos {
+		if p.IsCover {
+			return p
+		}
+	}
+	return photos[0]
+}
+
+// Reorder places the album's photos in the order given by their IDs, which
+// must list every photo exactly once. The photos are returned in their new
+// order.
+func Reorder(photos []*Photo, order []uuid.UUID) ([]*Photo, error) {
+	if len(order) != len(photos) {
+		return nil, ErrInvalidOrder
+	}
+
+	byID := make(map[uuid.UUID]*Photo, len(photos))
+	for _, p := range photos {
+		byID[p.ID] = p
+	}
+
+	ordered := make([]*Photo, 0, len(order))
+	for _, id := range order {
+		p, ok := byID[id]
+		if !ok {
+			return nil, ErrInvalidOrder
+		}
+		delete(byID, id)
+		ordered = append(ordered, p)
+	}
+
+	now := time.Now()
+	for i, p := range ordered {
+		p.Position = i
+		p.UpdatedAt = now
+	}
+	return ordered, nil
+}
+
+// SetCover makes one of the album's photos its cover
+func SetCover(photos []*Photo, photoID uuid.UUID) error {
+	found := false
+	for _, p := range photos {
+		if p.ID == photoID {
+			found = true
+		}
+	}
+	if !found {
+		return ErrPhotoNotFound
+	}
+
+	now := time.Now()
+	for _, p := range photos {
+		if p.IsCover != (p.ID == photoID) {
+			p.IsCover = p.ID == photoID
+			p.UpdatedAt = now
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package album
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPhoto(t *testing.T) {
+	tests := []struct {
+		name string
+		head string
+		size int64
+		err  error
+	}{
+		{"jpeg", "\xff\xd8\xff\xe0", 1000, nil},
+		{"png", "\x89PNG\r\n\x1a\n", 1000, nil},
+		{"gif", "GIF89a", 1000, nil},
+		{"pdf", "%PDF-1.7", 1000, ErrInvalidPhoto},
+		{"html", "<html><script>", 1000, ErrInvalidPhoto},
+		{"empty", "", 0, ErrInvalidPhoto},
+		{"too large", "\xff\xd8\xff\xe0", MaxPhotoSize + 1, ErrPhotoTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPhoto([]byte(tt.head), tt.size)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestNewPhoto(t *testing.T) {
+	tripID, userID := uuid.New(), uuid.New()
+
+	p, err := NewPhoto(tripID, userID, "  Sunset over the bay  ", "", 1234)
+	require.NoError(t, err)
+	assert.Equal(t, "Sunset over the bay", p.Caption)
+	assert.Equal(t, VisibilityParticipants, p.Visibility)
+	assert.False(t, p.IsPublic())
+	assert.Equal(t, "trip-photos/"+tripID.String()+"/"+p.ID.String()+".jpg", p.StorageKey)
+	assert.Equal(t, "trip-photos/"+tripID.String()+"/"+p.ID.String()+"-thumb.jpg", p.ThumbnailKey)
+
+	p, err = NewPhoto(tripID, userID, "", VisibilityPublic, 1234)
+	require.NoError(t, err)
+	assert.True(t, p.IsPublic())
+
+	_, err = NewPhoto(tripID, userID, strings.Repeat("é", MaxCaptionLength+1), "", 1234)
+	assert.ErrorIs(t, err, ErrInvalidCaption)
+	_, err = NewPhoto(tripID, userID, "", "everyone", 1234)
+	assert.ErrorIs(t, err, ErrInvalidVisibility)
+}
+
+func TestPhoto_Update(t *testing.T) {
+	p, err := NewPhoto(uuid.New(), uuid.New(), "Beach", "", 1234)
+	require.NoError(t, err)
+
+	public := VisibilityPublic
+	require.NoError(t, p.Update(nil, &public))
+	assert.Equal(t, "Beach", p.Caption)
+	assert.True(t, p.IsPublic())
+
+	caption := "Beach day"
+	require.NoError(t, p.Update(&caption, nil))
+	assert.Equal(t, "Beach day", p.Caption)
+	assert.True(t, p.IsPublic())
+
+	invalid := Visibility("friends")
+	assert.ErrorIs(t, p.Update(nil, &invalid), ErrInvalidVisibility)
+}
+
+func TestPhoto_CanModify(t *testing.T) {
+	uploader, organizer := uuid.New(), uuid.New()
+	p, err := NewPhoto(uuid.New(), uploader, "", "", 1234)
+	require.NoError(t, err)
+
+	assert.True(t, p.CanModify(uploader, organizer))
+	assert.True(t, p.CanModify(organizer, organizer))
+	assert.False(t, p.CanModify(uuid.New(), organizer))
+}
+
+// testAlbum returns photos at positions 0 to n-1, every other one public
+func testAlbum(t *testing.T, n int) []*Photo {
+	t.Helper()
+	tripID, userID := uuid.New(), uuid.New()
+	photos := make([]*Photo, n)
+	for i := range photos {
+		visibility := VisibilityParticipants
+		if i%2 == 1 {
+			visibility = VisibilityPublic
+		}
+		p, err := NewPhoto(tripID, userID, "", visibility, 1234)
+		require.NoError(t, err)
+		p.Position = i
+		photos[i] = p
+	}
+	return photos
+}
+
+func TestVisibleTo(t *testing.T) {
+	photos := testAlbum(t, 4)
+
+	assert.Equal(t, photos, VisibleTo(photos, true))
+	assert.Equal(t, []*Photo{photos[1], photos[3]}, VisibleTo(photos, false))
+	assert.Empty(t, VisibleTo(photos[:1], false))
+}
+
+func TestCover(t *testing.T) {
+	assert.Nil(t, Cover(nil))
+
+	photos := testAlbum(t, 3)
+	assert.Same(t, photos[0], Cover(photos))
+
+	require.NoError(t, SetCover(photos, photos[2].ID))
+	assert.Same(t, photos[2], Cover(photos))
+
+	require.NoError(t, SetCover(photos, photos[1].ID))
+	assert.Same(t, photos[1], Cover(photos))
+	assert.False(t, photos[2].IsCover)
+
+	// A cover hidden from the viewer gives way to the first photo they see
+	photos[2].Visibility = VisibilityPublic
+	require.NoError(t, SetCover(photos, photos[0].ID))
+	assert.Same(t, photos[1], Cover(VisibleTo(photos, false)))
+
+	assert.ErrorIs(t, SetCover(photos, uuid.New()), ErrPhotoNotFound)
+	assert.True(t, photos[0].IsCover)
+}
+
+func TestReorder(t *testing.T) {
+	photos := testAlbum(t, 3)
+	a, b, c := photos[0], photos[1], photos[2]
+
+	ordered, err := Reorder(photos, []uuid.UUID{c.ID, a.ID, b.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []*Photo{c, a, b}, ordered)
+	assert.Equal(t, []int{1, 2, 0}, []int{a.Position, b.Position, c.Position})
+
+	tests := []struct {
+		name  string
+		order []uuid.UUID
+	}{
+		{"missing photo", []uuid.UUID{a.ID, b.ID}},
+		{"duplicate photo", []uuid.UUID{a.ID, b.ID, b.ID}},
+		{"unknown photo", []uuid.UUID{a.ID, b.ID, uuid.New()}},
+		{"extra photo", []uuid.UUID{a.ID, b.ID, c.ID, uuid.New()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Reorder(photos, tt.order)
+			assert.ErrorIs(t, err, ErrInvalidOrder)
+			assert.Equal(t, []int{1, 2, 0}, []int{a.Position, b.Position, c.Position})
+		})
+	}
+}
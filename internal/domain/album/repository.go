@@ -0,0 +1,47 @@
+package album
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Domain errors
+var (
+	ErrPhotoNotFound     = errors.New("photo not found")
+	ErrInvalidPhoto      = errors.New("photos must be JPEG, PNG or GIF images")
+	ErrPhotoTooLarge     = errors.New("photos can be at most 20 MB")
+	ErrInvalidCaption    = errors.New("caption can be at most 500 characters")
+	ErrInvalidVisibility = errors.New("visibility must be participants or public")
+	ErrInvalidOrder      = errors.New("the new order must list every photo of the album exactly once")
+	ErrAlbumFull         = errors.New("an album can hold at most 500 photos")
+	ErrNotTripMember     = errors.New("only the organizer and approved participants can add photos to the album")
+	ErrNotPhotoOwner     = errors.New("only the participant who uploaded a photo or the organizer can change it")
+	ErrNotAlbumOrganizer = errors.New("only the organizer can arrange the album")
+)
+
+// Repository defines the interface for trip photo persistence. The files
+// themselves are kept in blob storage.
+type Repository interface {
+	// Create stores a new photo at the end of its trip's album and sets its
+	// position, failing with ErrAlbumFull once the album holds
+	// MaxPhotosPerTrip photos
+	Create(ctx context.Context, photo *Photo) error
+
+	// GetByID retrieves a photo
+	GetByID(ctx context.Context, id uuid.UUID) (*Photo, error)
+
+	// ListByTrip retrieves a trip's photos in album order
+	ListByTrip(ctx context.Context, tripID uuid.UUID) ([]*Photo, error)
+
+	// Update stores a photo's caption and visibility
+	Update(ctx context.Context, photo *Photo) error
+
+	// UpdateArrangement stores the positions and cover of all the photos of
+	// one trip together
+	UpdateArrangement(ctx context.Context, photos []*Photo) error
+
+	// Delete deletes a photo
+	Delete(ctx context.Context, id uuid.UUID) error
+}
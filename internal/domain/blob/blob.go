@@ -0,0 +1,103 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Domain errors
+var (
+	ErrNotFound    = errors.New("file not found")
+	ErrInvalidKey  = errors.New("file keys must be relative slash-separated paths of letters, digits, '.', '_' and '-'")
+	ErrInvalidLink = errors.New("file link is invalid")
+	ErrLinkExpired = errors.New("file link has expired")
+)
+
+// Content types recognized by DetectContentType
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+	ContentTypeGIF  = "image/gif"
+	ContentTypeWebP = "image/webp"
+	ContentTypePDF  = "application/pdf"
+)
+
+// SniffLength is how many leading bytes DetectContentType looks at
+const SniffLength = 512
+
+// MaxKeyLength is the longest key a store accepts
+const MaxKeyLength = 512
+
+// Store defines the interface for storing uploaded files. Files are
+// addressed by keys such as "receipts/<trip ID>/<expense ID>/<receipt ID>";
+// what a file is and who may see it is recorded by the caller.
+type Store interface {
+	// Put stores size bytes of content under key, replacing any file already there
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+
+	// Open returns the content stored under key. It returns ErrNotFound if
+	// there is none.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the file stored under key. Deleting a missing file is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// SignedURL returns a link that lets anyone holding it download the file
+	// stored under key until expiresAt, without further access checks
+	SignedURL(ctx context.Context, key string, expiresAt time.Time) (string, error)
+}
+
+// LinkServer is implemented by stores whose signed links point back at the
+// application, which then serves the files itself
+type LinkServer interface {
+	// OpenLink checks the signature and expiry carried in the query of a
+	// signed link to key and returns the file it grants. It returns
+	// ErrInvalidLink or ErrLinkExpired if the link does not grant it.
+	OpenLink(ctx context.Context, key string, query url.Values, now time.Time) (io.ReadCloser, error)
+}
+
+// ValidKey checks that a key is a clean relative path, so that no driver
+// can be made to reach outside its root
+func ValidKey(key string) bool {
+	if key == "" || len(key) > MaxKeyLength {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+		for _, c := range segment {
+			switch {
+			case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			case c == '.', c == '_', c == '-':
+			default:
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// DetectContentType identifies a file by its magic bytes rather than by
+// what the uploader claims. It returns an empty string for anything it
+// does not recognize.
+func DetectContentType(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("\xff\xd8\xff")):
+		return ContentTypeJPEG
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1a\n")):
+		return ContentTypePNG
+	case bytes.HasPrefix(head, []byte("GIF87a")), bytes.HasPrefix(head, []byte("GIF89a")):
+		return ContentTypeGIF
+	case len(head) >= 12 && bytes.Equal(head[:4], []byte("RIFF")) && bytes.Equal(head[8:12], []byte("WEBP")):
+		return ContentTypeWebP
+	case bytes.HasPrefix(head, []byte("%PDF-")):
+		return ContentTypePDF
+	}
+	return ""
+}
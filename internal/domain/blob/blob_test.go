@@ -0,0 +1,59 @@
+package blob
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidKey(t *testing.T) {
+	valid := []string{
+		"receipt",
+		"receipts/4f1c/9a2b/photo.jpg",
+		"avatars/user_1/128-abc.JPG",
+	}
+	for _, key := range valid {
+		assert.True(t, ValidKey(key), key)
+	}
+
+	invalid := []string{
+		"",
+		"/etc/passwd",
+		"receipts/../secrets",
+		"receipts/./a",
+		"receipts//a",
+		"receipts/a/",
+		`receipts\a`,
+		"receipts/a b",
+		"receipts/ä",
+		strings.Repeat("a", MaxKeyLength+1),
+	}
+	for _, key := range invalid {
+		assert.False(t, ValidKey(key), key)
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		name     string
+		head     string
+		expected string
+	}{
+		{"jpeg", "\xff\xd8\xff\xe0\x00\x10JFIF", ContentTypeJPEG},
+		{"png", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", ContentTypePNG},
+		{"gif", "GIF89a\x01\x00", ContentTypeGIF},
+		{"webp", "RIFF\x24\x00\x00\x00WEBPVP8 ", ContentTypeWebP},
+		{"pdf", "%PDF-1.7\n", ContentTypePDF},
+		{"html", "<html><body>", ""},
+		{"riff but not webp", "RIFF\x24\x00\x00\x00WAVEfmt ", ""},
+		{"empty", "", ""},
+		{"short riff", "RIFF", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectContentType([]byte(tt.head)))
+		})
+	}
+}
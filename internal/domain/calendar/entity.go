@@ -0,0 +1,70 @@
+package calendar
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// tokenBytes is the amount of randomness in a feed token
+const tokenBytes = 32
+
+// FeedToken is a revocable secret that grants read access to a user's calendar feed.
+// Calendar clients cannot send Authorization headers, so the token travels in the
+// feed URL; only its SHA-256 hash is stored.
+type FeedToken struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	TokenHash  string     `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// NewFeedToken creates a new feed token and returns it together with its plaintext secret.
+// The secret is only available here; it cannot be recovered later.
+func NewFeedToken(userID uuid.UUID) (*FeedToken, string, error) {
+	if userID == uuid.Nil {
+		return nil, "", errors.New("user ID is required")
+	}
+
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	secret := base64.RawURLEncoding.EncodeToString(buf)
+
+	token := &FeedToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TokenHash: HashToken(secret),
+		CreatedAt: time.Now(),
+	}
+
+	return token, secret, nil
+}
+
+// HashToken returns the stored representation of a feed token secret
+func HashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsActive returns true if the token has not been revoked
+func (t *FeedToken) IsActive() bool {
+	return t.RevokedAt == nil
+}
+
+// Revoke permanently disables the token
+func (t *FeedToken) Revoke() {
+	if t.RevokedAt == nil {
+		now := time.Now()
+		t.RevokedAt = &now
+	}
+}
@@ -0,0 +1,41 @@
+package calendar
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFeedToken(t *testing.T) {
+	userID := uuid.New()
+
+	token, secret, err := NewFeedToken(userID)
+	require.NoError(t, err)
+
+	assert.Equal(t, userID, token.UserID)
+	assert.NotEmpty(t, secret)
+	assert.NotEqual(t, secret, token.TokenHash)
+	assert.Equal(t, HashToken(secret), token.TokenHash)
+	assert.True(t, token.IsActive())
+
+	_, other, err := NewFeedToken(userID)
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+
+	_, _, err = NewFeedToken(uuid.Nil)
+	assert.Error(t, err)
+}
+
+func TestFeedToken_Revoke(t *testing.T) {
+	token, _, err := NewFeedToken(uuid.New())
+	require.NoError(t, err)
+
+	token.Revoke()
+	assert.False(t, token.IsActive())
+
+	revokedAt := *token.RevokedAt
+	token.Revoke()
+	assert.Equal(t, revokedAt, *token.RevokedAt)
+}
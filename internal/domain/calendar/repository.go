@@ -0,0 +1,31 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Domain errors
+var (
+	ErrFeedTokenNotFound = errors.New("calendar feed token not found")
+)
+
+// Repository defines the interface for calendar feed token persistence
+type Repository interface {
+	// Rotate revokes the user's active token, if any, and stores the new one
+	Rotate(ctx context.Context, token *FeedToken) error
+
+	// GetActiveByUser retrieves the user's active token
+	GetActiveByUser(ctx context.Context, userID uuid.UUID) (*FeedToken, error)
+
+	// GetActiveByHash retrieves an active token by the hash of its secret
+	GetActiveByHash(ctx context.Context, tokenHash string) (*FeedToken, error)
+
+	// RevokeByUser revokes the user's active token
+	RevokeByUser(ctx context.Context, userID uuid.UUID) error
+
+	// MarkUsed records that the token was used to fetch the feed
+	MarkUsed(ctx context.Context, id uuid.UUID) error
+}
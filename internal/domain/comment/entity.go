@@ -0,0 +1,206 @@
+package comment
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Comment limits
+const (
+	MaxContentLength = 2000
+	// MaxDepth is how deep replies nest; top-level comments have depth 0
+	MaxDepth = 5
+	// MaxMentions is how many users a comment can mention
+	MaxMentions = 10
+)
+
+// Comment is a public comment on a trip, or a reply to another comment.
+// Deleted comments stay in place without their content so that the replies
+// below them keep their thread; hidden ones only show to their author and
+// the trip creator.
+type Comment struct {
+	ID               uuid.UUID   `json:"id"`
+	TripID           uuid.UUID   `json:"trip_id"`
+	UserID           uuid.UUID   `json:"user_id"`
+	ParentCommentID  *uuid.UUID  `json:"parent_comment_id,omitempty"`
+	Depth            int         `json:"depth"`
+	Content          string      `json:"content"`
+	MentionedUserIDs []uuid.UUID `json:"mentioned_user_ids"`
+	IsEdited         bool        `json:"is_edited"`
+	ReplyCount       int         `json:"reply_count"`
+	HiddenAt         *time.Time  `json:"hidden_at,omitempty"`
+	DeletedAt        *time.Time  `json:"deleted_at,omitempty"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+// Revision is an earlier version of an edited comment
+type Revision struct {
+	ID        uuid.UUID `json:"id"`
+	CommentID uuid.UUID `json:"comment_id"`
+	Content   string    `json:"content"`
+	EditedAt  time.Time `json:"edited_at"`
+}
+
+// NewComment creates a comment on a trip, as a reply when a parent is given
+func NewComment(tripID, userID uuid.UUID, content string, parent *Comment) (*Comment, error) {
+	content, err := normalizeContent(content)
+	if err != nil {
+		return nil, err
+	}
+
+	now := timestamp()
+	c := &Comment{
+		ID:               uuid.New(),
+		TripID:           tripID,
+		UserID:           userID,
+		Content:          content,
+		MentionedUserIDs: []uuid.UUID{},
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	if parent != nil {
+		if parent.TripID != tripID {
+			return nil, ErrCommentNotFound
+		}
+		if parent.IsDeleted() {
+			return nil, ErrCommentDeleted
+		}
+		if parent.Depth >= MaxDepth {
+			return nil, ErrThreadTooDeep
+		}
+		c.ParentCommentID = &parent.ID
+		c.Depth = parent.Depth + 1
+	}
+
+	return c, nil
+}
+
+// IsDeleted returns true if the author deleted the comment
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// IsHidden returns true if the trip creator hid the comment
+func (c *Comment) IsHidden() bool {
+	return c.HiddenAt != nil
+}
+
+// Edit replaces the content on behalf of the author and returns the revision
+// keeping the previous content, or nil when the content did not change
+func (c *Comment) Edit(userID uuid.UUID, content string) (*Revision, error) {
+	if c.UserID != userID {
+		return nil, ErrNotCommentAuthor
+	}
+	if c.IsDeleted() {
+		return nil, ErrCommentDeleted
+	}
+
+	content, err := normalizeContent(content)
+	if err != nil {
+		return nil, err
+	}
+	if content == c.Content {
+		return nil, nil
+	}
+
+	now := timestamp()
+	revision := &Revision{
+		ID:        uuid.New(),
+		CommentID: c.ID,
+		Content:   c.Content,
+		EditedAt:  now,
+	}
+	c.Content = content
+	c.IsEdited = true
+	c.UpdatedAt = now
+
+	return revision, nil
+}
+
+// Delete removes the content on behalf of the author. The comment stays as a
+// placeholder for its replies.
+func (c *Comment) Delete(userID uuid.UUID) error {
+	if c.UserID != userID {
+		return ErrNotCommentAuthor
+	}
+	if c.IsDeleted() {
+		return ErrCommentDeleted
+	}
+
+	now := timestamp()
+	c.DeletedAt = &now
+	c.Content = ""
+	c.MentionedUserIDs = []uuid.UUID{}
+	c.UpdatedAt = now
+	return nil
+}
+
+// Hide hides the comment from everyone but its author and the trip creator
+func (c *Comment) Hide() {
+	if c.HiddenAt == nil {
+		now := timestamp()
+		c.HiddenAt = &now
+	}
+}
+
+// Unhide shows a hidden comment again
+func (c *Comment) Unhide() {
+	c.HiddenAt = nil
+}
+
+// CanSeeContent returns true if the viewer may read the comment's content
+func (c *Comment) CanSeeContent(viewerID, tripCreatorID uuid.UUID) bool {
+	return !c.IsHidden() || viewerID == c.UserID || viewerID == tripCreatorID
+}
+
+// RedactFor removes the content of a hidden comment unless the viewer may read it
+func (c *Comment) RedactFor(viewerID, tripCreatorID uuid.UUID) {
+	if !c.CanSeeContent(viewerID, tripCreatorID) {
+		c.Content = ""
+		c.MentionedUserIDs = []uuid.UUID{}
+	}
+}
+
+// mentionPattern matches @username at the start of the text or after a
+// character that cannot be part of a username, so email addresses are skipped
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.@-])@([\p{L}\p{N}_][\p{L}\p{N}_.-]*)`)
+
+// ParseMentions returns the distinct usernames mentioned in the content, in
+// order of appearance. Dots and hyphens ending a mention are punctuation.
+func ParseMentions(content string) ([]string, error) {
+	usernames := []string{}
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		username := strings.TrimRight(match[1], ".-")
+		if seen[username] {
+			continue
+		}
+		if len(usernames) == MaxMentions {
+			return nil, ErrTooManyMentions
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+	return usernames, nil
+}
+
+// normalizeContent trims the content and checks its length
+func normalizeContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" || utf8.RuneCountInString(content) > MaxContentLength {
+		return "", ErrInvalidContent
+	}
+	return content, nil
+}
+
+// timestamp returns the current time at the precision stored by the database,
+// so that cursors compare equal after a round trip
+func timestamp() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
@@ -0,0 +1,170 @@
+package comment
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewComment(t *testing.T) {
+	tripID, userID := uuid.New(), uuid.New()
+
+	root, err := NewComment(tripID, userID, "  Who else is bringing a tent?  ", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Who else is bringing a tent?", root.Content)
+	assert.Nil(t, root.ParentCommentID)
+	assert.Zero(t, root.Depth)
+	assert.Equal(t, root.CreatedAt, root.UpdatedAt)
+
+	reply, err := NewComment(tripID, uuid.New(), "Me!", root)
+	require.NoError(t, err)
+	require.NotNil(t, reply.ParentCommentID)
+	assert.Equal(t, root.ID, *reply.ParentCommentID)
+	assert.Equal(t, 1, reply.Depth)
+
+	tests := []struct {
+		name     string
+		tripID   uuid.UUID
+		content  string
+		parent   func() *Comment
+		expected error
+	}{
+		{"empty", tripID, "   ", func() *Comment { return nil }, ErrInvalidContent},
+		{"too long", tripID, strings.Repeat("a", MaxContentLength+1), func() *Comment { return nil }, ErrInvalidContent},
+		{"parent on another trip", uuid.New(), "Hi", func() *Comment { return root }, ErrCommentNotFound},
+		{"deleted parent", tripID, "Hi", func() *Comment {
+			deleted, err := NewComment(tripID, userID, "Oops", nil)
+			require.NoError(t, err)
+			require.NoError(t, deleted.Delete(userID))
+			return deleted
+		}, ErrCommentDeleted},
+		{"too deep", tripID, "Hi", func() *Comment {
+			return &Comment{ID: uuid.New(), TripID: tripID, Depth: MaxDepth}
+		}, ErrThreadTooDeep},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewComment(tt.tripID, userID, tt.content, tt.parent())
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestComment_Edit(t *testing.T) {
+	author := uuid.New()
+	c, err := NewComment(uuid.New(), author, "Meet at 8", nil)
+	require.NoError(t, err)
+
+	_, err = c.Edit(uuid.New(), "Meet at 9")
+	assert.ErrorIs(t, err, ErrNotCommentAuthor)
+
+	revision, err := c.Edit(author, " Meet at 8 ")
+	require.NoError(t, err)
+	assert.Nil(t, revision, "unchanged content is not an edit")
+	assert.False(t, c.IsEdited)
+
+	revision, err = c.Edit(author, "Meet at 9")
+	require.NoError(t, err)
+	require.NotNil(t, revision)
+	assert.Equal(t, "Meet at 8", revision.Content)
+	assert.Equal(t, c.ID, revision.CommentID)
+	assert.Equal(t, "Meet at 9", c.Content)
+	assert.True(t, c.IsEdited)
+	assert.Equal(t, revision.EditedAt, c.UpdatedAt)
+
+	_, err = c.Edit(author, "")
+	assert.ErrorIs(t, err, ErrInvalidContent)
+}
+
+func TestComment_Delete(t *testing.T) {
+	author := uuid.New()
+	c, err := NewComment(uuid.New(), author, "Hi @bob", nil)
+	require.NoError(t, err)
+	c.MentionedUserIDs = []uuid.UUID{uuid.New()}
+
+	assert.ErrorIs(t, c.Delete(uuid.New()), ErrNotCommentAuthor)
+
+	require.NoError(t, c.Delete(author))
+	assert.True(t, c.IsDeleted())
+	assert.Empty(t, c.Content)
+	assert.Empty(t, c.MentionedUserIDs)
+
+	assert.ErrorIs(t, c.Delete(author), ErrCommentDeleted)
+	_, err = c.Edit(author, "Back")
+	assert.ErrorIs(t, err, ErrCommentDeleted)
+}
+
+func TestComment_HideAndRedact(t *testing.T) {
+	author, creator, visitor := uuid.New(), uuid.New(), uuid.New()
+	c, err := NewComment(uuid.New(), author, "Buy my timeshare", nil)
+	require.NoError(t, err)
+	assert.True(t, c.CanSeeContent(visitor, creator))
+
+	c.Hide()
+	assert.True(t, c.IsHidden())
+	assert.True(t, c.CanSeeContent(author, creator))
+	assert.True(t, c.CanSeeContent(creator, creator))
+	assert.False(t, c.CanSeeContent(visitor, creator))
+
+	c.RedactFor(author, creator)
+	assert.Equal(t, "Buy my timeshare", c.Content)
+	c.RedactFor(visitor, creator)
+	assert.Empty(t, c.Content)
+
+	c.Unhide()
+	assert.False(t, c.IsHidden())
+}
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{"none", "See you there", []string{}},
+		{"start of text", "@anna are you in?", []string{"anna"}},
+		{"several in order", "Ask @ben and @anna.", []string{"ben", "anna"}},
+		{"duplicates", "@anna @anna", []string{"anna"}},
+		{"trailing punctuation", "Thanks @joe.smith-!", []string{"joe.smith"}},
+		{"accented username", "Hola @JoséGarcía1a2b3c4d", []string{"JoséGarcía1a2b3c4d"}},
+		{"email address", "Write to anna@example.com", []string{}},
+		{"after parenthesis", "(cc @ben)", []string{"ben"}},
+		{"lone at sign", "Meet @ 8", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mentions, err := ParseMentions(tt.content)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, mentions)
+		})
+	}
+
+	var many []string
+	for i := 0; i <= MaxMentions; i++ {
+		many = append(many, "@user"+string(rune('a'+i)))
+	}
+	_, err := ParseMentions(strings.Join(many, " "))
+	assert.ErrorIs(t, err, ErrTooManyMentions)
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c, err := NewComment(uuid.New(), uuid.New(), "Hi", nil)
+	require.NoError(t, err)
+
+	cursor := Cursor{At: c.CreatedAt, ID: c.ID}
+	decoded, err := DecodeCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, cursor, *decoded)
+
+	empty, err := DecodeCursor("")
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+
+	_, err = DecodeCursor("not a cursor")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
@@ -0,0 +1,18 @@
+package comment
+
+import "github.com/google/uuid"
+
+// Comment event names
+const (
+	EventUsersMentioned = "comment.users_mentioned"
+)
+
+// UsersMentioned is published when a new or edited comment mentions users who
+// were not mentioned in it before
+type UsersMentioned struct {
+	Comment *Comment
+	UserIDs []uuid.UUID
+}
+
+// EventName implements event.Event
+func (UsersMentioned) EventName() string { return EventUsersMentioned }
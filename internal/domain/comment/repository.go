@@ -0,0 +1,51 @@
+package comment
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Domain errors
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrInvalidContent   = errors.New("comment must contain between 1 and 2000 characters")
+	ErrNotCommentAuthor = errors.New("only the author can change this comment")
+	ErrCommentDeleted   = errors.New("comment has been deleted")
+	ErrThreadTooDeep    = errors.New("replies cannot be nested any deeper")
+	ErrTooManyMentions  = errors.New("a comment can mention at most 10 users")
+	ErrInvalidCursor    = errors.New("invalid pagination cursor")
+)
+
+// ListFilter selects a page of one thread: the top-level comments of a trip,
+// newest first, or the direct replies to a comment, oldest first
+type ListFilter struct {
+	// ParentID selects the replies to a comment; nil selects top-level comments
+	ParentID *uuid.UUID
+	// After continues a previous page
+	After *Cursor
+	Limit int
+}
+
+// Repository defines the interface for trip comment persistence
+type Repository interface {
+	// Create stores a new comment with its mentions
+	Create(ctx context.Context, comment *Comment) error
+
+	// GetByID retrieves a comment with its mentions and reply count
+	GetByID(ctx context.Context, id uuid.UUID) (*Comment, error)
+
+	// List retrieves the trip's comments selected by the filter
+	List(ctx context.Context, tripID uuid.UUID, filter ListFilter) ([]*Comment, error)
+
+	// Update stores a comment's content, mentions and visibility together with
+	// the revision of an edit, if any
+	Update(ctx context.Context, comment *Comment, revision *Revision) error
+
+	// Delete stores a soft deletion and erases the comment's revisions and mentions
+	Delete(ctx context.Context, comment *Comment) error
+
+	// ListRevisions retrieves the earlier versions of a comment, newest first
+	ListRevisions(ctx context.Context, commentID uuid.UUID) ([]*Revision, error)
+}
@@ -0,0 +1,110 @@
+package currency
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency
+type Currency struct {
+	Code string `json:"code"`
+	// MinorUnits is the number of decimal places of the minor unit:
+	// 2 for EUR (cents), 0 for JPY, 3 for KWD (fils)
+	MinorUnits int `json:"minor_units"`
+}
+
+// Money is an amount in minor units of a currency
+type Money struct {
+	Currency string `json:"currency"`
+	Amount   int64  `json:"amount"`
+}
+
+// Decimal formats the amount in major units, such as "-12.50" for -1250 EUR.
+// Unknown currencies are formatted with two decimals.
+func (m Money) Decimal() string {
+	units := 2
+	if c, err := Lookup(m.Currency); err == nil {
+		units = c.MinorUnits
+	}
+
+	sign := ""
+	magnitude := uint64(m.Amount)
+	if m.Amount < 0 {
+		sign = "-"
+		magnitude = -magnitude
+	}
+
+	digits := strconv.FormatUint(magnitude, 10)
+	if units == 0 {
+		return sign + digits
+	}
+	if len(digits) <= units {
+		digits = strings.Repeat("0", units-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-units] + "." + digits[len(digits)-units:]
+}
+
+// String formats the amount with its currency code, such as "12.50 EUR"
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// minorUnits maps the circulating ISO 4217 currencies to their minor units.
+// Fund codes, precious metals and withdrawn currencies are left out.
+var minorUnits = map[string]int{
+	// No minor unit
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+
+	// Thousandths
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+
+	// Hundredths
+	"AED": 2, "AFN": 2, "ALL": 2, "AMD": 2, "AOA": 2, "ARS": 2, "AUD": 2, "AWG": 2,
+	"AZN": 2, "BAM": 2, "BBD": 2, "BDT": 2, "BGN": 2, "BMD": 2, "BND": 2, "BOB": 2,
+	"BRL": 2, "BSD": 2, "BTN": 2, "BWP": 2, "BYN": 2, "BZD": 2, "CAD": 2, "CDF": 2,
+	"CHF": 2, "CNY": 2, "COP": 2, "CRC": 2, "CUP": 2, "CVE": 2, "CZK": 2, "DKK": 2,
+	"DOP": 2, "DZD": 2, "EGP": 2, "ERN": 2, "ETB": 2, "EUR": 2, "FJD": 2, "FKP": 2,
+	"GBP": 2, "GEL": 2, "GHS": 2, "GIP": 2, "GMD": 2, "GTQ": 2, "GYD": 2, "HKD": 2,
+	"HNL": 2, "HTG": 2, "HUF": 2, "IDR": 2, "ILS": 2, "INR": 2, "IRR": 2, "JMD": 2,
+	"KES": 2, "KGS": 2, "KHR": 2, "KPW": 2, "KYD": 2, "KZT": 2, "LAK": 2, "LBP": 2,
+	"LKR": 2, "LRD": 2, "LSL": 2, "MAD": 2, "MDL": 2, "MGA": 2, "MKD": 2, "MMK": 2,
+	"MNT": 2, "MOP": 2, "MRU": 2, "MUR": 2, "MVR": 2, "MWK": 2, "MXN": 2, "MYR": 2,
+	"MZN": 2, "NAD": 2, "NGN": 2, "NIO": 2, "NOK": 2, "NPR": 2, "NZD": 2, "PAB": 2,
+	"PEN": 2, "PGK": 2, "PHP": 2, "PKR": 2, "PLN": 2, "QAR": 2, "RON": 2, "RSD": 2,
+	"RUB": 2, "SAR": 2, "SBD": 2, "SCR": 2, "SDG": 2, "SEK": 2, "SGD": 2, "SHP": 2,
+	"SLE": 2, "SOS": 2, "SRD": 2, "SSP": 2, "STN": 2, "SVC": 2, "SYP": 2, "SZL": 2,
+	"THB": 2, "TJS": 2, "TMT": 2, "TOP": 2, "TRY": 2, "TTD": 2, "TWD": 2, "TZS": 2,
+	"UAH": 2, "USD": 2, "UYU": 2, "UZS": 2, "VES": 2, "WST": 2, "XCD": 2, "XCG": 2,
+	"YER": 2, "ZAR": 2, "ZMW": 2, "ZWG": 2,
+}
+
+// Lookup returns the currency with the given code, in any letter case
+func Lookup(code string) (Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	units, ok := minorUnits[code]
+	if !ok {
+		return Currency{}, ErrInvalidCurrency
+	}
+	return Currency{Code: code, MinorUnits: units}, nil
+}
+
+// Normalize returns the upper-case code of a known currency
+func Normalize(code string) (string, error) {
+	c, err := Lookup(code)
+	if err != nil {
+		return "", err
+	}
+	return c.Code, nil
+}
+
+// All returns every supported currency, ordered by code
+func All() []Currency {
+	currencies := make([]Currency, 0, len(minorUnits))
+	for code, units := range minorUnits {
+		currencies = append(currencies, Currency{Code: code, MinorUnits: units})
+	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i].Code < currencies[j].Code })
+	return currencies
+}
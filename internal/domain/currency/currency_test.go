@@ -0,0 +1,142 @@
+package currency
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		code       string
+		expected   string
+		minorUnits int
+	}{
+		{"EUR", "EUR", 2},
+		{" usd ", "USD", 2},
+		{"JPY", "JPY", 0},
+		{"KWD", "KWD", 3},
+		{"isk", "ISK", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			c, err := Lookup(tt.code)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, c.Code)
+			assert.Equal(t, tt.minorUnits, c.MinorUnits)
+		})
+	}
+
+	for _, code := range []string{"", "EU", "EURO", "XXX", "DEM", "E1R"} {
+		_, err := Normalize(code)
+		assert.ErrorIs(t, err, ErrInvalidCurrency, code)
+	}
+
+	all := All()
+	assert.Equal(t, len(minorUnits), len(all))
+	assert.Equal(t, "AED", all[0].Code)
+}
+
+func TestMoney_Decimal(t *testing.T) {
+	tests := []struct {
+		money    Money
+		expected string
+	}{
+		{Money{"EUR", 1250}, "12.50"},
+		{Money{"EUR", -1250}, "-12.50"},
+		{Money{"EUR", 5}, "0.05"},
+		{Money{"EUR", -5}, "-0.05"},
+		{Money{"EUR", 0}, "0.00"},
+		{Money{"JPY", 1500}, "1500"},
+		{Money{"KWD", 12345}, "12.345"},
+		{Money{"XXX", 100}, "1.00"},
+		{Money{"EUR", math.MinInt64}, "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.money.Decimal())
+		})
+	}
+
+	assert.Equal(t, "12.50 EUR", Money{"EUR", 1250}.String())
+}
+
+func TestNewRate(t *testing.T) {
+	asOf := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+
+	r, err := NewRate("eur", "usd", " 1.0823 ", asOf, "ecb")
+	require.NoError(t, err)
+	assert.Equal(t, Rate{Base: "EUR", Quote: "USD", Value: "1.0823", AsOf: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), Source: "ecb"}, r)
+
+	for _, value := range []string{"", "0", "0.000", "-1.2", "1e3", "3/4", "1,08", "abc"} {
+		_, err := NewRate("EUR", "USD", value, asOf, "ecb")
+		assert.ErrorIs(t, err, ErrInvalidRate, value)
+	}
+
+	_, err = NewRate("EUR", "ZZZ", "1", asOf, "ecb")
+	assert.ErrorIs(t, err, ErrInvalidCurrency)
+}
+
+func TestRate_Convert(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		quote    string
+		value    string
+		amount   int64
+		expected int64
+	}{
+		{"identity", "EUR", "EUR", "1", 12345, 12345},
+		{"cents to cents", "EUR", "USD", "1.0823", 10000, 10823},
+		{"rounds half away from zero", "EUR", "USD", "1.5", 1, 2},
+		{"rounds down below half", "EUR", "USD", "1.0823", 1, 1},
+		{"negative", "EUR", "USD", "1.5", -1, -2},
+		{"to no minor unit", "EUR", "JPY", "158.42", 1000, 1584},
+		{"from no minor unit", "JPY", "EUR", "0.0063", 10000, 6300},
+		{"to thousandths", "EUR", "KWD", "0.3321", 10000, 33210},
+		{"from thousandths", "KWD", "EUR", "3.0111", 1000, 301},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Rate{Base: tt.base, Quote: tt.quote, Value: tt.value}
+			converted, err := r.Convert(tt.amount)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, converted)
+		})
+	}
+
+	_, err := Rate{Base: "EUR", Quote: "VND", Value: "100000000000"}.Convert(1 << 60)
+	assert.ErrorIs(t, err, ErrConversionOverflow)
+	_, err = Rate{Base: "EUR", Quote: "USD", Value: "abc"}.Convert(1)
+	assert.ErrorIs(t, err, ErrInvalidRate)
+}
+
+func TestRate_InvertAndCross(t *testing.T) {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	eurUSD := Rate{Base: "EUR", Quote: "USD", Value: "1.25", AsOf: day, Source: "ecb"}
+	eurGBP := Rate{Base: "EUR", Quote: "GBP", Value: "0.8", AsOf: day.AddDate(0, 0, -1), Source: "ecb"}
+
+	usdEUR, err := eurUSD.Invert()
+	require.NoError(t, err)
+	assert.Equal(t, Rate{Base: "USD", Quote: "EUR", Value: "0.8", AsOf: day, Source: "ecb"}, usdEUR)
+
+	usdGBP, err := Cross(eurUSD, eurGBP)
+	require.NoError(t, err)
+	assert.Equal(t, Rate{Base: "USD", Quote: "GBP", Value: "0.64", AsOf: day.AddDate(0, 0, -1), Source: "ecb"}, usdGBP)
+
+	thirds, err := Rate{Base: "EUR", Quote: "USD", Value: "3"}.Invert()
+	require.NoError(t, err)
+	assert.Equal(t, "0.333333333333", thirds.Value)
+
+	_, err = Cross(eurUSD, usdGBP)
+	assert.ErrorIs(t, err, ErrRateNotFound)
+
+	identity := Identity("CHF", day.Add(5*time.Hour))
+	assert.Equal(t, Rate{Base: "CHF", Quote: "CHF", Value: "1", AsOf: day, Source: SourceIdentity}, identity)
+}
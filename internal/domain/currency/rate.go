@@ -0,0 +1,166 @@
+package currency
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// rateDecimals is the precision of derived (inverted or crossed) rates
+const rateDecimals = 12
+
+// SourceIdentity marks the rate between a currency and itself
+const SourceIdentity = "identity"
+
+// decimalPattern matches a plain positive decimal such as 1.0823
+var decimalPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// Rate is the price of one unit of Base in units of Quote, as published by
+// Source for a day. The value is an exact decimal string, never a float.
+type Rate struct {
+	Base   string    `json:"base"`
+	Quote  string    `json:"quote"`
+	Value  string    `json:"rate"`
+	AsOf   time.Time `json:"as_of"`
+	Source string    `json:"source"`
+}
+
+// NewRate creates a rate between two known currencies
+func NewRate(base, quote, value string, asOf time.Time, source string) (Rate, error) {
+	base, err := Normalize(base)
+	if err != nil {
+		return Rate{}, err
+	}
+	quote, err = Normalize(quote)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	value = strings.TrimSpace(value)
+	if !decimalPattern.MatchString(value) {
+		return Rate{}, ErrInvalidRate
+	}
+	if r, _ := new(big.Rat).SetString(value); r.Sign() <= 0 {
+		return Rate{}, ErrInvalidRate
+	}
+
+	year, month, day := asOf.Date()
+	return Rate{
+		Base:   base,
+		Quote:  quote,
+		Value:  value,
+		AsOf:   time.Date(year, month, day, 0, 0, 0, 0, time.UTC),
+		Source: source,
+	}, nil
+}
+
+// Identity returns the rate of 1 between a currency and itself
+func Identity(code string, asOf time.Time) Rate {
+	year, month, day := asOf.Date()
+	return Rate{
+		Base:   code,
+		Quote:  code,
+		Value:  "1",
+		AsOf:   time.Date(year, month, day, 0, 0, 0, 0, time.UTC),
+		Source: SourceIdentity,
+	}
+}
+
+// Convert converts an amount in minor units of Base into minor units of
+// Quote, rounding half away from zero
+func (r Rate) Convert(amount int64) (int64, error) {
+	base, err := Lookup(r.Base)
+	if err != nil {
+		return 0, err
+	}
+	quote, err := Lookup(r.Quote)
+	if err != nil {
+		return 0, err
+	}
+	value, ok := new(big.Rat).SetString(r.Value)
+	if !ok || value.Sign() <= 0 {
+		return 0, ErrInvalidRate
+	}
+
+	scaled := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), value)
+	scaled.Mul(scaled, new(big.Rat).SetFrac(pow10(quote.MinorUnits), pow10(base.MinorUnits)))
+
+	result := roundHalfAway(scaled)
+	if !result.IsInt64() {
+		return 0, ErrConversionOverflow
+	}
+	return result.Int64(), nil
+}
+
+// Invert returns the rate from Quote to Base
+func (r Rate) Invert() (Rate, error) {
+	value, ok := new(big.Rat).SetString(r.Value)
+	if !ok || value.Sign() <= 0 {
+		return Rate{}, ErrInvalidRate
+	}
+
+	return Rate{
+		Base:   r.Quote,
+		Quote:  r.Base,
+		Value:  formatRat(new(big.Rat).Inv(value)),
+		AsOf:   r.AsOf,
+		Source: r.Source,
+	}, nil
+}
+
+// Cross derives the rate between the quotes of two rates that share a base,
+// such as USD to GBP from the euro reference rates EUR/USD and EUR/GBP. The
+// result is as old as the older of the two.
+func Cross(toBase, toQuote Rate) (Rate, error) {
+	if toBase.Base != toQuote.Base {
+		return Rate{}, ErrRateNotFound
+	}
+	from, ok := new(big.Rat).SetString(toBase.Value)
+	if !ok || from.Sign() <= 0 {
+		return Rate{}, ErrInvalidRate
+	}
+	to, ok := new(big.Rat).SetString(toQuote.Value)
+	if !ok || to.Sign() <= 0 {
+		return Rate{}, ErrInvalidRate
+	}
+
+	asOf := toBase.AsOf
+	if toQuote.AsOf.Before(asOf) {
+		asOf = toQuote.AsOf
+	}
+
+	return Rate{
+		Base:   toBase.Quote,
+		Quote:  toQuote.Quote,
+		Value:  formatRat(new(big.Rat).Quo(to, from)),
+		AsOf:   asOf,
+		Source: toBase.Source,
+	}, nil
+}
+
+// pow10 returns 10^n
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundHalfAway rounds a rational to the nearest integer, halves away from zero
+func roundHalfAway(r *big.Rat) *big.Int {
+	num := new(big.Int).Abs(r.Num())
+	den := r.Denom()
+
+	quotient, remainder := new(big.Int).QuoRem(num, den, new(big.Int))
+	if new(big.Int).Mul(remainder, big.NewInt(2)).Cmp(den) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		quotient.Neg(quotient)
+	}
+	return quotient
+}
+
+// formatRat formats a derived rate as a decimal without trailing zeros
+func formatRat(r *big.Rat) string {
+	s := strings.TrimRight(r.FloatString(rateDecimals), "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,26 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Domain errors
+var (
+	ErrInvalidCurrency    = errors.New("currency must be a circulating ISO 4217 code")
+	ErrInvalidRate        = errors.New("exchange rate must be a positive decimal number")
+	ErrRateNotFound       = errors.New("no exchange rate is available for this currency pair")
+	ErrConversionOverflow = errors.New("converted amount is too large")
+)
+
+// RateRepository defines the interface for exchange rate persistence
+type RateRepository interface {
+	// SaveRates stores rates, replacing those already stored for the same pair and day
+	SaveRates(ctx context.Context, rates []Rate) error
+
+	// GetClosest retrieves the rate for a pair published closest to the given
+	// day, preferring the latest one on or before it. It returns ErrRateNotFound
+	// if the pair has no rates.
+	GetClosest(ctx context.Context, base, quote string, on time.Time) (Rate, error)
+}
@@ -0,0 +1,136 @@
+package email
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message is a rendered email with a plain-text and an HTML body
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Validate checks that the message can be sent
+func (m *Message) Validate() error {
+	if _, err := mail.ParseAddress(m.To); err != nil {
+		return ErrInvalidAddress
+	}
+	if strings.TrimSpace(m.Subject) == "" {
+		return errors.New("subject is required")
+	}
+	if m.Text == "" && m.HTML == "" {
+		return errors.New("body is required")
+	}
+	return nil
+}
+
+// OutboxStatus represents the delivery state of a queued email
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending"
+	OutboxStatusSent    OutboxStatus = "sent"
+	OutboxStatusFailed  OutboxStatus = "failed"
+)
+
+// Delivery retry policy
+const (
+	MaxAttempts    = 8
+	RetryBaseDelay = time.Minute
+	RetryMaxDelay  = 6 * time.Hour
+	MaxErrorLength = 500
+)
+
+// OutboxEntry is an email queued for asynchronous delivery. Failed attempts
+// are retried with exponential backoff until MaxAttempts is reached.
+type OutboxEntry struct {
+	ID             uuid.UUID    `json:"id"`
+	UserID         uuid.UUID    `json:"user_id"`
+	NotificationID *uuid.UUID   `json:"notification_id,omitempty"`
+	Message        Message      `json:"message"`
+	Status         OutboxStatus `json:"status"`
+	Attempts       int          `json:"attempts"`
+	NextAttemptAt  time.Time    `json:"next_attempt_at"`
+	LastError      string       `json:"last_error,omitempty"`
+	SentAt         *time.Time   `json:"sent_at,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// NewOutboxEntry queues a message to a user for immediate delivery
+func NewOutboxEntry(userID uuid.UUID, notificationID *uuid.UUID, message Message) (*OutboxEntry, error) {
+	if userID == uuid.Nil {
+		return nil, errors.New("user ID is required")
+	}
+	if err := message.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	return &OutboxEntry{
+		ID:             uuid.New(),
+		UserID:         userID,
+		NotificationID: notificationID,
+		Message:        message,
+		Status:         OutboxStatusPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}, nil
+}
+
+// HoldUntil postpones the first attempt to the given time, such as the end of
+// the recipient's quiet hours. Earlier times are ignored.
+func (e *OutboxEntry) HoldUntil(until time.Time) {
+	if until.After(e.NextAttemptAt) {
+		e.NextAttemptAt = until
+	}
+}
+
+// MarkSent records a successful delivery
+func (e *OutboxEntry) MarkSent(now time.Time) {
+	e.Attempts++
+	e.Status = OutboxStatusSent
+	e.LastError = ""
+	e.SentAt = &now
+}
+
+// MarkFailed records a failed delivery and schedules the next attempt, or
+// gives up once the attempts are exhausted
+func (e *OutboxEntry) MarkFailed(now time.Time, cause error) {
+	e.Attempts++
+	e.LastError = truncateError(cause.Error())
+
+	if e.Attempts >= MaxAttempts {
+		e.Status = OutboxStatusFailed
+		return
+	}
+	e.NextAttemptAt = now.Add(RetryDelay(e.Attempts))
+}
+
+// RetryDelay returns how long to wait after the given number of failed
+// attempts: the base delay doubled per attempt, capped at RetryMaxDelay
+func RetryDelay(attempts int) time.Duration {
+	delay := RetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= RetryMaxDelay {
+			return RetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// truncateError keeps stored error messages short
+func truncateError(message string) string {
+	runes := []rune(message)
+	if len(runes) <= MaxErrorLength {
+		return message
+	}
+	return string(runes[:MaxErrorLength])
+}
@@ -0,0 +1,134 @@
+package email
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validMessage() Message {
+	return Message{
+		To:      "anna@example.com",
+		Subject: "Anna wants to join Alps",
+		Text:    "Review the request.",
+		HTML:    "<p>Review the request.</p>",
+	}
+}
+
+func TestMessage_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(m *Message)
+		wantErr bool
+	}{
+		{"valid", func(m *Message) {}, false},
+		{"text only", func(m *Message) { m.HTML = "" }, false},
+		{"invalid address", func(m *Message) { m.To = "not an address" }, true},
+		{"blank subject", func(m *Message) { m.Subject = "  " }, true},
+		{"no body", func(m *Message) { m.Text, m.HTML = "", "" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := validMessage()
+			tt.modify(&m)
+			err := m.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	m := validMessage()
+	m.To = "nope"
+	assert.ErrorIs(t, m.Validate(), ErrInvalidAddress)
+}
+
+func TestNewOutboxEntry(t *testing.T) {
+	notificationID := uuid.New()
+	entry, err := NewOutboxEntry(uuid.New(), &notificationID, validMessage())
+	require.NoError(t, err)
+	assert.Equal(t, OutboxStatusPending, entry.Status)
+	assert.Zero(t, entry.Attempts)
+	assert.False(t, entry.NextAttemptAt.IsZero())
+
+	_, err = NewOutboxEntry(uuid.Nil, nil, validMessage())
+	assert.Error(t, err)
+
+	_, err = NewOutboxEntry(uuid.New(), nil, Message{To: "anna@example.com"})
+	assert.Error(t, err)
+}
+
+func TestOutboxEntry_HoldUntil(t *testing.T) {
+	entry, err := NewOutboxEntry(uuid.New(), nil, validMessage())
+	require.NoError(t, err)
+	created := entry.NextAttemptAt
+
+	entry.HoldUntil(time.Time{})
+	assert.Equal(t, created, entry.NextAttemptAt)
+
+	until := created.Add(8 * time.Hour)
+	entry.HoldUntil(until)
+	assert.Equal(t, until, entry.NextAttemptAt)
+}
+
+func TestOutboxEntry_Delivery(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	entry, err := NewOutboxEntry(uuid.New(), nil, validMessage())
+	require.NoError(t, err)
+
+	entry.MarkFailed(now, errors.New("connection refused"))
+	assert.Equal(t, OutboxStatusPending, entry.Status)
+	assert.Equal(t, 1, entry.Attempts)
+	assert.Equal(t, now.Add(RetryBaseDelay), entry.NextAttemptAt)
+	assert.Equal(t, "connection refused", entry.LastError)
+
+	entry.MarkSent(now)
+	assert.Equal(t, OutboxStatusSent, entry.Status)
+	assert.Equal(t, 2, entry.Attempts)
+	assert.Empty(t, entry.LastError)
+	require.NotNil(t, entry.SentAt)
+}
+
+func TestOutboxEntry_GivesUp(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	entry, err := NewOutboxEntry(uuid.New(), nil, validMessage())
+	require.NoError(t, err)
+
+	for i := 0; i < MaxAttempts-1; i++ {
+		entry.MarkFailed(now, errors.New(strings.Repeat("x", MaxErrorLength+10)))
+		assert.Equal(t, OutboxStatusPending, entry.Status)
+	}
+	assert.Len(t, entry.LastError, MaxErrorLength)
+
+	entry.MarkFailed(now, errors.New("mailbox unavailable"))
+	assert.Equal(t, OutboxStatusFailed, entry.Status)
+	assert.Equal(t, MaxAttempts, entry.Attempts)
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{9, 256 * time.Minute},
+		{10, RetryMaxDelay},
+		{40, RetryMaxDelay},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, RetryDelay(tt.attempts), "attempts %d", tt.attempts)
+	}
+}
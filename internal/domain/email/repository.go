@@ -0,0 +1,26 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Domain errors
+var (
+	ErrInvalidAddress = errors.New("invalid email address")
+)
+
+// OutboxRepository defines the interface for email outbox persistence
+type OutboxRepository interface {
+	// Enqueue stores a new outbox entry
+	Enqueue(ctx context.Context, entry *OutboxEntry) error
+
+	// ClaimDue returns up to limit pending entries whose next attempt is due
+	// and hides them from other claimers for the lease duration, so that
+	// concurrent workers never send the same email twice
+	ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*OutboxEntry, error)
+
+	// Update stores the delivery state of an entry
+	Update(ctx context.Context, entry *OutboxEntry) error
+}
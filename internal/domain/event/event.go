@@ -0,0 +1,23 @@
+package event
+
+import "context"
+
+// Event is something that happened in the domain that other parts of the
+// system may react to
+type Event interface {
+	// EventName identifies the kind of event, e.g. "trip.participant_joined"
+	EventName() string
+}
+
+// Handler reacts to a published event
+type Handler func(ctx context.Context, e Event) error
+
+// Publisher publishes domain events to their subscribers
+type Publisher interface {
+	Publish(ctx context.Context, events ...Event)
+}
+
+// Subscriber registers handlers for named events
+type Subscriber interface {
+	Subscribe(name string, handler Handler)
+}
@@ -0,0 +1,304 @@
+package expense
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"jointrip/internal/domain/currency"
+
+	"github.com/google/uuid"
+)
+
+// OptimalSettleUpLimit is the largest number of people with an open balance
+// in one currency for which SettleUp searches for the fewest transfers.
+// Larger groups fall back to the greedy plan.
+const OptimalSettleUpLimit = 12
+
+// Basis selects the amounts balances are computed from
+type Basis string
+
+const (
+	// BasisOriginal keeps every expense in the currency it was paid in
+	BasisOriginal Basis = "original"
+	// BasisBase uses the amounts converted into the trip's base currency
+	BasisBase Basis = "base"
+)
+
+// Balance is what a user is owed (positive) or owes (negative) in one
+// currency, in minor units, counting only unsettled shares
+type Balance struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Currency string    `json:"currency"`
+	Paid     int64     `json:"paid"`
+	Owed     int64     `json:"owed"`
+	Net      int64     `json:"net"`
+	// Converted is the net balance in the user's preferred currency, when asked for
+	Converted *currency.Money `json:"converted,omitempty"`
+}
+
+// Transfer is one payment of a settle-up plan
+type Transfer struct {
+	FromUserID uuid.UUID `json:"from_user_id"`
+	ToUserID   uuid.UUID `json:"to_user_id"`
+	Currency   string    `json:"currency"`
+	Amount     int64     `json:"amount"`
+	// Converted is the amount in the payer's preferred currency, when asked for
+	Converted *currency.Money `json:"converted,omitempty"`
+}
+
+// ComputeBalances returns every user's net balance per currency, ordered by
+// currency and user ID. Paid is what the user paid for others and Owed what
+// they owe others; shares of the payer's own expense and settled shares do
+// not count. The balances of each currency always add up to zero.
+func ComputeBalances(expenses []*Expense, basis Basis) []Balance {
+	type key struct {
+		currency string
+		userID   uuid.UUID
+	}
+	totals := make(map[key]*Balance)
+	get := func(code string, userID uuid.UUID) *Balance {
+		k := key{code, userID}
+		if totals[k] == nil {
+			totals[k] = &Balance{UserID: userID, Currency: code}
+		}
+		return totals[k]
+	}
+
+	for _, e := range expenses {
+		code := e.Currency
+		if basis == BasisBase {
+			code = e.BaseCurrency
+		}
+		for _, share := range e.Shares {
+			amount := share.AmountOwed
+			if basis == BasisBase {
+				amount = share.BaseAmountOwed
+			}
+			if share.IsSettled || share.UserID == e.PayerID || amount == 0 {
+				continue
+			}
+			get(code, e.PayerID).Paid += amount
+			get(code, share.UserID).Owed += amount
+		}
+	}
+
+	balances := make([]Balance, 0, len(totals))
+	for _, b := range totals {
+		b.Net = b.Paid - b.Owed
+		balances = append(balances, *b)
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].Currency != balances[j].Currency {
+			return balances[i].Currency < balances[j].Currency
+		}
+		return lessUUID(balances[i].UserID, balances[j].UserID)
+	})
+
+	return balances
+}
+
+// SettleUp returns the transfers that bring every balance to zero, per
+// currency. Small groups get a plan with the fewest possible transfers;
+// larger ones a greedy plan where the biggest debtor pays the biggest
+// creditor until everyone is even. Either way a group of n people needs at
+// most n-1 transfers.
+func SettleUp(balances []Balance) []Transfer {
+	byCurrency := make(map[string][]Balance)
+	var currencies []string
+	for _, b := range balances {
+		if b.Net == 0 {
+			continue
+		}
+		if _, ok := byCurrency[b.Currency]; !ok {
+			currencies = append(currencies, b.Currency)
+		}
+		byCurrency[b.Currency] = append(byCurrency[b.Currency], b)
+	}
+	sort.Strings(currencies)
+
+	transfers := []Transfer{}
+	for _, code := range currencies {
+		open := byCurrency[code]
+		sort.Slice(open, func(i, j int) bool { return lessUUID(open[i].UserID, open[j].UserID) })
+
+		if len(open) <= OptimalSettleUpLimit {
+			for _, group := range zeroSumGroups(open) {
+				transfers = append(transfers, greedyTransfers(code, group)...)
+			}
+		} else {
+			transfers = append(transfers, greedyTransfers(code, open)...)
+		}
+	}
+
+	return transfers
+}
+
+// SettleBetween settles every open share between two users in a currency,
+// in both directions, as when they square up with one payment. It returns
+// the settled shares and that payment, from whoever owed more overall.
+func SettleBetween(expenses []*Expense, userID, otherID uuid.UUID, code, notes string, at time.Time) ([]*Share, Transfer, error) {
+	transfer := Transfer{FromUserID: userID, ToUserID: otherID, Currency: code}
+	if userID == otherID {
+		return nil, transfer, ErrNothingToSettle
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(notes)) > MaxNotesLength {
+		return nil, transfer, ErrInvalidSettlement
+	}
+
+	var settled []*Share
+	for _, e := range expenses {
+		if e.Currency != code || (e.PayerID != userID && e.PayerID != otherID) {
+			continue
+		}
+		for _, share := range e.Shares {
+			if share.IsSettled || share.UserID == e.PayerID || share.AmountOwed == 0 {
+				continue
+			}
+			switch {
+			case e.PayerID == otherID && share.UserID == userID:
+				transfer.Amount += share.AmountOwed
+			case e.PayerID == userID && share.UserID == otherID:
+				transfer.Amount -= share.AmountOwed
+			default:
+				continue
+			}
+			if err := e.SettleShare(share, notes, at); err != nil {
+				return nil, transfer, err
+			}
+			settled = append(settled, share)
+		}
+	}
+
+	if len(settled) == 0 {
+		return nil, transfer, ErrNothingToSettle
+	}
+	if transfer.Amount < 0 {
+		transfer.FromUserID, transfer.ToUserID = otherID, userID
+		transfer.Amount = -transfer.Amount
+	}
+
+	return settled, transfer, nil
+}
+
+// zeroSumGroups splits balances into the largest number of groups that each
+// add up to zero. Settling each group on its own then takes the fewest
+// transfers overall: a group of k people that cannot be split further needs
+// exactly k-1. This is a dynamic program over subsets, so it is only used
+// for small groups.
+func zeroSumGroups(balances []Balance) [][]Balance {
+	n := len(balances)
+	full := 1<<n - 1
+
+	sums := make([]int64, full+1)
+	groups := make([]int, full+1)
+	for mask := 1; mask <= full; mask++ {
+		low := mask & -mask
+		i := bitIndex(low)
+		sums[mask] = sums[mask^low] + balances[i].Net
+
+		best := 0
+		for j := 0; j < n; j++ {
+			if mask&(1<<j) != 0 && groups[mask^(1<<j)] > best {
+				best = groups[mask^(1<<j)]
+			}
+		}
+		if sums[mask] == 0 {
+			best++
+		}
+		groups[mask] = best
+	}
+
+	// Walk back from the full set, removing one person at a time along an
+	// optimal path; every zero-sum subset on the way closes a group
+	var result [][]Balance
+	var current []Balance
+	for mask := full; mask != 0; {
+		bonus := 0
+		if sums[mask] == 0 {
+			bonus = 1
+		}
+		for j := 0; j < n; j++ {
+			bit := 1 << j
+			if mask&bit == 0 || groups[mask^bit]+bonus != groups[mask] {
+				continue
+			}
+			current = append(current, balances[j])
+			mask ^= bit
+			if mask == 0 || sums[mask] == 0 {
+				result = append(result, current)
+				current = nil
+			}
+			break
+		}
+	}
+
+	return result
+}
+
+// greedyTransfers settles a zero-sum set of balances by repeatedly having
+// the largest debtor pay the largest creditor. Ties go to the lowest user ID.
+func greedyTransfers(code string, balances []Balance) []Transfer {
+	var debtors, creditors []Balance
+	for _, b := range balances {
+		switch {
+		case b.Net < 0:
+			debtors = append(debtors, Balance{UserID: b.UserID, Net: -b.Net})
+		case b.Net > 0:
+			creditors = append(creditors, Balance{UserID: b.UserID, Net: b.Net})
+		}
+	}
+
+	var transfers []Transfer
+	for len(debtors) > 0 && len(creditors) > 0 {
+		sortLargestFirst(debtors)
+		sortLargestFirst(creditors)
+
+		debtor, creditor := &debtors[0], &creditors[0]
+		amount := min(debtor.Net, creditor.Net)
+		transfers = append(transfers, Transfer{
+			FromUserID: debtor.UserID,
+			ToUserID:   creditor.UserID,
+			Currency:   code,
+			Amount:     amount,
+		})
+
+		debtor.Net -= amount
+		creditor.Net -= amount
+		if debtor.Net == 0 {
+			debtors = debtors[1:]
+		}
+		if creditor.Net == 0 {
+			creditors = creditors[1:]
+		}
+	}
+
+	return transfers
+}
+
+// sortLargestFirst orders balances by amount, largest first, then by user ID
+func sortLargestFirst(balances []Balance) {
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].Net != balances[j].Net {
+			return balances[i].Net > balances[j].Net
+		}
+		return lessUUID(balances[i].UserID, balances[j].UserID)
+	})
+}
+
+// bitIndex returns the position of the single set bit
+func bitIndex(bit int) int {
+	i := 0
+	for bit > 1 {
+		bit >>= 1
+		i++
+	}
+	return i
+}
+
+// lessUUID orders user IDs by their bytes
+func lessUUID(a, b uuid.UUID) bool {
+	return bytes.Compare(a[:], b[:]) < 0
+}
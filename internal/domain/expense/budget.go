@@ -0,0 +1,315 @@
+package expense
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultAlertPercent is the share of a budget line at which members are
+// warned that its category is running out, unless the line sets its own
+const DefaultAlertPercent = 80
+
+// AlertLevel is how far spending in a budget line's category has gone
+type AlertLevel int
+
+const (
+	// AlertNone means spending is below the line's alert threshold
+	AlertNone AlertLevel = iota
+	// AlertThreshold means spending reached the line's alert percentage
+	AlertThreshold
+	// AlertExceeded means spending went over the planned amount
+	AlertExceeded
+)
+
+// String returns the level as shown to clients: ok, warning or exceeded
+func (l AlertLevel) String() string {
+	switch l {
+	case AlertThreshold:
+		return "warning"
+	case AlertExceeded:
+		return "exceeded"
+	default:
+		return "ok"
+	}
+}
+
+// MarshalJSON implements json.Marshaler
+func (l AlertLevel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.String())
+}
+
+// BudgetLine plans the spending in one category of a trip, either for the
+// whole trip or, when UserID is set, for one member's own shares. Amounts
+// are minor units of Currency, the trip's currency when it was planned.
+type BudgetLine struct {
+	ID           uuid.UUID  `json:"id"`
+	TripID       uuid.UUID  `json:"trip_id"`
+	UserID       *uuid.UUID `json:"user_id,omitempty"`
+	Category     Category   `json:"category"`
+	Amount       int64      `json:"amount"`
+	Currency     string     `json:"currency"`
+	AlertPercent int        `json:"alert_percent"`
+	// AlertLevel is the highest level members were alerted about, so each
+	// level is only announced once while spending stays above it
+	AlertLevel AlertLevel `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// BudgetLineInput holds the planned amount of one category. A zero alert
+// percentage falls back to DefaultAlertPercent.
+type BudgetLineInput struct {
+	Category     Category
+	Amount       int64
+	AlertPercent int
+}
+
+// NewBudgetPlan builds the budget lines of a trip, or of one member when
+// userID is set, replacing the current ones. Lines whose amount and alert
+// percentage are unchanged keep their alert level, so editing another
+// category does not repeat alerts.
+func NewBudgetPlan(tripID uuid.UUID, userID *uuid.UUID, code string, inputs []BudgetLineInput, current []*BudgetLine, now time.Time) ([]*BudgetLine, error) {
+	previous := make(map[Category]*BudgetLine, len(current))
+	for _, line := range current {
+		previous[line.Category] = line
+	}
+
+	seen := make(map[Category]bool, len(inputs))
+	lines := make([]*BudgetLine, 0, len(inputs))
+	for _, input := range inputs {
+		if !input.Category.IsValid() {
+			return nil, ErrInvalidCategory
+		}
+		if seen[input.Category] {
+			return nil, ErrInvalidBudget
+		}
+		seen[input.Category] = true
+
+		if input.AlertPercent == 0 {
+			input.AlertPercent = DefaultAlertPercent
+		}
+		if input.Amount <= 0 || input.AlertPercent < 1 || input.AlertPercent > 100 {
+			return nil, ErrInvalidBudget
+		}
+
+		line := &BudgetLine{
+			ID:           uuid.New(),
+			TripID:       tripID,
+			UserID:       userID,
+			Category:     input.Category,
+			Amount:       input.Amount,
+			Currency:     code,
+			AlertPercent: input.AlertPercent,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		if old, ok := previous[input.Category]; ok {
+			line.ID = old.ID
+			line.CreatedAt = old.CreatedAt
+			if old.Amount == line.Amount && old.AlertPercent == line.AlertPercent && old.Currency == line.Currency {
+				line.AlertLevel = old.AlertLevel
+				line.UpdatedAt = old.UpdatedAt
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// IsPersonal returns true if the line plans one member's own spending
+func (l *BudgetLine) IsPersonal() bool {
+	return l.UserID != nil
+}
+
+// BelongsTo returns true if the line is part of the plan of the member, or
+// of the whole trip when userID is nil
+func (l *BudgetLine) BelongsTo(userID *uuid.UUID) bool {
+	if l.UserID == nil || userID == nil {
+		return l.UserID == nil && userID == nil
+	}
+	return *l.UserID == *userID
+}
+
+// Spent returns what the expenses spent in the line's category: their full
+// amounts for trip lines and the member's shares for personal lines.
+// Expenses recorded while the trip had another currency are left out.
+func (l *BudgetLine) Spent(expenses []*Expense) int64 {
+	var total int64
+	for _, e := range expenses {
+		if e.Category == l.Category && e.BaseCurrency == l.Currency {
+			total += spentBy(e, l.UserID)
+		}
+	}
+	return total
+}
+
+// Level returns the alert level the spending reaches
+func (l *BudgetLine) Level(spent int64) AlertLevel {
+	switch {
+	case spent > l.Amount:
+		return AlertExceeded
+	case spent*100 >= l.Amount*int64(l.AlertPercent):
+		return AlertThreshold
+	default:
+		return AlertNone
+	}
+}
+
+// UpdateAlert records the level the spending reaches. It reports whether
+// the level changed, and whether it rose to a level members have not been
+// alerted about yet. Falling back below a level re-arms its alert.
+func (l *BudgetLine) UpdateAlert(spent int64) (changed, alert bool) {
+	level := l.Level(spent)
+	if level == l.AlertLevel {
+		return false, false
+	}
+
+	alert = level > l.AlertLevel
+	l.AlertLevel = level
+	return true, alert
+}
+
+// PlanOf returns the lines of the member's plan, or of the trip's plan when
+// userID is nil
+func PlanOf(lines []*BudgetLine, userID *uuid.UUID) []*BudgetLine {
+	plan := make([]*BudgetLine, 0, len(lines))
+	for _, line := range lines {
+		if line.BelongsTo(userID) {
+			plan = append(plan, line)
+		}
+	}
+	return plan
+}
+
+// CategoryComparison sets the planned amount of a category against what was
+// spent in it. Categories spent in without a plan have a zero Planned.
+type CategoryComparison struct {
+	Category    Category   `json:"category"`
+	Planned     int64      `json:"planned"`
+	Spent       int64      `json:"spent"`
+	Remaining   int64      `json:"remaining"`
+	PercentUsed int        `json:"percent_used"`
+	Status      AlertLevel `json:"status"`
+}
+
+// BurnDownDay is the budget left at the end of a trip day. Ideal spreads
+// the plan evenly over the trip; Remaining is nil for days still to come.
+type BurnDownDay struct {
+	Date      time.Time `json:"date"`
+	Ideal     int64     `json:"ideal"`
+	Remaining *int64    `json:"remaining"`
+}
+
+// BudgetComparison sets a budget plan against the recorded expenses
+type BudgetComparison struct {
+	TripID     uuid.UUID            `json:"trip_id"`
+	UserID     *uuid.UUID           `json:"user_id,omitempty"`
+	Currency   string               `json:"currency"`
+	Planned    int64                `json:"planned"`
+	Spent      int64                `json:"spent"`
+	Remaining  int64                `json:"remaining"`
+	Categories []CategoryComparison `json:"categories"`
+	BurnDown   []BurnDownDay        `json:"burn_down"`
+}
+
+// CompareBudget sets the plan of the trip, or of the member when userID is
+// set, against the expenses in the trip's currency. The burn-down runs over
+// the trip's days: spending before the trip counts on its first day and
+// spending after it on its last.
+func CompareBudget(tripID uuid.UUID, userID *uuid.UUID, code string, plan []*BudgetLine, expenses []*Expense, start, end, today time.Time) *BudgetComparison {
+	comparison := &BudgetComparison{
+		TripID:     tripID,
+		UserID:     userID,
+		Currency:   code,
+		Categories: []CategoryComparison{},
+		BurnDown:   []BurnDownDay{},
+	}
+
+	planned := make(map[Category]*BudgetLine, len(plan))
+	for _, line := range plan {
+		planned[line.Category] = line
+	}
+
+	spent := make(map[Category]int64)
+	for _, e := range expenses {
+		if e.BaseCurrency == code {
+			spent[e.Category] += spentBy(e, userID)
+		}
+	}
+
+	for _, category := range Categories() {
+		line, ok := planned[category]
+		if !ok && spent[category] == 0 {
+			continue
+		}
+
+		row := CategoryComparison{Category: category, Spent: spent[category]}
+		if ok {
+			row.Planned = line.Amount
+			row.PercentUsed = int(row.Spent * 100 / line.Amount)
+			row.Status = line.Level(row.Spent)
+		}
+		row.Remaining = row.Planned - row.Spent
+
+		comparison.Planned += row.Planned
+		comparison.Spent += row.Spent
+		comparison.Categories = append(comparison.Categories, row)
+	}
+	comparison.Remaining = comparison.Planned - comparison.Spent
+
+	comparison.BurnDown = burnDown(comparison.Planned, code, userID, expenses, start, end, today)
+	return comparison
+}
+
+// burnDown computes the budget left at the end of each trip day
+func burnDown(planned int64, code string, userID *uuid.UUID, expenses []*Expense, start, end, today time.Time) []BurnDownDay {
+	start, end, today = truncateToDate(start), truncateToDate(end), truncateToDate(today)
+	if end.Before(start) {
+		return []BurnDownDay{}
+	}
+	days := int(end.Sub(start).Hours()/24) + 1
+
+	daily := make([]int64, days)
+	for _, e := range expenses {
+		if e.BaseCurrency != code {
+			continue
+		}
+		day := int(truncateToDate(e.Date).Sub(start).Hours() / 24)
+		day = max(0, min(day, days-1))
+		daily[day] += spentBy(e, userID)
+	}
+
+	result := make([]BurnDownDay, days)
+	remaining := planned
+	for i := range result {
+		date := start.AddDate(0, 0, i)
+		remaining -= daily[i]
+		result[i] = BurnDownDay{
+			Date:  date,
+			Ideal: planned - planned*int64(i+1)/int64(days),
+		}
+		if !date.After(today) {
+			left := remaining
+			result[i].Remaining = &left
+		}
+	}
+	return result
+}
+
+// spentBy returns what an expense cost the whole trip, or the member's share
+// of it when userID is set, in the trip's currency
+func spentBy(e *Expense, userID *uuid.UUID) int64 {
+	if userID == nil {
+		return e.BaseAmount
+	}
+	var total int64
+	for _, share := range e.Shares {
+		if share.UserID == *userID {
+			total += share.BaseAmountOwed
+		}
+	}
+	return total
+}
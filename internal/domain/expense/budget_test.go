@@ -0,0 +1,213 @@
+package expense
+
+import (
+	"testing"
+	"time"
+
+	"jointrip/internal/domain/currency"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func budgetExpense(t *testing.T, category Category, amount int64, date time.Time, portions ...Portion) *Expense {
+	t.Helper()
+	details := validDetails()
+	details.Category = category
+	details.Amount = amount
+	details.Date = date
+	e, err := NewExpense(uuid.New(), alice, details, SplitEqual, portions)
+	require.NoError(t, err)
+	require.NoError(t, e.ApplyRate(currency.Identity(e.Currency, e.Date)))
+	return e
+}
+
+func day(d int) time.Time {
+	return time.Date(2026, 5, d, 12, 0, 0, 0, time.UTC)
+}
+
+func TestNewBudgetPlan(t *testing.T) {
+	tripID := uuid.New()
+	now := time.Now()
+
+	lines, err := NewBudgetPlan(tripID, nil, "EUR", []BudgetLineInput{
+		{Category: CategoryFood, Amount: 30000},
+		{Category: CategoryTransport, Amount: 10000, AlertPercent: 50},
+	}, nil, now)
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	assert.Equal(t, DefaultAlertPercent, lines[0].AlertPercent)
+	assert.Equal(t, 50, lines[1].AlertPercent)
+	assert.Equal(t, "EUR", lines[0].Currency)
+	assert.False(t, lines[0].IsPersonal())
+
+	tests := []struct {
+		name   string
+		inputs []BudgetLineInput
+		err    error
+	}{
+		{"unknown category", []BudgetLineInput{{Category: "casino", Amount: 100}}, ErrInvalidCategory},
+		{"duplicate category", []BudgetLineInput{{Category: CategoryFood, Amount: 100}, {Category: CategoryFood, Amount: 200}}, ErrInvalidBudget},
+		{"zero amount", []BudgetLineInput{{Category: CategoryFood}}, ErrInvalidBudget},
+		{"alert over 100", []BudgetLineInput{{Category: CategoryFood, Amount: 100, AlertPercent: 120}}, ErrInvalidBudget},
+		{"negative alert", []BudgetLineInput{{Category: CategoryFood, Amount: 100, AlertPercent: -5}}, ErrInvalidBudget},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBudgetPlan(tripID, nil, "EUR", tt.inputs, nil, now)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestNewBudgetPlan_KeepsAlertLevelOfUnchangedLines(t *testing.T) {
+	tripID := uuid.New()
+	current, err := NewBudgetPlan(tripID, &alice, "EUR", []BudgetLineInput{
+		{Category: CategoryFood, Amount: 30000},
+		{Category: CategoryShopping, Amount: 5000},
+	}, nil, time.Now())
+	require.NoError(t, err)
+	current[0].AlertLevel = AlertThreshold
+	current[1].AlertLevel = AlertExceeded
+
+	lines, err := NewBudgetPlan(tripID, &alice, "EUR", []BudgetLineInput{
+		{Category: CategoryFood, Amount: 30000},
+		{Category: CategoryShopping, Amount: 8000},
+	}, current, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, current[0].ID, lines[0].ID)
+	assert.Equal(t, AlertThreshold, lines[0].AlertLevel)
+	assert.Equal(t, current[1].ID, lines[1].ID)
+	assert.Equal(t, AlertNone, lines[1].AlertLevel)
+}
+
+func TestBudgetLine_UpdateAlert(t *testing.T) {
+	line := &BudgetLine{Category: CategoryFood, Amount: 10000, Currency: "EUR", AlertPercent: 80}
+
+	steps := []struct {
+		spent   int64
+		level   AlertLevel
+		changed bool
+		alert   bool
+	}{
+		{5000, AlertNone, false, false},
+		{8000, AlertThreshold, true, true},
+		{9000, AlertThreshold, false, false},
+		{10000, AlertThreshold, false, false},
+		{10001, AlertExceeded, true, true},
+		{9500, AlertThreshold, true, false},
+		{12000, AlertExceeded, true, true},
+		{1000, AlertNone, true, false},
+		{8500, AlertThreshold, true, true},
+	}
+	for _, step := range steps {
+		changed, alert := line.UpdateAlert(step.spent)
+		assert.Equal(t, step.level, line.AlertLevel, "spent %d", step.spent)
+		assert.Equal(t, step.changed, changed, "spent %d", step.spent)
+		assert.Equal(t, step.alert, alert, "spent %d", step.spent)
+	}
+}
+
+func TestBudgetLine_Spent(t *testing.T) {
+	dinner := budgetExpense(t, CategoryFood, 9000, day(3), Portion{UserID: alice}, Portion{UserID: bob}, Portion{UserID: carol})
+	lunch := budgetExpense(t, CategoryFood, 2000, day(4), Portion{UserID: bob})
+	taxi := budgetExpense(t, CategoryTransport, 4000, day(4), Portion{UserID: alice})
+	expenses := []*Expense{dinner, lunch, taxi}
+
+	tripLine := &BudgetLine{Category: CategoryFood, Amount: 20000, Currency: "EUR"}
+	assert.Equal(t, int64(11000), tripLine.Spent(expenses))
+
+	personal := &BudgetLine{UserID: &bob, Category: CategoryFood, Amount: 5000, Currency: "EUR"}
+	assert.Equal(t, int64(5000), personal.Spent(expenses))
+
+	otherCurrency := &BudgetLine{Category: CategoryFood, Amount: 20000, Currency: "GBP"}
+	assert.Zero(t, otherCurrency.Spent(expenses))
+}
+
+func TestPlanOf(t *testing.T) {
+	tripLine := &BudgetLine{Category: CategoryFood}
+	aliceLine := &BudgetLine{UserID: &alice, Category: CategoryFood}
+	bobLine := &BudgetLine{UserID: &bob, Category: CategoryFood}
+	lines := []*BudgetLine{tripLine, aliceLine, bobLine}
+
+	assert.Equal(t, []*BudgetLine{tripLine}, PlanOf(lines, nil))
+	assert.Equal(t, []*BudgetLine{aliceLine}, PlanOf(lines, &alice))
+	assert.Empty(t, PlanOf(lines, &carol))
+}
+
+func TestCompareBudget(t *testing.T) {
+	tripID := uuid.New()
+	plan, err := NewBudgetPlan(tripID, nil, "EUR", []BudgetLineInput{
+		{Category: CategoryFood, Amount: 10000},
+		{Category: CategoryTransport, Amount: 5000},
+	}, nil, time.Now())
+	require.NoError(t, err)
+
+	expenses := []*Expense{
+		budgetExpense(t, CategoryTransport, 6000, day(1), Portion{UserID: alice}),
+		budgetExpense(t, CategoryFood, 3000, day(3), Portion{UserID: alice}),
+		budgetExpense(t, CategoryFood, 5000, day(4), Portion{UserID: bob}),
+		budgetExpense(t, CategoryShopping, 1500, day(4), Portion{UserID: alice}),
+		budgetExpense(t, CategoryFood, 1000, day(9), Portion{UserID: alice}),
+	}
+
+	comparison := CompareBudget(tripID, nil, "EUR", plan, expenses, day(3), day(6), day(4))
+
+	assert.Equal(t, int64(15000), comparison.Planned)
+	assert.Equal(t, int64(16500), comparison.Spent)
+	assert.Equal(t, int64(-1500), comparison.Remaining)
+	assert.Equal(t, []CategoryComparison{
+		{Category: CategoryFood, Planned: 10000, Spent: 9000, Remaining: 1000, PercentUsed: 90, Status: AlertThreshold},
+		{Category: CategoryTransport, Planned: 5000, Spent: 6000, Remaining: -1000, PercentUsed: 120, Status: AlertExceeded},
+		{Category: CategoryShopping, Spent: 1500, Remaining: -1500},
+	}, comparison.Categories)
+
+	require.Len(t, comparison.BurnDown, 4)
+	assert.Equal(t, time.Date(2026, 5, 3, 0, 0, 0, 0, time.UTC), comparison.BurnDown[0].Date)
+	ideal := make([]int64, 0, 4)
+	for _, d := range comparison.BurnDown {
+		ideal = append(ideal, d.Ideal)
+	}
+	assert.Equal(t, []int64{11250, 7500, 3750, 0}, ideal)
+
+	// Spending before the trip counts on its first day, after it on its last
+	require.NotNil(t, comparison.BurnDown[0].Remaining)
+	assert.Equal(t, int64(6000), *comparison.BurnDown[0].Remaining)
+	require.NotNil(t, comparison.BurnDown[1].Remaining)
+	assert.Equal(t, int64(-500), *comparison.BurnDown[1].Remaining)
+	assert.Nil(t, comparison.BurnDown[2].Remaining)
+	assert.Nil(t, comparison.BurnDown[3].Remaining)
+
+	after := CompareBudget(tripID, nil, "EUR", plan, expenses, day(3), day(6), day(10))
+	require.NotNil(t, after.BurnDown[3].Remaining)
+	assert.Equal(t, int64(-1500), *after.BurnDown[3].Remaining)
+}
+
+func TestCompareBudget_Personal(t *testing.T) {
+	tripID := uuid.New()
+	plan, err := NewBudgetPlan(tripID, &bob, "EUR", []BudgetLineInput{{Category: CategoryFood, Amount: 4000}}, nil, time.Now())
+	require.NoError(t, err)
+
+	expenses := []*Expense{
+		budgetExpense(t, CategoryFood, 9000, day(3), Portion{UserID: alice}, Portion{UserID: bob}, Portion{UserID: carol}),
+		budgetExpense(t, CategoryTransport, 2000, day(3), Portion{UserID: alice}),
+	}
+
+	comparison := CompareBudget(tripID, &bob, "EUR", plan, expenses, day(3), day(3), day(3))
+	assert.Equal(t, &bob, comparison.UserID)
+	assert.Equal(t, []CategoryComparison{
+		{Category: CategoryFood, Planned: 4000, Spent: 3000, Remaining: 1000, PercentUsed: 75, Status: AlertNone},
+	}, comparison.Categories)
+	require.Len(t, comparison.BurnDown, 1)
+	assert.Equal(t, int64(1000), *comparison.BurnDown[0].Remaining)
+}
+
+func TestAlertLevel_MarshalJSON(t *testing.T) {
+	data, err := AlertExceeded.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, `"exceeded"`, string(data))
+	assert.Equal(t, "ok", AlertNone.String())
+	assert.Equal(t, "warning", AlertThreshold.String())
+}
@@ -0,0 +1,305 @@
+package expense
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"jointrip/internal/domain/currency"
+
+	"github.com/google/uuid"
+)
+
+// Expense limits
+const (
+	MaxTitleLength = 200
+	MaxNotesLength = 2000
+	// MaxAmount caps an expense so that split arithmetic on minor units can
+	// never overflow
+	MaxAmount int64 = 1_000_000_000_000
+)
+
+// Category classifies an expense for analysis and reporting
+type Category string
+
+const (
+	CategoryFood          Category = "food"
+	CategoryTransport     Category = "transport"
+	CategoryAccommodation Category = "accommodation"
+	CategoryActivities    Category = "activities"
+	CategoryShopping      Category = "shopping"
+	CategoryOther         Category = "other"
+)
+
+// Categories returns every expense category
+func Categories() []Category {
+	return []Category{
+		CategoryFood,
+		CategoryTransport,
+		CategoryAccommodation,
+		CategoryActivities,
+		CategoryShopping,
+		CategoryOther,
+	}
+}
+
+// IsValid returns true if the category is known
+func (c Category) IsValid() bool {
+	for _, category := range Categories() {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Expense is a cost one trip member paid on behalf of others. Amounts are
+// integer minor units of the currency (cents for EUR), never floats.
+type Expense struct {
+	ID          uuid.UUID   `json:"id"`
+	TripID      uuid.UUID   `json:"trip_id"`
+	PayerID     uuid.UUID   `json:"payer_id"`
+	Title       string      `json:"title"`
+	Amount      int64       `json:"amount"`
+	Currency    string      `json:"currency"`
+	Category    Category    `json:"category"`
+	Date        time.Time   `json:"date"`
+	SplitMethod SplitMethod `json:"split_method"`
+	Notes       string      `json:"notes"`
+	// BaseCurrency is the trip's currency when the expense was recorded, and
+	// BaseAmount the amount in it at the snapshotted ExchangeRate
+	BaseCurrency string        `json:"base_currency"`
+	BaseAmount   int64         `json:"base_amount"`
+	ExchangeRate currency.Rate `json:"exchange_rate"`
+	CreatedBy    uuid.UUID     `json:"created_by"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	Shares       []*Share      `json:"shares"`
+}
+
+// Share is the part of an expense one user owes the payer
+type Share struct {
+	ID             uuid.UUID `json:"id"`
+	ExpenseID      uuid.UUID `json:"expense_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	AmountOwed     int64     `json:"amount_owed"`
+	BaseAmountOwed int64     `json:"base_amount_owed"`
+	// Weight is the value the split was entered with: the exact amount,
+	// the percentage in basis points or the number of shares (1 for equal splits)
+	Weight    int64      `json:"weight"`
+	IsSettled bool       `json:"is_settled"`
+	SettledAt *time.Time `json:"settled_at,omitempty"`
+	Notes     string     `json:"notes"`
+}
+
+// Details holds the editable fields of an expense
+type Details struct {
+	PayerID  uuid.UUID
+	Title    string
+	Amount   int64
+	Currency string
+	Category Category
+	Date     time.Time
+	Notes    string
+}
+
+// NewExpense records an expense on a trip and splits it among the given portions
+func NewExpense(tripID, createdBy uuid.UUID, details Details, method SplitMethod, portions []Portion) (*Expense, error) {
+	if tripID == uuid.Nil {
+		return nil, errors.New("trip ID is required")
+	}
+	if createdBy == uuid.Nil {
+		return nil, errors.New("creator ID is required")
+	}
+
+	now := time.Now()
+	e := &Expense{
+		ID:        uuid.New(),
+		TripID:    tripID,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+	}
+
+	if err := e.Update(details, method, portions); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Update changes the expense and recomputes its split. Users who keep a share
+// keep its ID. Expenses with settled shares can no longer be changed.
+func (e *Expense) Update(details Details, method SplitMethod, portions []Portion) error {
+	if e.HasSettledShares() {
+		return ErrExpenseSettled
+	}
+
+	details.Title = strings.TrimSpace(details.Title)
+	details.Notes = strings.TrimSpace(details.Notes)
+	if details.PayerID == uuid.Nil || details.Title == "" || details.Date.IsZero() ||
+		utf8.RuneCountInString(details.Title) > MaxTitleLength ||
+		utf8.RuneCountInString(details.Notes) > MaxNotesLength {
+		return ErrInvalidExpense
+	}
+	if details.Amount <= 0 || details.Amount > MaxAmount {
+		return ErrInvalidAmount
+	}
+	if !details.Category.IsValid() {
+		return ErrInvalidCategory
+	}
+
+	code, err := currency.Normalize(details.Currency)
+	if err != nil {
+		return err
+	}
+
+	owed, err := Split(details.Amount, method, portions)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[uuid.UUID]*Share, len(e.Shares))
+	for _, share := range e.Shares {
+		existing[share.UserID] = share
+	}
+
+	shares := make([]*Share, len(portions))
+	for i, portion := range portions {
+		share := &Share{
+			ID:        uuid.New(),
+			ExpenseID: e.ID,
+			UserID:    portion.UserID,
+		}
+		if previous, ok := existing[portion.UserID]; ok {
+			share.ID = previous.ID
+			share.Notes = previous.Notes
+		}
+		share.AmountOwed = owed[i]
+		share.Weight = portion.weight(method)
+		shares[i] = share
+	}
+
+	e.PayerID = details.PayerID
+	e.Title = details.Title
+	e.Amount = details.Amount
+	e.Currency = code
+	e.Category = details.Category
+	e.Date = truncateToDate(details.Date)
+	e.Notes = details.Notes
+	e.SplitMethod = method
+	e.Shares = shares
+	e.UpdatedAt = time.Now()
+	return nil
+}
+
+// ApplyRate snapshots the rate from the expense currency into the trip's
+// base currency and converts the amount and shares with it. Share amounts are
+// apportioned from the converted total, so they still add up exactly.
+func (e *Expense) ApplyRate(rate currency.Rate) error {
+	if rate.Base != e.Currency {
+		return currency.ErrRateNotFound
+	}
+
+	baseAmount, err := rate.Convert(e.Amount)
+	if err != nil {
+		return err
+	}
+
+	weights := make([]int64, len(e.Shares))
+	ids := make([]uuid.UUID, len(e.Shares))
+	for i, share := range e.Shares {
+		weights[i] = share.AmountOwed
+		ids[i] = share.UserID
+	}
+	for i, owed := range apportion(baseAmount, weights, ids) {
+		e.Shares[i].BaseAmountOwed = owed
+	}
+
+	e.BaseCurrency = rate.Quote
+	e.BaseAmount = baseAmount
+	e.ExchangeRate = rate
+	return nil
+}
+
+// Share returns the share with the given ID
+func (e *Expense) Share(shareID uuid.UUID) (*Share, error) {
+	for _, share := range e.Shares {
+		if share.ID == shareID {
+			return share, nil
+		}
+	}
+	return nil, ErrShareNotFound
+}
+
+// SettleShare records that the share's user paid the payer back. The payer's
+// own share has nothing to settle.
+func (e *Expense) SettleShare(share *Share, notes string, at time.Time) error {
+	if share.UserID == e.PayerID || share.AmountOwed == 0 {
+		return ErrNothingToSettle
+	}
+	if share.IsSettled {
+		return ErrAlreadySettled
+	}
+
+	notes = strings.TrimSpace(notes)
+	if utf8.RuneCountInString(notes) > MaxNotesLength {
+		return ErrInvalidSettlement
+	}
+
+	share.IsSettled = true
+	share.SettledAt = &at
+	share.Notes = notes
+	return nil
+}
+
+// UnsettleShare reopens a share that was settled by mistake
+func (e *Expense) UnsettleShare(share *Share) error {
+	if !share.IsSettled {
+		return ErrNotSettled
+	}
+
+	share.IsSettled = false
+	share.SettledAt = nil
+	share.Notes = ""
+	return nil
+}
+
+// CanSettle returns true if the user may record or undo a settlement of the
+// share: the payer who got the money, the user who owed it and the trip organizer
+func (e *Expense) CanSettle(share *Share, userID, tripCreatorID uuid.UUID) bool {
+	return userID == e.PayerID || userID == share.UserID || userID == tripCreatorID
+}
+
+// Participants returns the payer and everyone who owes a share
+func (e *Expense) Participants() []uuid.UUID {
+	ids := []uuid.UUID{e.PayerID}
+	for _, share := range e.Shares {
+		if share.UserID != e.PayerID {
+			ids = append(ids, share.UserID)
+		}
+	}
+	return ids
+}
+
+// HasSettledShares returns true if any share of the expense has been settled
+func (e *Expense) HasSettledShares() bool {
+	for _, share := range e.Shares {
+		if share.IsSettled {
+			return true
+		}
+	}
+	return false
+}
+
+// CanModify returns true if the user may change or delete the expense: the
+// member who recorded it, the payer and the trip organizer
+func (e *Expense) CanModify(userID, tripCreatorID uuid.UUID) bool {
+	return userID == e.CreatedBy || userID == e.PayerID || userID == tripCreatorID
+}
+
+// truncateToDate drops the time of day, keeping the calendar date
+func truncateToDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,136 @@
+package expense
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"jointrip/internal/domain/currency"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validDetails() Details {
+	return Details{
+		PayerID:  alice,
+		Title:    " Dinner in Lisbon ",
+		Amount:   9000,
+		Currency: "eur",
+		Category: CategoryFood,
+		Date:     time.Date(2026, 5, 3, 21, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestNewExpense(t *testing.T) {
+	tripID := uuid.New()
+
+	e, err := NewExpense(tripID, alice, validDetails(), SplitEqual, []Portion{{UserID: alice}, {UserID: bob}, {UserID: carol}})
+	require.NoError(t, err)
+	assert.Equal(t, "Dinner in Lisbon", e.Title)
+	assert.Equal(t, "EUR", e.Currency)
+	assert.Equal(t, time.Date(2026, 5, 3, 0, 0, 0, 0, time.UTC), e.Date)
+	require.Len(t, e.Shares, 3)
+	for _, share := range e.Shares {
+		assert.Equal(t, e.ID, share.ExpenseID)
+		assert.Equal(t, int64(3000), share.AmountOwed)
+		assert.Equal(t, int64(1), share.Weight)
+	}
+	assert.Equal(t, []uuid.UUID{alice, bob, carol}, e.Participants())
+
+	tests := []struct {
+		name     string
+		modify   func(d *Details)
+		expected error
+	}{
+		{"no payer", func(d *Details) { d.PayerID = uuid.Nil }, ErrInvalidExpense},
+		{"no title", func(d *Details) { d.Title = "  " }, ErrInvalidExpense},
+		{"long title", func(d *Details) { d.Title = strings.Repeat("a", MaxTitleLength+1) }, ErrInvalidExpense},
+		{"no date", func(d *Details) { d.Date = time.Time{} }, ErrInvalidExpense},
+		{"zero amount", func(d *Details) { d.Amount = 0 }, ErrInvalidAmount},
+		{"negative amount", func(d *Details) { d.Amount = -100 }, ErrInvalidAmount},
+		{"huge amount", func(d *Details) { d.Amount = MaxAmount + 1 }, ErrInvalidAmount},
+		{"bad category", func(d *Details) { d.Category = "souvenirs" }, ErrInvalidCategory},
+		{"bad currency", func(d *Details) { d.Currency = "EURO" }, currency.ErrInvalidCurrency},
+		{"currency digits", func(d *Details) { d.Currency = "E1R" }, currency.ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := validDetails()
+			tt.modify(&details)
+			_, err := NewExpense(tripID, alice, details, SplitEqual, []Portion{{UserID: alice}})
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestExpense_Update(t *testing.T) {
+	e, err := NewExpense(uuid.New(), alice, validDetails(), SplitEqual, []Portion{{UserID: alice}, {UserID: bob}})
+	require.NoError(t, err)
+	bobShare := e.Shares[1]
+
+	details := validDetails()
+	details.PayerID = bob
+	details.Amount = 10000
+	require.NoError(t, e.Update(details, SplitShares, []Portion{{UserID: bob, Value: 3}, {UserID: carol, Value: 1}}))
+
+	assert.Equal(t, bob, e.PayerID)
+	assert.Equal(t, SplitShares, e.SplitMethod)
+	require.Len(t, e.Shares, 2)
+	assert.Equal(t, bobShare.ID, e.Shares[0].ID, "kept participants keep their share")
+	assert.Equal(t, int64(7500), e.Shares[0].AmountOwed)
+	assert.Equal(t, int64(3), e.Shares[0].Weight)
+	assert.Equal(t, int64(2500), e.Shares[1].AmountOwed)
+	assert.Equal(t, []uuid.UUID{bob, carol}, e.Participants())
+
+	assert.ErrorIs(t, e.Update(details, SplitExact, []Portion{{UserID: bob, Value: 1}}), ErrSplitMismatch)
+	assert.Equal(t, SplitShares, e.SplitMethod, "a failed update changes nothing")
+
+	now := time.Now()
+	e.Shares[1].IsSettled = true
+	e.Shares[1].SettledAt = &now
+	assert.True(t, e.HasSettledShares())
+	assert.ErrorIs(t, e.Update(details, SplitEqual, []Portion{{UserID: bob}}), ErrExpenseSettled)
+}
+
+func TestExpense_CanModify(t *testing.T) {
+	organizer := uuid.New()
+	details := validDetails()
+	details.PayerID = bob
+	e, err := NewExpense(uuid.New(), alice, details, SplitEqual, []Portion{{UserID: bob}, {UserID: carol}})
+	require.NoError(t, err)
+
+	assert.True(t, e.CanModify(alice, organizer))
+	assert.True(t, e.CanModify(bob, organizer))
+	assert.True(t, e.CanModify(organizer, organizer))
+	assert.False(t, e.CanModify(carol, organizer))
+}
+
+func TestExpense_ApplyRate(t *testing.T) {
+	details := validDetails()
+	details.Currency = "USD"
+	details.Amount = 1000
+	e, err := NewExpense(uuid.New(), alice, details, SplitEqual, []Portion{{UserID: alice}, {UserID: bob}, {UserID: carol}})
+	require.NoError(t, err)
+
+	rate := currency.Rate{Base: "USD", Quote: "JPY", Value: "150.05", AsOf: e.Date, Source: "ecb"}
+	require.NoError(t, e.ApplyRate(rate))
+	assert.Equal(t, "JPY", e.BaseCurrency)
+	assert.Equal(t, int64(1501), e.BaseAmount)
+	assert.Equal(t, rate, e.ExchangeRate)
+
+	var sum int64
+	for _, share := range e.Shares {
+		sum += share.BaseAmountOwed
+	}
+	assert.Equal(t, e.BaseAmount, sum, "converted shares add up to the converted amount")
+	assert.Equal(t, []int64{501, 500, 500}, []int64{e.Shares[0].BaseAmountOwed, e.Shares[1].BaseAmountOwed, e.Shares[2].BaseAmountOwed})
+
+	assert.ErrorIs(t, e.ApplyRate(currency.Rate{Base: "EUR", Quote: "JPY", Value: "160"}), currency.ErrRateNotFound)
+
+	require.NoError(t, e.ApplyRate(currency.Identity("USD", e.Date)))
+	assert.Equal(t, int64(1000), e.BaseAmount)
+	assert.Equal(t, int64(334), e.Shares[0].BaseAmountOwed)
+}
@@ -0,0 +1,19 @@
+package expense
+
+import "time"
+
+// Expense event names
+const (
+	EventBudgetAlerted = "expense.budget_alerted"
+)
+
+// BudgetAlerted is published when spending in a budget line's category
+// reaches its alert percentage or goes over the planned amount
+type BudgetAlerted struct {
+	Line       *BudgetLine
+	Spent      int64
+	OccurredAt time.Time
+}
+
+// EventName implements event.Event
+func (BudgetAlerted) EventName() string { return EventBudgetAlerted }
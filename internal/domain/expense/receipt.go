@@ -0,0 +1,117 @@
+package expense
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"jointrip/internal/domain/blob"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxReceiptSize is the largest receipt file accepted, in bytes
+	MaxReceiptSize = 10 << 20
+	// MaxReceiptsPerExpense limits how many files can be attached to one expense
+	MaxReceiptsPerExpense = 5
+	// MaxReceiptFilenameLength is the longest original file name kept
+	MaxReceiptFilenameLength = 255
+)
+
+// receiptContentTypes are the file types receipts can be, with the
+// extension given to files without one
+var receiptContentTypes = map[string]string{
+	blob.ContentTypeJPEG: ".jpg",
+	blob.ContentTypePNG:  ".png",
+	blob.ContentTypePDF:  ".pdf",
+}
+
+// Receipt is a scan or photo of a receipt attached to an expense. The file
+// and its thumbnail live in blob storage under the keys recorded here.
+type Receipt struct {
+	ID          uuid.UUID `json:"id"`
+	ExpenseID   uuid.UUID `json:"expense_id"`
+	UploadedBy  uuid.UUID `json:"uploaded_by"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	// StorageKey holds the file and ThumbnailKey, for images, a JPEG preview
+	StorageKey   string    `json:"-"`
+	ThumbnailKey string    `json:"-"`
+	HasThumbnail bool      `json:"has_thumbnail"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewReceipt creates a receipt for a file attached to an expense. The type is
+// detected from the first bytes of the file, whatever its name or the
+// uploader claim.
+func NewReceipt(e *Expense, uploadedBy uuid.UUID, filename string, head []byte, size int64) (*Receipt, error) {
+	if size <= 0 {
+		return nil, ErrInvalidReceipt
+	}
+	if size > MaxReceiptSize {
+		return nil, ErrReceiptTooLarge
+	}
+
+	contentType := blob.DetectContentType(head)
+	extension, ok := receiptContentTypes[contentType]
+	if !ok {
+		return nil, ErrInvalidReceipt
+	}
+
+	id := uuid.New()
+	r := &Receipt{
+		ID:          id,
+		ExpenseID:   e.ID,
+		UploadedBy:  uploadedBy,
+		Filename:    cleanFilename(filename, "receipt"+extension),
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  fmt.Sprintf("receipts/%s/%s/%s", e.TripID, e.ID, id),
+		CreatedAt:   time.Now(),
+	}
+	return r, nil
+}
+
+// IsImage returns true if the receipt is a photo rather than a document
+func (r *Receipt) IsImage() bool {
+	return strings.HasPrefix(r.ContentType, "image/")
+}
+
+// SetThumbnail records that a preview of the receipt was stored
+func (r *Receipt) SetThumbnail() {
+	r.ThumbnailKey = r.StorageKey + "-thumb"
+	r.HasThumbnail = true
+}
+
+// CanDelete returns true if the user may remove the receipt: whoever
+// uploaded it and whoever may change the expense
+func (r *Receipt) CanDelete(e *Expense, userID, tripCreatorID uuid.UUID) bool {
+	return userID == r.UploadedBy || e.CanModify(userID, tripCreatorID)
+}
+
+// cleanFilename keeps the base name of an uploaded file without control
+// characters, cut to MaxReceiptFilenameLength, or fallback if nothing is left
+func cleanFilename(filename, fallback string) string {
+	filename = path.Base(strings.ReplaceAll(filename, `\`, "/"))
+	filename = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, filename))
+
+	for len(filename) > MaxReceiptFilenameLength {
+		_, n := utf8.DecodeLastRuneInString(filename)
+		filename = filename[:len(filename)-n]
+	}
+
+	if filename == "" || filename == "." || filename == "/" {
+		return fallback
+	}
+	return filename
+}
@@ -0,0 +1,87 @@
+package expense
+
+import (
+	"strings"
+	"testing"
+
+	"jointrip/internal/domain/blob"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReceipt(t *testing.T) {
+	e := mustExpense(t, alice, 9000, "EUR", SplitEqual, Portion{UserID: alice}, Portion{UserID: bob})
+
+	r, err := NewReceipt(e, bob, "C:\\Users\\bob\\Scan 01.PDF", []byte("%PDF-1.7\n"), 2048)
+	require.NoError(t, err)
+	assert.Equal(t, e.ID, r.ExpenseID)
+	assert.Equal(t, bob, r.UploadedBy)
+	assert.Equal(t, "Scan 01.PDF", r.Filename)
+	assert.Equal(t, blob.ContentTypePDF, r.ContentType)
+	assert.Equal(t, "receipts/"+e.TripID.String()+"/"+e.ID.String()+"/"+r.ID.String(), r.StorageKey)
+	assert.True(t, blob.ValidKey(r.StorageKey))
+	assert.False(t, r.IsImage())
+	assert.False(t, r.HasThumbnail)
+
+	// The type comes from the content, not the name
+	r, err = NewReceipt(e, bob, "receipt.pdf", []byte("\xff\xd8\xff\xe0"), 2048)
+	require.NoError(t, err)
+	assert.Equal(t, blob.ContentTypeJPEG, r.ContentType)
+	assert.True(t, r.IsImage())
+	r.SetThumbnail()
+	assert.True(t, r.HasThumbnail)
+	assert.True(t, blob.ValidKey(r.ThumbnailKey))
+
+	tests := []struct {
+		name     string
+		head     string
+		size     int64
+		expected error
+	}{
+		{"empty", "%PDF-1.7", 0, ErrInvalidReceipt},
+		{"too large", "%PDF-1.7", MaxReceiptSize + 1, ErrReceiptTooLarge},
+		{"html", "<html><script>", 100, ErrInvalidReceipt},
+		{"gif", "GIF89a", 100, ErrInvalidReceipt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReceipt(e, bob, "receipt", []byte(tt.head), tt.size)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestCleanFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		expected string
+	}{
+		{"receipt.jpg", "receipt.jpg"},
+		{"../../etc/passwd", "passwd"},
+		{"dir\\file.png", "file.png"},
+		{"\"quoted\"\n.pdf", "quoted.pdf"},
+		{"", "receipt.pdf"},
+		{"/", "receipt.pdf"},
+		{"  ", "receipt.pdf"},
+		{strings.Repeat("é", 200), strings.Repeat("é", 127)},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, cleanFilename(tt.filename, "receipt.pdf"), tt.filename)
+	}
+}
+
+func TestReceipt_CanDelete(t *testing.T) {
+	organizer := uuid.New()
+	e := mustExpense(t, alice, 9000, "EUR", SplitEqual, Portion{UserID: alice}, Portion{UserID: bob}, Portion{UserID: carol})
+	r, err := NewReceipt(e, bob, "receipt.pdf", []byte("%PDF-1.7"), 10)
+	require.NoError(t, err)
+
+	assert.True(t, r.CanDelete(e, bob, organizer))
+	assert.True(t, r.CanDelete(e, alice, organizer))
+	assert.True(t, r.CanDelete(e, organizer, organizer))
+	assert.False(t, r.CanDelete(e, carol, organizer))
+}
@@ -0,0 +1,274 @@
+package expense
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"jointrip/internal/domain/currency"
+
+	"github.com/google/uuid"
+)
+
+// Frequency is how often a recurring expense comes back
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "daily"
+	FrequencyWeekly  Frequency = "weekly"
+	FrequencyMonthly Frequency = "monthly"
+)
+
+// IsValid returns true if the frequency is known
+func (f Frequency) IsValid() bool {
+	switch f {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+		return true
+	}
+	return false
+}
+
+// MaxRecurringDays caps how long a recurring expense can run
+const MaxRecurringDays = 366
+
+// RecurringExpense is a cost that comes back every period of a trip, such as
+// nightly rent or daily car hire. Each period is recorded as an expense once
+// it is over, split among the members by the nights they stayed during it.
+// StartDate is the first night charged and EndDate the last one.
+type RecurringExpense struct {
+	ID        uuid.UUID `json:"id"`
+	TripID    uuid.UUID `json:"trip_id"`
+	PayerID   uuid.UUID `json:"payer_id"`
+	Title     string    `json:"title"`
+	Amount    int64     `json:"amount"`
+	Currency  string    `json:"currency"`
+	Category  Category  `json:"category"`
+	Notes     string    `json:"notes"`
+	Frequency Frequency `json:"frequency"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	// NextDate is the first night of the next period to record
+	NextDate  time.Time `json:"next_date"`
+	CreatedBy uuid.UUID `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RecurringDetails holds the editable fields of a recurring expense. Amount
+// is the cost of one full period.
+type RecurringDetails struct {
+	PayerID   uuid.UUID
+	Title     string
+	Amount    int64
+	Currency  string
+	Category  Category
+	Notes     string
+	Frequency Frequency
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// NewRecurringExpense creates a recurring expense on a trip
+func NewRecurringExpense(tripID, createdBy uuid.UUID, details RecurringDetails) (*RecurringExpense, error) {
+	if tripID == uuid.Nil || createdBy == uuid.Nil {
+		return nil, ErrInvalidRecurringExpense
+	}
+
+	now := time.Now()
+	r := &RecurringExpense{
+		ID:        uuid.New(),
+		TripID:    tripID,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+	}
+
+	if err := r.Update(details); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Update changes the recurring expense. Once a period was recorded, the
+// start date and frequency are fixed and the end date cannot move before
+// the nights already recorded.
+func (r *RecurringExpense) Update(details RecurringDetails) error {
+	details.Title = strings.TrimSpace(details.Title)
+	details.Notes = strings.TrimSpace(details.Notes)
+	details.StartDate = truncateToDate(details.StartDate)
+	details.EndDate = truncateToDate(details.EndDate)
+	if details.PayerID == uuid.Nil || details.Title == "" ||
+		utf8.RuneCountInString(details.Title) > MaxTitleLength ||
+		utf8.RuneCountInString(details.Notes) > MaxNotesLength ||
+		!details.Frequency.IsValid() ||
+		details.StartDate.IsZero() || details.EndDate.Before(details.StartDate) ||
+		details.EndDate.Sub(details.StartDate) >= MaxRecurringDays*24*time.Hour {
+		return ErrInvalidRecurringExpense
+	}
+	if details.Amount <= 0 || details.Amount > MaxAmount {
+		return ErrInvalidAmount
+	}
+	if !details.Category.IsValid() {
+		return ErrInvalidCategory
+	}
+
+	code, err := currency.Normalize(details.Currency)
+	if err != nil {
+		return err
+	}
+
+	if r.HasStarted() {
+		if !details.StartDate.Equal(r.StartDate) || details.Frequency != r.Frequency ||
+			details.EndDate.Before(r.NextDate.AddDate(0, 0, -1)) {
+			return ErrRecurringStarted
+		}
+	} else {
+		r.NextDate = details.StartDate
+	}
+
+	r.PayerID = details.PayerID
+	r.Title = details.Title
+	r.Amount = details.Amount
+	r.Currency = code
+	r.Category = details.Category
+	r.Notes = details.Notes
+	r.Frequency = details.Frequency
+	r.StartDate = details.StartDate
+	r.EndDate = details.EndDate
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// HasStarted returns true once a period was recorded
+func (r *RecurringExpense) HasStarted() bool {
+	return !r.NextDate.IsZero() && r.NextDate.After(r.StartDate)
+}
+
+// IsFinished returns true once every period was recorded
+func (r *RecurringExpense) IsFinished() bool {
+	return r.NextDate.After(r.EndDate)
+}
+
+// IsDue returns true if the next period is over by the given day, so it can
+// be recorded
+func (r *RecurringExpense) IsDue(today time.Time) bool {
+	if r.IsFinished() {
+		return false
+	}
+	_, to := r.period()
+	return !to.After(truncateToDate(today))
+}
+
+// period returns the nights of the next period, from the first one up to
+// but excluding to. The last period ends early with the end date.
+func (r *RecurringExpense) period() (from, to time.Time) {
+	from = r.NextDate
+	to = r.periodEnd(from)
+	if last := r.EndDate.AddDate(0, 0, 1); to.After(last) {
+		to = last
+	}
+	return from, to
+}
+
+// periodEnd returns the first night after the full period starting on from.
+// Monthly periods keep the day of the month of the start date, falling back
+// to the last day of shorter months.
+func (r *RecurringExpense) periodEnd(from time.Time) time.Time {
+	switch r.Frequency {
+	case FrequencyWeekly:
+		return from.AddDate(0, 0, 7)
+	case FrequencyMonthly:
+		months := (from.Year()-r.StartDate.Year())*12 + int(from.Month()-r.StartDate.Month()) + 1
+		return addMonths(r.StartDate, months)
+	default:
+		return from.AddDate(0, 0, 1)
+	}
+}
+
+// Stay is the nights a member spends on a trip, from From up to but
+// excluding To. A nil To means the member is still there.
+type Stay struct {
+	UserID uuid.UUID
+	From   time.Time
+	To     *time.Time
+}
+
+// nights returns how many nights of the period the stay covers
+func (s Stay) nights(from, to time.Time) int64 {
+	if start := truncateToDate(s.From); start.After(from) {
+		from = start
+	}
+	if s.To != nil {
+		if end := truncateToDate(*s.To); end.Before(to) {
+			to = end
+		}
+	}
+	if !to.After(from) {
+		return 0
+	}
+	return int64(to.Sub(from).Hours() / 24)
+}
+
+// NextOccurrence creates the expense of the next period and moves on to the
+// following one. The cost is split among the members by the nights they
+// stayed during the period; when nobody stayed, the payer bears it. A last
+// period cut short by the end date costs its share of nights of a full one.
+func (r *RecurringExpense) NextOccurrence(stays []Stay) (*Expense, error) {
+	if r.IsFinished() {
+		return nil, ErrRecurringFinished
+	}
+
+	from, to := r.period()
+	nights := int64(to.Sub(from).Hours() / 24)
+	fullNights := int64(r.periodEnd(from).Sub(from).Hours() / 24)
+	amount := max(1, r.Amount*nights/fullNights)
+
+	stayed := make(map[uuid.UUID]int64)
+	var order []uuid.UUID
+	for _, stay := range stays {
+		n := stay.nights(from, to)
+		if n == 0 {
+			continue
+		}
+		if _, ok := stayed[stay.UserID]; !ok {
+			order = append(order, stay.UserID)
+		}
+		stayed[stay.UserID] += n
+	}
+
+	method := SplitEqual
+	portions := make([]Portion, 0, len(order))
+	for _, userID := range order {
+		if stayed[userID] != nights {
+			method = SplitShares
+		}
+		portions = append(portions, Portion{UserID: userID, Value: stayed[userID]})
+	}
+	if len(portions) == 0 {
+		portions = []Portion{{UserID: r.PayerID}}
+	}
+
+	e, err := NewExpense(r.TripID, r.CreatedBy, Details{
+		PayerID:  r.PayerID,
+		Title:    r.Title,
+		Amount:   amount,
+		Currency: r.Currency,
+		Category: r.Category,
+		Date:     from,
+		Notes:    r.Notes,
+	}, method, portions)
+	if err != nil {
+		return nil, err
+	}
+
+	r.NextDate = to
+	return e, nil
+}
+
+// addMonths adds months to a date, keeping its day of the month unless the
+// target month is shorter
+func addMonths(date time.Time, months int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(date.Day(), lastDay)-1)
+}
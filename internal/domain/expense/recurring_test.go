@@ -0,0 +1,206 @@
+package expense
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(month time.Month, d int) time.Time {
+	return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func validRecurringDetails() RecurringDetails {
+	return RecurringDetails{
+		PayerID:   alice,
+		Title:     " House rent ",
+		Amount:    70000,
+		Currency:  "eur",
+		Category:  CategoryAccommodation,
+		Frequency: FrequencyWeekly,
+		StartDate: date(6, 1),
+		EndDate:   date(6, 30),
+	}
+}
+
+func mustRecurring(t *testing.T, details RecurringDetails) *RecurringExpense {
+	t.Helper()
+	r, err := NewRecurringExpense(uuid.New(), alice, details)
+	require.NoError(t, err)
+	return r
+}
+
+func TestNewRecurringExpense(t *testing.T) {
+	r := mustRecurring(t, validRecurringDetails())
+	assert.Equal(t, "House rent", r.Title)
+	assert.Equal(t, "EUR", r.Currency)
+	assert.Equal(t, date(6, 1), r.NextDate)
+	assert.False(t, r.HasStarted())
+	assert.False(t, r.IsFinished())
+
+	tests := []struct {
+		name   string
+		modify func(*RecurringDetails)
+		err    error
+	}{
+		{"no title", func(d *RecurringDetails) { d.Title = " " }, ErrInvalidRecurringExpense},
+		{"unknown frequency", func(d *RecurringDetails) { d.Frequency = "hourly" }, ErrInvalidRecurringExpense},
+		{"ends before start", func(d *RecurringDetails) { d.EndDate = date(5, 31) }, ErrInvalidRecurringExpense},
+		{"longer than a year", func(d *RecurringDetails) { d.EndDate = d.StartDate.AddDate(1, 0, 1) }, ErrInvalidRecurringExpense},
+		{"no payer", func(d *RecurringDetails) { d.PayerID = uuid.Nil }, ErrInvalidRecurringExpense},
+		{"zero amount", func(d *RecurringDetails) { d.Amount = 0 }, ErrInvalidAmount},
+		{"unknown category", func(d *RecurringDetails) { d.Category = "casino" }, ErrInvalidCategory},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := validRecurringDetails()
+			tt.modify(&details)
+			_, err := NewRecurringExpense(uuid.New(), alice, details)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestRecurringExpense_DailyFollowsMembers(t *testing.T) {
+	details := validRecurringDetails()
+	details.Frequency = FrequencyDaily
+	details.Amount = 3000
+	details.StartDate = date(6, 1)
+	details.EndDate = date(6, 3)
+	r := mustRecurring(t, details)
+
+	left := date(6, 2)
+	stays := []Stay{
+		{UserID: alice, From: date(5, 1)},
+		{UserID: bob, From: date(5, 20), To: &left},
+		{UserID: carol, From: date(6, 2).Add(15 * time.Hour)},
+	}
+
+	assert.False(t, r.IsDue(date(6, 1)))
+	assert.True(t, r.IsDue(date(6, 2)))
+
+	first, err := r.NextOccurrence(stays)
+	require.NoError(t, err)
+	assert.Equal(t, date(6, 1), first.Date)
+	assert.Equal(t, SplitEqual, first.SplitMethod)
+	assert.Equal(t, []uuid.UUID{alice, bob}, first.Participants())
+	assert.True(t, r.HasStarted())
+
+	// Bob left and Carol arrived on the second night
+	second, err := r.NextOccurrence(stays)
+	require.NoError(t, err)
+	assert.Equal(t, date(6, 2), second.Date)
+	assert.Equal(t, []uuid.UUID{alice, carol}, second.Participants())
+	assert.Equal(t, int64(3000), second.Amount)
+
+	_, err = r.NextOccurrence(stays)
+	require.NoError(t, err)
+	assert.True(t, r.IsFinished())
+	assert.False(t, r.IsDue(date(7, 1)))
+
+	_, err = r.NextOccurrence(stays)
+	assert.ErrorIs(t, err, ErrRecurringFinished)
+}
+
+func TestRecurringExpense_WeeklyProratesByNights(t *testing.T) {
+	r := mustRecurring(t, validRecurringDetails())
+
+	left := date(6, 4)
+	stays := []Stay{
+		{UserID: alice, From: date(5, 1)},
+		{UserID: bob, From: date(5, 1), To: &left},
+		{UserID: carol, From: date(6, 5)},
+	}
+
+	assert.False(t, r.IsDue(date(6, 7)))
+	assert.True(t, r.IsDue(date(6, 8)))
+
+	week, err := r.NextOccurrence(stays)
+	require.NoError(t, err)
+	assert.Equal(t, SplitShares, week.SplitMethod)
+	assert.Equal(t, int64(70000), week.Amount)
+
+	owed := map[uuid.UUID]int64{}
+	weights := map[uuid.UUID]int64{}
+	for _, share := range week.Shares {
+		owed[share.UserID] = share.AmountOwed
+		weights[share.UserID] = share.Weight
+	}
+	// Alice stayed 7 nights, Bob 3 and Carol 3, out of 13 nights
+	assert.Equal(t, map[uuid.UUID]int64{alice: 7, bob: 3, carol: 3}, weights)
+	assert.Equal(t, int64(70000), owed[alice]+owed[bob]+owed[carol])
+	assert.InDelta(t, 37692, owed[alice], 1)
+	assert.Equal(t, date(6, 8), r.NextDate)
+
+	// The last period runs from 29 to 30 June: 2 of 7 nights
+	for !r.IsFinished() {
+		last, err := r.NextOccurrence(stays)
+		require.NoError(t, err)
+		if r.IsFinished() {
+			assert.Equal(t, date(6, 29), last.Date)
+			assert.Equal(t, int64(20000), last.Amount)
+		}
+	}
+}
+
+func TestRecurringExpense_NobodyStayedPayerBearsIt(t *testing.T) {
+	details := validRecurringDetails()
+	details.Frequency = FrequencyDaily
+	r := mustRecurring(t, details)
+
+	e, err := r.NextOccurrence(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{alice}, e.Participants())
+}
+
+func TestRecurringExpense_MonthlyKeepsDayOfMonth(t *testing.T) {
+	details := validRecurringDetails()
+	details.Frequency = FrequencyMonthly
+	details.StartDate = date(1, 31)
+	details.EndDate = date(4, 30)
+	r := mustRecurring(t, details)
+
+	stays := []Stay{{UserID: alice, From: date(1, 1)}}
+	var starts []time.Time
+	for !r.IsFinished() {
+		e, err := r.NextOccurrence(stays)
+		require.NoError(t, err)
+		starts = append(starts, e.Date)
+	}
+	assert.Equal(t, []time.Time{date(1, 31), date(2, 28), date(3, 31), date(4, 30)}, starts)
+}
+
+func TestRecurringExpense_UpdateAfterStart(t *testing.T) {
+	r := mustRecurring(t, validRecurringDetails())
+
+	// Before the first period is recorded, everything can change
+	details := validRecurringDetails()
+	details.StartDate = date(6, 2)
+	require.NoError(t, r.Update(details))
+	assert.Equal(t, date(6, 2), r.NextDate)
+
+	_, err := r.NextOccurrence(nil)
+	require.NoError(t, err)
+	require.Equal(t, date(6, 9), r.NextDate)
+
+	changed := details
+	changed.Amount = 80000
+	changed.EndDate = date(6, 8)
+	require.NoError(t, r.Update(changed))
+	assert.True(t, r.IsFinished())
+
+	moved := details
+	moved.StartDate = date(6, 3)
+	assert.ErrorIs(t, r.Update(moved), ErrRecurringStarted)
+
+	daily := details
+	daily.Frequency = FrequencyDaily
+	assert.ErrorIs(t, r.Update(daily), ErrRecurringStarted)
+
+	early := details
+	early.EndDate = date(6, 7)
+	assert.ErrorIs(t, r.Update(early), ErrRecurringStarted)
+}
@@ -0,0 +1,151 @@
+package expense
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportFormat is the file format of an expense report
+type ReportFormat string
+
+const (
+	ReportCSV ReportFormat = "csv"
+	ReportPDF ReportFormat = "pdf"
+)
+
+// IsValid checks if the report format is supported
+func (f ReportFormat) IsValid() bool {
+	return f == ReportCSV || f == ReportPDF
+}
+
+// ReportClaims are what a signed report link grants: one trip member
+// downloading the trip's report in a format and with a filter, until the
+// link expires
+type ReportClaims struct {
+	TripID    uuid.UUID    `json:"trip_id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	Format    ReportFormat `json:"format"`
+	Category  Category     `json:"category,omitempty"`
+	From      *time.Time   `json:"from,omitempty"`
+	To        *time.Time   `json:"to,omitempty"`
+	ExpiresAt time.Time    `json:"expires_at"`
+}
+
+// NewReportClaims creates the claims of a report link valid for ttl
+func NewReportClaims(tripID, userID uuid.UUID, format ReportFormat, filter ListFilter, now time.Time, ttl time.Duration) (ReportClaims, error) {
+	if !format.IsValid() {
+		return ReportClaims{}, ErrInvalidReport
+	}
+	if filter.Category != "" && !filter.Category.IsValid() {
+		return ReportClaims{}, ErrInvalidCategory
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return ReportClaims{}, ErrInvalidReport
+	}
+
+	return ReportClaims{
+		TripID:    tripID,
+		UserID:    userID,
+		Format:    format,
+		Category:  filter.Category,
+		From:      filter.From,
+		To:        filter.To,
+		ExpiresAt: now.Add(ttl).UTC().Truncate(time.Second),
+	}, nil
+}
+
+// Filter returns the expense filter of the report
+func (c ReportClaims) Filter() ListFilter {
+	return ListFilter{Category: c.Category, From: c.From, To: c.To}
+}
+
+// IsExpired checks if the link can no longer be used
+func (c ReportClaims) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+// ParticipantTotal is how much one user paid and how much of it was their
+// share, in one currency, across the expenses of a report
+type ParticipantTotal struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Currency string    `json:"currency"`
+	Paid     int64     `json:"paid"`
+	Share    int64     `json:"share"`
+}
+
+// Report is a trip's expense report: the expenses matching a filter with
+// their split, what each participant paid and consumed, and the open
+// balances between them with a plan to settle them
+type Report struct {
+	TripTitle   string
+	Filter      ListFilter
+	GeneratedAt time.Time
+	Expenses    []*Expense
+	Totals      []ParticipantTotal
+	Balances    []Balance
+	Transfers   []Transfer
+	// Names maps everyone appearing in the report to their display name
+	Names map[uuid.UUID]string
+}
+
+// NewReport creates a report of the given expenses. Balances and the
+// settle-up plan cover only those expenses, in the currencies they were paid in.
+func NewReport(tripTitle string, filter ListFilter, expenses []*Expense, names map[uuid.UUID]string, at time.Time) *Report {
+	balances := ComputeBalances(expenses, BasisOriginal)
+	return &Report{
+		TripTitle:   tripTitle,
+		Filter:      filter,
+		GeneratedAt: at,
+		Expenses:    expenses,
+		Totals:      participantTotals(expenses),
+		Balances:    balances,
+		Transfers:   SettleUp(balances),
+		Names:       names,
+	}
+}
+
+// Name returns a user's display name, or the start of their ID if unknown
+func (r *Report) Name(userID uuid.UUID) string {
+	if name := r.Names[userID]; name != "" {
+		return name
+	}
+	return userID.String()[:8]
+}
+
+// participantTotals sums what every user paid and owed per currency, settled
+// or not, ordered by currency and user ID
+func participantTotals(expenses []*Expense) []ParticipantTotal {
+	type key struct {
+		currency string
+		userID   uuid.UUID
+	}
+	totals := make(map[key]*ParticipantTotal)
+	get := func(code string, userID uuid.UUID) *ParticipantTotal {
+		k := key{code, userID}
+		if totals[k] == nil {
+			totals[k] = &ParticipantTotal{UserID: userID, Currency: code}
+		}
+		return totals[k]
+	}
+
+	for _, e := range expenses {
+		get(e.Currency, e.PayerID).Paid += e.Amount
+		for _, share := range e.Shares {
+			get(e.Currency, share.UserID).Share += share.AmountOwed
+		}
+	}
+
+	result := make([]ParticipantTotal, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, *total)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Currency != result[j].Currency {
+			return result[i].Currency < result[j].Currency
+		}
+		return lessUUID(result[i].UserID, result[j].UserID)
+	})
+	return result
+}
@@ -0,0 +1,66 @@
+package expense
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReportClaims(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC)
+
+	claims, err := NewReportClaims(uuid.New(), alice, ReportPDF, ListFilter{Category: CategoryFood, From: &from, To: &to}, now, 10*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(10*time.Minute), claims.ExpiresAt)
+	assert.Equal(t, ListFilter{Category: CategoryFood, From: &from, To: &to}, claims.Filter())
+	assert.False(t, claims.IsExpired(now.Add(9*time.Minute)))
+	assert.True(t, claims.IsExpired(now.Add(10*time.Minute)))
+
+	tests := []struct {
+		name     string
+		format   ReportFormat
+		filter   ListFilter
+		expected error
+	}{
+		{"unknown format", "xlsx", ListFilter{}, ErrInvalidReport},
+		{"unknown category", ReportCSV, ListFilter{Category: "gifts"}, ErrInvalidCategory},
+		{"range ends before it starts", ReportCSV, ListFilter{From: &to, To: &from}, ErrInvalidReport},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReportClaims(uuid.New(), alice, tt.format, tt.filter, now, time.Minute)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	dinner := mustExpense(t, alice, 9000, "EUR", SplitEqual, Portion{UserID: alice}, Portion{UserID: bob}, Portion{UserID: carol})
+	taxi := mustExpense(t, bob, 3000, "EUR", SplitExact, Portion{UserID: alice, Value: 1000}, Portion{UserID: carol, Value: 2000})
+	museum := mustExpense(t, carol, 2000, "GBP", SplitEqual, Portion{UserID: alice}, Portion{UserID: bob})
+	require.NoError(t, dinner.SettleShare(dinner.Shares[1], "cash", time.Now()))
+
+	report := NewReport("Lisbon", ListFilter{}, []*Expense{dinner, taxi, museum}, map[uuid.UUID]string{alice: "Alice"}, time.Now())
+
+	assert.Equal(t, []ParticipantTotal{
+		{UserID: alice, Currency: "EUR", Paid: 9000, Share: 4000},
+		{UserID: bob, Currency: "EUR", Paid: 3000, Share: 3000},
+		{UserID: carol, Currency: "EUR", Paid: 0, Share: 5000},
+		{UserID: alice, Currency: "GBP", Paid: 0, Share: 1000},
+		{UserID: bob, Currency: "GBP", Paid: 0, Share: 1000},
+		{UserID: carol, Currency: "GBP", Paid: 2000, Share: 0},
+	}, report.Totals)
+
+	// Settled shares count towards the totals but not the balances
+	assert.Equal(t, ComputeBalances([]*Expense{dinner, taxi, museum}, BasisOriginal), report.Balances)
+	assert.Equal(t, SettleUp(report.Balances), report.Transfers)
+
+	assert.Equal(t, "Alice", report.Name(alice))
+	assert.Equal(t, bob.String()[:8], report.Name(bob))
+}
@@ -0,0 +1,131 @@
+package expense
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain errors
+var (
+	ErrExpenseNotFound    = errors.New("expense not found")
+	ErrInvalidExpense     = errors.New("an expense needs a payer, a date and a title of at most 200 characters, and notes of at most 2000")
+	ErrInvalidAmount      = errors.New("expense amount must be a positive number of minor currency units")
+	ErrInvalidCategory    = errors.New("invalid expense category")
+	ErrInvalidSplitMethod = errors.New("split method must be equal, exact, percentage or shares")
+	ErrInvalidSplit       = errors.New("an expense must be split among at least one participant, each listed once with a positive value")
+	ErrSplitMismatch      = errors.New("exact amounts must add up to the expense amount and percentages to 100%")
+	ErrNotTripMember      = errors.New("only members of the trip can see, pay or share its expenses")
+	ErrNotExpenseOwner    = errors.New("only the payer, the member who recorded the expense or the trip organizer can change it")
+	ErrExpenseSettled     = errors.New("an expense with settled shares cannot be changed")
+	ErrShareNotFound      = errors.New("expense share not found")
+	ErrNothingToSettle    = errors.New("there is nothing to settle")
+	ErrAlreadySettled     = errors.New("share is already settled")
+	ErrNotSettled         = errors.New("share is not settled")
+	ErrInvalidSettlement  = errors.New("settlement notes can be at most 2000 characters")
+	ErrNotSettlementParty = errors.New("only the payer, the member who owes the share or the trip organizer can settle it")
+	ErrInvalidReport      = errors.New("report format must be csv or pdf, and the date range must not end before it starts")
+	ErrInvalidReportLink  = errors.New("invalid report link")
+	ErrReportLinkExpired  = errors.New("report link has expired")
+	ErrReceiptNotFound    = errors.New("receipt not found")
+	ErrInvalidReceipt     = errors.New("receipts must be JPEG or PNG images or PDF documents")
+	ErrReceiptTooLarge    = errors.New("receipts can be at most 10 MB")
+	ErrTooManyReceipts    = errors.New("an expense can have at most 5 receipts")
+	ErrNotReceiptOwner    = errors.New("only the member who uploaded a receipt or who can change the expense can remove it")
+	ErrInvalidBudget      = errors.New("a budget plans each category once with a positive amount and an alert percentage between 1 and 100")
+	ErrNotBudgetOwner     = errors.New("only the trip organizer can plan the trip's budget")
+
+	ErrRecurringExpenseNotFound = errors.New("recurring expense not found")
+	ErrInvalidRecurringExpense  = errors.New("a recurring expense needs a payer, a title of at most 200 characters, a daily, weekly or monthly frequency and an end date at most a year after its start")
+	ErrRecurringStarted         = errors.New("once a recurring expense was charged, its start and frequency are fixed and it cannot end before the nights already charged")
+	ErrRecurringFinished        = errors.New("every period of the recurring expense was charged")
+	ErrNotRecurringOwner        = errors.New("only the trip organizer can manage recurring expenses")
+)
+
+// ListFilter narrows the expenses of a trip
+type ListFilter struct {
+	Category Category
+	From     *time.Time
+	To       *time.Time
+}
+
+// Repository defines the interface for expense persistence. Expenses are
+// always stored and loaded together with their shares.
+type Repository interface {
+	// Create creates an expense with its shares
+	Create(ctx context.Context, expense *Expense) error
+
+	// GetByID retrieves an expense with its shares
+	GetByID(ctx context.Context, id uuid.UUID) (*Expense, error)
+
+	// ListByTrip retrieves a trip's expenses matching the filter, most recent date first
+	ListByTrip(ctx context.Context, tripID uuid.UUID, filter ListFilter) ([]*Expense, error)
+
+	// Update updates an expense and replaces its shares
+	Update(ctx context.Context, expense *Expense) error
+
+	// UpdateSettlements saves the settlement state and notes of the shares
+	UpdateSettlements(ctx context.Context, shares []*Share) error
+
+	// Delete deletes an expense and its shares
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// ReceiptRepository defines the interface for receipt persistence. The files
+// themselves are kept in blob storage.
+type ReceiptRepository interface {
+	// Create creates a receipt
+	Create(ctx context.Context, receipt *Receipt) error
+
+	// GetByID retrieves a receipt
+	GetByID(ctx context.Context, id uuid.UUID) (*Receipt, error)
+
+	// ListByExpense retrieves an expense's receipts, oldest first
+	ListByExpense(ctx context.Context, expenseID uuid.UUID) ([]*Receipt, error)
+
+	// Delete deletes a receipt
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// BudgetRepository defines the interface for budget plan persistence
+type BudgetRepository interface {
+	// ListByTrip retrieves the budget lines of a trip, both the trip's plan
+	// and its members' personal plans
+	ListByTrip(ctx context.Context, tripID uuid.UUID) ([]*BudgetLine, error)
+
+	// ReplacePlan replaces the lines of the trip's plan, or of the member's
+	// plan when userID is set
+	ReplacePlan(ctx context.Context, tripID uuid.UUID, userID *uuid.UUID, lines []*BudgetLine) error
+
+	// UpdateAlertLevels saves the alert level of the lines
+	UpdateAlertLevels(ctx context.Context, lines []*BudgetLine) error
+}
+
+// RecurringRepository defines the interface for recurring expense persistence
+type RecurringRepository interface {
+	// Create creates a recurring expense
+	Create(ctx context.Context, recurring *RecurringExpense) error
+
+	// GetByID retrieves a recurring expense
+	GetByID(ctx context.Context, id uuid.UUID) (*RecurringExpense, error)
+
+	// ListByTrip retrieves a trip's recurring expenses, by start date
+	ListByTrip(ctx context.Context, tripID uuid.UUID) ([]*RecurringExpense, error)
+
+	// ListDue retrieves the unfinished recurring expenses whose next period
+	// started before the given day; weekly and monthly ones may not be due yet
+	ListDue(ctx context.Context, today time.Time) ([]*RecurringExpense, error)
+
+	// Update updates a recurring expense
+	Update(ctx context.Context, recurring *RecurringExpense) error
+
+	// Delete deletes a recurring expense; the expenses it recorded remain
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// Record creates the expense of a period and saves the recurring
+	// expense's next date together. It fails with ErrRecurringExpenseNotFound
+	// when the recurring expense is gone or the period was recorded already.
+	Record(ctx context.Context, recurring *RecurringExpense, previousNextDate time.Time, e *Expense) error
+}
@@ -0,0 +1,146 @@
+package expense
+
+import (
+	"bytes"
+	"math/bits"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// SplitMethod describes how an expense is divided among participants
+type SplitMethod string
+
+const (
+	// SplitEqual divides the amount evenly; portion values are ignored
+	SplitEqual SplitMethod = "equal"
+	// SplitExact takes each portion's value as the amount owed in minor units
+	SplitExact SplitMethod = "exact"
+	// SplitPercentage takes each portion's value as basis points
+	// (hundredths of a percent) that add up to 10000
+	SplitPercentage SplitMethod = "percentage"
+	// SplitShares divides the amount in proportion to each portion's share count
+	SplitShares SplitMethod = "shares"
+)
+
+// Split limits
+const (
+	// WholePercentage is 100% in basis points
+	WholePercentage int64 = 10_000
+	// MaxShareCount caps the shares one participant can hold
+	MaxShareCount int64 = 1_000
+)
+
+// IsValid returns true if the split method is known
+func (m SplitMethod) IsValid() bool {
+	switch m {
+	case SplitEqual, SplitExact, SplitPercentage, SplitShares:
+		return true
+	}
+	return false
+}
+
+// Portion is one participant's part of a split as entered by the user
+type Portion struct {
+	UserID uuid.UUID `json:"user_id"`
+	Value  int64     `json:"value"`
+}
+
+// weight returns the portion's weight under the split method
+func (p Portion) weight(method SplitMethod) int64 {
+	if method == SplitEqual {
+		return 1
+	}
+	return p.Value
+}
+
+// Split divides amount minor units among the portions and returns what each
+// one owes, in the order of the portions. Proportional splits are rounded
+// down and the leftover minor units go, one each, to the portions with the
+// largest rounding remainders; ties go to the lowest user ID, so the result
+// does not depend on the order of the portions.
+func Split(amount int64, method SplitMethod, portions []Portion) ([]int64, error) {
+	if !method.IsValid() {
+		return nil, ErrInvalidSplitMethod
+	}
+	if len(portions) == 0 {
+		return nil, ErrInvalidSplit
+	}
+
+	seen := make(map[uuid.UUID]bool, len(portions))
+	var total int64
+	for _, portion := range portions {
+		if portion.UserID == uuid.Nil || seen[portion.UserID] {
+			return nil, ErrInvalidSplit
+		}
+		seen[portion.UserID] = true
+
+		weight := portion.weight(method)
+		if weight <= 0 ||
+			(method == SplitExact && weight > amount) ||
+			(method == SplitPercentage && weight > WholePercentage) ||
+			(method == SplitShares && weight > MaxShareCount) {
+			return nil, ErrInvalidSplit
+		}
+		total += weight
+	}
+
+	switch method {
+	case SplitExact:
+		if total != amount {
+			return nil, ErrSplitMismatch
+		}
+	case SplitPercentage:
+		if total != WholePercentage {
+			return nil, ErrSplitMismatch
+		}
+	}
+
+	weights := make([]int64, len(portions))
+	ids := make([]uuid.UUID, len(portions))
+	for i, portion := range portions {
+		weights[i] = portion.weight(method)
+		ids[i] = portion.UserID
+	}
+
+	return apportion(amount, weights, ids), nil
+}
+
+// apportion divides total in proportion to the weights, which must not be
+// negative and must not all be zero. Shares are rounded down and the leftover
+// units go, one each, to the largest rounding remainders, ties to the lowest
+// ID. Products are computed in 128 bits so large totals cannot overflow.
+func apportion(total int64, weights []int64, ids []uuid.UUID) []int64 {
+	var sum uint64
+	for _, weight := range weights {
+		sum += uint64(weight)
+	}
+
+	owed := make([]int64, len(weights))
+	remainders := make([]uint64, len(weights))
+	leftover := total
+	for i, weight := range weights {
+		hi, lo := bits.Mul64(uint64(total), uint64(weight))
+		quotient, remainder := bits.Div64(hi, lo, sum)
+		owed[i] = int64(quotient)
+		remainders[i] = remainder
+		leftover -= owed[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		if remainders[i] != remainders[j] {
+			return remainders[i] > remainders[j]
+		}
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+	for _, i := range order[:leftover] {
+		owed[i]++
+	}
+
+	return owed
+}
@@ -0,0 +1,88 @@
+package expense
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	alice = uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	bob   = uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	carol = uuid.MustParse("00000000-0000-0000-0000-00000000000c")
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		method   SplitMethod
+		portions []Portion
+		expected []int64
+	}{
+		{"equal", 9000, SplitEqual, []Portion{{UserID: alice}, {UserID: bob}, {UserID: carol}}, []int64{3000, 3000, 3000}},
+		{"equal remainder goes to lowest IDs", 1000, SplitEqual, []Portion{{UserID: carol}, {UserID: bob}, {UserID: alice}}, []int64{333, 333, 334}},
+		{"equal two cents left", 200, SplitEqual, []Portion{{UserID: alice}, {UserID: bob}, {UserID: carol}}, []int64{67, 67, 66}},
+		{"equal ignores values", 100, SplitEqual, []Portion{{UserID: alice, Value: 90}, {UserID: bob}}, []int64{50, 50}},
+		{"exact", 1000, SplitExact, []Portion{{UserID: alice, Value: 250}, {UserID: bob, Value: 750}}, []int64{250, 750}},
+		{"percentage", 1000, SplitPercentage, []Portion{{UserID: alice, Value: 5000}, {UserID: bob, Value: 5000}}, []int64{500, 500}},
+		{"percentage thirds", 100, SplitPercentage, []Portion{{UserID: alice, Value: 3333}, {UserID: bob, Value: 3333}, {UserID: carol, Value: 3334}}, []int64{33, 33, 34}},
+		{"percentage largest remainder", 999, SplitPercentage, []Portion{{UserID: alice, Value: 1500}, {UserID: bob, Value: 8500}}, []int64{150, 849}},
+		{"shares", 1000, SplitShares, []Portion{{UserID: alice, Value: 1}, {UserID: bob, Value: 3}}, []int64{250, 750}},
+		{"shares remainder", 100, SplitShares, []Portion{{UserID: alice, Value: 2}, {UserID: bob, Value: 1}}, []int64{67, 33}},
+		{"single participant", 1234, SplitEqual, []Portion{{UserID: bob}}, []int64{1234}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owed, err := Split(tt.amount, tt.method, tt.portions)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, owed)
+
+			var sum int64
+			for _, amount := range owed {
+				sum += amount
+			}
+			assert.Equal(t, tt.amount, sum)
+		})
+	}
+}
+
+func TestSplit_IndependentOfOrder(t *testing.T) {
+	forward, err := Split(1001, SplitShares, []Portion{{UserID: alice, Value: 1}, {UserID: bob, Value: 1}, {UserID: carol, Value: 1}})
+	require.NoError(t, err)
+	backward, err := Split(1001, SplitShares, []Portion{{UserID: carol, Value: 1}, {UserID: bob, Value: 1}, {UserID: alice, Value: 1}})
+	require.NoError(t, err)
+
+	assert.Equal(t, forward, []int64{334, 334, 333})
+	assert.Equal(t, []int64{forward[2], forward[1], forward[0]}, backward)
+}
+
+func TestSplit_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   SplitMethod
+		portions []Portion
+		expected error
+	}{
+		{"unknown method", "halves", []Portion{{UserID: alice}}, ErrInvalidSplitMethod},
+		{"no portions", SplitEqual, nil, ErrInvalidSplit},
+		{"duplicate user", SplitEqual, []Portion{{UserID: alice}, {UserID: alice}}, ErrInvalidSplit},
+		{"nil user", SplitEqual, []Portion{{}}, ErrInvalidSplit},
+		{"zero exact", SplitExact, []Portion{{UserID: alice, Value: 1000}, {UserID: bob}}, ErrInvalidSplit},
+		{"negative shares", SplitShares, []Portion{{UserID: alice, Value: -1}}, ErrInvalidSplit},
+		{"too many shares", SplitShares, []Portion{{UserID: alice, Value: MaxShareCount + 1}}, ErrInvalidSplit},
+		{"exact short", SplitExact, []Portion{{UserID: alice, Value: 400}, {UserID: bob, Value: 500}}, ErrSplitMismatch},
+		{"exact over", SplitExact, []Portion{{UserID: alice, Value: 1001}}, ErrInvalidSplit},
+		{"percentages short", SplitPercentage, []Portion{{UserID: alice, Value: 5000}, {UserID: bob, Value: 4999}}, ErrSplitMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Split(1000, tt.method, tt.portions)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package messaging
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MaxMessageLength is the maximum number of characters in a message body
+const MaxMessageLength = 4000
+
+// MessageKind distinguishes user messages from generated system messages
+type MessageKind string
+
+const (
+	MessageKindUser   MessageKind = "user"
+	MessageKindSystem MessageKind = "system"
+)
+
+// Conversation is either a private thread between two users or the group
+// chat of a trip
+type Conversation struct {
+	ID             uuid.UUID  `json:"id"`
+	DirectKey      string     `json:"-"`
+	TripID         *uuid.UUID `json:"trip_id,omitempty"`
+	Members        []*Member  `json:"members"`
+	LastActivityAt time.Time  `json:"last_activity_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Member is a user taking part in a conversation together with their read
+// marker. Members who left a trip chat keep read access to the messages
+// posted up to their departure; members who rejoin never see what was
+// posted while they were away.
+type Member struct {
+	ConversationID    uuid.UUID  `json:"conversation_id"`
+	UserID            uuid.UUID  `json:"user_id"`
+	LastReadMessageID *uuid.UUID `json:"last_read_message_id,omitempty"`
+	LastReadAt        *time.Time `json:"last_read_at,omitempty"`
+	JoinedAt          time.Time  `json:"joined_at"`
+	LeftAt            *time.Time `json:"left_at,omitempty"`
+	Absences          []Absence  `json:"-"`
+}
+
+// Absence is a period during which a member had left a conversation
+type Absence struct {
+	LeftAt     time.Time
+	ReturnedAt time.Time
+}
+
+// Message is a single message posted in a conversation
+type Message struct {
+	ID             uuid.UUID   `json:"id"`
+	ConversationID uuid.UUID   `json:"conversation_id"`
+	Kind           MessageKind `json:"kind"`
+	SenderID       *uuid.UUID  `json:"sender_id,omitempty"`
+	Body           string      `json:"body"`
+	PinnedAt       *time.Time  `json:"pinned_at,omitempty"`
+	PinnedBy       *uuid.UUID  `json:"pinned_by,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+// NewDirectConversation creates a one-to-one conversation between two users
+func NewDirectConversation(userID, otherID uuid.UUID) (*Conversation, error) {
+	if userID == otherID {
+		return nil, ErrCannotMessageSelf
+	}
+
+	now := timestamp()
+	c := &Conversation{
+		ID:             uuid.New(),
+		DirectKey:      DirectKey(userID, otherID),
+		LastActivityAt: now,
+		CreatedAt:      now,
+	}
+	for _, id := range []uuid.UUID{userID, otherID} {
+		c.Members = append(c.Members, &Member{
+			ConversationID: c.ID,
+			UserID:         id,
+			JoinedAt:       now,
+		})
+	}
+
+	return c, nil
+}
+
+// NewTripConversation creates the group chat of a trip with its current members
+func NewTripConversation(tripID uuid.UUID, memberIDs []uuid.UUID) *Conversation {
+	now := timestamp()
+	c := &Conversation{
+		ID:             uuid.New(),
+		TripID:         &tripID,
+		LastActivityAt: now,
+		CreatedAt:      now,
+	}
+	for _, id := range memberIDs {
+		c.Members = append(c.Members, &Member{
+			ConversationID: c.ID,
+			UserID:         id,
+			JoinedAt:       now,
+		})
+	}
+
+	return c
+}
+
+// DirectKey identifies the direct conversation between two users regardless of order
+func DirectKey(userID, otherID uuid.UUID) string {
+	a, b := userID.String(), otherID.String()
+	if b < a {
+		a, b = b, a
+	}
+	return a + ":" + b
+}
+
+// Member returns the given user's membership, or nil if they are not a member
+func (c *Conversation) Member(userID uuid.UUID) *Member {
+	for _, m := range c.Members {
+		if m.UserID == userID {
+			return m
+		}
+	}
+	return nil
+}
+
+// IsMember returns true if the user takes part, or took part, in the conversation
+func (c *Conversation) IsMember(userID uuid.UUID) bool {
+	return c.Member(userID) != nil
+}
+
+// IsTripChat returns true if this is the group chat of a trip
+func (c *Conversation) IsTripChat() bool {
+	return c.TripID != nil
+}
+
+// OtherMembers returns the IDs of every current member except the given user
+func (c *Conversation) OtherMembers(userID uuid.UUID) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, m := range c.Members {
+		if m.UserID != userID && m.IsCurrent() {
+			ids = append(ids, m.UserID)
+		}
+	}
+	return ids
+}
+
+// CurrentMembers returns the IDs of every current member
+func (c *Conversation) CurrentMembers() []uuid.UUID {
+	return c.OtherMembers(uuid.Nil)
+}
+
+// NewMessage creates a message from a sender in a conversation
+func NewMessage(conversationID, senderID uuid.UUID, body string) (*Message, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > MaxMessageLength {
+		return nil, ErrInvalidMessageBody
+	}
+
+	return &Message{
+		ID:             uuid.New(),
+		ConversationID: conversationID,
+		Kind:           MessageKindUser,
+		SenderID:       &senderID,
+		Body:           body,
+		CreatedAt:      timestamp(),
+	}, nil
+}
+
+// NewSystemMessage creates a generated message such as "Ana joined the trip"
+func NewSystemMessage(conversationID uuid.UUID, body string) *Message {
+	return &Message{
+		ID:             uuid.New(),
+		ConversationID: conversationID,
+		Kind:           MessageKindSystem,
+		Body:           body,
+		CreatedAt:      timestamp(),
+	}
+}
+
+// IsSentBy returns true if the given user posted the message
+func (m *Message) IsSentBy(userID uuid.UUID) bool {
+	return m.SenderID != nil && *m.SenderID == userID
+}
+
+// Pin highlights the message at the top of its conversation
+func (m *Message) Pin(userID uuid.UUID) {
+	now := timestamp()
+	m.PinnedAt = &now
+	m.PinnedBy = &userID
+}
+
+// Unpin removes the message's highlight
+func (m *Message) Unpin() {
+	m.PinnedAt = nil
+	m.PinnedBy = nil
+}
+
+// IsCurrent returns true if the member has not left the conversation
+func (m *Member) IsCurrent() bool {
+	return m.LeftAt == nil
+}
+
+// CanSee returns true if the message was posted while the member could read it
+func (m *Member) CanSee(msg *Message) bool {
+	if m.LeftAt != nil && msg.CreatedAt.After(*m.LeftAt) {
+		return false
+	}
+	for _, a := range m.Absences {
+		if msg.CreatedAt.After(a.LeftAt) && msg.CreatedAt.Before(a.ReturnedAt) {
+			return false
+		}
+	}
+	return true
+}
+
+// Leave ends the membership; earlier messages stay readable
+func (m *Member) Leave(at time.Time) {
+	if m.LeftAt == nil {
+		m.LeftAt = &at
+	}
+}
+
+// Return restores a membership that was left. The time away is kept as an
+// absence, so messages posted in the meantime stay hidden.
+func (m *Member) Return(at time.Time) {
+	if m.LeftAt == nil {
+		return
+	}
+	m.Absences = append(m.Absences, Absence{LeftAt: *m.LeftAt, ReturnedAt: at})
+	m.LeftAt = nil
+}
+
+// MarkRead moves the read marker up to the given message; it never moves backwards
+func (m *Member) MarkRead(msg *Message) bool {
+	if !m.CanSee(msg) {
+		return false
+	}
+	if m.LastReadAt != nil && !msg.CreatedAt.After(*m.LastReadAt) {
+		return false
+	}
+
+	id, at := msg.ID, msg.CreatedAt
+	m.LastReadMessageID = &id
+	m.LastReadAt = &at
+	return true
+}
+
+// timestamp returns the current time at the precision stored by the database,
+// so that cursors and read markers compare equal after a round trip
+func timestamp() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
@@ -0,0 +1,144 @@
+package messaging
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDirectConversation(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+
+	c, err := NewDirectConversation(alice, bob)
+	require.NoError(t, err)
+	assert.Len(t, c.Members, 2)
+	assert.True(t, c.IsMember(alice))
+	assert.True(t, c.IsMember(bob))
+	assert.False(t, c.IsMember(uuid.New()))
+	assert.Equal(t, []uuid.UUID{bob}, c.OtherMembers(alice))
+	assert.Equal(t, DirectKey(bob, alice), c.DirectKey)
+
+	_, err = NewDirectConversation(alice, alice)
+	assert.ErrorIs(t, err, ErrCannotMessageSelf)
+}
+
+func TestNewMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected error
+	}{
+		{"valid", "Hi there!", nil},
+		{"blank", "   ", ErrInvalidMessageBody},
+		{"at limit", strings.Repeat("é", MaxMessageLength), nil},
+		{"too long", strings.Repeat("a", MaxMessageLength+1), ErrInvalidMessageBody},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMessage(uuid.New(), uuid.New(), tt.body)
+			if tt.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMember_MarkRead(t *testing.T) {
+	m := &Member{UserID: uuid.New()}
+	first := &Message{ID: uuid.New(), CreatedAt: time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)}
+	second := &Message{ID: uuid.New(), CreatedAt: first.CreatedAt.Add(time.Minute)}
+
+	assert.True(t, m.MarkRead(second))
+	assert.Equal(t, second.ID, *m.LastReadMessageID)
+
+	assert.False(t, m.MarkRead(first))
+	assert.Equal(t, second.ID, *m.LastReadMessageID)
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	cursor := Cursor{At: time.Date(2026, 5, 1, 10, 0, 0, 123456000, time.UTC), ID: uuid.New()}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, cursor, *decoded)
+
+	empty, err := DecodeCursor("")
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+
+	_, err = DecodeCursor("not-a-cursor")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestNewTripConversation(t *testing.T) {
+	tripID, organizer, guest := uuid.New(), uuid.New(), uuid.New()
+
+	c := NewTripConversation(tripID, []uuid.UUID{organizer, guest})
+	assert.True(t, c.IsTripChat())
+	assert.Equal(t, tripID, *c.TripID)
+	assert.Empty(t, c.DirectKey)
+	assert.Equal(t, []uuid.UUID{guest}, c.OtherMembers(organizer))
+	assert.Equal(t, []uuid.UUID{organizer, guest}, c.CurrentMembers())
+
+	c.Member(guest).Leave(time.Now())
+	assert.True(t, c.IsMember(guest))
+	assert.Empty(t, c.OtherMembers(organizer))
+	assert.Equal(t, []uuid.UUID{organizer}, c.CurrentMembers())
+}
+
+func TestMember_LeaveKeepsHistory(t *testing.T) {
+	leftAt := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	m := &Member{UserID: uuid.New()}
+	m.Leave(leftAt)
+	assert.False(t, m.IsCurrent())
+
+	before := &Message{ID: uuid.New(), CreatedAt: leftAt.Add(-time.Minute)}
+	at := &Message{ID: uuid.New(), CreatedAt: leftAt}
+	after := &Message{ID: uuid.New(), CreatedAt: leftAt.Add(time.Minute)}
+
+	assert.True(t, m.CanSee(before))
+	assert.True(t, m.CanSee(at))
+	assert.False(t, m.CanSee(after))
+
+	assert.True(t, m.MarkRead(at))
+	assert.False(t, m.MarkRead(after))
+	assert.Equal(t, at.ID, *m.LastReadMessageID)
+
+	returnedAt := leftAt.Add(time.Hour)
+	m.Return(returnedAt)
+	assert.True(t, m.IsCurrent())
+	assert.True(t, m.CanSee(before))
+	assert.False(t, m.CanSee(after))
+	assert.True(t, m.CanSee(&Message{ID: uuid.New(), CreatedAt: returnedAt}))
+	assert.True(t, m.CanSee(&Message{ID: uuid.New(), CreatedAt: returnedAt.Add(time.Minute)}))
+	assert.False(t, m.MarkRead(after))
+}
+
+func TestMessage_KindsAndPins(t *testing.T) {
+	senderID := uuid.New()
+
+	msg, err := NewMessage(uuid.New(), senderID, "Meet at 9?")
+	require.NoError(t, err)
+	assert.Equal(t, MessageKindUser, msg.Kind)
+	assert.True(t, msg.IsSentBy(senderID))
+
+	system := NewSystemMessage(uuid.New(), "Ana joined the trip")
+	assert.Equal(t, MessageKindSystem, system.Kind)
+	assert.Nil(t, system.SenderID)
+	assert.False(t, system.IsSentBy(senderID))
+
+	msg.Pin(senderID)
+	assert.NotNil(t, msg.PinnedAt)
+	assert.Equal(t, senderID, *msg.PinnedBy)
+
+	msg.Unpin()
+	assert.Nil(t, msg.PinnedAt)
+	assert.Nil(t, msg.PinnedBy)
+}
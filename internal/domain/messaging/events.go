@@ -0,0 +1,37 @@
+package messaging
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Messaging event names
+const (
+	EventMessagePosted = "messaging.message_posted"
+	EventTyping        = "messaging.typing"
+)
+
+// MessagePosted is published when a user or system message is added to a
+// conversation. Recipients are the conversation's current members, including
+// the sender so that their other devices stay in sync.
+type MessagePosted struct {
+	Message      *Message
+	TripID       *uuid.UUID
+	RecipientIDs []uuid.UUID
+}
+
+// EventName implements event.Event
+func (MessagePosted) EventName() string { return EventMessagePosted }
+
+// Typing is published when a member signals that they are writing a message.
+// It is not stored; recipients are the other current members.
+type Typing struct {
+	ConversationID uuid.UUID
+	UserID         uuid.UUID
+	RecipientIDs   []uuid.UUID
+	OccurredAt     time.Time
+}
+
+// EventName implements event.Event
+func (Typing) EventName() string { return EventTyping }
@@ -0,0 +1,88 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain errors
+var (
+	ErrConversationNotFound = errors.New("conversation not found")
+	ErrConversationExists   = errors.New("conversation already exists")
+	ErrMessageNotFound      = errors.New("message not found")
+	ErrCannotMessageSelf    = errors.New("you cannot message yourself")
+	ErrInvalidMessageBody   = errors.New("message must contain between 1 and 4000 characters")
+	ErrMessagingNotAllowed  = errors.New("this user only accepts messages from people they travel with")
+	ErrUserBlocked          = errors.New("messaging is not possible because one of you has blocked the other")
+	ErrInvalidCursor        = errors.New("invalid pagination cursor")
+	ErrNotCurrentMember     = errors.New("you are no longer a member of this conversation")
+	ErrNotTripChat          = errors.New("only trip group chats support this action")
+	ErrCannotPinMessage     = errors.New("only the trip organizer can pin messages")
+	ErrNotTripMember        = errors.New("only approved participants can access the trip chat")
+)
+
+// MessageFilter selects a page of a conversation's messages, newest first
+type MessageFilter struct {
+	// After continues a previous page
+	After *Cursor
+	// Until hides messages posted later, e.g. after a member left
+	Until *time.Time
+	// Viewer hides the messages posted while this member was away
+	Viewer uuid.UUID
+	// PinnedOnly restricts the page to pinned messages
+	PinnedOnly bool
+	Limit      int
+}
+
+// ConversationSummary is a conversation as listed in a user's inbox
+type ConversationSummary struct {
+	*Conversation
+	LastMessage *Message `json:"last_message,omitempty"`
+	UnreadCount int      `json:"unread_count"`
+}
+
+// Repository defines the interface for conversation and message persistence
+type Repository interface {
+	// CreateConversation creates a conversation with its members, failing with
+	// ErrConversationExists if the direct conversation between the users, or the
+	// trip's group chat, already exists
+	CreateConversation(ctx context.Context, conversation *Conversation) error
+
+	// GetConversation retrieves a conversation with its members
+	GetConversation(ctx context.Context, id uuid.UUID) (*Conversation, error)
+
+	// GetDirectConversation retrieves the direct conversation with the given key
+	GetDirectConversation(ctx context.Context, directKey string) (*Conversation, error)
+
+	// GetTripConversation retrieves the group chat of a trip
+	GetTripConversation(ctx context.Context, tripID uuid.UUID) (*Conversation, error)
+
+	// AddMember adds a member to a conversation, restoring them if they had left
+	// and recording their absences
+	AddMember(ctx context.Context, member *Member) error
+
+	// UpdateMember stores a member's read marker and departure
+	UpdateMember(ctx context.Context, member *Member) error
+
+	// ListConversations retrieves a user's conversations by latest activity, newest
+	// first, starting after the cursor if one is given. Conversations the user left
+	// only show what was posted before their departure, and never what was
+	// posted while they were away.
+	ListConversations(ctx context.Context, userID uuid.UUID, after *Cursor, limit int) ([]*ConversationSummary, error)
+
+	// CreateMessage stores a message, bumps the conversation's activity and moves
+	// the sender's read marker to it
+	CreateMessage(ctx context.Context, message *Message) error
+
+	// GetMessage retrieves a message by ID
+	GetMessage(ctx context.Context, id uuid.UUID) (*Message, error)
+
+	// ListMessages retrieves the conversation's messages selected by the filter
+	ListMessages(ctx context.Context, conversationID uuid.UUID, filter MessageFilter) ([]*Message, error)
+
+	// UpdatePin stores whether a message is pinned
+	UpdatePin(ctx context.Context, message *Message) error
+}
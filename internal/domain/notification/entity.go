@@ -0,0 +1,504 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Type identifies what a notification is about
+type Type string
+
+const (
+	TypeJoinRequest  Type = "join_request"
+	TypeJoinApproved Type = "join_approved"
+	TypeJoinRejected Type = "join_rejected"
+	TypeNewMessage   Type = "new_message"
+	TypeNewRating    Type = "new_rating"
+	TypeTripChanged  Type = "trip_changed"
+	TypeMention      Type = "mention"
+	TypeBudgetAlert  Type = "budget_alert"
+)
+
+// IsValid returns true if the type is known
+func (t Type) IsValid() bool {
+	for _, known := range Types() {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Channel is a way notifications reach users
+type Channel string
+
+const (
+	// ChannelInApp shows notifications in the notification center
+	ChannelInApp Channel = "in_app"
+	// ChannelEmail emails each notification as it arrives
+	ChannelEmail Channel = "email"
+	// ChannelPush sends notifications to the user's devices through Web Push
+	ChannelPush Channel = "push"
+	// ChannelDigest batches notifications into the daily or weekly digest
+	ChannelDigest Channel = "digest"
+)
+
+// IsValid returns true if the channel is known
+func (c Channel) IsValid() bool {
+	for _, known := range Channels() {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Channels returns every channel
+func Channels() []Channel {
+	return []Channel{ChannelInApp, ChannelEmail, ChannelPush, ChannelDigest}
+}
+
+// EntityType identifies the kind of entity a notification links to
+type EntityType string
+
+const (
+	EntityTrip         EntityType = "trip"
+	EntityConversation EntityType = "conversation"
+	EntityUser         EntityType = "user"
+)
+
+// Keys of the notification data that lets channels other than the
+// notification center, such as email, phrase a notification in the
+// recipient's language
+const (
+	DataActorName = "actor_name"
+	DataTripTitle = "trip_title"
+	DataStartDate = "start_date"
+	DataEndDate   = "end_date"
+	DataRating    = "rating"
+	DataCommentID = "comment_id"
+	DataCategory  = "category"
+	DataSpent     = "spent"
+	DataBudget    = "budget"
+	DataPercent   = "percent"
+	DataStatus    = "status"
+	DataPersonal  = "personal"
+)
+
+// DataDateLayout formats dates in notification data
+const DataDateLayout = "2006-01-02"
+
+// Notification limits
+const (
+	MaxTitleLength   = 200
+	MaxContentLength = 500
+)
+
+// Notification informs a user of activity that concerns them and links to the
+// related entity
+type Notification struct {
+	ID                uuid.UUID         `json:"id"`
+	UserID            uuid.UUID         `json:"user_id"`
+	Type              Type              `json:"type"`
+	Title             string            `json:"title"`
+	Content           string            `json:"content"`
+	RelatedEntityType EntityType        `json:"related_entity_type"`
+	RelatedEntityID   uuid.UUID         `json:"related_entity_id"`
+	Data              map[string]string `json:"data,omitempty"`
+	Channels          []Channel         `json:"-"`
+	IsRead            bool              `json:"is_read"`
+	ReadAt            *time.Time        `json:"read_at,omitempty"`
+	CreatedAt         time.Time         `json:"created_at"`
+}
+
+// NewNotification creates an unread notification for a user
+func NewNotification(userID uuid.UUID, notificationType Type, title, content string, entityType EntityType, entityID uuid.UUID) (*Notification, error) {
+	if userID == uuid.Nil {
+		return nil, errors.New("user ID is required")
+	}
+	if !notificationType.IsValid() {
+		return nil, ErrInvalidType
+	}
+	if entityType == "" || entityID == uuid.Nil {
+		return nil, errors.New("related entity is required")
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, errors.New("title is required")
+	}
+
+	return &Notification{
+		ID:                uuid.New(),
+		UserID:            userID,
+		Type:              notificationType,
+		Title:             truncate(title, MaxTitleLength),
+		Content:           truncate(strings.TrimSpace(content), MaxContentLength),
+		RelatedEntityType: entityType,
+		RelatedEntityID:   entityID,
+		Data:              map[string]string{},
+		Channels:          Channels(),
+		CreatedAt:         time.Now().UTC(),
+	}, nil
+}
+
+// HasChannel returns true if the notification is delivered on the channel
+func (n *Notification) HasChannel(channel Channel) bool {
+	for _, c := range n.Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// truncate shortens s to at most limit characters, ending with an ellipsis when cut
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
+
+// Types returns every notification type
+func Types() []Type {
+	return []Type{TypeJoinRequest, TypeJoinApproved, TypeJoinRejected, TypeNewMessage, TypeNewRating, TypeTripChanged, TypeMention, TypeBudgetAlert}
+}
+
+// DigestFrequency controls how notification emails are batched
+type DigestFrequency string
+
+const (
+	DigestImmediate DigestFrequency = "immediate"
+	DigestDaily     DigestFrequency = "daily"
+	DigestWeekly    DigestFrequency = "weekly"
+)
+
+// IsValid returns true if the frequency is known
+func (f DigestFrequency) IsValid() bool {
+	switch f {
+	case DigestImmediate, DigestDaily, DigestWeekly:
+		return true
+	default:
+		return false
+	}
+}
+
+// Digests go out at DigestHour in the user's timezone, weekly ones on DigestWeekday
+const (
+	DigestHour    = 8
+	DigestWeekday = time.Monday
+)
+
+// NextDigestAt returns the first digest time of the frequency strictly after
+// the given time, in the location's wall clock
+func NextDigestAt(frequency DigestFrequency, loc *time.Location, after time.Time) time.Time {
+	local := after.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), DigestHour, 0, 0, 0, loc)
+
+	step := 1
+	if frequency == DigestWeekly {
+		step = 7
+		next = next.AddDate(0, 0, (int(DigestWeekday)-int(next.Weekday())+7)%7)
+	}
+	for !next.After(after) {
+		next = next.AddDate(0, 0, step)
+	}
+
+	return next.UTC()
+}
+
+// ClockTime is a time of day in minutes after midnight, written as "15:04"
+type ClockTime int
+
+// MinutesPerDay bounds clock times
+const MinutesPerDay = 24 * 60
+
+// ParseClockTime parses a time of day written as "15:04"
+func ParseClockTime(value string) (ClockTime, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, ErrInvalidQuietHours
+	}
+	return ClockTime(parsed.Hour()*60 + parsed.Minute()), nil
+}
+
+// String formats the clock time as "15:04"
+func (c ClockTime) String() string {
+	return fmt.Sprintf("%02d:%02d", int(c)/60, int(c)%60)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (c ClockTime) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// QuietHours is a daily period, in the user's timezone, during which push
+// notifications and emails are held back. It wraps past midnight when it
+// ends before it starts.
+type QuietHours struct {
+	Start ClockTime `json:"start"`
+	End   ClockTime `json:"end"`
+}
+
+// Preferences holds a user's notification choices: which channels each type
+// of notification is delivered on, quiet hours and muted trips. Channels
+// without an explicit choice are enabled, so new types reach users by default,
+// unless the user switched the channel off for the whole account.
+//
+// Emails are sent as notifications arrive, or batched into a digest of the
+// notifications that are still unread when the digest is due. While digests
+// are on, types delivered on the digest channel are not emailed one by one.
+type Preferences struct {
+	UserID       uuid.UUID                 `json:"user_id"`
+	Channels     map[Type]map[Channel]bool `json:"channels"`
+	Digest       DigestFrequency           `json:"digest"`
+	Timezone     string                    `json:"timezone"`
+	QuietHours   *QuietHours               `json:"quiet_hours,omitempty"`
+	MutedTrips   []uuid.UUID               `json:"muted_trips"`
+	LastDigestAt *time.Time                `json:"last_digest_at,omitempty"`
+	NextDigestAt *time.Time                `json:"next_digest_at,omitempty"`
+	// DisabledChannels are switched off for every type, present and future
+	DisabledChannels []Channel `json:"disabled_channels"`
+}
+
+// NewPreferences creates preferences with every channel enabled and immediate emails
+func NewPreferences(userID uuid.UUID) *Preferences {
+	return &Preferences{
+		UserID:           userID,
+		Channels:         map[Type]map[Channel]bool{},
+		Digest:           DigestImmediate,
+		Timezone:         "UTC",
+		MutedTrips:       []uuid.UUID{},
+		DisabledChannels: []Channel{},
+	}
+}
+
+// Enabled returns true if the user wants notifications of the type on the channel
+func (p *Preferences) Enabled(t Type, channel Channel) bool {
+	if p.isSwitchedOff(channel) {
+		return false
+	}
+	enabled, ok := p.Channels[t][channel]
+	return !ok || enabled
+}
+
+// Set records whether the user wants notifications of the type on the channel
+func (p *Preferences) Set(t Type, channel Channel, enabled bool) error {
+	if !t.IsValid() {
+		return ErrInvalidType
+	}
+	if !channel.IsValid() {
+		return ErrInvalidChannel
+	}
+
+	// Turning one type on in a switched-off channel leaves the other types off
+	if enabled && p.isSwitchedOff(channel) {
+		p.switchOn(channel)
+		for _, other := range Types() {
+			p.set(other, channel, false)
+		}
+	}
+	p.set(t, channel, enabled)
+	return nil
+}
+
+// set records a choice for a type on a channel
+func (p *Preferences) set(t Type, channel Channel, enabled bool) {
+	if p.Channels[t] == nil {
+		p.Channels[t] = map[Channel]bool{}
+	}
+	p.Channels[t][channel] = enabled
+}
+
+// ChannelEnabled returns true if any type of notification is delivered on the channel
+func (p *Preferences) ChannelEnabled(channel Channel) bool {
+	for _, t := range Types() {
+		if p.Enabled(t, channel) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetChannelEnabled switches a channel off for the whole account, types added
+// later included, or back on. The per-type choices are kept, so switching a
+// channel back on restores them; when they leave every type off, every type
+// is turned on.
+func (p *Preferences) SetChannelEnabled(channel Channel, enabled bool) error {
+	if !channel.IsValid() {
+		return ErrInvalidChannel
+	}
+	if !enabled {
+		if !p.isSwitchedOff(channel) {
+			p.DisabledChannels = append(p.DisabledChannels, channel)
+		}
+		return nil
+	}
+
+	p.switchOn(channel)
+	if !p.ChannelEnabled(channel) {
+		for _, t := range Types() {
+			p.set(t, channel, true)
+		}
+	}
+	return nil
+}
+
+// isSwitchedOff returns true if the channel is off for the whole account
+func (p *Preferences) isSwitchedOff(channel Channel) bool {
+	for _, disabled := range p.DisabledChannels {
+		if disabled == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// switchOn removes the account-wide switch of a channel
+func (p *Preferences) switchOn(channel Channel) {
+	kept := []Channel{}
+	for _, disabled := range p.DisabledChannels {
+		if disabled != channel {
+			kept = append(kept, disabled)
+		}
+	}
+	p.DisabledChannels = kept
+}
+
+// ChannelsFor returns the channels a notification of the type about the trip
+// is delivered on, or none when the user muted the trip. Pass uuid.Nil for
+// notifications that are not about a trip.
+func (p *Preferences) ChannelsFor(t Type, tripID uuid.UUID) []Channel {
+	if tripID != uuid.Nil && p.IsTripMuted(tripID) {
+		return nil
+	}
+
+	channels := []Channel{}
+	for _, channel := range []Channel{ChannelInApp, ChannelPush} {
+		if p.Enabled(t, channel) {
+			channels = append(channels, channel)
+		}
+	}
+
+	switch {
+	case p.Digest != DigestImmediate && p.Enabled(t, ChannelDigest):
+		channels = append(channels, ChannelDigest)
+	case p.Enabled(t, ChannelEmail):
+		channels = append(channels, ChannelEmail)
+	}
+
+	return channels
+}
+
+// IsTripMuted returns true if the user muted notifications about the trip
+func (p *Preferences) IsTripMuted(tripID uuid.UUID) bool {
+	for _, muted := range p.MutedTrips {
+		if muted == tripID {
+			return true
+		}
+	}
+	return false
+}
+
+// SetQuietHours changes the user's quiet hours, written as "15:04". Empty
+// start and end turn quiet hours off.
+func (p *Preferences) SetQuietHours(start, end string) error {
+	if start == "" && end == "" {
+		p.QuietHours = nil
+		return nil
+	}
+
+	startTime, err := ParseClockTime(start)
+	if err != nil {
+		return err
+	}
+	endTime, err := ParseClockTime(end)
+	if err != nil {
+		return err
+	}
+	if startTime == endTime {
+		return ErrInvalidQuietHours
+	}
+
+	p.QuietHours = &QuietHours{Start: startTime, End: endTime}
+	return nil
+}
+
+// QuietUntil returns when the quiet hours that the given time falls in end,
+// or the zero time when it is outside quiet hours
+func (p *Preferences) QuietUntil(now time.Time) time.Time {
+	if p.QuietHours == nil {
+		return time.Time{}
+	}
+
+	local := now.In(p.Location())
+	minute := ClockTime(local.Hour()*60 + local.Minute())
+	start, end := p.QuietHours.Start, p.QuietHours.End
+
+	quiet := start <= minute && minute < end
+	if start > end {
+		quiet = minute >= start || minute < end
+	}
+	if !quiet {
+		return time.Time{}
+	}
+
+	until := time.Date(local.Year(), local.Month(), local.Day(), int(end)/60, int(end)%60, 0, 0, local.Location())
+	if !until.After(now) {
+		until = time.Date(local.Year(), local.Month(), local.Day()+1, int(end)/60, int(end)%60, 0, 0, local.Location())
+	}
+	return until.UTC()
+}
+
+// SetDigest changes how the user's emails are batched and the timezone
+// digests are scheduled and rendered in. A digest that starts now only covers
+// notifications from now on, as earlier ones were already emailed.
+func (p *Preferences) SetDigest(frequency DigestFrequency, timezone string, now time.Time) error {
+	if !frequency.IsValid() {
+		return ErrInvalidDigestFrequency
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "" || timezone == "Local" {
+		return ErrInvalidTimezone
+	}
+
+	if frequency != DigestImmediate && (p.Digest == DigestImmediate || p.LastDigestAt == nil) {
+		p.LastDigestAt = &now
+	}
+	p.Digest = frequency
+	p.Timezone = timezone
+
+	if frequency == DigestImmediate {
+		p.NextDigestAt = nil
+		return nil
+	}
+	next := NextDigestAt(frequency, loc, now)
+	p.NextDigestAt = &next
+	return nil
+}
+
+// Location returns the user's timezone, or UTC when it is unknown
+func (p *Preferences) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// DigestSent records a digest run and schedules the next one
+func (p *Preferences) DigestSent(now time.Time) {
+	p.LastDigestAt = &now
+	if p.Digest == DigestImmediate {
+		p.NextDigestAt = nil
+		return
+	}
+	next := NextDigestAt(p.Digest, p.Location(), now)
+	p.NextDigestAt = &next
+}
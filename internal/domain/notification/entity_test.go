@@ -0,0 +1,415 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNotification(t *testing.T) {
+	userID, tripID := uuid.New(), uuid.New()
+
+	n, err := NewNotification(userID, TypeJoinRequest, " Anna wants to join Alps ", "Review it", EntityTrip, tripID)
+	require.NoError(t, err)
+	assert.Equal(t, "Anna wants to join Alps", n.Title)
+	assert.Equal(t, EntityTrip, n.RelatedEntityType)
+	assert.Equal(t, tripID, n.RelatedEntityID)
+	assert.False(t, n.IsRead)
+	assert.Nil(t, n.ReadAt)
+
+	_, err = NewNotification(userID, Type("party"), "Title", "", EntityTrip, tripID)
+	assert.ErrorIs(t, err, ErrInvalidType)
+
+	_, err = NewNotification(userID, TypeTripChanged, "  ", "", EntityTrip, tripID)
+	assert.Error(t, err)
+
+	_, err = NewNotification(userID, TypeTripChanged, "Title", "", EntityTrip, uuid.Nil)
+	assert.Error(t, err)
+
+	_, err = NewNotification(uuid.Nil, TypeTripChanged, "Title", "", EntityTrip, tripID)
+	assert.Error(t, err)
+}
+
+func TestNewNotification_TruncatesContent(t *testing.T) {
+	body := strings.Repeat("ü", MaxContentLength+20)
+
+	n, err := NewNotification(uuid.New(), TypeNewMessage, "New message", body, EntityConversation, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, MaxContentLength, utf8.RuneCountInString(n.Content))
+	assert.True(t, strings.HasSuffix(n.Content, "…"))
+}
+
+func TestListFilter_Normalize(t *testing.T) {
+	tests := []struct {
+		name           string
+		filter         ListFilter
+		expectedLimit  int
+		expectedOffset int
+	}{
+		{"defaults", ListFilter{}, DefaultListLimit, 0},
+		{"capped", ListFilter{Limit: 1000, Offset: 40}, MaxListLimit, 40},
+		{"negative offset", ListFilter{Limit: 5, Offset: -3}, 5, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.Normalize()
+			assert.Equal(t, tt.expectedLimit, tt.filter.Limit)
+			assert.Equal(t, tt.expectedOffset, tt.filter.Offset)
+		})
+	}
+}
+
+func TestPreferences_Channels(t *testing.T) {
+	prefs := NewPreferences(uuid.New())
+	for _, notificationType := range Types() {
+		for _, channel := range Channels() {
+			assert.True(t, prefs.Enabled(notificationType, channel), "%s %s", notificationType, channel)
+		}
+	}
+
+	require.NoError(t, prefs.Set(TypeNewMessage, ChannelEmail, false))
+	assert.False(t, prefs.Enabled(TypeNewMessage, ChannelEmail))
+	assert.True(t, prefs.Enabled(TypeNewMessage, ChannelPush))
+	assert.True(t, prefs.Enabled(TypeTripChanged, ChannelEmail))
+
+	require.NoError(t, prefs.Set(TypeNewMessage, ChannelEmail, true))
+	assert.True(t, prefs.Enabled(TypeNewMessage, ChannelEmail))
+
+	assert.ErrorIs(t, prefs.Set(Type("party"), ChannelEmail, false), ErrInvalidType)
+	assert.ErrorIs(t, prefs.Set(TypeNewMessage, Channel("pigeon"), false), ErrInvalidChannel)
+}
+
+func TestPreferences_SetChannelEnabled(t *testing.T) {
+	prefs := NewPreferences(uuid.New())
+	require.NoError(t, prefs.Set(TypeNewRating, ChannelPush, false))
+
+	require.NoError(t, prefs.SetChannelEnabled(ChannelPush, false))
+	require.NoError(t, prefs.SetChannelEnabled(ChannelPush, false))
+	assert.Equal(t, []Channel{ChannelPush}, prefs.DisabledChannels)
+	assert.False(t, prefs.ChannelEnabled(ChannelPush))
+	for _, notificationType := range Types() {
+		assert.False(t, prefs.Enabled(notificationType, ChannelPush), notificationType)
+		assert.True(t, prefs.Enabled(notificationType, ChannelEmail), notificationType)
+	}
+
+	// Switching back on restores the per-type choices
+	require.NoError(t, prefs.SetChannelEnabled(ChannelPush, true))
+	assert.Empty(t, prefs.DisabledChannels)
+	assert.True(t, prefs.Enabled(TypeNewMessage, ChannelPush))
+	assert.False(t, prefs.Enabled(TypeNewRating, ChannelPush), "switching a channel on keeps per-type choices")
+
+	// ...unless they leave every type off
+	for _, notificationType := range Types() {
+		require.NoError(t, prefs.Set(notificationType, ChannelPush, false))
+	}
+	require.NoError(t, prefs.SetChannelEnabled(ChannelPush, true))
+	assert.True(t, prefs.Enabled(TypeNewRating, ChannelPush))
+
+	assert.ErrorIs(t, prefs.SetChannelEnabled(Channel("pigeon"), true), ErrInvalidChannel)
+}
+
+func TestPreferences_SwitchedOffChannelCoversNewTypes(t *testing.T) {
+	// Preferences saved before the mention type existed, with email switched off
+	prefs := NewPreferences(uuid.New())
+	prefs.DisabledChannels = []Channel{ChannelEmail, ChannelDigest}
+	delete(prefs.Channels, TypeMention)
+
+	assert.False(t, prefs.Enabled(TypeMention, ChannelEmail))
+	assert.False(t, prefs.Enabled(TypeBudgetAlert, ChannelDigest))
+	assert.Equal(t, []Channel{ChannelInApp, ChannelPush}, prefs.ChannelsFor(TypeMention, uuid.Nil))
+}
+
+func TestPreferences_SetInSwitchedOffChannel(t *testing.T) {
+	prefs := NewPreferences(uuid.New())
+	require.NoError(t, prefs.SetChannelEnabled(ChannelEmail, false))
+
+	require.NoError(t, prefs.Set(TypeNewMessage, ChannelEmail, false))
+	assert.Equal(t, []Channel{ChannelEmail}, prefs.DisabledChannels)
+
+	// Turning one type on leaves the others off
+	require.NoError(t, prefs.Set(TypeMention, ChannelEmail, true))
+	assert.Empty(t, prefs.DisabledChannels)
+	for _, notificationType := range Types() {
+		assert.Equal(t, notificationType == TypeMention, prefs.Enabled(notificationType, ChannelEmail), notificationType)
+	}
+}
+
+func TestPreferences_ChannelsFor(t *testing.T) {
+	now := time.Date(2026, 5, 6, 12, 0, 0, 0, time.UTC)
+	tripID := uuid.New()
+
+	tests := []struct {
+		name     string
+		setup    func(p *Preferences)
+		tripID   uuid.UUID
+		expected []Channel
+	}{
+		{
+			name:     "defaults",
+			setup:    func(p *Preferences) {},
+			tripID:   tripID,
+			expected: []Channel{ChannelInApp, ChannelPush, ChannelEmail},
+		},
+		{
+			name: "digest replaces immediate email",
+			setup: func(p *Preferences) {
+				require.NoError(t, p.SetDigest(DigestDaily, "UTC", now))
+			},
+			tripID:   tripID,
+			expected: []Channel{ChannelInApp, ChannelPush, ChannelDigest},
+		},
+		{
+			name: "type left out of digest is emailed",
+			setup: func(p *Preferences) {
+				require.NoError(t, p.SetDigest(DigestDaily, "UTC", now))
+				require.NoError(t, p.Set(TypeJoinRequest, ChannelDigest, false))
+			},
+			tripID:   tripID,
+			expected: []Channel{ChannelInApp, ChannelPush, ChannelEmail},
+		},
+		{
+			name: "disabled channels",
+			setup: func(p *Preferences) {
+				require.NoError(t, p.Set(TypeJoinRequest, ChannelInApp, false))
+				require.NoError(t, p.Set(TypeJoinRequest, ChannelEmail, false))
+			},
+			tripID:   tripID,
+			expected: []Channel{ChannelPush},
+		},
+		{
+			name: "muted trip",
+			setup: func(p *Preferences) {
+				p.MutedTrips = []uuid.UUID{tripID}
+			},
+			tripID:   tripID,
+			expected: nil,
+		},
+		{
+			name: "other trip muted",
+			setup: func(p *Preferences) {
+				p.MutedTrips = []uuid.UUID{uuid.New()}
+			},
+			tripID:   tripID,
+			expected: []Channel{ChannelInApp, ChannelPush, ChannelEmail},
+		},
+		{
+			name: "not about a trip",
+			setup: func(p *Preferences) {
+				p.MutedTrips = []uuid.UUID{tripID}
+			},
+			tripID:   uuid.Nil,
+			expected: []Channel{ChannelInApp, ChannelPush, ChannelEmail},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := NewPreferences(uuid.New())
+			tt.setup(prefs)
+			assert.Equal(t, tt.expected, prefs.ChannelsFor(TypeJoinRequest, tt.tripID))
+		})
+	}
+}
+
+func TestNotification_HasChannel(t *testing.T) {
+	n, err := NewNotification(uuid.New(), TypeNewRating, "Anna rated you 5/5", "", EntityUser, uuid.New())
+	require.NoError(t, err)
+	assert.True(t, n.HasChannel(ChannelPush))
+
+	n.Channels = []Channel{ChannelInApp}
+	assert.True(t, n.HasChannel(ChannelInApp))
+	assert.False(t, n.HasChannel(ChannelPush))
+}
+
+func TestPreferences_SetQuietHours(t *testing.T) {
+	prefs := NewPreferences(uuid.New())
+
+	require.NoError(t, prefs.SetQuietHours("22:30", "07:00"))
+	require.NotNil(t, prefs.QuietHours)
+	assert.Equal(t, ClockTime(22*60+30), prefs.QuietHours.Start)
+	assert.Equal(t, "07:00", prefs.QuietHours.End.String())
+
+	assert.ErrorIs(t, prefs.SetQuietHours("25:00", "07:00"), ErrInvalidQuietHours)
+	assert.ErrorIs(t, prefs.SetQuietHours("22:00", ""), ErrInvalidQuietHours)
+	assert.ErrorIs(t, prefs.SetQuietHours("07:00", "07:00"), ErrInvalidQuietHours)
+	assert.NotNil(t, prefs.QuietHours, "invalid quiet hours keep the current ones")
+
+	require.NoError(t, prefs.SetQuietHours("", ""))
+	assert.Nil(t, prefs.QuietHours)
+}
+
+func TestPreferences_QuietUntil(t *testing.T) {
+	tests := []struct {
+		name     string
+		timezone string
+		start    string
+		end      string
+		now      time.Time
+		expected time.Time
+	}{
+		{
+			name:     "outside overnight quiet hours",
+			timezone: "Europe/Berlin",
+			start:    "22:00",
+			end:      "07:00",
+			now:      time.Date(2026, 5, 6, 12, 0, 0, 0, time.UTC),
+			expected: time.Time{},
+		},
+		{
+			name:     "before midnight",
+			timezone: "Europe/Berlin",
+			start:    "22:00",
+			end:      "07:00",
+			now:      time.Date(2026, 5, 6, 21, 30, 0, 0, time.UTC),
+			expected: time.Date(2026, 5, 7, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "after midnight",
+			timezone: "Europe/Berlin",
+			start:    "22:00",
+			end:      "07:00",
+			now:      time.Date(2026, 5, 7, 1, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 5, 7, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "end is exclusive",
+			timezone: "Europe/Berlin",
+			start:    "22:00",
+			end:      "07:00",
+			now:      time.Date(2026, 5, 7, 5, 0, 0, 0, time.UTC),
+			expected: time.Time{},
+		},
+		{
+			name:     "daytime quiet hours",
+			timezone: "Asia/Tokyo",
+			start:    "13:00",
+			end:      "15:00",
+			now:      time.Date(2026, 5, 6, 4, 30, 0, 0, time.UTC),
+			expected: time.Date(2026, 5, 6, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "night clocks go forward",
+			timezone: "Europe/Berlin",
+			start:    "22:00",
+			end:      "07:00",
+			now:      time.Date(2026, 3, 28, 22, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 3, 29, 5, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := NewPreferences(uuid.New())
+			prefs.Timezone = tt.timezone
+			require.NoError(t, prefs.SetQuietHours(tt.start, tt.end))
+			assert.Equal(t, tt.expected, prefs.QuietUntil(tt.now))
+		})
+	}
+}
+
+func TestNextDigestAt(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		frequency DigestFrequency
+		loc       *time.Location
+		after     time.Time
+		expected  time.Time
+	}{
+		{
+			name:      "daily later today",
+			frequency: DigestDaily,
+			loc:       time.UTC,
+			after:     time.Date(2026, 5, 6, 6, 30, 0, 0, time.UTC),
+			expected:  time.Date(2026, 5, 6, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "daily exactly at digest time moves to tomorrow",
+			frequency: DigestDaily,
+			loc:       time.UTC,
+			after:     time.Date(2026, 5, 6, 8, 0, 0, 0, time.UTC),
+			expected:  time.Date(2026, 5, 7, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "daily in a timezone ahead of UTC",
+			frequency: DigestDaily,
+			loc:       tokyo,
+			after:     time.Date(2026, 5, 6, 0, 0, 0, 0, time.UTC), // 09:00 in Tokyo
+			expected:  time.Date(2026, 5, 6, 23, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "daily across the spring DST change",
+			frequency: DigestDaily,
+			loc:       berlin,
+			after:     time.Date(2026, 3, 28, 12, 0, 0, 0, time.UTC),
+			expected:  time.Date(2026, 3, 29, 6, 0, 0, 0, time.UTC), // 08:00 CEST
+		},
+		{
+			name:      "weekly on the coming Monday",
+			frequency: DigestWeekly,
+			loc:       time.UTC,
+			after:     time.Date(2026, 5, 6, 12, 0, 0, 0, time.UTC), // Wednesday
+			expected:  time.Date(2026, 5, 11, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "weekly on a Monday after digest time",
+			frequency: DigestWeekly,
+			loc:       time.UTC,
+			after:     time.Date(2026, 5, 11, 9, 0, 0, 0, time.UTC),
+			expected:  time.Date(2026, 5, 18, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "weekly when it is already Monday in the user's timezone",
+			frequency: DigestWeekly,
+			loc:       tokyo,
+			after:     time.Date(2026, 5, 10, 20, 0, 0, 0, time.UTC), // Monday 05:00 in Tokyo
+			expected:  time.Date(2026, 5, 10, 23, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NextDigestAt(tt.frequency, tt.loc, tt.after))
+		})
+	}
+}
+
+func TestPreferences_Digest(t *testing.T) {
+	now := time.Date(2026, 5, 6, 12, 0, 0, 0, time.UTC)
+	prefs := NewPreferences(uuid.New())
+	assert.Equal(t, DigestImmediate, prefs.Digest)
+	assert.Nil(t, prefs.NextDigestAt)
+
+	assert.ErrorIs(t, prefs.SetDigest(DigestFrequency("hourly"), "UTC", now), ErrInvalidDigestFrequency)
+	assert.ErrorIs(t, prefs.SetDigest(DigestDaily, "Mars/Olympus", now), ErrInvalidTimezone)
+	assert.ErrorIs(t, prefs.SetDigest(DigestDaily, "", now), ErrInvalidTimezone)
+
+	require.NoError(t, prefs.SetDigest(DigestDaily, "Europe/Lisbon", now))
+	require.NotNil(t, prefs.LastDigestAt)
+	assert.Equal(t, now, *prefs.LastDigestAt)
+	require.NotNil(t, prefs.NextDigestAt)
+	assert.Equal(t, time.Date(2026, 5, 7, 7, 0, 0, 0, time.UTC), *prefs.NextDigestAt)
+
+	later := now.Add(time.Hour)
+	require.NoError(t, prefs.SetDigest(DigestWeekly, "Europe/Lisbon", later))
+	assert.Equal(t, now, *prefs.LastDigestAt, "switching between digests keeps the covered period")
+	assert.Equal(t, time.Date(2026, 5, 11, 7, 0, 0, 0, time.UTC), *prefs.NextDigestAt)
+
+	sentAt := time.Date(2026, 5, 11, 7, 0, 5, 0, time.UTC)
+	prefs.DigestSent(sentAt)
+	assert.Equal(t, sentAt, *prefs.LastDigestAt)
+	assert.Equal(t, time.Date(2026, 5, 18, 7, 0, 0, 0, time.UTC), *prefs.NextDigestAt)
+
+	require.NoError(t, prefs.SetDigest(DigestImmediate, "Europe/Lisbon", later))
+	assert.Nil(t, prefs.NextDigestAt)
+}
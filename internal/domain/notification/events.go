@@ -0,0 +1,14 @@
+package notification
+
+// Notification event names
+const (
+	EventCreated = "notification.created"
+)
+
+// Created is published when a notification is stored for a user
+type Created struct {
+	Notification *Notification
+}
+
+// EventName implements event.Event
+func (Created) EventName() string { return EventCreated }
@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain errors
+var (
+	ErrInvalidType            = errors.New("invalid notification type")
+	ErrInvalidDigestFrequency = errors.New("invalid digest frequency")
+	ErrInvalidTimezone        = errors.New("invalid timezone")
+	ErrInvalidChannel         = errors.New("invalid notification channel")
+	ErrInvalidQuietHours      = errors.New("quiet hours must be two different times written as HH:MM")
+)
+
+// Listing limits
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// ListFilter selects a page of a user's notifications, newest first
+type ListFilter struct {
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
+
+// Normalize applies defaults and bounds to the filter
+func (f *ListFilter) Normalize() {
+	if f.Limit <= 0 {
+		f.Limit = DefaultListLimit
+	}
+	if f.Limit > MaxListLimit {
+		f.Limit = MaxListLimit
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+}
+
+// Repository defines the interface for notification persistence
+type Repository interface {
+	// Create stores a new notification
+	Create(ctx context.Context, n *Notification) error
+
+	// List retrieves a page of the user's in-app notifications, newest first
+	List(ctx context.Context, userID uuid.UUID, filter ListFilter) ([]*Notification, error)
+
+	// CountUnread returns the number of unread in-app notifications of the user
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+
+	// HasUnread returns true if the user has an unread notification of the
+	// given type about the entity
+	HasUnread(ctx context.Context, userID uuid.UUID, notificationType Type, entityType EntityType, entityID uuid.UUID) (bool, error)
+
+	// ListUnreadSince retrieves up to limit of the user's unread digest
+	// notifications created after the given time, oldest first
+	ListUnreadSince(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]*Notification, error)
+
+	// MarkRead marks the given notifications of the user as read, or all of
+	// them when no IDs are given, and returns how many changed
+	MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, readAt time.Time) (int, error)
+}
+
+// PreferenceRepository defines the interface for notification preference persistence
+type PreferenceRepository interface {
+	// GetPreferences retrieves the user's preferences, with every channel
+	// enabled when the user never chose
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*Preferences, error)
+
+	// SavePreferences stores the user's preferences, except muted trips
+	SavePreferences(ctx context.Context, prefs *Preferences) error
+
+	// MuteTrip stops notifications about the trip reaching the user
+	MuteTrip(ctx context.Context, userID, tripID uuid.UUID) error
+
+	// UnmuteTrip lets notifications about the trip reach the user again
+	UnmuteTrip(ctx context.Context, userID, tripID uuid.UUID) error
+
+	// ClaimDueDigests returns up to limit users whose digest is due and pushes
+	// their next digest past the lease, so that concurrent workers never send
+	// the same digest twice
+	ClaimDueDigests(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]uuid.UUID, error)
+}
@@ -0,0 +1,221 @@
+package push
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Subscription key sizes, as produced by the browser's PushManager
+const (
+	P256dhKeySize = 65
+	AuthKeySize   = 16
+)
+
+// Subscription limits
+const (
+	MaxUserAgentLength      = 255
+	MaxSubscriptionsPerUser = 20
+)
+
+// MaxPayloadSize is the largest payload that fits a single encrypted record
+// in the 4096 bytes push services must accept (RFC 8291 section 4)
+const MaxPayloadSize = 3993
+
+// Subscription is a browser's push endpoint for one of a user's devices,
+// with the keys its messages are encrypted to
+type Subscription struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Endpoint   string     `json:"endpoint"`
+	P256dh     string     `json:"-"`
+	Auth       string     `json:"-"`
+	UserAgent  string     `json:"user_agent"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// NewSubscription validates a browser subscription for a user. The keys are
+// base64url encoded, as in PushSubscription.toJSON().
+func NewSubscription(userID uuid.UUID, endpoint, p256dh, auth, userAgent string) (*Subscription, error) {
+	if userID == uuid.Nil {
+		return nil, errors.New("user ID is required")
+	}
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" || !isPublicHost(parsed.Hostname()) {
+		return nil, ErrInvalidEndpoint
+	}
+
+	if key, err := DecodeKey(p256dh); err != nil || len(key) != P256dhKeySize || key[0] != 0x04 {
+		return nil, ErrInvalidKeys
+	}
+	if key, err := DecodeKey(auth); err != nil || len(key) != AuthKeySize {
+		return nil, ErrInvalidKeys
+	}
+
+	userAgent = strings.TrimSpace(userAgent)
+	if runes := []rune(userAgent); len(runes) > MaxUserAgentLength {
+		userAgent = string(runes[:MaxUserAgentLength])
+	}
+
+	return &Subscription{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Endpoint:  endpoint,
+		P256dh:    p256dh,
+		Auth:      auth,
+		UserAgent: userAgent,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// isPublicHost rejects endpoints that obviously point into the server's own
+// network. Names that resolve to such addresses are caught when dialing.
+func isPublicHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return IsPublicAddress(addr)
+	}
+	return true
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598)
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// IsPublicAddress returns true if the address is routable on the internet,
+// as push services are. Loopback, private, link-local and similar addresses
+// would let a subscription reach services inside the server's network.
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// DecodeKey decodes a base64url key, with or without padding
+func DecodeKey(key string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(key, "="))
+}
+
+// Urgency tells the push service how soon a message must reach the device
+// (RFC 8030 section 5.3)
+type Urgency string
+
+const (
+	UrgencyLow    Urgency = "low"
+	UrgencyNormal Urgency = "normal"
+	UrgencyHigh   Urgency = "high"
+)
+
+// DeliveryStatus represents the state of a queued push message
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending DeliveryStatus = "pending"
+	DeliveryStatusSent    DeliveryStatus = "sent"
+	DeliveryStatusFailed  DeliveryStatus = "failed"
+)
+
+// Delivery retry policy. Push messages are only worth delivering while they
+// are fresh, so retries stop when the message's TTL runs out.
+const (
+	MaxAttempts    = 5
+	RetryBaseDelay = 30 * time.Second
+	DefaultTTL     = 24 * time.Hour
+	MaxErrorLength = 500
+)
+
+// Delivery is a push message queued for one subscription
+type Delivery struct {
+	ID             uuid.UUID      `json:"id"`
+	SubscriptionID uuid.UUID      `json:"subscription_id"`
+	Payload        []byte         `json:"payload"`
+	Urgency        Urgency        `json:"urgency"`
+	Status         DeliveryStatus `json:"status"`
+	Attempts       int            `json:"attempts"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at"`
+	ExpiresAt      time.Time      `json:"expires_at"`
+	LastError      string         `json:"last_error,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// NewDelivery queues a payload for a subscription, to be dropped once the TTL
+// has passed
+func NewDelivery(subscriptionID uuid.UUID, payload []byte, urgency Urgency, ttl time.Duration) (*Delivery, error) {
+	if subscriptionID == uuid.Nil {
+		return nil, errors.New("subscription ID is required")
+	}
+	if len(payload) > MaxPayloadSize {
+		return nil, ErrPayloadTooLarge
+	}
+
+	now := time.Now().UTC()
+	return &Delivery{
+		ID:             uuid.New(),
+		SubscriptionID: subscriptionID,
+		Payload:        payload,
+		Urgency:        urgency,
+		Status:         DeliveryStatusPending,
+		NextAttemptAt:  now,
+		ExpiresAt:      now.Add(ttl),
+		CreatedAt:      now,
+	}, nil
+}
+
+// TTL returns how long the push service should keep the message for an
+// offline device, counted from now
+func (d *Delivery) TTL(now time.Time) time.Duration {
+	if ttl := d.ExpiresAt.Sub(now); ttl > 0 {
+		return ttl
+	}
+	return 0
+}
+
+// HoldUntil postpones the first attempt to the given time, such as the end of
+// the recipient's quiet hours. The expiry moves along, so a held message
+// still gets its whole TTL. Earlier times are ignored.
+func (d *Delivery) HoldUntil(until time.Time) {
+	if !until.After(d.NextAttemptAt) {
+		return
+	}
+	d.ExpiresAt = d.ExpiresAt.Add(until.Sub(d.NextAttemptAt))
+	d.NextAttemptAt = until
+}
+
+// MarkSent records a successful delivery
+func (d *Delivery) MarkSent() {
+	d.Attempts++
+	d.Status = DeliveryStatusSent
+	d.LastError = ""
+}
+
+// MarkFailed records a failed delivery. Retryable failures are attempted again
+// after the given delay, or after an exponential backoff when there is none,
+// until the attempts or the TTL run out.
+func (d *Delivery) MarkFailed(now time.Time, cause error, retryable bool, retryAfter time.Duration) {
+	d.Attempts++
+	d.LastError = cause.Error()
+	if runes := []rune(d.LastError); len(runes) > MaxErrorLength {
+		d.LastError = string(runes[:MaxErrorLength])
+	}
+
+	if retryAfter <= 0 {
+		retryAfter = RetryBaseDelay << (d.Attempts - 1)
+	}
+	next := now.Add(retryAfter)
+
+	if !retryable || d.Attempts >= MaxAttempts || !next.Before(d.ExpiresAt) {
+		d.Status = DeliveryStatusFailed
+		return
+	}
+	d.NextAttemptAt = next
+}
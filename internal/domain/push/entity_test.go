@@ -0,0 +1,133 @@
+package push
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testP256dh = "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"
+	testAuth   = "BTBZMqHH6r4Tts7J_aSIgg"
+)
+
+func TestNewSubscription(t *testing.T) {
+	userID := uuid.New()
+	endpoint := "https://fcm.googleapis.com/fcm/send/abc123"
+
+	sub, err := NewSubscription(userID, endpoint, testP256dh, testAuth, "  Firefox on Linux ")
+	require.NoError(t, err)
+	assert.Equal(t, endpoint, sub.Endpoint)
+	assert.Equal(t, "Firefox on Linux", sub.UserAgent)
+
+	_, err = NewSubscription(userID, endpoint, testP256dh, testAuth+"==", strings.Repeat("a", 300))
+	assert.NoError(t, err, "padded keys are accepted")
+
+	tests := []struct {
+		name     string
+		endpoint string
+		p256dh   string
+		auth     string
+		expected error
+	}{
+		{"plain http endpoint", "http://push.example/abc", testP256dh, testAuth, ErrInvalidEndpoint},
+		{"not a URL", "::", testP256dh, testAuth, ErrInvalidEndpoint},
+		{"localhost", "https://localhost:8443/abc", testP256dh, testAuth, ErrInvalidEndpoint},
+		{"loopback address", "https://127.0.0.1/abc", testP256dh, testAuth, ErrInvalidEndpoint},
+		{"private address", "https://10.0.0.5/abc", testP256dh, testAuth, ErrInvalidEndpoint},
+		{"link-local address", "https://169.254.169.254/latest", testP256dh, testAuth, ErrInvalidEndpoint},
+		{"IPv6 loopback", "https://[::1]/abc", testP256dh, testAuth, ErrInvalidEndpoint},
+		{"short public key", endpoint, testAuth, testAuth, ErrInvalidKeys},
+		{"bad encoding", endpoint, "!!!", testAuth, ErrInvalidKeys},
+		{"short auth secret", endpoint, testP256dh, "AAAA", ErrInvalidKeys},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSubscription(userID, tt.endpoint, tt.p256dh, tt.auth, "")
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestNewDelivery(t *testing.T) {
+	delivery, err := NewDelivery(uuid.New(), []byte(`{"title":"Hi"}`), UrgencyNormal, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryStatusPending, delivery.Status)
+	assert.Equal(t, time.Hour, delivery.ExpiresAt.Sub(delivery.CreatedAt))
+
+	_, err = NewDelivery(uuid.New(), make([]byte, MaxPayloadSize+1), UrgencyNormal, time.Hour)
+	assert.ErrorIs(t, err, ErrPayloadTooLarge)
+}
+
+func TestDelivery_MarkFailed(t *testing.T) {
+	now := time.Date(2026, 5, 6, 12, 0, 0, 0, time.UTC)
+	newDelivery := func(ttl time.Duration) *Delivery {
+		return &Delivery{Status: DeliveryStatusPending, NextAttemptAt: now, ExpiresAt: now.Add(ttl)}
+	}
+	cause := errors.New("service unavailable")
+
+	t.Run("backs off exponentially", func(t *testing.T) {
+		d := newDelivery(DefaultTTL)
+		d.MarkFailed(now, cause, true, 0)
+		assert.Equal(t, now.Add(RetryBaseDelay), d.NextAttemptAt)
+		d.MarkFailed(now, cause, true, 0)
+		assert.Equal(t, now.Add(2*RetryBaseDelay), d.NextAttemptAt)
+		assert.Equal(t, DeliveryStatusPending, d.Status)
+		assert.Equal(t, "service unavailable", d.LastError)
+	})
+
+	t.Run("honours retry after", func(t *testing.T) {
+		d := newDelivery(DefaultTTL)
+		d.MarkFailed(now, cause, true, 10*time.Minute)
+		assert.Equal(t, now.Add(10*time.Minute), d.NextAttemptAt)
+	})
+
+	t.Run("gives up on permanent failures", func(t *testing.T) {
+		d := newDelivery(DefaultTTL)
+		d.MarkFailed(now, cause, false, 0)
+		assert.Equal(t, DeliveryStatusFailed, d.Status)
+	})
+
+	t.Run("gives up when the message would expire", func(t *testing.T) {
+		d := newDelivery(time.Minute)
+		d.MarkFailed(now, cause, true, 5*time.Minute)
+		assert.Equal(t, DeliveryStatusFailed, d.Status)
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		d := newDelivery(DefaultTTL)
+		for i := 0; i < MaxAttempts; i++ {
+			d.MarkFailed(now, cause, true, time.Second)
+		}
+		assert.Equal(t, DeliveryStatusFailed, d.Status)
+		assert.Equal(t, MaxAttempts, d.Attempts)
+	})
+}
+
+func TestDelivery_HoldUntil(t *testing.T) {
+	delivery, err := NewDelivery(uuid.New(), []byte(`{"title":"Hi"}`), UrgencyNormal, time.Hour)
+	require.NoError(t, err)
+	created := delivery.NextAttemptAt
+
+	delivery.HoldUntil(time.Time{})
+	assert.Equal(t, created, delivery.NextAttemptAt)
+
+	until := created.Add(8 * time.Hour)
+	delivery.HoldUntil(until)
+	assert.Equal(t, until, delivery.NextAttemptAt)
+	assert.Equal(t, time.Hour, delivery.TTL(until))
+}
+
+func TestDelivery_TTL(t *testing.T) {
+	now := time.Date(2026, 5, 6, 12, 0, 0, 0, time.UTC)
+	d := &Delivery{ExpiresAt: now.Add(time.Hour)}
+
+	assert.Equal(t, time.Hour, d.TTL(now))
+	assert.Equal(t, time.Duration(0), d.TTL(now.Add(2*time.Hour)))
+}
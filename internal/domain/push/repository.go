@@ -0,0 +1,77 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain errors
+var (
+	ErrInvalidEndpoint      = errors.New("push endpoint must be a public https URL")
+	ErrInvalidKeys          = errors.New("invalid push subscription keys")
+	ErrSubscriptionNotFound = errors.New("push subscription not found")
+	ErrSubscriptionGone     = errors.New("push subscription expired or was unsubscribed")
+	ErrPayloadTooLarge      = errors.New("push payload too large")
+	ErrPushNotConfigured    = errors.New("push notifications are not configured")
+	ErrTooManySubscriptions = errors.New("too many push subscriptions")
+)
+
+// SubscriptionRepository defines the interface for push subscription persistence
+type SubscriptionRepository interface {
+	// Save stores a subscription. A subscription with the same endpoint is
+	// replaced, so that re-registering a device refreshes its keys and owner.
+	Save(ctx context.Context, sub *Subscription) error
+
+	// GetByID retrieves a subscription by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*Subscription, error)
+
+	// ListByUser retrieves the user's subscriptions, newest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*Subscription, error)
+
+	// Delete removes one of the user's subscriptions
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+
+	// DeleteByID removes a subscription, e.g. after the push service
+	// reported it gone
+	DeleteByID(ctx context.Context, id uuid.UUID) error
+
+	// Touch records that a message was delivered to the subscription
+	Touch(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
+
+// DeliveryRepository defines the interface for queued push message persistence
+type DeliveryRepository interface {
+	// Enqueue stores a new delivery
+	Enqueue(ctx context.Context, delivery *Delivery) error
+
+	// ClaimDue returns up to limit pending deliveries whose next attempt is
+	// due and hides them from other claimers for the lease duration
+	ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*Delivery, error)
+
+	// Update stores the state of a delivery
+	Update(ctx context.Context, delivery *Delivery) error
+
+	// DeleteFinishedBefore removes sent, failed and expired deliveries
+	// created before the given time
+	DeleteFinishedBefore(ctx context.Context, before time.Time) error
+}
+
+// DeliveryError is a failed attempt to hand a message to a push service
+type DeliveryError struct {
+	StatusCode int
+	Retryable  bool
+	RetryAfter time.Duration
+	Message    string
+}
+
+// Error implements error
+func (e *DeliveryError) Error() string {
+	if e.StatusCode == 0 {
+		return "push delivery failed: " + e.Message
+	}
+	return fmt.Sprintf("push service responded %d: %s", e.StatusCode, e.Message)
+}
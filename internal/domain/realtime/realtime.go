@@ -0,0 +1,64 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Event types pushed to connected clients
+const (
+	TypeMessageCreated      = "message.created"
+	TypeTyping              = "typing"
+	TypeNotificationCreated = "notification.created"
+	TypeHeartbeat           = "heartbeat"
+)
+
+// Event is a real-time update pushed to a user's connected clients. Events
+// that are kept in the event log carry the log ID, which clients use to
+// resume a stream.
+type Event struct {
+	ID   int64           `json:"id,omitempty"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// NewEvent creates an event carrying the JSON encoding of data
+func NewEvent(eventType string, data any) (Event, error) {
+	if eventType == "" {
+		return Event{}, errors.New("event type is required")
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	return Event{Type: eventType, Data: encoded}, nil
+}
+
+// IsReplayable returns true if the event is kept in the event log and replayed
+// to clients that reconnect. Transient signals such as typing indicators are not.
+func (e Event) IsReplayable() bool {
+	switch e.Type {
+	case TypeTyping, TypeHeartbeat:
+		return false
+	default:
+		return true
+	}
+}
+
+// Delivery addresses an event to a set of users
+type Delivery struct {
+	UserIDs []uuid.UUID `json:"user_ids"`
+	Event   Event       `json:"event"`
+}
+
+// Broker fans deliveries out to every server instance, each of which hands
+// them to the clients connected to it
+type Broker interface {
+	Publish(ctx context.Context, delivery Delivery) error
+}
@@ -0,0 +1,39 @@
+package realtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEvent(t *testing.T) {
+	e, err := NewEvent(TypeMessageCreated, map[string]string{"body": "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, TypeMessageCreated, e.Type)
+	assert.JSONEq(t, `{"body":"hi"}`, string(e.Data))
+
+	_, err = NewEvent("", nil)
+	assert.Error(t, err)
+
+	_, err = NewEvent(TypeTyping, make(chan int))
+	assert.Error(t, err)
+}
+
+func TestEvent_IsReplayable(t *testing.T) {
+	tests := []struct {
+		eventType string
+		expected  bool
+	}{
+		{TypeMessageCreated, true},
+		{TypeNotificationCreated, true},
+		{TypeTyping, false},
+		{TypeHeartbeat, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Event{Type: tt.eventType}.IsReplayable())
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain errors
+var (
+	ErrInvalidLastEventID = errors.New("invalid last event ID")
+)
+
+// EventLog keeps the replayable events pushed to users so that clients can
+// resume a stream after reconnecting
+type EventLog interface {
+	// Append stores the delivery's event and assigns its ID
+	Append(ctx context.Context, delivery *Delivery) error
+	// ListSince returns the user's events logged after the given ID, oldest first
+	ListSince(ctx context.Context, userID uuid.UUID, afterID int64, limit int) ([]Event, error)
+	// DeleteBefore removes events logged before the given time
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
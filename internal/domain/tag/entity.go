@@ -0,0 +1,165 @@
+package tag
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"jointrip/internal/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// Category groups tags in the curated taxonomy
+type Category string
+
+const (
+	CategoryActivity Category = "activity"
+	CategoryStyle    Category = "style"
+	CategoryBudget   Category = "budget"
+)
+
+// IsValid returns true if the category is part of the taxonomy
+func (c Category) IsValid() bool {
+	switch c {
+	case CategoryActivity, CategoryStyle, CategoryBudget:
+		return true
+	}
+	return false
+}
+
+// DefaultColor is used when a tag is created without a color
+const DefaultColor = "#6B7280"
+
+var (
+	namePattern  = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+)
+
+// Tag represents a curated trip tag
+type Tag struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Category    Category  `json:"category"`
+	Description string    `json:"description"`
+	Color       string    `json:"color"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewTag creates a new tag. Style tags must use a user.TravelStyle value as
+// their name so trip styles line up with the styles users pick on their profile.
+func NewTag(name string, category Category, description, color string) (*Tag, error) {
+	name = NormalizeName(name)
+	if err := validate(name, category, color); err != nil {
+		return nil, err
+	}
+
+	if color == "" {
+		color = DefaultColor
+	}
+
+	now := time.Now()
+	return &Tag{
+		ID:          uuid.New(),
+		Name:        name,
+		Category:    category,
+		Description: description,
+		Color:       color,
+		IsActive:    true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Update changes the tag's descriptive fields
+func (t *Tag) Update(name string, category Category, description, color string) error {
+	name = NormalizeName(name)
+	if err := validate(name, category, color); err != nil {
+		return err
+	}
+
+	if color == "" {
+		color = DefaultColor
+	}
+
+	t.Name = name
+	t.Category = category
+	t.Description = description
+	t.Color = color
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// Deactivate hides the tag from new assignments and listings
+func (t *Tag) Deactivate() {
+	t.IsActive = false
+	t.UpdatedAt = time.Now()
+}
+
+// Activate makes the tag available again
+func (t *Tag) Activate() {
+	t.IsActive = true
+	t.UpdatedAt = time.Now()
+}
+
+// TravelStyle returns the travel style a style tag represents
+func (t *Tag) TravelStyle() (user.TravelStyle, bool) {
+	if t.Category != CategoryStyle {
+		return "", false
+	}
+	style := user.TravelStyle(t.Name)
+	return style, style.IsValid()
+}
+
+// NormalizeName lowercases a tag name and turns whitespace into dashes
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), "-")
+}
+
+// validate checks tag fields against the taxonomy rules
+func validate(name string, category Category, color string) error {
+	if name == "" {
+		return ErrInvalidTagName
+	}
+	if !namePattern.MatchString(name) {
+		return ErrInvalidTagName
+	}
+	if !category.IsValid() {
+		return ErrInvalidCategory
+	}
+	if category == CategoryStyle && !user.TravelStyle(name).IsValid() {
+		return ErrUnknownTravelStyle
+	}
+	if color != "" && !colorPattern.MatchString(color) {
+		return ErrInvalidTagColor
+	}
+	return nil
+}
+
+// Assignment links a tag to a trip
+type Assignment struct {
+	ID         uuid.UUID `json:"id"`
+	TripID     uuid.UUID `json:"trip_id"`
+	TagID      uuid.UUID `json:"tag_id"`
+	AssignedBy uuid.UUID `json:"assigned_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewAssignment creates a new tag assignment
+func NewAssignment(tripID, tagID, assignedBy uuid.UUID) *Assignment {
+	return &Assignment{
+		ID:         uuid.New(),
+		TripID:     tripID,
+		TagID:      tagID,
+		AssignedBy: assignedBy,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// Usage reports how many trips use a tag
+type Usage struct {
+	Tag        *Tag `json:"tag"`
+	UsageCount int  `json:"usage_count"`
+}
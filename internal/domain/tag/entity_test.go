@@ -0,0 +1,129 @@
+package tag
+
+import (
+	"testing"
+
+	"jointrip/internal/domain/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		tagName     string
+		category    Category
+		color       string
+		expectError error
+		wantName    string
+	}{
+		{
+			name:     "valid activity tag",
+			tagName:  "Hiking",
+			category: CategoryActivity,
+			wantName: "hiking",
+		},
+		{
+			name:     "name is normalized",
+			tagName:  "  Scuba   Diving ",
+			category: CategoryActivity,
+			color:    "#1A2B3C",
+			wantName: "scuba-diving",
+		},
+		{
+			name:     "style tag using a travel style",
+			tagName:  "Mid-Range",
+			category: CategoryStyle,
+			wantName: "mid-range",
+		},
+		{
+			name:        "style tag outside the travel styles",
+			tagName:     "glamping",
+			category:    CategoryStyle,
+			expectError: ErrUnknownTravelStyle,
+		},
+		{
+			name:        "invalid category",
+			tagName:     "hiking",
+			category:    Category("weather"),
+			expectError: ErrInvalidCategory,
+		},
+		{
+			name:        "empty name",
+			tagName:     "   ",
+			category:    CategoryActivity,
+			expectError: ErrInvalidTagName,
+		},
+		{
+			name:        "invalid characters",
+			tagName:     "food&wine",
+			category:    CategoryActivity,
+			expectError: ErrInvalidTagName,
+		},
+		{
+			name:        "invalid color",
+			tagName:     "hiking",
+			category:    CategoryActivity,
+			color:       "green",
+			expectError: ErrInvalidTagColor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, err := NewTag(tt.tagName, tt.category, "", tt.color)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, tag)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, tag.Name)
+			assert.Equal(t, tt.category, tag.Category)
+			assert.True(t, tag.IsActive)
+			if tt.color == "" {
+				assert.Equal(t, DefaultColor, tag.Color)
+			} else {
+				assert.Equal(t, tt.color, tag.Color)
+			}
+		})
+	}
+}
+
+func TestTag_TravelStyle(t *testing.T) {
+	for _, style := range user.TravelStyles() {
+		tag, err := NewTag(string(style), CategoryStyle, "", "")
+		require.NoError(t, err)
+
+		got, ok := tag.TravelStyle()
+		assert.True(t, ok)
+		assert.Equal(t, style, got)
+	}
+
+	activity, err := NewTag("budget", CategoryBudget, "", "")
+	require.NoError(t, err)
+
+	_, ok := activity.TravelStyle()
+	assert.False(t, ok)
+}
+
+func TestTag_UpdateAndDeactivate(t *testing.T) {
+	tag, err := NewTag("hiking", CategoryActivity, "", "")
+	require.NoError(t, err)
+
+	require.NoError(t, tag.Update("Trekking", CategoryActivity, "Multi-day walks", "#10B981"))
+	assert.Equal(t, "trekking", tag.Name)
+	assert.Equal(t, "#10B981", tag.Color)
+
+	assert.ErrorIs(t, tag.Update("trekking", CategoryStyle, "", ""), ErrUnknownTravelStyle)
+	assert.Equal(t, CategoryActivity, tag.Category)
+
+	tag.Deactivate()
+	assert.False(t, tag.IsActive)
+
+	tag.Activate()
+	assert.True(t, tag.IsActive)
+}
@@ -0,0 +1,59 @@
+package tag
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Domain errors
+var (
+	ErrTagNotFound        = errors.New("tag not found")
+	ErrTagAlreadyExists   = errors.New("tag already exists")
+	ErrTagInactive        = errors.New("tag is not active")
+	ErrInvalidTagName     = errors.New("tag name may only contain lowercase letters, digits and dashes")
+	ErrInvalidTagColor    = errors.New("tag color must be a hex color like #1A2B3C")
+	ErrInvalidCategory    = errors.New("invalid tag category")
+	ErrUnknownTravelStyle = errors.New("style tags must use a supported travel style")
+	ErrTooManyTags        = errors.New("too many tags for one trip")
+)
+
+// MaxTagsPerTrip limits how many tags a single trip can carry
+const MaxTagsPerTrip = 10
+
+// ListFilter narrows tag listings
+type ListFilter struct {
+	Category        *Category
+	IncludeInactive bool
+}
+
+// Repository defines the interface for tag data persistence
+type Repository interface {
+	// Create creates a new tag
+	Create(ctx context.Context, tag *Tag) error
+
+	// GetByID retrieves a tag by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*Tag, error)
+
+	// GetByIDs retrieves all tags with the given IDs
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*Tag, error)
+
+	// List retrieves tags ordered by category and name
+	List(ctx context.Context, filter ListFilter) ([]*Tag, error)
+
+	// Update updates an existing tag
+	Update(ctx context.Context, tag *Tag) error
+
+	// ListByTrip retrieves the tags assigned to a trip
+	ListByTrip(ctx context.Context, tripID uuid.UUID) ([]*Tag, error)
+
+	// ReplaceTripTags replaces every tag assignment of a trip
+	ReplaceTripTags(ctx context.Context, tripID uuid.UUID, assignments []*Assignment) error
+
+	// RemoveFromTrip removes a single tag from a trip
+	RemoveFromTrip(ctx context.Context, tripID, tagID uuid.UUID) error
+
+	// ListPopular retrieves active tags ordered by the number of visible trips using them
+	ListPopular(ctx context.Context, limit int) ([]*Usage, error)
+}
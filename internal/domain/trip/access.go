@@ -0,0 +1,53 @@
+package trip
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Access is what a user may see of a trip
+type Access struct {
+	// Member is true for the organizer and approved participants
+	Member bool
+	// Visible is true if the user may see the trip and what belongs to it
+	Visible bool
+}
+
+// IsOpenToPublic returns true if anyone may see the trip: it is public and
+// has been published
+func (t *Trip) IsOpenToPublic() bool {
+	return t.IsPublic && !t.IsDraft()
+}
+
+// AccessFor returns what the user may see of the trip, given their
+// participation, which is nil if they never asked to join. The organizer and
+// approved participants see the trip even while it is a draft; anyone else
+// only once a public trip is published.
+func (t *Trip) AccessFor(userID uuid.UUID, participant *Participant) Access {
+	member := t.IsOrganizer(userID) || (participant != nil && participant.UserID == userID && participant.IsApproved())
+	return Access{
+		Member:  member,
+		Visible: member || t.IsOpenToPublic(),
+	}
+}
+
+// CheckAccess looks up the user's participation and returns what they may
+// see of the trip. Every service that shows trip content goes through it, so
+// the visibility rule lives in one place.
+func CheckAccess(ctx context.Context, participants ParticipantRepository, t *Trip, userID uuid.UUID) (Access, error) {
+	if t.IsOrganizer(userID) {
+		return t.AccessFor(userID, nil), nil
+	}
+
+	participant, err := participants.GetByTripAndUser(ctx, t.ID, userID)
+	if errors.Is(err, ErrParticipantNotFound) {
+		return t.AccessFor(userID, nil), nil
+	}
+	if err != nil {
+		return Access{}, err
+	}
+
+	return t.AccessFor(userID, participant), nil
+}
@@ -0,0 +1,179 @@
+package trip
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status represents the lifecycle state of a trip
+type Status string
+
+const (
+	StatusActive    Status = "active"
+	StatusCompleted Status = "completed"
+	StatusCanceled  Status = "canceled"
+	StatusFull      Status = "full"
+	StatusDraft     Status = "draft"
+)
+
+// Trip represents a travel opportunity posted by a user
+type Trip struct {
+	ID                  uuid.UUID `json:"id"`
+	CreatorID           uuid.UUID `json:"creator_id"`
+	Title               string    `json:"title"`
+	Description         string    `json:"description"`
+	DestinationCountry  string    `json:"destination_country"`
+	DestinationCity     string    `json:"destination_city"`
+	StartDate           time.Time `json:"start_date"`
+	EndDate             time.Time `json:"end_date"`
+	MaxParticipants     int       `json:"max_participants"`
+	CurrentParticipants int       `json:"current_participants"`
+	EstimatedBudget     float64   `json:"estimated_budget"`
+	Currency            string    `json:"currency"`
+	TripType            string    `json:"trip_type"`
+	Status              Status    `json:"status"`
+	IsPublic            bool      `json:"is_public"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// NewTrip creates a new trip; the creator counts as the first participant
+func NewTrip(creatorID uuid.UUID, title, destinationCountry, destinationCity string, startDate, endDate time.Time, maxParticipants int) (*Trip, error) {
+	if creatorID == uuid.Nil {
+		return nil, errors.New("creator ID is required")
+	}
+	if title == "" {
+		return nil, errors.New("title is required")
+	}
+	if destinationCountry == "" {
+		return nil, errors.New("destination country is required")
+	}
+	if !endDate.After(startDate) {
+		return nil, errors.New("end date must be after start date")
+	}
+	if maxParticipants < 2 {
+		return nil, errors.New("a trip needs room for at least two participants")
+	}
+
+	now := time.Now()
+	trip := &Trip{
+		ID:                  uuid.New(),
+		CreatorID:           creatorID,
+		Title:               title,
+		DestinationCountry:  destinationCountry,
+		DestinationCity:     destinationCity,
+		StartDate:           startDate,
+		EndDate:             endDate,
+		MaxParticipants:     maxParticipants,
+		CurrentParticipants: 1,
+		Currency:            "EUR",
+		Status:              StatusActive,
+		IsPublic:            true,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+
+	return trip, nil
+}
+
+// UpdateDetails changes the descriptive fields and dates of a trip. It reports
+// whether the dates changed.
+func (t *Trip) UpdateDetails(title, description, destinationCountry, destinationCity string, startDate, endDate time.Time) (bool, error) {
+	if title == "" || destinationCountry == "" || !endDate.After(startDate) {
+		return false, ErrInvalidTripDetails
+	}
+
+	datesChanged := !t.StartDate.Equal(startDate) || !t.EndDate.Equal(endDate)
+
+	t.Title = title
+	t.Description = description
+	t.DestinationCountry = destinationCountry
+	t.DestinationCity = destinationCity
+	t.StartDate = startDate
+	t.EndDate = endDate
+	t.UpdatedAt = time.Now()
+
+	return datesChanged, nil
+}
+
+// IsOrganizer returns true if the given user created the trip
+func (t *Trip) IsOrganizer(userID uuid.UUID) bool {
+	return t.CreatorID == userID
+}
+
+// Covers returns true if the given time falls on one of the trip's days
+func (t *Trip) Covers(at time.Time) bool {
+	start := time.Date(t.StartDate.Year(), t.StartDate.Month(), t.StartDate.Day(), 0, 0, 0, 0, at.Location())
+	end := time.Date(t.EndDate.Year(), t.EndDate.Month(), t.EndDate.Day(), 0, 0, 0, 0, at.Location()).AddDate(0, 0, 1)
+	return !at.Before(start) && at.Before(end)
+}
+
+// DurationDays returns the number of days between the start and end dates
+func (t *Trip) DurationDays() int {
+	return int(t.EndDate.Sub(t.StartDate).Hours() / 24)
+}
+
+// IsFull returns true if no seats are left on the trip
+func (t *Trip) IsFull() bool {
+	return t.CurrentParticipants >= t.MaxParticipants
+}
+
+// AvailableSeats returns the number of free seats on the trip
+func (t *Trip) AvailableSeats() int {
+	if t.IsFull() {
+		return 0
+	}
+	return t.MaxParticipants - t.CurrentParticipants
+}
+
+// IsJoinable returns true if the trip still accepts join requests
+func (t *Trip) IsJoinable() bool {
+	return t.Status == StatusActive || t.Status == StatusFull
+}
+
+// IsDraft returns true if the trip has not been published yet
+func (t *Trip) IsDraft() bool {
+	return t.Status == StatusDraft
+}
+
+// Publish makes a draft trip visible and open for join requests
+func (t *Trip) Publish() error {
+	if !t.IsDraft() {
+		return ErrTripNotDraft
+	}
+
+	t.Status = StatusActive
+	if t.IsFull() {
+		t.Status = StatusFull
+	}
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// AddParticipant takes a seat on the trip
+func (t *Trip) AddParticipant() error {
+	if t.IsFull() {
+		return ErrTripFull
+	}
+
+	t.CurrentParticipants++
+	if t.IsFull() && t.Status == StatusActive {
+		t.Status = StatusFull
+	}
+	t.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// RemoveParticipant frees a seat on the trip
+func (t *Trip) RemoveParticipant() {
+	if t.CurrentParticipants > 0 {
+		t.CurrentParticipants--
+	}
+	if !t.IsFull() && t.Status == StatusFull {
+		t.Status = StatusActive
+	}
+	t.UpdatedAt = time.Now()
+}
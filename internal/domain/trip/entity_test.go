@@ -0,0 +1,234 @@
+package trip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTrip(t *testing.T) {
+	creatorID := uuid.New()
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 10)
+
+	tests := []struct {
+		name            string
+		creatorID       uuid.UUID
+		title           string
+		country         string
+		startDate       time.Time
+		endDate         time.Time
+		maxParticipants int
+		expectError     bool
+	}{
+		{
+			name:            "valid trip creation",
+			creatorID:       creatorID,
+			title:           "Iceland ring road",
+			country:         "Iceland",
+			startDate:       start,
+			endDate:         end,
+			maxParticipants: 4,
+			expectError:     false,
+		},
+		{
+			name:            "missing creator",
+			creatorID:       uuid.Nil,
+			title:           "Iceland ring road",
+			country:         "Iceland",
+			startDate:       start,
+			endDate:         end,
+			maxParticipants: 4,
+			expectError:     true,
+		},
+		{
+			name:            "missing title",
+			creatorID:       creatorID,
+			title:           "",
+			country:         "Iceland",
+			startDate:       start,
+			endDate:         end,
+			maxParticipants: 4,
+			expectError:     true,
+		},
+		{
+			name:            "end before start",
+			creatorID:       creatorID,
+			title:           "Iceland ring road",
+			country:         "Iceland",
+			startDate:       end,
+			endDate:         start,
+			maxParticipants: 4,
+			expectError:     true,
+		},
+		{
+			name:            "too few seats",
+			creatorID:       creatorID,
+			title:           "Iceland ring road",
+			country:         "Iceland",
+			startDate:       start,
+			endDate:         end,
+			maxParticipants: 1,
+			expectError:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trip, err := NewTrip(tt.creatorID, tt.title, tt.country, "", tt.startDate, tt.endDate, tt.maxParticipants)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, trip)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, trip)
+
+				assert.Equal(t, tt.creatorID, trip.CreatorID)
+				assert.Equal(t, 1, trip.CurrentParticipants)
+				assert.Equal(t, StatusActive, trip.Status)
+				assert.True(t, trip.IsOrganizer(tt.creatorID))
+			}
+		})
+	}
+}
+
+func TestTrip_Seats(t *testing.T) {
+	start := time.Now()
+	trip, err := NewTrip(uuid.New(), "Weekend in Porto", "Portugal", "Porto", start, start.Add(48*time.Hour), 2)
+	require.NoError(t, err)
+
+	assert.False(t, trip.IsFull())
+	assert.Equal(t, 1, trip.AvailableSeats())
+
+	require.NoError(t, trip.AddParticipant())
+	assert.True(t, trip.IsFull())
+	assert.Equal(t, StatusFull, trip.Status)
+	assert.Equal(t, 0, trip.AvailableSeats())
+	assert.True(t, trip.IsJoinable())
+
+	assert.ErrorIs(t, trip.AddParticipant(), ErrTripFull)
+
+	trip.RemoveParticipant()
+	assert.False(t, trip.IsFull())
+	assert.Equal(t, StatusActive, trip.Status)
+}
+
+func TestParticipant_Lifecycle(t *testing.T) {
+	p, err := NewJoinRequest(uuid.New(), uuid.New(), "Happy to share driving")
+	require.NoError(t, err)
+
+	assert.Equal(t, ParticipantStatusRequested, p.Status)
+	assert.True(t, p.IsActive())
+	assert.False(t, p.IsApproved())
+
+	require.NoError(t, p.Approve())
+	assert.True(t, p.IsApproved())
+	assert.NotNil(t, p.JoinDate)
+
+	// Cannot approve or reject twice
+	assert.ErrorIs(t, p.Approve(), ErrInvalidParticipantState)
+	assert.ErrorIs(t, p.Reject(), ErrInvalidParticipantState)
+
+	require.NoError(t, p.Leave())
+	assert.Equal(t, ParticipantStatusLeft, p.Status)
+	assert.False(t, p.IsActive())
+	assert.NotNil(t, p.LeftDate)
+
+	require.NoError(t, p.Rejoin("Plans changed, back in"))
+	assert.Equal(t, ParticipantStatusRequested, p.Status)
+	assert.Nil(t, p.JoinDate)
+	assert.Nil(t, p.LeftDate)
+
+	assert.ErrorIs(t, p.Rejoin(""), ErrAlreadyParticipant)
+}
+
+func TestParticipant_CreatorCannotLeave(t *testing.T) {
+	creator := NewCreatorParticipant(uuid.New(), uuid.New())
+
+	assert.Equal(t, ParticipantRoleCreator, creator.Role)
+	assert.True(t, creator.IsApproved())
+	assert.Error(t, creator.Leave())
+}
+
+func TestParticipant_RejectedCannotRejoin(t *testing.T) {
+	p, err := NewJoinRequest(uuid.New(), uuid.New(), "")
+	require.NoError(t, err)
+
+	require.NoError(t, p.Reject())
+	assert.ErrorIs(t, p.Rejoin(""), ErrInvalidParticipantState)
+}
+
+func TestParticipant_Remove(t *testing.T) {
+	p, err := NewJoinRequest(uuid.New(), uuid.New(), "")
+	require.NoError(t, err)
+
+	// Only approved participants can be removed
+	assert.ErrorIs(t, p.Remove(), ErrInvalidParticipantState)
+
+	require.NoError(t, p.Approve())
+	require.NoError(t, p.Remove())
+	assert.Equal(t, ParticipantStatusRemoved, p.Status)
+	assert.False(t, p.IsActive())
+	assert.NotNil(t, p.LeftDate)
+
+	// Removed participants cannot come back on their own
+	assert.ErrorIs(t, p.Rejoin(""), ErrInvalidParticipantState)
+
+	creator := NewCreatorParticipant(uuid.New(), uuid.New())
+	assert.ErrorIs(t, creator.Remove(), ErrCannotRemoveCreator)
+}
+
+func TestTrip_UpdateDetails(t *testing.T) {
+	start := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	trip, err := NewTrip(uuid.New(), "Weekend in Porto", "Portugal", "Porto", start, start.AddDate(0, 0, 2), 4)
+	require.NoError(t, err)
+
+	changed, err := trip.UpdateDetails("Long weekend in Porto", "Wine tasting", "Portugal", "Porto", start, start.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, "Long weekend in Porto", trip.Title)
+
+	changed, err = trip.UpdateDetails("Long weekend in Porto", "", "Portugal", "Porto", start, start.AddDate(0, 0, 3))
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	_, err = trip.UpdateDetails("", "", "Portugal", "Porto", start, start.AddDate(0, 0, 3))
+	assert.ErrorIs(t, err, ErrInvalidTripDetails)
+
+	_, err = trip.UpdateDetails("Porto", "", "Portugal", "Porto", start, start)
+	assert.ErrorIs(t, err, ErrInvalidTripDetails)
+}
+
+func TestTrip_AccessFor(t *testing.T) {
+	organizerID, guestID, strangerID := uuid.New(), uuid.New(), uuid.New()
+	approved := &Participant{UserID: guestID, Status: ParticipantStatusApproved}
+	requested := &Participant{UserID: guestID, Status: ParticipantStatusRequested}
+
+	tests := []struct {
+		name        string
+		isPublic    bool
+		status      Status
+		userID      uuid.UUID
+		participant *Participant
+		want        Access
+	}{
+		{"organizer of a draft", false, StatusDraft, organizerID, nil, Access{Member: true, Visible: true}},
+		{"approved participant of a draft", true, StatusDraft, guestID, approved, Access{Member: true, Visible: true}},
+		{"stranger on a public draft", true, StatusDraft, strangerID, nil, Access{}},
+		{"stranger on a published public trip", true, StatusActive, strangerID, nil, Access{Visible: true}},
+		{"stranger on a private trip", false, StatusActive, strangerID, nil, Access{}},
+		{"pending request on a private trip", false, StatusActive, guestID, requested, Access{}},
+		{"approved participant of a private trip", false, StatusActive, guestID, approved, Access{Member: true, Visible: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trip := &Trip{ID: uuid.New(), CreatorID: organizerID, IsPublic: tt.isPublic, Status: tt.status}
+			assert.Equal(t, tt.want, trip.AccessFor(tt.userID, tt.participant))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package trip
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Trip event names
+const (
+	EventJoinRequested     = "trip.join_requested"
+	EventJoinReviewed      = "trip.join_reviewed"
+	EventParticipantJoined = "trip.participant_joined"
+	EventParticipantLeft   = "trip.participant_left"
+	EventTripUpdated       = "trip.updated"
+)
+
+// JoinRequested is published when a user asks to join a trip and the request
+// awaits the organizer's review
+type JoinRequested struct {
+	TripID        uuid.UUID
+	UserID        uuid.UUID
+	ParticipantID uuid.UUID
+	OccurredAt    time.Time
+}
+
+// EventName implements event.Event
+func (JoinRequested) EventName() string { return EventJoinRequested }
+
+// JoinReviewed is published when the organizer approves or rejects a join request
+type JoinReviewed struct {
+	TripID        uuid.UUID
+	UserID        uuid.UUID
+	ParticipantID uuid.UUID
+	ReviewerID    uuid.UUID
+	Approved      bool
+	OccurredAt    time.Time
+}
+
+// EventName implements event.Event
+func (JoinReviewed) EventName() string { return EventJoinReviewed }
+
+// ParticipantJoined is published when a user takes a seat on a trip
+type ParticipantJoined struct {
+	TripID     uuid.UUID
+	UserID     uuid.UUID
+	OccurredAt time.Time
+}
+
+// EventName implements event.Event
+func (ParticipantJoined) EventName() string { return EventParticipantJoined }
+
+// ParticipantLeft is published when an approved participant leaves a trip or
+// is removed from it by the organizer
+type ParticipantLeft struct {
+	TripID     uuid.UUID
+	UserID     uuid.UUID
+	Removed    bool
+	OccurredAt time.Time
+}
+
+// EventName implements event.Event
+func (ParticipantLeft) EventName() string { return EventParticipantLeft }
+
+// TripUpdated is published when the organizer changes a trip's details
+type TripUpdated struct {
+	TripID       uuid.UUID
+	ActorID      uuid.UUID
+	DatesChanged bool
+	OccurredAt   time.Time
+}
+
+// EventName implements event.Event
+func (TripUpdated) EventName() string { return EventTripUpdated }
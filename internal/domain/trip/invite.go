@@ -0,0 +1,156 @@
+package trip
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invite link limits
+const (
+	DefaultInviteLinkTTL = 72 * time.Hour
+	MaxInviteLinkTTL     = 30 * 24 * time.Hour
+)
+
+// InviteLink is a shareable, signed link that lets its holders join a trip,
+// including private trips that are not listed in search
+type InviteLink struct {
+	ID          uuid.UUID  `json:"id"`
+	TripID      uuid.UUID  `json:"trip_id"`
+	CreatedBy   uuid.UUID  `json:"created_by"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	MaxUses     *int       `json:"max_uses,omitempty"`
+	Uses        int        `json:"uses"`
+	PreApproved bool       `json:"pre_approved"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// NewInviteLink creates a new invite link valid for the given duration
+func NewInviteLink(tripID, createdBy uuid.UUID, ttl time.Duration, maxUses *int, preApproved bool, now time.Time) (*InviteLink, error) {
+	if tripID == uuid.Nil {
+		return nil, errors.New("trip ID is required")
+	}
+	if createdBy == uuid.Nil {
+		return nil, errors.New("creator ID is required")
+	}
+	if ttl <= 0 {
+		ttl = DefaultInviteLinkTTL
+	}
+	if ttl > MaxInviteLinkTTL {
+		return nil, ErrInvalidInviteOptions
+	}
+	if maxUses != nil && *maxUses < 1 {
+		return nil, ErrInvalidInviteOptions
+	}
+
+	return &InviteLink{
+		ID:          uuid.New(),
+		TripID:      tripID,
+		CreatedBy:   createdBy,
+		ExpiresAt:   now.Add(ttl),
+		MaxUses:     maxUses,
+		PreApproved: preApproved,
+		CreatedAt:   now,
+	}, nil
+}
+
+// CheckUsable returns an error if the link can no longer be redeemed
+func (l *InviteLink) CheckUsable(now time.Time) error {
+	if l.RevokedAt != nil {
+		return ErrInviteRevoked
+	}
+	if !now.Before(l.ExpiresAt) {
+		return ErrInviteExpired
+	}
+	if l.MaxUses != nil && l.Uses >= *l.MaxUses {
+		return ErrInviteExhausted
+	}
+	return nil
+}
+
+// Revoke disables the link
+func (l *InviteLink) Revoke(now time.Time) {
+	if l.RevokedAt == nil {
+		l.RevokedAt = &now
+	}
+}
+
+// InvitationStatus represents the state of a direct invitation
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "pending"
+	InvitationStatusAccepted InvitationStatus = "accepted"
+	InvitationStatusDeclined InvitationStatus = "declined"
+	InvitationStatusCanceled InvitationStatus = "canceled"
+)
+
+// Invitation is a direct invite from an organizer to a specific user
+type Invitation struct {
+	ID          uuid.UUID        `json:"id"`
+	TripID      uuid.UUID        `json:"trip_id"`
+	InviterID   uuid.UUID        `json:"inviter_id"`
+	InviteeID   uuid.UUID        `json:"invitee_id"`
+	Status      InvitationStatus `json:"status"`
+	Message     string           `json:"message"`
+	PreApproved bool             `json:"pre_approved"`
+	CreatedAt   time.Time        `json:"created_at"`
+	RespondedAt *time.Time       `json:"responded_at,omitempty"`
+}
+
+// NewInvitation creates a pending direct invitation
+func NewInvitation(tripID, inviterID, inviteeID uuid.UUID, message string, preApproved bool) (*Invitation, error) {
+	if tripID == uuid.Nil {
+		return nil, errors.New("trip ID is required")
+	}
+	if inviterID == uuid.Nil || inviteeID == uuid.Nil {
+		return nil, errors.New("inviter and invitee are required")
+	}
+	if inviterID == inviteeID {
+		return nil, ErrCannotInviteSelf
+	}
+
+	return &Invitation{
+		ID:          uuid.New(),
+		TripID:      tripID,
+		InviterID:   inviterID,
+		InviteeID:   inviteeID,
+		Status:      InvitationStatusPending,
+		Message:     message,
+		PreApproved: preApproved,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// Accept marks the invitation as accepted
+func (i *Invitation) Accept(now time.Time) error {
+	return i.respond(InvitationStatusAccepted, now)
+}
+
+// Decline marks the invitation as declined
+func (i *Invitation) Decline(now time.Time) error {
+	return i.respond(InvitationStatusDeclined, now)
+}
+
+// Cancel withdraws the invitation
+func (i *Invitation) Cancel(now time.Time) error {
+	return i.respond(InvitationStatusCanceled, now)
+}
+
+// IsPending returns true if the invitation awaits an answer
+func (i *Invitation) IsPending() bool {
+	return i.Status == InvitationStatusPending
+}
+
+// respond moves a pending invitation into a final state
+func (i *Invitation) respond(status InvitationStatus, now time.Time) error {
+	if !i.IsPending() {
+		return ErrInvalidInvitationState
+	}
+
+	i.Status = status
+	i.RespondedAt = &now
+	return nil
+}
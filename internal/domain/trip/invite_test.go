@@ -0,0 +1,78 @@
+package trip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInviteLink(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	link, err := NewInviteLink(uuid.New(), uuid.New(), 0, nil, false, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(DefaultInviteLinkTTL), link.ExpiresAt)
+	assert.Nil(t, link.MaxUses)
+
+	_, err = NewInviteLink(uuid.New(), uuid.New(), MaxInviteLinkTTL+time.Hour, nil, false, now)
+	assert.ErrorIs(t, err, ErrInvalidInviteOptions)
+
+	zero := 0
+	_, err = NewInviteLink(uuid.New(), uuid.New(), time.Hour, &zero, false, now)
+	assert.ErrorIs(t, err, ErrInvalidInviteOptions)
+}
+
+func TestInviteLink_CheckUsable(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	maxUses := 2
+
+	tests := []struct {
+		name     string
+		modify   func(l *InviteLink)
+		at       time.Time
+		expected error
+	}{
+		{"fresh link", func(l *InviteLink) {}, now, nil},
+		{"one use left", func(l *InviteLink) { l.Uses = 1 }, now, nil},
+		{"exhausted", func(l *InviteLink) { l.Uses = 2 }, now, ErrInviteExhausted},
+		{"expired", func(l *InviteLink) {}, now.Add(DefaultInviteLinkTTL), ErrInviteExpired},
+		{"revoked", func(l *InviteLink) { l.Revoke(now) }, now, ErrInviteRevoked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, err := NewInviteLink(uuid.New(), uuid.New(), 0, &maxUses, false, now)
+			require.NoError(t, err)
+
+			tt.modify(link)
+			err = link.CheckUsable(tt.at)
+			if tt.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInvitation_Lifecycle(t *testing.T) {
+	inviterID := uuid.New()
+
+	_, err := NewInvitation(uuid.New(), inviterID, inviterID, "", false)
+	assert.ErrorIs(t, err, ErrCannotInviteSelf)
+
+	invitation, err := NewInvitation(uuid.New(), inviterID, uuid.New(), "Join us!", true)
+	require.NoError(t, err)
+	assert.True(t, invitation.IsPending())
+
+	now := time.Now()
+	require.NoError(t, invitation.Accept(now))
+	assert.Equal(t, InvitationStatusAccepted, invitation.Status)
+	assert.Equal(t, now, *invitation.RespondedAt)
+
+	assert.ErrorIs(t, invitation.Decline(now), ErrInvalidInvitationState)
+	assert.ErrorIs(t, invitation.Cancel(now), ErrInvalidInvitationState)
+}
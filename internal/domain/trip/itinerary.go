@@ -0,0 +1,67 @@
+package trip
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ItineraryItem represents a planned stop or activity within a trip
+type ItineraryItem struct {
+	ID          uuid.UUID  `json:"id"`
+	TripID      uuid.UUID  `json:"trip_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Location    string     `json:"location"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	CreatedBy   uuid.UUID  `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// NewItineraryItem creates a new itinerary item
+func NewItineraryItem(tripID, createdBy uuid.UUID, title, description, location string, startsAt time.Time, endsAt *time.Time) (*ItineraryItem, error) {
+	if tripID == uuid.Nil {
+		return nil, errors.New("trip ID is required")
+	}
+	if createdBy == uuid.Nil {
+		return nil, errors.New("creator ID is required")
+	}
+
+	now := time.Now()
+	item := &ItineraryItem{
+		ID:        uuid.New(),
+		TripID:    tripID,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+	}
+
+	if err := item.Update(title, description, location, startsAt, endsAt); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// Update changes the item's details
+func (i *ItineraryItem) Update(title, description, location string, startsAt time.Time, endsAt *time.Time) error {
+	if title == "" {
+		return errors.New("title is required")
+	}
+	if startsAt.IsZero() {
+		return errors.New("start time is required")
+	}
+	if endsAt != nil && !endsAt.After(startsAt) {
+		return ErrInvalidItemTimes
+	}
+
+	i.Title = title
+	i.Description = description
+	i.Location = location
+	i.StartsAt = startsAt
+	i.EndsAt = endsAt
+	i.UpdatedAt = time.Now()
+	return nil
+}
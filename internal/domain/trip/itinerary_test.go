@@ -0,0 +1,55 @@
+package trip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewItineraryItem(t *testing.T) {
+	startsAt := time.Date(2026, 6, 2, 10, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(2 * time.Hour)
+
+	item, err := NewItineraryItem(uuid.New(), uuid.New(), "Cable car", "", "Zermatt", startsAt, &endsAt)
+	require.NoError(t, err)
+	assert.Equal(t, "Cable car", item.Title)
+	assert.Equal(t, endsAt, *item.EndsAt)
+
+	_, err = NewItineraryItem(uuid.New(), uuid.New(), "", "", "", startsAt, nil)
+	assert.Error(t, err)
+
+	_, err = NewItineraryItem(uuid.New(), uuid.New(), "Cable car", "", "", time.Time{}, nil)
+	assert.Error(t, err)
+
+	before := startsAt.Add(-time.Hour)
+	_, err = NewItineraryItem(uuid.New(), uuid.New(), "Cable car", "", "", startsAt, &before)
+	assert.ErrorIs(t, err, ErrInvalidItemTimes)
+}
+
+func TestTrip_Covers(t *testing.T) {
+	trip := &Trip{
+		StartDate: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC),
+	}
+	cest := time.FixedZone("CEST", 2*60*60)
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected bool
+	}{
+		{"first morning", time.Date(2026, 6, 1, 0, 0, 0, 0, cest), true},
+		{"last evening", time.Date(2026, 6, 10, 23, 59, 0, 0, cest), true},
+		{"day before", time.Date(2026, 5, 31, 23, 0, 0, 0, cest), false},
+		{"day after", time.Date(2026, 6, 11, 0, 0, 0, 0, cest), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, trip.Covers(tt.at))
+		})
+	}
+}
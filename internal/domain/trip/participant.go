@@ -0,0 +1,164 @@
+package trip
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ParticipantStatus represents the state of a user's participation in a trip
+type ParticipantStatus string
+
+const (
+	ParticipantStatusRequested ParticipantStatus = "requested"
+	ParticipantStatusApproved  ParticipantStatus = "approved"
+	ParticipantStatusRejected  ParticipantStatus = "rejected"
+	ParticipantStatusLeft      ParticipantStatus = "left"
+	ParticipantStatusRemoved   ParticipantStatus = "removed"
+)
+
+// ParticipantRole represents the role a participant has within a trip
+type ParticipantRole string
+
+const (
+	ParticipantRoleCreator     ParticipantRole = "creator"
+	ParticipantRoleCoOrganizer ParticipantRole = "co-organizer"
+	ParticipantRoleParticipant ParticipantRole = "participant"
+)
+
+// Participant represents a user's participation in a trip
+type Participant struct {
+	ID        uuid.UUID         `json:"id"`
+	TripID    uuid.UUID         `json:"trip_id"`
+	UserID    uuid.UUID         `json:"user_id"`
+	Status    ParticipantStatus `json:"status"`
+	Role      ParticipantRole   `json:"role"`
+	JoinDate  *time.Time        `json:"join_date,omitempty"`
+	LeftDate  *time.Time        `json:"left_date,omitempty"`
+	Notes     string            `json:"notes"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// NewJoinRequest creates a pending participation request for a trip
+func NewJoinRequest(tripID, userID uuid.UUID, notes string) (*Participant, error) {
+	if tripID == uuid.Nil {
+		return nil, errors.New("trip ID is required")
+	}
+	if userID == uuid.Nil {
+		return nil, errors.New("user ID is required")
+	}
+
+	return &Participant{
+		ID:        uuid.New(),
+		TripID:    tripID,
+		UserID:    userID,
+		Status:    ParticipantStatusRequested,
+		Role:      ParticipantRoleParticipant,
+		Notes:     notes,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// NewCreatorParticipant creates the approved participation of a trip's creator
+func NewCreatorParticipant(tripID, userID uuid.UUID) *Participant {
+	p := NewApprovedParticipant(tripID, userID, "")
+	p.Role = ParticipantRoleCreator
+	return p
+}
+
+// NewApprovedParticipant creates a participation that already holds a seat,
+// such as one taken from a waitlist offer
+func NewApprovedParticipant(tripID, userID uuid.UUID, notes string) *Participant {
+	now := time.Now()
+	return &Participant{
+		ID:        uuid.New(),
+		TripID:    tripID,
+		UserID:    userID,
+		Status:    ParticipantStatusApproved,
+		Role:      ParticipantRoleParticipant,
+		JoinDate:  &now,
+		Notes:     notes,
+		CreatedAt: now,
+	}
+}
+
+// Approve approves a pending join request
+func (p *Participant) Approve() error {
+	if p.Status != ParticipantStatusRequested {
+		return ErrInvalidParticipantState
+	}
+
+	now := time.Now()
+	p.Status = ParticipantStatusApproved
+	p.JoinDate = &now
+	return nil
+}
+
+// Reject rejects a pending join request
+func (p *Participant) Reject() error {
+	if p.Status != ParticipantStatusRequested {
+		return ErrInvalidParticipantState
+	}
+
+	p.Status = ParticipantStatusRejected
+	return nil
+}
+
+// Leave marks the participant as having left the trip
+func (p *Participant) Leave() error {
+	if p.Role == ParticipantRoleCreator {
+		return errors.New("the trip creator cannot leave the trip")
+	}
+	if p.Status != ParticipantStatusApproved && p.Status != ParticipantStatusRequested {
+		return ErrInvalidParticipantState
+	}
+
+	if p.Status == ParticipantStatusApproved {
+		now := time.Now()
+		p.LeftDate = &now
+	}
+	p.Status = ParticipantStatusLeft
+	return nil
+}
+
+// Remove marks an approved participant as removed from the trip by the organizer
+func (p *Participant) Remove() error {
+	if p.Role == ParticipantRoleCreator {
+		return ErrCannotRemoveCreator
+	}
+	if p.Status != ParticipantStatusApproved {
+		return ErrInvalidParticipantState
+	}
+
+	now := time.Now()
+	p.Status = ParticipantStatusRemoved
+	p.LeftDate = &now
+	return nil
+}
+
+// Rejoin turns a participation that was left into a new join request
+func (p *Participant) Rejoin(notes string) error {
+	if p.IsActive() {
+		return ErrAlreadyParticipant
+	}
+	if p.Status != ParticipantStatusLeft {
+		return ErrInvalidParticipantState
+	}
+
+	p.Status = ParticipantStatusRequested
+	p.JoinDate = nil
+	p.LeftDate = nil
+	p.Notes = notes
+	return nil
+}
+
+// IsApproved returns true if the participant holds a seat on the trip
+func (p *Participant) IsApproved() bool {
+	return p.Status == ParticipantStatusApproved
+}
+
+// IsActive returns true if the participation is pending or approved
+func (p *Participant) IsActive() bool {
+	return p.Status == ParticipantStatusRequested || p.Status == ParticipantStatusApproved
+}
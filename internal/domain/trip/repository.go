@@ -0,0 +1,205 @@
+package trip
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain errors
+var (
+	ErrTripNotFound            = errors.New("trip not found")
+	ErrTripFull                = errors.New("trip is full")
+	ErrTripNotJoinable         = errors.New("trip is not accepting participants")
+	ErrNotOrganizer            = errors.New("only the trip organizer can perform this action")
+	ErrParticipantNotFound     = errors.New("participant not found")
+	ErrAlreadyParticipant      = errors.New("user already participates in this trip")
+	ErrInvalidParticipantState = errors.New("invalid participant state transition")
+	ErrWaitlistEntryNotFound   = errors.New("waitlist entry not found")
+	ErrAlreadyWaitlisted       = errors.New("user is already on the waitlist")
+	ErrInvalidWaitlistState    = errors.New("invalid waitlist state transition")
+	ErrNoPendingOffer          = errors.New("no pending seat offer")
+	ErrOfferExpired            = errors.New("seat offer has expired")
+	ErrWaitlistEmpty           = errors.New("waitlist is empty")
+	ErrNoSeatAvailable         = errors.New("no seat available")
+	ErrItineraryItemNotFound   = errors.New("itinerary item not found")
+	ErrItemOutsideTrip         = errors.New("itinerary item must fall within the trip dates")
+	ErrInvalidItemTimes        = errors.New("itinerary item must end after it starts")
+	ErrTripNotDraft            = errors.New("trip is not a draft")
+	ErrInvalidTripDetails      = errors.New("a trip needs a title, a destination country and an end date after its start date")
+	ErrCannotRemoveCreator     = errors.New("the trip creator cannot be removed")
+	ErrTemplateNotFound        = errors.New("trip template not found")
+	ErrNotTemplateOwner        = errors.New("only the template owner can perform this action")
+	ErrInviteRequired          = errors.New("this trip can only be joined with an invitation")
+	ErrInvalidInviteToken      = errors.New("invalid invite link")
+	ErrInviteLinkNotFound      = errors.New("invite link not found")
+	ErrInviteRevoked           = errors.New("invite link has been revoked")
+	ErrInviteExpired           = errors.New("invite link has expired")
+	ErrInviteExhausted         = errors.New("invite link has reached its maximum uses")
+	ErrInvitationNotFound      = errors.New("invitation not found")
+	ErrAlreadyInvited          = errors.New("user already has a pending invitation to this trip")
+	ErrInvalidInvitationState  = errors.New("invalid invitation state transition")
+	ErrInviteeNotFound         = errors.New("invited user not found")
+	ErrInvalidInviteOptions    = errors.New("invite links need at least one use and can be valid for at most 30 days")
+	ErrCannotInviteSelf        = errors.New("you cannot invite yourself")
+)
+
+// Repository defines the interface for trip data persistence
+type Repository interface {
+	// Create creates a new trip together with its creator's participation
+	Create(ctx context.Context, trip *Trip, creator *Participant) error
+
+	// CreateWithItinerary creates a new trip, its creator's participation, its itinerary
+	// and its tags at once
+	CreateWithItinerary(ctx context.Context, trip *Trip, creator *Participant, items []*ItineraryItem, tagIDs []uuid.UUID) error
+
+	// GetByID retrieves a trip by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*Trip, error)
+
+	// Update updates an existing trip
+	Update(ctx context.Context, trip *Trip) error
+
+	// Search retrieves public trips matching the filter
+	Search(ctx context.Context, filter SearchFilter) ([]*Trip, error)
+
+	// ListByParticipant retrieves the published trips a user is approved on, ordered by start date
+	ListByParticipant(ctx context.Context, userID uuid.UUID) ([]*Trip, error)
+}
+
+// ParticipantRepository defines the interface for trip participant persistence
+type ParticipantRepository interface {
+	// Create creates a new participation request
+	Create(ctx context.Context, participant *Participant) error
+
+	// GetByID retrieves a participant by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*Participant, error)
+
+	// GetByTripAndUser retrieves a user's participation in a trip
+	GetByTripAndUser(ctx context.Context, tripID, userID uuid.UUID) (*Participant, error)
+
+	// ListByTrip retrieves all participants of a trip
+	ListByTrip(ctx context.Context, tripID uuid.UUID) ([]*Participant, error)
+
+	// Update updates a participant without touching seat counts
+	Update(ctx context.Context, participant *Participant) error
+
+	// Approve atomically approves a participant and takes a seat, failing with ErrTripFull
+	Approve(ctx context.Context, participant *Participant) error
+
+	// Leave atomically marks a participant as left (or removed) and frees their seat if they held one
+	Leave(ctx context.Context, participant *Participant) error
+
+	// SharesTrip checks if both users are approved participants of a common trip
+	SharesTrip(ctx context.Context, userID, otherID uuid.UUID) (bool, error)
+}
+
+// WaitlistRepository defines the interface for trip waitlist persistence
+type WaitlistRepository interface {
+	// Create appends an entry to the end of the trip's waitlist
+	Create(ctx context.Context, entry *WaitlistEntry) error
+
+	// GetActiveByTripAndUser retrieves a user's waiting or offered entry for a trip
+	GetActiveByTripAndUser(ctx context.Context, tripID, userID uuid.UUID) (*WaitlistEntry, error)
+
+	// ListActiveByTrip retrieves waiting and offered entries for a trip in queue order
+	ListActiveByTrip(ctx context.Context, tripID uuid.UUID) ([]*WaitlistEntry, error)
+
+	// CountActiveByTrip counts waiting and offered entries for a trip
+	CountActiveByTrip(ctx context.Context, tripID uuid.UUID) (int, error)
+
+	// Update updates an existing entry
+	Update(ctx context.Context, entry *WaitlistEntry) error
+
+	// OfferNext atomically offers a free seat to the next waiting user.
+	// It returns ErrNoSeatAvailable when every free seat already has an
+	// outstanding offer and ErrWaitlistEmpty when nobody is waiting.
+	OfferNext(ctx context.Context, tripID uuid.UUID, now time.Time, window time.Duration) (*WaitlistEntry, error)
+
+	// AcceptOffer atomically accepts an offer and turns it into an approved participant
+	AcceptOffer(ctx context.Context, entry *WaitlistEntry, participant *Participant) error
+
+	// ExpireOffers expires lapsed offers and returns the IDs of the affected trips
+	ExpireOffers(ctx context.Context, now time.Time) ([]uuid.UUID, error)
+}
+
+// ItineraryRepository defines the interface for trip itinerary persistence
+type ItineraryRepository interface {
+	// Create creates a new itinerary item
+	Create(ctx context.Context, item *ItineraryItem) error
+
+	// GetByID retrieves an itinerary item by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*ItineraryItem, error)
+
+	// ListByTrip retrieves a trip's itinerary in chronological order
+	ListByTrip(ctx context.Context, tripID uuid.UUID) ([]*ItineraryItem, error)
+
+	// ListByTrips retrieves the itineraries of several trips in chronological order
+	ListByTrips(ctx context.Context, tripIDs []uuid.UUID) ([]*ItineraryItem, error)
+
+	// Update updates an existing itinerary item
+	Update(ctx context.Context, item *ItineraryItem) error
+
+	// Delete deletes an itinerary item
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// TemplateRepository defines the interface for trip template persistence
+type TemplateRepository interface {
+	// Create creates a new template
+	Create(ctx context.Context, template *Template) error
+
+	// GetByID retrieves a template by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*Template, error)
+
+	// ListPublic retrieves published templates, most recent first
+	ListPublic(ctx context.Context, limit, offset int) ([]*Template, error)
+
+	// ListByOwner retrieves a user's templates, most recent first
+	ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]*Template, error)
+
+	// Update updates an existing template
+	Update(ctx context.Context, template *Template) error
+
+	// Delete deletes a template
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// InviteRepository defines the interface for trip invite link and invitation persistence
+type InviteRepository interface {
+	// CreateLink creates a new invite link
+	CreateLink(ctx context.Context, link *InviteLink) error
+
+	// GetLink retrieves an invite link by ID
+	GetLink(ctx context.Context, id uuid.UUID) (*InviteLink, error)
+
+	// ListLinksByTrip retrieves a trip's invite links, most recent first
+	ListLinksByTrip(ctx context.Context, tripID uuid.UUID) ([]*InviteLink, error)
+
+	// UpdateLink updates an existing invite link
+	UpdateLink(ctx context.Context, link *InviteLink) error
+
+	// ClaimLinkUse atomically consumes one use of a link, failing with
+	// ErrInviteRevoked, ErrInviteExpired or ErrInviteExhausted
+	ClaimLinkUse(ctx context.Context, id uuid.UUID, now time.Time) error
+
+	// ReleaseLinkUse gives back a use claimed for a redemption that failed
+	ReleaseLinkUse(ctx context.Context, id uuid.UUID) error
+
+	// CreateInvitation creates a direct invitation, failing with ErrAlreadyInvited
+	// if the user already has a pending one for the trip
+	CreateInvitation(ctx context.Context, invitation *Invitation) error
+
+	// GetInvitation retrieves an invitation by ID
+	GetInvitation(ctx context.Context, id uuid.UUID) (*Invitation, error)
+
+	// ListPendingInvitationsByInvitee retrieves a user's pending invitations, most recent first
+	ListPendingInvitationsByInvitee(ctx context.Context, inviteeID uuid.UUID) ([]*Invitation, error)
+
+	// ListInvitationsByTrip retrieves a trip's invitations, most recent first
+	ListInvitationsByTrip(ctx context.Context, tripID uuid.UUID) ([]*Invitation, error)
+
+	// UpdateInvitation updates an existing invitation
+	UpdateInvitation(ctx context.Context, invitation *Invitation) error
+}
@@ -0,0 +1,48 @@
+package trip
+
+import (
+	"time"
+)
+
+// TagMatch controls how a tag filter is applied
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any"
+	TagMatchAll TagMatch = "all"
+)
+
+// Search pagination limits
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// SearchFilter holds the criteria for searching public trips
+type SearchFilter struct {
+	Destination string     // Matches the destination country or city
+	StartsAfter *time.Time // Trips starting on or after this date
+	EndsBefore  *time.Time // Trips ending on or before this date
+	MaxBudget   *float64
+	TripType    string
+	Tags        []string // Tag names
+	TagMatch    TagMatch
+	Limit       int
+	Offset      int
+}
+
+// Normalize applies defaults and bounds to the filter
+func (f *SearchFilter) Normalize() {
+	if f.TagMatch != TagMatchAll {
+		f.TagMatch = TagMatchAny
+	}
+	if f.Limit <= 0 {
+		f.Limit = DefaultSearchLimit
+	}
+	if f.Limit > MaxSearchLimit {
+		f.Limit = MaxSearchLimit
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+}
@@ -0,0 +1,149 @@
+package trip
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Template is a reusable blueprint of a trip. Dates are stored relative to
+// the trip start so a template can be instantiated for any departure date.
+type Template struct {
+	ID                 uuid.UUID      `json:"id"`
+	OwnerID            uuid.UUID      `json:"owner_id"`
+	SourceTripID       *uuid.UUID     `json:"source_trip_id,omitempty"`
+	Name               string         `json:"name"`
+	Title              string         `json:"title"`
+	Description        string         `json:"description"`
+	DestinationCountry string         `json:"destination_country"`
+	DestinationCity    string         `json:"destination_city"`
+	DurationDays       int            `json:"duration_days"`
+	MaxParticipants    int            `json:"max_participants"`
+	EstimatedBudget    float64        `json:"estimated_budget"`
+	Currency           string         `json:"currency"`
+	TripType           string         `json:"trip_type"`
+	TagIDs             []uuid.UUID    `json:"tag_ids"`
+	Itinerary          []TemplateItem `json:"itinerary"`
+	IsPublic           bool           `json:"is_public"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+}
+
+// TemplateItem is an itinerary item positioned relative to the trip start
+type TemplateItem struct {
+	Title              string `json:"title"`
+	Description        string `json:"description"`
+	Location           string `json:"location"`
+	StartOffsetMinutes int64  `json:"start_offset_minutes"`
+	DurationMinutes    *int64 `json:"duration_minutes,omitempty"`
+}
+
+// NewTemplateFromTrip snapshots a trip, its tags and its itinerary into a private template
+func NewTemplateFromTrip(ownerID uuid.UUID, name string, t *Trip, tagIDs []uuid.UUID, items []*ItineraryItem) (*Template, error) {
+	if ownerID == uuid.Nil {
+		return nil, errors.New("owner ID is required")
+	}
+	if name == "" {
+		name = t.Title
+	}
+
+	now := time.Now()
+	sourceTripID := t.ID
+	template := &Template{
+		ID:                 uuid.New(),
+		OwnerID:            ownerID,
+		SourceTripID:       &sourceTripID,
+		Name:               name,
+		Title:              t.Title,
+		Description:        t.Description,
+		DestinationCountry: t.DestinationCountry,
+		DestinationCity:    t.DestinationCity,
+		DurationDays:       t.DurationDays(),
+		MaxParticipants:    t.MaxParticipants,
+		EstimatedBudget:    t.EstimatedBudget,
+		Currency:           t.Currency,
+		TripType:           t.TripType,
+		TagIDs:             append([]uuid.UUID{}, tagIDs...),
+		Itinerary:          make([]TemplateItem, 0, len(items)),
+		IsPublic:           false,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	for _, item := range items {
+		templateItem := TemplateItem{
+			Title:              item.Title,
+			Description:        item.Description,
+			Location:           item.Location,
+			StartOffsetMinutes: int64(item.StartsAt.Sub(t.StartDate) / time.Minute),
+		}
+		if item.EndsAt != nil {
+			duration := int64(item.EndsAt.Sub(item.StartsAt) / time.Minute)
+			templateItem.DurationMinutes = &duration
+		}
+		template.Itinerary = append(template.Itinerary, templateItem)
+	}
+
+	return template, nil
+}
+
+// Instantiate creates a draft trip from the template starting on the given date,
+// together with its itinerary
+func (tpl *Template) Instantiate(creatorID uuid.UUID, startDate time.Time) (*Trip, []*ItineraryItem, error) {
+	t, err := NewTrip(
+		creatorID,
+		tpl.Title,
+		tpl.DestinationCountry,
+		tpl.DestinationCity,
+		startDate,
+		startDate.AddDate(0, 0, tpl.DurationDays),
+		tpl.MaxParticipants,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t.Description = tpl.Description
+	t.EstimatedBudget = tpl.EstimatedBudget
+	t.TripType = tpl.TripType
+	t.Status = StatusDraft
+	if tpl.Currency != "" {
+		t.Currency = tpl.Currency
+	}
+
+	items := make([]*ItineraryItem, 0, len(tpl.Itinerary))
+	for _, templateItem := range tpl.Itinerary {
+		startsAt := startDate.Add(time.Duration(templateItem.StartOffsetMinutes) * time.Minute)
+
+		var endsAt *time.Time
+		if templateItem.DurationMinutes != nil {
+			end := startsAt.Add(time.Duration(*templateItem.DurationMinutes) * time.Minute)
+			endsAt = &end
+		}
+
+		item, err := NewItineraryItem(t.ID, creatorID, templateItem.Title, templateItem.Description, templateItem.Location, startsAt, endsAt)
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, item)
+	}
+
+	return t, items, nil
+}
+
+// IsOwner returns true if the given user owns the template
+func (tpl *Template) IsOwner(userID uuid.UUID) bool {
+	return tpl.OwnerID == userID
+}
+
+// IsVisibleTo returns true if the user may view and use the template
+func (tpl *Template) IsVisibleTo(userID uuid.UUID) bool {
+	return tpl.IsPublic || tpl.IsOwner(userID)
+}
+
+// SetPublic publishes or unpublishes the template
+func (tpl *Template) SetPublic(public bool) {
+	tpl.IsPublic = public
+	tpl.UpdatedAt = time.Now()
+}
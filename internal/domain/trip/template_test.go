@@ -0,0 +1,115 @@
+package trip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTemplateSourceTrip(t *testing.T) (*Trip, []*ItineraryItem) {
+	t.Helper()
+
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	trip, err := NewTrip(uuid.New(), "Iceland ring road", "Iceland", "Reykjavik", start, start.AddDate(0, 0, 10), 4)
+	require.NoError(t, err)
+	trip.Description = "Ten days around the island"
+	trip.EstimatedBudget = 1800
+	trip.TripType = "adventure"
+
+	firstEnd := start.Add(12 * time.Hour)
+	first, err := NewItineraryItem(trip.ID, trip.CreatorID, "Golden circle", "", "Thingvellir", start.Add(9*time.Hour), &firstEnd)
+	require.NoError(t, err)
+	second, err := NewItineraryItem(trip.ID, trip.CreatorID, "Glacier hike", "", "Skaftafell", start.AddDate(0, 0, 3).Add(10*time.Hour), nil)
+	require.NoError(t, err)
+
+	return trip, []*ItineraryItem{first, second}
+}
+
+func TestNewTemplateFromTrip(t *testing.T) {
+	source, items := newTemplateSourceTrip(t)
+	tagID := uuid.New()
+
+	tpl, err := NewTemplateFromTrip(source.CreatorID, "", source, []uuid.UUID{tagID}, items)
+	require.NoError(t, err)
+
+	assert.Equal(t, source.Title, tpl.Name)
+	assert.Equal(t, 10, tpl.DurationDays)
+	assert.Equal(t, []uuid.UUID{tagID}, tpl.TagIDs)
+	assert.False(t, tpl.IsPublic)
+	require.NotNil(t, tpl.SourceTripID)
+	assert.Equal(t, source.ID, *tpl.SourceTripID)
+
+	require.Len(t, tpl.Itinerary, 2)
+	assert.Equal(t, int64(9*60), tpl.Itinerary[0].StartOffsetMinutes)
+	require.NotNil(t, tpl.Itinerary[0].DurationMinutes)
+	assert.Equal(t, int64(3*60), *tpl.Itinerary[0].DurationMinutes)
+	assert.Equal(t, int64((3*24+10)*60), tpl.Itinerary[1].StartOffsetMinutes)
+	assert.Nil(t, tpl.Itinerary[1].DurationMinutes)
+}
+
+func TestTemplate_Instantiate(t *testing.T) {
+	source, items := newTemplateSourceTrip(t)
+
+	tpl, err := NewTemplateFromTrip(source.CreatorID, "Yearly Iceland", source, nil, items)
+	require.NoError(t, err)
+
+	creatorID := uuid.New()
+	nextYear := source.StartDate.AddDate(1, 0, 0)
+
+	clone, cloneItems, err := tpl.Instantiate(creatorID, nextYear)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, source.ID, clone.ID)
+	assert.Equal(t, creatorID, clone.CreatorID)
+	assert.Equal(t, StatusDraft, clone.Status)
+	assert.False(t, clone.IsJoinable())
+	assert.Equal(t, nextYear, clone.StartDate)
+	assert.Equal(t, nextYear.AddDate(0, 0, 10), clone.EndDate)
+	assert.Equal(t, source.Description, clone.Description)
+	assert.Equal(t, source.EstimatedBudget, clone.EstimatedBudget)
+	assert.Equal(t, 1, clone.CurrentParticipants)
+
+	require.Len(t, cloneItems, 2)
+	offset := nextYear.Sub(source.StartDate)
+	for i, item := range cloneItems {
+		assert.Equal(t, clone.ID, item.TripID)
+		assert.Equal(t, items[i].StartsAt.Add(offset), item.StartsAt)
+		assert.True(t, clone.Covers(item.StartsAt))
+	}
+	require.NotNil(t, cloneItems[0].EndsAt)
+	assert.Equal(t, items[0].EndsAt.Add(offset), *cloneItems[0].EndsAt)
+}
+
+func TestTemplate_Visibility(t *testing.T) {
+	source, items := newTemplateSourceTrip(t)
+
+	tpl, err := NewTemplateFromTrip(source.CreatorID, "", source, nil, items)
+	require.NoError(t, err)
+
+	stranger := uuid.New()
+	assert.True(t, tpl.IsVisibleTo(source.CreatorID))
+	assert.False(t, tpl.IsVisibleTo(stranger))
+
+	tpl.SetPublic(true)
+	assert.True(t, tpl.IsVisibleTo(stranger))
+	assert.False(t, tpl.IsOwner(stranger))
+}
+
+func TestTrip_Publish(t *testing.T) {
+	source, items := newTemplateSourceTrip(t)
+	tpl, err := NewTemplateFromTrip(source.CreatorID, "", source, nil, items)
+	require.NoError(t, err)
+
+	draft, _, err := tpl.Instantiate(source.CreatorID, source.StartDate)
+	require.NoError(t, err)
+	assert.True(t, draft.IsDraft())
+
+	require.NoError(t, draft.Publish())
+	assert.Equal(t, StatusActive, draft.Status)
+	assert.True(t, draft.IsJoinable())
+
+	assert.ErrorIs(t, draft.Publish(), ErrTripNotDraft)
+}
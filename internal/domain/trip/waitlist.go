@@ -0,0 +1,126 @@
+package trip
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WaitlistStatus represents the state of a waitlist entry
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting  WaitlistStatus = "waiting"
+	WaitlistStatusOffered  WaitlistStatus = "offered"
+	WaitlistStatusAccepted WaitlistStatus = "accepted"
+	WaitlistStatusDeclined WaitlistStatus = "declined"
+	WaitlistStatusExpired  WaitlistStatus = "expired"
+	WaitlistStatusCanceled WaitlistStatus = "canceled"
+)
+
+// WaitlistEntry represents a user queued for a seat on a full trip
+type WaitlistEntry struct {
+	ID             uuid.UUID      `json:"id"`
+	TripID         uuid.UUID      `json:"trip_id"`
+	UserID         uuid.UUID      `json:"user_id"`
+	Position       int64          `json:"position"`
+	Status         WaitlistStatus `json:"status"`
+	Notes          string         `json:"notes"`
+	OfferedAt      *time.Time     `json:"offered_at,omitempty"`
+	OfferExpiresAt *time.Time     `json:"offer_expires_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// NewWaitlistEntry creates a new waiting entry; the position is assigned on persistence
+func NewWaitlistEntry(tripID, userID uuid.UUID, notes string) (*WaitlistEntry, error) {
+	if tripID == uuid.Nil {
+		return nil, errors.New("trip ID is required")
+	}
+	if userID == uuid.Nil {
+		return nil, errors.New("user ID is required")
+	}
+
+	now := time.Now()
+	return &WaitlistEntry{
+		ID:        uuid.New(),
+		TripID:    tripID,
+		UserID:    userID,
+		Status:    WaitlistStatusWaiting,
+		Notes:     notes,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Offer offers a seat to the entry with an acceptance window
+func (w *WaitlistEntry) Offer(now time.Time, window time.Duration) error {
+	if w.Status != WaitlistStatusWaiting {
+		return ErrInvalidWaitlistState
+	}
+
+	expiresAt := now.Add(window)
+	w.Status = WaitlistStatusOffered
+	w.OfferedAt = &now
+	w.OfferExpiresAt = &expiresAt
+	w.UpdatedAt = now
+	return nil
+}
+
+// IsOfferExpired returns true if an outstanding offer has run out of time
+func (w *WaitlistEntry) IsOfferExpired(now time.Time) bool {
+	return w.Status == WaitlistStatusOffered && w.OfferExpiresAt != nil && !now.Before(*w.OfferExpiresAt)
+}
+
+// Accept accepts an outstanding seat offer
+func (w *WaitlistEntry) Accept(now time.Time) error {
+	if w.Status != WaitlistStatusOffered {
+		return ErrNoPendingOffer
+	}
+	if w.IsOfferExpired(now) {
+		return ErrOfferExpired
+	}
+
+	w.Status = WaitlistStatusAccepted
+	w.UpdatedAt = now
+	return nil
+}
+
+// Decline declines an outstanding seat offer
+func (w *WaitlistEntry) Decline(now time.Time) error {
+	if w.Status != WaitlistStatusOffered {
+		return ErrNoPendingOffer
+	}
+
+	w.Status = WaitlistStatusDeclined
+	w.UpdatedAt = now
+	return nil
+}
+
+// Expire marks an outstanding offer as expired
+func (w *WaitlistEntry) Expire(now time.Time) error {
+	if !w.IsOfferExpired(now) {
+		return ErrInvalidWaitlistState
+	}
+
+	w.Status = WaitlistStatusExpired
+	w.UpdatedAt = now
+	return nil
+}
+
+// Cancel removes the entry from the waitlist
+func (w *WaitlistEntry) Cancel(now time.Time) error {
+	if !w.IsActive() {
+		return ErrInvalidWaitlistState
+	}
+
+	w.Status = WaitlistStatusCanceled
+	w.UpdatedAt = now
+	return nil
+}
+
+// IsActive returns true if the entry is still queued or holding an offer
+func (w *WaitlistEntry) IsActive() bool {
+	return w.Status == WaitlistStatusWaiting || w.Status == WaitlistStatusOffered
+}
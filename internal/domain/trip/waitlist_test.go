@@ -0,0 +1,84 @@
+package trip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWaitlistEntry(t *testing.T) {
+	entry, err := NewWaitlistEntry(uuid.New(), uuid.New(), "Flexible on dates")
+	require.NoError(t, err)
+
+	assert.Equal(t, WaitlistStatusWaiting, entry.Status)
+	assert.True(t, entry.IsActive())
+	assert.Nil(t, entry.OfferExpiresAt)
+
+	_, err = NewWaitlistEntry(uuid.Nil, uuid.New(), "")
+	assert.Error(t, err)
+
+	_, err = NewWaitlistEntry(uuid.New(), uuid.Nil, "")
+	assert.Error(t, err)
+}
+
+func TestWaitlistEntry_OfferAndAccept(t *testing.T) {
+	entry, err := NewWaitlistEntry(uuid.New(), uuid.New(), "")
+	require.NoError(t, err)
+
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, entry.Offer(now, 24*time.Hour))
+
+	assert.Equal(t, WaitlistStatusOffered, entry.Status)
+	require.NotNil(t, entry.OfferExpiresAt)
+	assert.Equal(t, now.Add(24*time.Hour), *entry.OfferExpiresAt)
+	assert.True(t, entry.IsActive())
+
+	// An entry can only hold one offer at a time
+	assert.ErrorIs(t, entry.Offer(now, time.Hour), ErrInvalidWaitlistState)
+
+	require.NoError(t, entry.Accept(now.Add(time.Hour)))
+	assert.Equal(t, WaitlistStatusAccepted, entry.Status)
+	assert.False(t, entry.IsActive())
+}
+
+func TestWaitlistEntry_OfferExpires(t *testing.T) {
+	entry, err := NewWaitlistEntry(uuid.New(), uuid.New(), "")
+	require.NoError(t, err)
+
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, entry.Offer(now, time.Hour))
+
+	assert.False(t, entry.IsOfferExpired(now.Add(59*time.Minute)))
+	assert.True(t, entry.IsOfferExpired(now.Add(time.Hour)))
+
+	assert.ErrorIs(t, entry.Accept(now.Add(2*time.Hour)), ErrOfferExpired)
+	assert.ErrorIs(t, entry.Expire(now.Add(30*time.Minute)), ErrInvalidWaitlistState)
+
+	require.NoError(t, entry.Expire(now.Add(2*time.Hour)))
+	assert.Equal(t, WaitlistStatusExpired, entry.Status)
+	assert.False(t, entry.IsActive())
+}
+
+func TestWaitlistEntry_DeclineAndCancel(t *testing.T) {
+	now := time.Now()
+
+	entry, err := NewWaitlistEntry(uuid.New(), uuid.New(), "")
+	require.NoError(t, err)
+
+	// Nothing to decline before an offer is made
+	assert.ErrorIs(t, entry.Decline(now), ErrNoPendingOffer)
+
+	require.NoError(t, entry.Offer(now, time.Hour))
+	require.NoError(t, entry.Decline(now))
+	assert.Equal(t, WaitlistStatusDeclined, entry.Status)
+
+	waiting, err := NewWaitlistEntry(uuid.New(), uuid.New(), "")
+	require.NoError(t, err)
+
+	require.NoError(t, waiting.Cancel(now))
+	assert.Equal(t, WaitlistStatusCanceled, waiting.Status)
+	assert.ErrorIs(t, waiting.Cancel(now), ErrInvalidWaitlistState)
+}
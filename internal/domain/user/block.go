@@ -0,0 +1,27 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Block records that a user no longer wants to be contacted by another user
+type Block struct {
+	BlockerID uuid.UUID `json:"blocker_id"`
+	BlockedID uuid.UUID `json:"blocked_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewBlock creates a block of one user by another
+func NewBlock(blockerID, blockedID uuid.UUID) (*Block, error) {
+	if blockerID == blockedID {
+		return nil, ErrCannotBlockSelf
+	}
+
+	return &Block{
+		BlockerID: blockerID,
+		BlockedID: blockedID,
+		CreatedAt: time.Now(),
+	}, nil
+}
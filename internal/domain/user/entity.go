@@ -29,6 +29,29 @@ const (
 	TravelStyleRelaxation TravelStyle = "relaxation"
 )
 
+// TravelStyles returns every supported travel style
+func TravelStyles() []TravelStyle {
+	return []TravelStyle{
+		TravelStyleBudget,
+		TravelStyleMidRange,
+		TravelStyleLuxury,
+		TravelStyleBackpacker,
+		TravelStyleAdventure,
+		TravelStyleCultural,
+		TravelStyleRelaxation,
+	}
+}
+
+// IsValid returns true if the travel style is one of the supported values
+func (s TravelStyle) IsValid() bool {
+	for _, style := range TravelStyles() {
+		if s == style {
+			return true
+		}
+	}
+	return false
+}
+
 // Gender represents the user's gender
 type Gender string
 
@@ -58,14 +81,17 @@ type User struct {
 	TravelStyle     *TravelStyle `json:"travel_style,omitempty"`
 	ProfilePhotoURL string       `json:"profile_photo_url"`
 	GooglePhotoURL  string       `json:"google_photo_url"`
+	// ProfilePhotos are the sizes of an uploaded profile photo, stored
+	// under ProfilePhotoKey
+	ProfilePhotos   []ProfilePhoto `json:"profile_photos"`
+	ProfilePhotoKey string         `json:"-"`
 
 	ReputationScore             float64      `json:"reputation_score"`
 	RatingAverage               float64      `json:"rating_average"`
 	RatingCount                 int          `json:"rating_count"`
 	PrivacyLevel                PrivacyLevel `json:"privacy_level"`
 	ProfileVisibility           PrivacyLevel `json:"profile_visibility"`
-	EmailNotifications          bool         `json:"email_notifications"`
-	PushNotifications           bool         `json:"push_notifications"`
+	PreferredCurrency           string       `json:"preferred_currency"`
 	ProfileCompletionPercentage int          `json:"profile_completion_percentage"`
 	IsActive                    bool         `json:"is_active"`
 	LastLogin                   *time.Time   `json:"last_login,omitempty"`
@@ -97,14 +123,13 @@ func NewUser(googleID, email, firstName, lastName, googlePhotoURL string) (*User
 		Interests:       []string{},
 		GooglePhotoURL:  googlePhotoURL,
 		ProfilePhotoURL: googlePhotoURL, // Initially use Google photo
+		ProfilePhotos:   []ProfilePhoto{},
 
 		ReputationScore:             0.0,
 		RatingAverage:               0.0,
 		RatingCount:                 0,
 		PrivacyLevel:                PrivacyLevelPublic,
 		ProfileVisibility:           PrivacyLevelPublic,
-		EmailNotifications:          true,
-		PushNotifications:           true,
 		ProfileCompletionPercentage: 0,
 		IsActive:                    true,
 		CreatedAt:                   now,
@@ -162,6 +187,13 @@ func (u *User) Activate() {
 	u.UpdatedAt = time.Now()
 }
 
+// AcceptsMessagesFromStrangers returns true if users who share no trip
+// with this user may start a direct conversation with them, which only
+// users with a public profile allow
+func (u *User) AcceptsMessagesFromStrangers() bool {
+	return u.ProfileVisibility == PrivacyLevelPublic
+}
+
 // CanCreateTrips returns true if the user can create trips
 func (u *User) CanCreateTrips() bool {
 	return u.IsActive
@@ -225,13 +257,6 @@ func (u *User) RemoveInterest(interest string) {
 	}
 }
 
-// UpdateNotificationSettings updates the user's notification preferences
-func (u *User) UpdateNotificationSettings(emailNotifications, pushNotifications bool) {
-	u.EmailNotifications = emailNotifications
-	u.PushNotifications = pushNotifications
-	u.UpdatedAt = time.Now()
-}
-
 // UpdateRating updates the user's rating information
 func (u *User) UpdateRating(average float64, count int) {
 	u.RatingAverage = average
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -183,3 +184,27 @@ func TestUser_CanCreateTrips(t *testing.T) {
 	user.Activate()
 	assert.True(t, user.CanCreateTrips())
 }
+
+func TestUser_AcceptsMessagesFromStrangers(t *testing.T) {
+	user, err := NewUser("google123", "test@example.com", "John", "Doe", "photo.jpg")
+	require.NoError(t, err)
+
+	assert.True(t, user.AcceptsMessagesFromStrangers())
+
+	user.ProfileVisibility = PrivacyLevelFriends
+	assert.False(t, user.AcceptsMessagesFromStrangers())
+
+	user.ProfileVisibility = PrivacyLevelPrivate
+	assert.False(t, user.AcceptsMessagesFromStrangers())
+}
+
+func TestNewBlock(t *testing.T) {
+	blockerID := uuid.New()
+
+	block, err := NewBlock(blockerID, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, blockerID, block.BlockerID)
+
+	_, err = NewBlock(blockerID, blockerID)
+	assert.ErrorIs(t, err, ErrCannotBlockSelf)
+}
@@ -0,0 +1,24 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// User event names
+const (
+	EventRatingReceived = "user.rating_received"
+)
+
+// RatingReceived is published when a user is rated by a fellow traveler
+type RatingReceived struct {
+	RaterID     uuid.UUID
+	RatedUserID uuid.UUID
+	TripID      *uuid.UUID
+	Rating      int
+	OccurredAt  time.Time
+}
+
+// EventName implements event.Event
+func (RatingReceived) EventName() string { return EventRatingReceived }
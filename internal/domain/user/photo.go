@@ -0,0 +1,108 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"jointrip/internal/domain/blob"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxProfilePhotoSize is the largest profile photo file accepted, in bytes
+	MaxProfilePhotoSize = 5 << 20
+	// DefaultProfilePhotoSize is the side of the variant ProfilePhotoURL points at
+	DefaultProfilePhotoSize = 128
+)
+
+// ProfilePhotoSizes are the sides, in pixels, of the square variants made
+// of every uploaded profile photo, smallest first
+var ProfilePhotoSizes = []int{64, 128, 512}
+
+// profilePhotoContentTypes are the image types profile photos can be uploaded as
+var profilePhotoContentTypes = map[string]bool{
+	blob.ContentTypeJPEG: true,
+	blob.ContentTypePNG:  true,
+	blob.ContentTypeGIF:  true,
+}
+
+// ProfilePhoto is one size of an uploaded profile photo
+type ProfilePhoto struct {
+	Size int    `json:"size"`
+	URL  string `json:"url"`
+}
+
+// CheckProfilePhoto checks an uploaded profile photo by its size and its
+// first bytes, whatever its name or the uploader claim
+func CheckProfilePhoto(head []byte, size int64) error {
+	if size > MaxProfilePhotoSize {
+		return ErrProfilePhotoTooLarge
+	}
+	if size <= 0 || !profilePhotoContentTypes[blob.DetectContentType(head)] {
+		return ErrInvalidProfilePhoto
+	}
+	return nil
+}
+
+// ProfilePhotoKey returns where the variants of an uploaded photo are
+// stored. It is named after the hash of the photo, so that a new photo
+// gets new URLs and caches never serve a stale one.
+func ProfilePhotoKey(userID uuid.UUID, hash string) string {
+	return fmt.Sprintf("profile-photos/%s/%s", userID, hash)
+}
+
+// ProfilePhotoVariantKey returns where one size of the photo stored under
+// key is kept
+func ProfilePhotoVariantKey(key string, size int) string {
+	return fmt.Sprintf("%s-%d.jpg", key, size)
+}
+
+// SetProfilePhoto records a newly uploaded photo, whose variants are stored
+// under key. url gives the address clients load a size from.
+func (u *User) SetProfilePhoto(key string, url func(size int) string) {
+	u.ProfilePhotoKey = key
+	u.ProfilePhotos = make([]ProfilePhoto, 0, len(ProfilePhotoSizes))
+	for _, size := range ProfilePhotoSizes {
+		u.ProfilePhotos = append(u.ProfilePhotos, ProfilePhoto{Size: size, URL: url(size)})
+	}
+	u.ProfilePhotoURL = url(DefaultProfilePhotoSize)
+	u.UpdatedAt = time.Now()
+}
+
+// ProfilePhotoFile returns where the smallest variant of the uploaded photo
+// that is at least size pixels wide is stored, or the largest one. Photos
+// uploaded before variants were made have only their original.
+func (u *User) ProfilePhotoFile(size int) (string, error) {
+	if u.ProfilePhotoKey == "" {
+		return "", ErrNoProfilePhoto
+	}
+	if len(u.ProfilePhotos) == 0 {
+		return u.ProfilePhotoKey, nil
+	}
+
+	chosen := u.ProfilePhotos[len(u.ProfilePhotos)-1].Size
+	for _, photo := range u.ProfilePhotos {
+		if photo.Size >= size {
+			chosen = photo.Size
+			break
+		}
+	}
+	return ProfilePhotoVariantKey(u.ProfilePhotoKey, chosen), nil
+}
+
+// ProfilePhotoFiles returns every stored file of the uploaded photo
+func (u *User) ProfilePhotoFiles() []string {
+	if u.ProfilePhotoKey == "" {
+		return nil
+	}
+	if len(u.ProfilePhotos) == 0 {
+		return []string{u.ProfilePhotoKey}
+	}
+
+	files := make([]string, 0, len(u.ProfilePhotos))
+	for _, photo := range u.ProfilePhotos {
+		files = append(files, ProfilePhotoVariantKey(u.ProfilePhotoKey, photo.Size))
+	}
+	return files
+}
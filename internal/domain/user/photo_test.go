@@ -0,0 +1,88 @@
+package user
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckProfilePhoto(t *testing.T) {
+	tests := []struct {
+		name string
+		head string
+		size int64
+		err  error
+	}{
+		{"jpeg", "\xff\xd8\xff\xe0", 1000, nil},
+		{"png", "\x89PNG\r\n\x1a\n", 1000, nil},
+		{"gif", "GIF89a", 1000, nil},
+		{"webp", "RIFF\x00\x00\x00\x00WEBPVP8 ", 1000, ErrInvalidProfilePhoto},
+		{"pdf claiming to be an image", "%PDF-1.7", 1000, ErrInvalidProfilePhoto},
+		{"html", "<html><script>", 1000, ErrInvalidProfilePhoto},
+		{"empty", "", 0, ErrInvalidProfilePhoto},
+		{"too large", "\xff\xd8\xff\xe0", MaxProfilePhotoSize + 1, ErrProfilePhotoTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckProfilePhoto([]byte(tt.head), tt.size)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestUser_SetProfilePhoto(t *testing.T) {
+	u, err := NewUser("google123", "test@example.com", "John", "Doe", "https://example.com/photo.jpg")
+	require.NoError(t, err)
+
+	_, err = u.ProfilePhotoFile(128)
+	assert.ErrorIs(t, err, ErrNoProfilePhoto)
+	assert.Empty(t, u.ProfilePhotoFiles())
+
+	key := ProfilePhotoKey(u.ID, "abc123")
+	assert.Equal(t, "profile-photos/"+u.ID.String()+"/abc123", key)
+	u.SetProfilePhoto(key, func(size int) string {
+		return fmt.Sprintf("/photo?size=%d", size)
+	})
+
+	assert.Equal(t, []ProfilePhoto{
+		{Size: 64, URL: "/photo?size=64"},
+		{Size: 128, URL: "/photo?size=128"},
+		{Size: 512, URL: "/photo?size=512"},
+	}, u.ProfilePhotos)
+	assert.Equal(t, "/photo?size=128", u.ProfilePhotoURL)
+	assert.Equal(t, []string{key + "-64.jpg", key + "-128.jpg", key + "-512.jpg"}, u.ProfilePhotoFiles())
+
+	tests := []struct {
+		size int
+		file string
+	}{
+		{32, key + "-64.jpg"},
+		{64, key + "-64.jpg"},
+		{100, key + "-128.jpg"},
+		{512, key + "-512.jpg"},
+		{2048, key + "-512.jpg"},
+	}
+	for _, tt := range tests {
+		file, err := u.ProfilePhotoFile(tt.size)
+		require.NoError(t, err)
+		assert.Equal(t, tt.file, file, "size %d", tt.size)
+	}
+}
+
+func TestUser_ProfilePhotoFile_WithoutSizes(t *testing.T) {
+	// Photos stored before sizes were made only have their original
+	u := &User{ID: uuid.New(), ProfilePhotoKey: "profile-photos/user/original"}
+
+	file, err := u.ProfilePhotoFile(64)
+	require.NoError(t, err)
+	assert.Equal(t, "profile-photos/user/original", file)
+	assert.Equal(t, []string{"profile-photos/user/original"}, u.ProfilePhotoFiles())
+}
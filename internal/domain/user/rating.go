@@ -0,0 +1,59 @@
+package user
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Rating bounds
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+// Rating is a review of one user by a fellow traveler of a trip they shared.
+// Ratings given before trips were required have no trip.
+type Rating struct {
+	ID          uuid.UUID  `json:"id"`
+	RaterID     uuid.UUID  `json:"rater_id"`
+	RatedUserID uuid.UUID  `json:"rated_user_id"`
+	TripID      *uuid.UUID `json:"trip_id,omitempty"`
+	Rating      int        `json:"rating"`
+	Review      string     `json:"review,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// NewRating creates a rating of one user by another for a trip they shared
+func NewRating(raterID, ratedUserID, tripID uuid.UUID, rating int, review string) (*Rating, error) {
+	if raterID == ratedUserID {
+		return nil, ErrCannotRateSelf
+	}
+	if rating < MinRating || rating > MaxRating {
+		return nil, ErrInvalidRating
+	}
+
+	return &Rating{
+		ID:          uuid.New(),
+		RaterID:     raterID,
+		RatedUserID: ratedUserID,
+		TripID:      &tripID,
+		Rating:      rating,
+		Review:      strings.TrimSpace(review),
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// AverageRating returns the mean score of the given ratings, or zero if there are none
+func AverageRating(ratings []*Rating) float64 {
+	if len(ratings) == 0 {
+		return 0
+	}
+
+	total := 0
+	for _, r := range ratings {
+		total += r.Rating
+	}
+	return float64(total) / float64(len(ratings))
+}
@@ -0,0 +1,35 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRating(t *testing.T) {
+	raterID, ratedID, tripID := uuid.New(), uuid.New(), uuid.New()
+
+	r, err := NewRating(raterID, ratedID, tripID, 4, "  Great company  ")
+	require.NoError(t, err)
+	assert.Equal(t, raterID, r.RaterID)
+	assert.Equal(t, ratedID, r.RatedUserID)
+	assert.Equal(t, tripID, *r.TripID)
+	assert.Equal(t, "Great company", r.Review)
+
+	_, err = NewRating(raterID, raterID, tripID, 4, "")
+	assert.ErrorIs(t, err, ErrCannotRateSelf)
+
+	for _, score := range []int{MinRating - 1, MaxRating + 1} {
+		_, err = NewRating(raterID, ratedID, tripID, score, "")
+		assert.ErrorIs(t, err, ErrInvalidRating)
+	}
+}
+
+func TestAverageRating(t *testing.T) {
+	assert.Zero(t, AverageRating(nil))
+
+	ratings := []*Rating{{Rating: 5}, {Rating: 4}, {Rating: 4}}
+	assert.InDelta(t, 4.333, AverageRating(ratings), 0.001)
+}
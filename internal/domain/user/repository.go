@@ -9,9 +9,19 @@ import (
 
 // Domain errors
 var (
-	ErrUserNotFound      = errors.New("user not found")
-	ErrUserAlreadyExists = errors.New("user already exists")
-	ErrInvalidUserData   = errors.New("invalid user data")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrUserAlreadyExists    = errors.New("user already exists")
+	ErrInvalidUserData      = errors.New("invalid user data")
+	ErrCannotBlockSelf      = errors.New("you cannot block yourself")
+	ErrAlreadyBlocked       = errors.New("user is already blocked")
+	ErrBlockNotFound        = errors.New("user is not blocked")
+	ErrNoProfilePhoto       = errors.New("user has no uploaded profile photo")
+	ErrInvalidProfilePhoto  = errors.New("profile photos must be JPEG, PNG or GIF images")
+	ErrProfilePhotoTooLarge = errors.New("profile photos can be at most 5 MB")
+	ErrCannotRateSelf       = errors.New("you cannot rate yourself")
+	ErrInvalidRating        = errors.New("rating must be between 1 and 5")
+	ErrAlreadyRated         = errors.New("you have already rated this user for this trip")
+	ErrNotTripCompanion     = errors.New("you can only rate people you traveled with")
 )
 
 // Repository defines the interface for user data persistence
@@ -46,3 +56,31 @@ type Repository interface {
 	// ExistsByUsername checks if a user exists with the given username
 	ExistsByUsername(ctx context.Context, username string) (bool, error)
 }
+
+// BlockRepository defines the interface for user block persistence
+type BlockRepository interface {
+	// Create stores a block, failing with ErrAlreadyBlocked if it exists
+	Create(ctx context.Context, block *Block) error
+
+	// Delete removes a block
+	Delete(ctx context.Context, blockerID, blockedID uuid.UUID) error
+
+	// ListByBlocker retrieves the blocks a user has placed, most recent first
+	ListByBlocker(ctx context.Context, blockerID uuid.UUID) ([]*Block, error)
+
+	// ExistsBetween checks if either user has blocked the other
+	ExistsBetween(ctx context.Context, userID, otherID uuid.UUID) (bool, error)
+}
+
+// RatingRepository defines the interface for user rating persistence
+type RatingRepository interface {
+	// Create stores a rating, failing with ErrAlreadyRated if the rater already
+	// rated the user for the same trip
+	Create(ctx context.Context, rating *Rating) error
+
+	// ListByRatedUser retrieves the ratings a user received, newest first
+	ListByRatedUser(ctx context.Context, ratedUserID uuid.UUID) ([]*Rating, error)
+
+	// ListByRater retrieves the ratings a user gave, newest first
+	ListByRater(ctx context.Context, raterID uuid.UUID) ([]*Rating, error)
+}
@@ -0,0 +1,109 @@
+// Package archive writes trip albums out as zip files
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"jointrip/internal/domain/album"
+	"jointrip/internal/domain/blob"
+)
+
+// ContentType is the media type of album archives
+const ContentType = "application/zip"
+
+// maxSlugLength is the longest part of a file name taken from a title or caption
+const maxSlugLength = 60
+
+// PhotoSource opens the stored files of album photos
+type PhotoSource interface {
+	Open(ctx context.Context, photo *album.Photo) (io.ReadCloser, error)
+}
+
+// Filename returns the download name of an album, such as
+// "lisbon-weekend-photos.zip"
+func Filename(tripTitle string) string {
+	name := slug(tripTitle)
+	if name == "" {
+		name = "trip"
+	}
+	return name + "-photos.zip"
+}
+
+// WriteZip writes the photos, in order, as a zip archive to w. Each file is
+// copied from storage straight into the archive, one at a time, so neither
+// the photos nor the archive are ever held in memory. The JPEGs are stored
+// as they are, since compressing them again gains nothing. Photos whose
+// file is gone, because they were removed in the meantime, are left out.
+func WriteZip(ctx context.Context, w io.Writer, photos []*album.Photo, source PhotoSource) error {
+	archive := zip.NewWriter(w)
+
+	for i, photo := range photos {
+		file, err := source.Open(ctx, photo)
+		if errors.Is(err, blob.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		err = copyEntry(archive, entryName(i, photo), photo, file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// copyEntry adds a photo's file to the archive
+func copyEntry(archive *zip.Writer, name string, photo *album.Photo, file io.Reader) error {
+	entry, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Store,
+		Modified: photo.CreatedAt,
+		Comment:  photo.Caption,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(entry, file)
+	return err
+}
+
+// entryName names the ith photo of an archive after its place in the album
+// and its caption, such as "003-sunset-over-the-bay.jpg"
+func entryName(i int, photo *album.Photo) string {
+	name := fmt.Sprintf("%03d", i+1)
+	if caption := slug(photo.Caption); caption != "" {
+		name += "-" + caption
+	}
+	return name + ".jpg"
+}
+
+// slug keeps the letters and digits of text, lowercased, with dashes for
+// anything in between, cut to maxSlugLength
+func slug(text string) string {
+	var slug strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(text) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+			if slug.Len() >= maxSlugLength {
+				return strings.TrimSuffix(slug.String(), "-")
+			}
+			slug.WriteRune(c)
+			dash = false
+		case !dash && slug.Len() > 0:
+			slug.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(slug.String(), "-")
+}
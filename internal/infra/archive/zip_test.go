@@ -0,0 +1,122 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"jointrip/internal/domain/album"
+	"jointrip/internal/domain/blob"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource serves photo files from memory and tracks how many are open
+type fakeSource struct {
+	files   map[string]string
+	open    int
+	maxOpen int
+	err     error
+}
+
+func (s *fakeSource) Open(ctx context.Context, photo *album.Photo) (io.ReadCloser, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	content, ok := s.files[photo.StorageKey]
+	if !ok {
+		return nil, blob.ErrNotFound
+	}
+	s.open++
+	s.maxOpen = max(s.maxOpen, s.open)
+	return &trackedFile{Reader: strings.NewReader(content), source: s}, nil
+}
+
+type trackedFile struct {
+	io.Reader
+	source *fakeSource
+}
+
+func (f *trackedFile) Close() error {
+	f.source.open--
+	return nil
+}
+
+func testPhoto(t *testing.T, caption string) *album.Photo {
+	t.Helper()
+	p, err := album.NewPhoto(uuid.New(), uuid.New(), caption, "", 1)
+	require.NoError(t, err)
+	p.CreatedAt = time.Date(2026, 10, 16, 12, 30, 0, 0, time.UTC)
+	return p
+}
+
+func TestWriteZip(t *testing.T) {
+	sunset := testPhoto(t, "Sunset over the bay!")
+	untitled := testPhoto(t, "")
+	removed := testPhoto(t, "Gone")
+	beach := testPhoto(t, "  Beach -- day 2 ")
+	source := &fakeSource{files: map[string]string{
+		sunset.StorageKey:   "sunset",
+		untitled.StorageKey: "untitled",
+		beach.StorageKey:    "beach",
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteZip(context.Background(), &buf, []*album.Photo{sunset, untitled, removed, beach}, source))
+	assert.Equal(t, 1, source.maxOpen)
+	assert.Zero(t, source.open)
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	var names, contents []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+		assert.Equal(t, zip.Store, file.Method)
+		assert.True(t, sunset.CreatedAt.Equal(file.Modified), file.Modified)
+
+		r, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		contents = append(contents, string(content))
+	}
+	assert.Equal(t, []string{"001-sunset-over-the-bay.jpg", "002.jpg", "004-beach-day-2.jpg"}, names)
+	assert.Equal(t, []string{"sunset", "untitled", "beach"}, contents)
+	assert.Equal(t, "Sunset over the bay!", archive.File[0].Comment)
+
+	source.err = errors.New("storage unavailable")
+	assert.ErrorIs(t, WriteZip(context.Background(), io.Discard, []*album.Photo{sunset}, source), source.err)
+}
+
+func TestWriteZip_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteZip(context.Background(), &buf, nil, &fakeSource{}))
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Empty(t, archive.File)
+}
+
+func TestFilename(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{"Lisbon Weekend", "lisbon-weekend-photos.zip"},
+		{"  Café & Surf -- 2026!  ", "caf-surf-2026-photos.zip"},
+		{"日本", "trip-photos.zip"},
+		{strings.Repeat("ab ", 40), strings.TrimSuffix(strings.Repeat("ab-", 20), "-") + "-photos.zip"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, Filename(tt.title), tt.title)
+	}
+}
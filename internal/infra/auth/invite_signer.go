@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/infra/config"
+
+	"github.com/google/uuid"
+)
+
+// invitePurpose separates invite link signatures from other uses of the key
+const invitePurpose = "trip-invite:"
+
+// InviteSigner signs and verifies trip invite link tokens.
+// A token carries the invite link ID.
+type InviteSigner struct {
+	signer *Signer
+}
+
+// NewInviteSigner creates a new invite signer
+func NewInviteSigner(cfg *config.Config) *InviteSigner {
+	return &InviteSigner{
+		signer: NewSigner(cfg.GetInviteSigningSecret(), invitePurpose),
+	}
+}
+
+// Sign returns the token for an invite link
+func (s *InviteSigner) Sign(linkID uuid.UUID) string {
+	return s.signer.Sign(linkID[:])
+}
+
+// Verify checks a token's signature and returns the invite link ID it carries
+func (s *InviteSigner) Verify(token string) (uuid.UUID, error) {
+	rawID, err := s.signer.Verify(token)
+	if err != nil {
+		return uuid.Nil, trip.ErrInvalidInviteToken
+	}
+
+	linkID, err := uuid.FromBytes(rawID)
+	if err != nil {
+		return uuid.Nil, trip.ErrInvalidInviteToken
+	}
+
+	return linkID, nil
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/infra/config"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestInviteSigner(secret string) *InviteSigner {
+	return NewInviteSigner(&config.Config{
+		Trip: config.TripConfig{InviteSigningSecret: secret},
+	})
+}
+
+func TestInviteSigner_SignAndVerify(t *testing.T) {
+	signer := newTestInviteSigner("invite-secret")
+	linkID := uuid.New()
+
+	token := signer.Sign(linkID)
+	assert.NotContains(t, token, "/")
+	assert.NotContains(t, token, "+")
+
+	got, err := signer.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, linkID, got)
+
+	// Signing is deterministic so organizers can list their links again
+	assert.Equal(t, token, signer.Sign(linkID))
+}
+
+func TestInviteSigner_RejectsTamperedTokens(t *testing.T) {
+	signer := newTestInviteSigner("invite-secret")
+	token := signer.Sign(uuid.New())
+	encodedID, encodedMAC, _ := strings.Cut(token, ".")
+
+	otherID := uuid.New()
+	forged := signer.Sign(otherID)
+	_, forgedMAC, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no signature", encodedID},
+		{"swapped signature", encodedID + "." + forgedMAC},
+		{"truncated signature", encodedID + "." + encodedMAC[:10]},
+		{"invalid encoding", "!!!." + encodedMAC},
+		{"other secret", newTestInviteSigner("other-secret").Sign(otherID)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signer.Verify(tt.token)
+			assert.ErrorIs(t, err, trip.ErrInvalidInviteToken)
+		})
+	}
+}
+
+func TestInviteSigner_FallsBackToJWTSecret(t *testing.T) {
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: "jwt-secret"}}
+	linkID := uuid.New()
+
+	token := NewInviteSigner(cfg).Sign(linkID)
+
+	_, err := newTestInviteSigner("jwt-secret").Verify(token)
+	assert.NoError(t, err)
+}
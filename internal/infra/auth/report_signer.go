@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"encoding/json"
+	"time"
+
+	"jointrip/internal/domain/expense"
+	"jointrip/internal/infra/config"
+)
+
+// reportPurpose separates report link signatures from other uses of the key
+const reportPurpose = "expense-report:"
+
+// ReportSigner signs and verifies expense report download tokens. A token
+// carries the JSON encoded claims, so a link holds everything needed to
+// produce the report.
+type ReportSigner struct {
+	signer *Signer
+}
+
+// NewReportSigner creates a new report signer
+func NewReportSigner(cfg *config.Config) *ReportSigner {
+	return &ReportSigner{
+		signer: NewSigner(cfg.GetReportSigningSecret(), reportPurpose),
+	}
+}
+
+// Sign returns the token for a report link
+func (s *ReportSigner) Sign(claims expense.ReportClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	return s.signer.Sign(payload), nil
+}
+
+// Verify checks a token's signature and expiry and returns the claims it carries
+func (s *ReportSigner) Verify(token string, now time.Time) (expense.ReportClaims, error) {
+	payload, err := s.signer.Verify(token)
+	if err != nil {
+		return expense.ReportClaims{}, expense.ErrInvalidReportLink
+	}
+
+	var claims expense.ReportClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return expense.ReportClaims{}, expense.ErrInvalidReportLink
+	}
+	if claims.IsExpired(now) {
+		return expense.ReportClaims{}, expense.ErrReportLinkExpired
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"jointrip/internal/domain/expense"
+	"jointrip/internal/infra/config"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReportSigner(secret string) *ReportSigner {
+	return NewReportSigner(&config.Config{
+		JWT: config.JWTConfig{Secret: secret},
+	})
+}
+
+func testReportClaims(t *testing.T, now time.Time) expense.ReportClaims {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	claims, err := expense.NewReportClaims(uuid.New(), uuid.New(), expense.ReportCSV,
+		expense.ListFilter{Category: expense.CategoryFood, From: &from}, now, 10*time.Minute)
+	require.NoError(t, err)
+	return claims
+}
+
+func TestReportSigner_SignAndVerify(t *testing.T) {
+	signer := newTestReportSigner("jwt-secret")
+	now := time.Now()
+	claims := testReportClaims(t, now)
+
+	token, err := signer.Sign(claims)
+	require.NoError(t, err)
+	assert.NotContains(t, token, "/")
+	assert.NotContains(t, token, "+")
+
+	got, err := signer.Verify(token, now)
+	require.NoError(t, err)
+	assert.Equal(t, claims.TripID, got.TripID)
+	assert.Equal(t, claims.UserID, got.UserID)
+	assert.Equal(t, claims.Format, got.Format)
+	assert.Equal(t, claims.Category, got.Category)
+	assert.True(t, claims.From.Equal(*got.From))
+	assert.Nil(t, got.To)
+	assert.True(t, claims.ExpiresAt.Equal(got.ExpiresAt))
+
+	_, err = signer.Verify(token, now.Add(10*time.Minute))
+	assert.ErrorIs(t, err, expense.ErrReportLinkExpired)
+}
+
+func TestReportSigner_RejectsTamperedTokens(t *testing.T) {
+	signer := newTestReportSigner("jwt-secret")
+	now := time.Now()
+	token, err := signer.Sign(testReportClaims(t, now))
+	require.NoError(t, err)
+	encodedPayload, encodedMAC, _ := strings.Cut(token, ".")
+
+	forged, err := signer.Sign(testReportClaims(t, now))
+	require.NoError(t, err)
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	other, err := newTestReportSigner("other-secret").Sign(testReportClaims(t, now))
+	require.NoError(t, err)
+
+	// Invite tokens may be signed with the same key but must not pass as reports
+	invite := newTestInviteSigner("jwt-secret").Sign(uuid.New())
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no signature", encodedPayload},
+		{"swapped payload", forgedPayload + "." + encodedMAC},
+		{"truncated signature", encodedPayload + "." + encodedMAC[:10]},
+		{"invalid encoding", "!!!." + encodedMAC},
+		{"other secret", other},
+		{"invite token", invite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signer.Verify(tt.token, now)
+			assert.ErrorIs(t, err, expense.ErrInvalidReportLink)
+		})
+	}
+}
+
+func TestReportSigner_PrefersDedicatedSecret(t *testing.T) {
+	cfg := &config.Config{
+		JWT:     config.JWTConfig{Secret: "jwt-secret"},
+		Expense: config.ExpenseConfig{ReportSigningSecret: "report-secret"},
+	}
+	now := time.Now()
+
+	token, err := NewReportSigner(cfg).Sign(testReportClaims(t, now))
+	require.NoError(t, err)
+
+	_, err = newTestReportSigner("jwt-secret").Verify(token, now)
+	assert.ErrorIs(t, err, expense.ErrInvalidReportLink)
+}
@@ -0,0 +1,151 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"jointrip/internal/domain/blob"
+	"jointrip/internal/infra/auth"
+)
+
+// linkPurpose separates file link signatures from other uses of the key
+const linkPurpose = "file-link:"
+
+// LocalStore keeps files in a directory on the local disk. Files are
+// written to a temporary file first and renamed into place, so readers
+// never see a partial file. Its signed links point at linkBase, where the
+// application serves the files after checking the link with OpenLink.
+type LocalStore struct {
+	root     string
+	linkBase string
+	signer   *auth.Signer
+}
+
+// NewLocalStore creates a store rooted at dir, creating it if needed.
+// Signed links are linkBase followed by the key and signed with secret.
+func NewLocalStore(dir, linkBase, secret string) (*LocalStore, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &LocalStore{root: root, linkBase: linkBase, signer: auth.NewSigner(secret, linkPurpose)}, nil
+}
+
+// Put stores size bytes of content under key
+func (s *LocalStore) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, io.LimitReader(content, size+1))
+	if err == nil && written != size {
+		err = fmt.Errorf("expected %d bytes, got %d", size, written)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Open returns the content stored under key
+func (s *LocalStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, blob.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Delete removes the file stored under key
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// SignedURL returns a link to the file stored under key that is valid until expiresAt
+func (s *LocalStore) SignedURL(ctx context.Context, key string, expiresAt time.Time) (string, error) {
+	if !blob.ValidKey(key) {
+		return "", blob.ErrInvalidKey
+	}
+
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	query := url.Values{
+		"expires":   {expires},
+		"signature": {s.signer.Sign(linkPayload(key, expires))},
+	}
+	return s.linkBase + "/" + key + "?" + query.Encode(), nil
+}
+
+// OpenLink checks a signed link to key and returns the file it grants
+func (s *LocalStore) OpenLink(ctx context.Context, key string, query url.Values, now time.Time) (io.ReadCloser, error) {
+	expires := query.Get("expires")
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return nil, blob.ErrInvalidLink
+	}
+	payload, err := s.signer.Verify(query.Get("signature"))
+	if err != nil || string(payload) != string(linkPayload(key, expires)) {
+		return nil, blob.ErrInvalidLink
+	}
+	if now.Unix() >= expiresAt {
+		return nil, blob.ErrLinkExpired
+	}
+
+	return s.Open(ctx, key)
+}
+
+// linkPayload is what a link to key expiring at expires is signed over
+func linkPayload(key, expires string) []byte {
+	return []byte(key + "\x00" + expires)
+}
+
+// path returns where a key is stored on disk
+func (s *LocalStore) path(key string) (string, error) {
+	if !blob.ValidKey(key) {
+		return "", blob.ErrInvalidKey
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,128 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"jointrip/internal/domain/blob"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStore(filepath.Join(dir, "files"), "/files", "secret")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	key := "receipts/trip/expense/receipt"
+	require.NoError(t, store.Put(ctx, key, strings.NewReader("first"), 5, blob.ContentTypePDF))
+	require.NoError(t, store.Put(ctx, key, strings.NewReader("second"), 6, blob.ContentTypePDF))
+
+	file, err := store.Open(ctx, key)
+	require.NoError(t, err)
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	assert.Equal(t, "second", string(content))
+
+	require.NoError(t, store.Delete(ctx, key))
+	require.NoError(t, store.Delete(ctx, key))
+	_, err = store.Open(ctx, key)
+	assert.ErrorIs(t, err, blob.ErrNotFound)
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(filepath.Join(dir, "files", "receipts", "trip", "expense"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestLocalStore_SizeMismatch(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "/files", "secret")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	assert.Error(t, store.Put(ctx, "short", strings.NewReader("abc"), 5, ""))
+	assert.Error(t, store.Put(ctx, "long", strings.NewReader("abcdef"), 5, ""))
+	assert.Error(t, store.Put(ctx, "failing", io.MultiReader(strings.NewReader("ab"), errReader{}), 5, ""))
+
+	for _, key := range []string{"short", "long", "failing"} {
+		_, err := store.Open(ctx, key)
+		assert.ErrorIs(t, err, blob.ErrNotFound, key)
+	}
+}
+
+func TestLocalStore_RejectsInvalidKeys(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStore(filepath.Join(dir, "files"), "/files", "secret")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	err = store.Put(ctx, "../escape", strings.NewReader("x"), 1, "")
+	assert.ErrorIs(t, err, blob.ErrInvalidKey)
+	_, err = store.Open(ctx, "/etc/passwd")
+	assert.ErrorIs(t, err, blob.ErrInvalidKey)
+	assert.ErrorIs(t, store.Delete(ctx, "a/../../b"), blob.ErrInvalidKey)
+
+	_, err = os.Stat(filepath.Join(dir, "escape"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLocalStore_SignedURL(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "/api/v1/files", "secret")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	key := "profile-photos/user/photo"
+	require.NoError(t, store.Put(ctx, key, strings.NewReader("photo"), 5, blob.ContentTypeJPEG))
+
+	now := time.Unix(1_700_000_000, 0)
+	link, err := store.SignedURL(ctx, key, now.Add(time.Minute))
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/files/"+key, parsed.Path)
+	query := parsed.Query()
+
+	file, err := store.OpenLink(ctx, key, query, now)
+	require.NoError(t, err)
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	assert.Equal(t, "photo", string(content))
+
+	_, err = store.OpenLink(ctx, key, query, now.Add(time.Minute))
+	assert.ErrorIs(t, err, blob.ErrLinkExpired)
+
+	// The signature covers both the key and the expiry
+	_, err = store.OpenLink(ctx, "profile-photos/user/other", query, now)
+	assert.ErrorIs(t, err, blob.ErrInvalidLink)
+	extended := url.Values{"expires": {"1800000000"}, "signature": {query.Get("signature")}}
+	_, err = store.OpenLink(ctx, key, extended, now)
+	assert.ErrorIs(t, err, blob.ErrInvalidLink)
+	_, err = store.OpenLink(ctx, key, url.Values{}, now)
+	assert.ErrorIs(t, err, blob.ErrInvalidLink)
+
+	other, err := NewLocalStore(t.TempDir(), "/api/v1/files", "other secret")
+	require.NoError(t, err)
+	_, err = other.OpenLink(ctx, key, query, now)
+	assert.ErrorIs(t, err, blob.ErrInvalidLink)
+
+	_, err = store.SignedURL(ctx, "../escape", now)
+	assert.ErrorIs(t, err, blob.ErrInvalidKey)
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
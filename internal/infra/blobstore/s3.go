@@ -0,0 +1,282 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"jointrip/internal/domain/blob"
+	"jointrip/internal/infra/config"
+)
+
+// S3 request signing (AWS Signature Version 4)
+const (
+	s3Algorithm      = "AWS4-HMAC-SHA256"
+	s3Service        = "s3"
+	s3UnsignedBody   = "UNSIGNED-PAYLOAD"
+	s3EmptyBodyHash  = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	s3MaxLinkExpires = 7 * 24 * time.Hour
+)
+
+// S3Store keeps files in a bucket of an S3-compatible service such as AWS
+// S3 or MinIO. Requests are signed with Signature Version 4; signed links
+// are presigned GET requests, so files are downloaded from the service
+// directly.
+type S3Store struct {
+	endpoint        *url.URL
+	publicEndpoint  *url.URL
+	region          string
+	bucket          string
+	accessKeyID     string
+	secretAccessKey string
+	pathStyle       bool
+	client          *http.Client
+	now             func() time.Time
+}
+
+// NewS3Store creates a store for the configured bucket
+func NewS3Store(cfg *config.Config, client *http.Client) (*S3Store, error) {
+	endpoint, err := parseEndpoint(cfg.Storage.S3Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	publicEndpoint := endpoint
+	if cfg.Storage.S3PublicEndpoint != "" {
+		if publicEndpoint, err = parseEndpoint(cfg.Storage.S3PublicEndpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	return &S3Store{
+		endpoint:        endpoint,
+		publicEndpoint:  publicEndpoint,
+		region:          cfg.Storage.S3Region,
+		bucket:          cfg.Storage.S3Bucket,
+		accessKeyID:     cfg.Storage.S3AccessKeyID,
+		secretAccessKey: cfg.Storage.S3SecretAccessKey,
+		pathStyle:       cfg.Storage.S3PathStyle,
+		client:          client,
+		now:             time.Now,
+	}, nil
+}
+
+// Put stores size bytes of content under key
+func (s *S3Store) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	if size == 0 {
+		content = http.NoBody
+	}
+	req, err := s.newRequest(ctx, http.MethodPut, key, content)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req, s3UnsignedBody)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Open returns the content stored under key
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req, s3EmptyBodyHash)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete removes the file stored under key
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req, s3EmptyBodyHash)
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// SignedURL returns a presigned link to download the file stored under key
+// until expiresAt. S3 caps presigned links at a week.
+func (s *S3Store) SignedURL(ctx context.Context, key string, expiresAt time.Time) (string, error) {
+	if !blob.ValidKey(key) {
+		return "", blob.ErrInvalidKey
+	}
+
+	now := s.now().UTC()
+	expires := min(max(expiresAt.Sub(now), time.Second), s3MaxLinkExpires)
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.scope(now)
+
+	u := s.objectURL(s.publicEndpoint, key)
+	query := url.Values{
+		"X-Amz-Algorithm":     {s3Algorithm},
+		"X-Amz-Credential":    {s.accessKeyID + "/" + scope},
+		"X-Amz-Date":          {amzDate},
+		"X-Amz-Expires":       {strconv.FormatInt(int64(expires/time.Second), 10)},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		canonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		s3UnsignedBody,
+	}, "\n")
+	query.Set("X-Amz-Signature", s.signature(now, scope, canonicalRequest))
+
+	u.RawQuery = canonicalQuery(query)
+	return u.String(), nil
+}
+
+// newRequest creates a request for the object stored under key
+func (s *S3Store) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	if !blob.ValidKey(key) {
+		return nil, blob.ErrInvalidKey
+	}
+	return http.NewRequestWithContext(ctx, method, s.objectURL(s.endpoint, key).String(), body)
+}
+
+// do signs and sends a request. Responses other than 2xx are turned into
+// errors, ErrNotFound for a missing object.
+func (s *S3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	s.sign(req, payloadHash, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, blob.ErrNotFound
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("s3 %s %s returned %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(detail)))
+}
+
+// sign adds the Signature Version 4 authorization to a request, covering
+// the host and every header set on it
+func (s *S3Store) sign(req *http.Request, payloadHash string, now time.Time) {
+	now = now.UTC()
+	req.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := s.scope(now)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.accessKeyID, scope, signedHeaders, s.signature(now, scope, canonicalRequest)))
+}
+
+// signature signs a canonical request with the key derived for its day
+func (s *S3Store) signature(now time.Time, scope, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format("20060102T150405Z"),
+		scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// scope returns the credential scope of requests signed on a day
+func (s *S3Store) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.region + "/" + s3Service + "/aws4_request"
+}
+
+// objectURL returns the address of the object stored under key. Keys only
+// hold characters that need no escaping, so the path is already canonical.
+func (s *S3Store) objectURL(endpoint *url.URL, key string) *url.URL {
+	u := *endpoint
+	if s.pathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket + "/" + key
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + key
+	}
+	return &u
+}
+
+// parseEndpoint checks that an S3 endpoint is an absolute http(s) address
+func parseEndpoint(endpoint string) (*url.URL, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+	return u, nil
+}
+
+// canonicalQuery encodes a query the way Signature Version 4 expects:
+// sorted by name, with spaces as %20
+func canonicalQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+// hmacSHA256 computes the HMAC-SHA256 of data
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
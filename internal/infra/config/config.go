@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -17,6 +18,14 @@ type Config struct {
 	JWT      JWTConfig
 	Google   GoogleOAuthConfig
 	Session  SessionConfig
+	Trip     TripConfig
+	Admin    AdminConfig
+	Realtime RealtimeConfig
+	Mail     MailConfig
+	Push     PushConfig
+	Expense  ExpenseConfig
+	Rates    RatesConfig
+	Storage  StorageConfig
 	Log      LogConfig
 }
 
@@ -56,6 +65,85 @@ type SessionConfig struct {
 	MaxSessionsPerUser int
 }
 
+// TripConfig holds trip participation configuration
+type TripConfig struct {
+	WaitlistOfferHours  int
+	InviteSigningSecret string
+}
+
+// AdminConfig holds administrator configuration
+type AdminConfig struct {
+	Emails []string
+}
+
+// RealtimeConfig holds real-time delivery configuration
+type RealtimeConfig struct {
+	// Broker is "memory" for a single server or "postgres" to fan out across
+	// servers through LISTEN/NOTIFY
+	Broker string
+}
+
+// MailConfig holds email delivery configuration
+type MailConfig struct {
+	// Backend is "smtp" to deliver through SMTP_HOST or "capture" to keep
+	// emails in memory without sending them
+	Backend      string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUser     string
+	SMTPPassword string
+	From         string
+	// AppBaseURL is where links in emails point to
+	AppBaseURL string
+}
+
+// PushConfig holds Web Push configuration
+type PushConfig struct {
+	// VAPIDPublicKey and VAPIDPrivateKey are the base64url encoded P-256 key
+	// pair that identifies the server to push services
+	VAPIDPublicKey  string
+	VAPIDPrivateKey string
+	// VAPIDSubject is a mailto: or https: contact for push service operators
+	VAPIDSubject string
+}
+
+// ExpenseConfig holds expense sharing configuration
+type ExpenseConfig struct {
+	ReportSigningSecret string
+}
+
+// RatesConfig holds exchange rate configuration
+type RatesConfig struct {
+	// File is an ECB-style reference rates file (.xml or .csv) loaded at
+	// startup and reloaded daily; without it only same-currency expenses work
+	File string
+}
+
+// StorageConfig holds file storage configuration
+type StorageConfig struct {
+	// Driver is "local" to keep uploaded files in Dir or "s3" to keep them
+	// in an S3-compatible bucket, which every replica can reach
+	Driver string
+	// Dir is where uploaded files are kept. It must not be served as is,
+	// as access to the files is checked by the application.
+	Dir string
+	// LinkSigningSecret signs the links to files kept by the local driver
+	LinkSigningSecret string
+	// S3Endpoint is the S3 API address, such as https://s3.eu-west-1.amazonaws.com
+	// or http://localhost:9000 for MinIO
+	S3Endpoint string
+	// S3PublicEndpoint is the S3 address browsers use for signed links,
+	// when it differs from the one the server reaches
+	S3PublicEndpoint  string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	// S3PathStyle addresses the bucket in the path rather than the host
+	// name, as MinIO expects
+	S3PathStyle bool
+}
+
 // LogConfig holds logging configuration
 type LogConfig struct {
 	Level  string
@@ -96,6 +184,48 @@ func Load() (*Config, error) {
 		Session: SessionConfig{
 			MaxSessionsPerUser: getEnvAsInt("MAX_SESSIONS_PER_USER", 5),
 		},
+		Trip: TripConfig{
+			WaitlistOfferHours:  getEnvAsInt("WAITLIST_OFFER_HOURS", 24),
+			InviteSigningSecret: getEnv("INVITE_SIGNING_SECRET", ""),
+		},
+		Admin: AdminConfig{
+			Emails: getEnvAsList("ADMIN_EMAILS"),
+		},
+		Realtime: RealtimeConfig{
+			Broker: getEnv("REALTIME_BROKER", "memory"),
+		},
+		Mail: MailConfig{
+			Backend:      getEnv("MAIL_BACKEND", "smtp"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     getEnv("SMTP_PORT", "1025"),
+			SMTPUser:     getEnv("SMTP_USER", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			From:         getEnv("MAIL_FROM", "JoinTrip <no-reply@jointrip.local>"),
+			AppBaseURL:   getEnv("APP_BASE_URL", "http://localhost:8080"),
+		},
+		Push: PushConfig{
+			VAPIDPublicKey:  getEnv("VAPID_PUBLIC_KEY", ""),
+			VAPIDPrivateKey: getEnv("VAPID_PRIVATE_KEY", ""),
+			VAPIDSubject:    getEnv("VAPID_SUBJECT", "mailto:admin@jointrip.local"),
+		},
+		Expense: ExpenseConfig{
+			ReportSigningSecret: getEnv("REPORT_SIGNING_SECRET", ""),
+		},
+		Rates: RatesConfig{
+			File: getEnv("EXCHANGE_RATES_FILE", ""),
+		},
+		Storage: StorageConfig{
+			Driver:            getEnv("STORAGE_DRIVER", "local"),
+			Dir:               getEnv("STORAGE_DIR", "./data/storage"),
+			LinkSigningSecret: getEnv("FILE_SIGNING_SECRET", ""),
+			S3Endpoint:        getEnv("S3_ENDPOINT", ""),
+			S3PublicEndpoint:  getEnv("S3_PUBLIC_ENDPOINT", ""),
+			S3Region:          getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:          getEnv("S3_BUCKET", ""),
+			S3AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+			S3SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+			S3PathStyle:       getEnv("S3_PATH_STYLE", "true") == "true",
+		},
 		Log: LogConfig{
 			Level:  getEnv("LOG_LEVEL", "info"),
 			Format: getEnv("LOG_FORMAT", "json"),
@@ -126,6 +256,22 @@ func (c *Config) Validate() error {
 	if c.Database.Password == "" {
 		return fmt.Errorf("DB_PASSWORD is required")
 	}
+	if c.Realtime.Broker != "memory" && c.Realtime.Broker != "postgres" {
+		return fmt.Errorf("REALTIME_BROKER must be memory or postgres")
+	}
+	if c.Mail.Backend != "smtp" && c.Mail.Backend != "capture" {
+		return fmt.Errorf("MAIL_BACKEND must be smtp or capture")
+	}
+	if (c.Push.VAPIDPublicKey == "") != (c.Push.VAPIDPrivateKey == "") {
+		return fmt.Errorf("VAPID_PUBLIC_KEY and VAPID_PRIVATE_KEY must be set together")
+	}
+	if c.Storage.Driver != "local" && c.Storage.Driver != "s3" {
+		return fmt.Errorf("STORAGE_DRIVER must be local or s3")
+	}
+	if c.Storage.Driver == "s3" && (c.Storage.S3Endpoint == "" || c.Storage.S3Bucket == "" ||
+		c.Storage.S3AccessKeyID == "" || c.Storage.S3SecretAccessKey == "") {
+		return fmt.Errorf("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required for the s3 storage driver")
+	}
 	return nil
 }
 
@@ -166,6 +312,38 @@ func (c *Config) GetRefreshTokenExpiration() time.Duration {
 	return time.Duration(c.JWT.RefreshExpirationHours) * time.Hour
 }
 
+// GetWaitlistOfferWindow returns how long a waitlisted user has to accept a seat offer
+func (c *Config) GetWaitlistOfferWindow() time.Duration {
+	return time.Duration(c.Trip.WaitlistOfferHours) * time.Hour
+}
+
+// GetInviteSigningSecret returns the key used to sign trip invite links,
+// falling back to the JWT secret when no dedicated key is configured
+func (c *Config) GetInviteSigningSecret() string {
+	if c.Trip.InviteSigningSecret != "" {
+		return c.Trip.InviteSigningSecret
+	}
+	return c.JWT.Secret
+}
+
+// GetReportSigningSecret returns the key used to sign expense report links,
+// falling back to the JWT secret when no dedicated key is configured
+func (c *Config) GetReportSigningSecret() string {
+	if c.Expense.ReportSigningSecret != "" {
+		return c.Expense.ReportSigningSecret
+	}
+	return c.JWT.Secret
+}
+
+// GetFileSigningSecret returns the key used to sign links to locally stored
+// files, falling back to the JWT secret when no dedicated key is configured
+func (c *Config) GetFileSigningSecret() string {
+	if c.Storage.LinkSigningSecret != "" {
+		return c.Storage.LinkSigningSecret
+	}
+	return c.JWT.Secret
+}
+
 // getEnv gets an environment variable with a default value
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -183,3 +361,14 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvAsList gets a comma-separated environment variable as a list of trimmed values
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,50 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"jointrip/internal/domain/event"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Bus is an in-process event bus that delivers events synchronously to their
+// subscribers. A failing subscriber is logged and does not affect the
+// publisher or the other subscribers.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]event.Handler
+	logger   *logrus.Logger
+}
+
+// NewBus creates a new event bus
+func NewBus(logger *logrus.Logger) *Bus {
+	return &Bus{
+		handlers: make(map[string][]event.Handler),
+		logger:   logger,
+	}
+}
+
+// Subscribe registers a handler for the named event
+func (b *Bus) Subscribe(name string, handler event.Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// Publish delivers events to their subscribers in registration order
+func (b *Bus) Publish(ctx context.Context, events ...event.Event) {
+	for _, e := range events {
+		b.mu.RLock()
+		handlers := b.handlers[e.EventName()]
+		b.mu.RUnlock()
+
+		for _, handler := range handlers {
+			if err := handler(ctx, e); err != nil {
+				b.logger.WithError(err).WithField("event", e.EventName()).Error("Event handler failed")
+			}
+		}
+	}
+}
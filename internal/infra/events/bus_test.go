@@ -0,0 +1,41 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"jointrip/internal/domain/event"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type testEvent struct{ name string }
+
+func (e testEvent) EventName() string { return e.name }
+
+func TestBus_Publish(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	bus := NewBus(logger)
+
+	var calls []string
+	bus.Subscribe("a", func(ctx context.Context, e event.Event) error {
+		calls = append(calls, "first")
+		return errors.New("boom")
+	})
+	bus.Subscribe("a", func(ctx context.Context, e event.Event) error {
+		calls = append(calls, "second")
+		return nil
+	})
+	bus.Subscribe("b", func(ctx context.Context, e event.Event) error {
+		calls = append(calls, "other")
+		return nil
+	})
+
+	bus.Publish(context.Background(), testEvent{name: "a"})
+
+	assert.Equal(t, []string{"first", "second"}, calls)
+}
@@ -0,0 +1,141 @@
+package exchangerate
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"jointrip/internal/domain/currency"
+)
+
+// ECB reference rates are quoted against the euro
+const ecbBase = "EUR"
+
+// ecbDateLayouts are the date formats of the daily and historical ECB files
+var ecbDateLayouts = []string{"2006-01-02", "2 January 2006"}
+
+// ecbEnvelope is the layout of the ECB eurofxref XML files. The daily file
+// has one dated cube, the historical ones many.
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ParseXML reads euro reference rates from an ECB-style XML file. Currencies
+// that are not circulating ISO 4217 currencies are skipped.
+func ParseXML(r io.Reader, source string) ([]currency.Rate, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode rates: %w", err)
+	}
+
+	var rates []currency.Rate
+	for _, day := range envelope.Days {
+		asOf, err := parseECBDate(day.Time)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range day.Rates {
+			rate, ok, err := newECBRate(entry.Currency, entry.Rate, asOf, source)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				rates = append(rates, rate)
+			}
+		}
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.New("no exchange rates found")
+	}
+	return rates, nil
+}
+
+// ParseCSV reads euro reference rates from an ECB-style CSV file: a header of
+// "Date" and currency codes, then one row of rates per day. Missing rates
+// (N/A or empty) and unknown currencies are skipped.
+func ParseCSV(r io.Reader, source string) ([]currency.Rate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rates header: %w", err)
+	}
+	if len(header) == 0 || !strings.EqualFold(strings.TrimSpace(header[0]), "date") {
+		return nil, errors.New("rates file must start with a Date column")
+	}
+
+	var rates []currency.Rate
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rates: %w", err)
+		}
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		asOf, err := parseECBDate(record[0])
+		if err != nil {
+			return nil, err
+		}
+		for i := 1; i < len(record) && i < len(header); i++ {
+			value := strings.TrimSpace(record[i])
+			if value == "" || strings.EqualFold(value, "N/A") {
+				continue
+			}
+			rate, ok, err := newECBRate(header[i], value, asOf, source)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				rates = append(rates, rate)
+			}
+		}
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.New("no exchange rates found")
+	}
+	return rates, nil
+}
+
+// newECBRate creates a euro rate, reporting false for unknown currencies
+func newECBRate(code, value string, asOf time.Time, source string) (currency.Rate, bool, error) {
+	code = strings.TrimSpace(code)
+	if _, err := currency.Lookup(code); err != nil {
+		return currency.Rate{}, false, nil
+	}
+
+	rate, err := currency.NewRate(ecbBase, code, value, asOf, source)
+	if err != nil {
+		return currency.Rate{}, false, fmt.Errorf("invalid %s rate %q on %s: %w", code, value, asOf.Format("2006-01-02"), err)
+	}
+	return rate, true, nil
+}
+
+// parseECBDate parses a date in any of the ECB file formats
+func parseECBDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range ecbDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid rates date %q", value)
+}
@@ -0,0 +1,99 @@
+package exchangerate
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testXML = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender>
+		<gesmes:name>European Central Bank</gesmes:name>
+	</gesmes:Sender>
+	<Cube>
+		<Cube time="2026-10-16">
+			<Cube currency="USD" rate="1.0823"/>
+			<Cube currency="JPY" rate="162.41"/>
+			<Cube currency="XXX" rate="1.5"/>
+		</Cube>
+		<Cube time="2026-10-15">
+			<Cube currency="USD" rate="1.0791"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func TestParseXML(t *testing.T) {
+	rates, err := ParseXML(strings.NewReader(testXML), "eurofxref")
+	require.NoError(t, err)
+	require.Len(t, rates, 3)
+
+	assert.Equal(t, "EUR", rates[0].Base)
+	assert.Equal(t, "USD", rates[0].Quote)
+	assert.Equal(t, "1.0823", rates[0].Value)
+	assert.Equal(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), rates[0].AsOf)
+	assert.Equal(t, "eurofxref", rates[0].Source)
+
+	assert.Equal(t, "JPY", rates[1].Quote)
+	assert.Equal(t, time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), rates[2].AsOf)
+}
+
+func TestParseXML_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not xml", "Date, USD"},
+		{"no rates", `<Envelope><Cube></Cube></Envelope>`},
+		{"bad date", `<Envelope><Cube><Cube time="yesterday"><Cube currency="USD" rate="1.1"/></Cube></Cube></Envelope>`},
+		{"bad rate", `<Envelope><Cube><Cube time="2026-10-16"><Cube currency="USD" rate="-1"/></Cube></Cube></Envelope>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseXML(strings.NewReader(tt.body), "test")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	body := "Date, USD, JPY, CYP, XXX, \n" +
+		"16 October 2026, 1.0823, 162.41, N/A, 2, \n" +
+		"2026-10-15, 1.0791, , N/A, 2, \n"
+
+	rates, err := ParseCSV(strings.NewReader(body), "eurofxref-hist")
+	require.NoError(t, err)
+	require.Len(t, rates, 3)
+
+	assert.Equal(t, "USD", rates[0].Quote)
+	assert.Equal(t, "1.0823", rates[0].Value)
+	assert.Equal(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), rates[0].AsOf)
+	assert.Equal(t, "JPY", rates[1].Quote)
+	assert.Equal(t, "1.0791", rates[2].Value)
+	assert.Equal(t, time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), rates[2].AsOf)
+}
+
+func TestParseCSV_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty", ""},
+		{"no date column", "USD, JPY\n1.1, 160\n"},
+		{"no rates", "Date, USD\n"},
+		{"bad date", "Date, USD\nyesterday, 1.1\n"},
+		{"bad rate", "Date, USD\n2026-10-16, abc\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCSV(strings.NewReader(tt.body), "test")
+			assert.Error(t, err)
+		})
+	}
+}
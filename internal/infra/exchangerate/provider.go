@@ -0,0 +1,92 @@
+package exchangerate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"jointrip/internal/domain/currency"
+)
+
+// Provider looks up exchange rates in the rates table. Pairs that are not
+// stored directly are inverted or crossed through the pivot currency the
+// reference rates are quoted against.
+type Provider struct {
+	repo  currency.RateRepository
+	pivot string
+}
+
+// NewProvider creates a new rate provider
+func NewProvider(repo currency.RateRepository, pivot string) *Provider {
+	return &Provider{
+		repo:  repo,
+		pivot: pivot,
+	}
+}
+
+// Rate returns the rate from base to quote published closest to the given day
+func (p *Provider) Rate(ctx context.Context, base, quote string, on time.Time) (currency.Rate, error) {
+	if base == quote {
+		return currency.Identity(base, on), nil
+	}
+
+	rate, err := p.repo.GetClosest(ctx, base, quote, on)
+	if err == nil || !errors.Is(err, currency.ErrRateNotFound) {
+		return rate, err
+	}
+
+	inverse, err := p.repo.GetClosest(ctx, quote, base, on)
+	if err == nil {
+		return inverse.Invert()
+	}
+	if !errors.Is(err, currency.ErrRateNotFound) {
+		return currency.Rate{}, err
+	}
+
+	if base == p.pivot || quote == p.pivot {
+		return currency.Rate{}, currency.ErrRateNotFound
+	}
+	toBase, err := p.repo.GetClosest(ctx, p.pivot, base, on)
+	if err != nil {
+		return currency.Rate{}, err
+	}
+	toQuote, err := p.repo.GetClosest(ctx, p.pivot, quote, on)
+	if err != nil {
+		return currency.Rate{}, err
+	}
+	return currency.Cross(toBase, toQuote)
+}
+
+// LoadFile reads a reference rates file, XML or CSV by its extension, into
+// the rates table and returns how many rates it stored
+func LoadFile(ctx context.Context, repo currency.RateRepository, path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open rates file: %w", err)
+	}
+	defer file.Close()
+
+	source := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	var rates []currency.Rate
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xml":
+		rates, err = ParseXML(file, source)
+	case ".csv":
+		rates, err = ParseCSV(file, source)
+	default:
+		return 0, fmt.Errorf("unsupported rates file %q, expected .xml or .csv", path)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := repo.SaveRates(ctx, rates); err != nil {
+		return 0, err
+	}
+	return len(rates), nil
+}
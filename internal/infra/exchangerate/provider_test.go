@@ -0,0 +1,112 @@
+package exchangerate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"jointrip/internal/domain/currency"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRates is an in-memory rate repository returning the latest rate on
+// or before the requested day
+type memoryRates struct {
+	rates []currency.Rate
+}
+
+func (m *memoryRates) SaveRates(ctx context.Context, rates []currency.Rate) error {
+	m.rates = append(m.rates, rates...)
+	return nil
+}
+
+func (m *memoryRates) GetClosest(ctx context.Context, base, quote string, on time.Time) (currency.Rate, error) {
+	var found *currency.Rate
+	for i, rate := range m.rates {
+		if rate.Base != base || rate.Quote != quote || rate.AsOf.After(on) {
+			continue
+		}
+		if found == nil || rate.AsOf.After(found.AsOf) {
+			found = &m.rates[i]
+		}
+	}
+	if found == nil {
+		return currency.Rate{}, currency.ErrRateNotFound
+	}
+	return *found, nil
+}
+
+func testRate(t *testing.T, base, quote, value string, asOf time.Time) currency.Rate {
+	rate, err := currency.NewRate(base, quote, value, asOf, "test")
+	require.NoError(t, err)
+	return rate
+}
+
+func TestProvider_Rate(t *testing.T) {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	repo := &memoryRates{rates: []currency.Rate{
+		testRate(t, "EUR", "USD", "1.25", day),
+		testRate(t, "EUR", "GBP", "0.8", day.AddDate(0, 0, -1)),
+	}}
+	provider := NewProvider(repo, "EUR")
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		base     string
+		quote    string
+		expected string
+		asOf     time.Time
+	}{
+		{"identity", "JPY", "JPY", "1", day},
+		{"direct", "EUR", "USD", "1.25", day},
+		{"inverse", "USD", "EUR", "0.8", day},
+		{"cross", "USD", "GBP", "0.64", day.AddDate(0, 0, -1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := provider.Rate(ctx, tt.base, tt.quote, day)
+			require.NoError(t, err)
+			assert.Equal(t, tt.base, rate.Base)
+			assert.Equal(t, tt.quote, rate.Quote)
+			assert.Equal(t, tt.expected, rate.Value)
+			assert.Equal(t, tt.asOf, rate.AsOf)
+		})
+	}
+
+	_, err := provider.Rate(ctx, "USD", "JPY", day)
+	assert.ErrorIs(t, err, currency.ErrRateNotFound)
+	_, err = provider.Rate(ctx, "EUR", "JPY", day)
+	assert.ErrorIs(t, err, currency.ErrRateNotFound)
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	csvPath := filepath.Join(dir, "eurofxref.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("Date, USD, \n2026-10-16, 1.0823, \n"), 0o600))
+
+	repo := &memoryRates{}
+	count, err := LoadFile(ctx, repo, csvPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	require.Len(t, repo.rates, 1)
+	assert.Equal(t, "eurofxref", repo.rates[0].Source)
+
+	xmlPath := filepath.Join(dir, "daily.xml")
+	require.NoError(t, os.WriteFile(xmlPath, []byte(testXML), 0o600))
+	count, err = LoadFile(ctx, repo, xmlPath)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	_, err = LoadFile(ctx, repo, filepath.Join(dir, "rates.json"))
+	assert.Error(t, err)
+	_, err = LoadFile(ctx, repo, filepath.Join(dir, "missing.csv"))
+	assert.Error(t, err)
+}
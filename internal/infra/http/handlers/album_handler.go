@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	appAlbum "jointrip/internal/app/album"
+	"jointrip/internal/domain/album"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/infra/archive"
+	"jointrip/internal/infra/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// archiveWriteTimeout is how long a client reading an album archive may
+// stall before the download is dropped
+const archiveWriteTimeout = 30 * time.Second
+
+// AlbumHandler handles trip photo album HTTP requests
+type AlbumHandler struct {
+	albumService *appAlbum.Service
+	logger       *logrus.Logger
+}
+
+// NewAlbumHandler creates a new album handler
+func NewAlbumHandler(albumService *appAlbum.Service, logger *logrus.Logger) *AlbumHandler {
+	return &AlbumHandler{
+		albumService: albumService,
+		logger:       logger,
+	}
+}
+
+// UpdatePhotoRequest represents a request to change a photo's caption or
+// visibility. Omitted fields are left unchanged.
+type UpdatePhotoRequest struct {
+	Caption    *string           `json:"caption"`
+	Visibility *album.Visibility `json:"visibility"`
+}
+
+// ReorderPhotosRequest represents a request to place every photo of an album
+// in a new order
+type ReorderPhotosRequest struct {
+	PhotoIDs []uuid.UUID `json:"photo_ids" binding:"required"`
+}
+
+// GetAlbum returns the photos of a trip's album the current user can see
+func (h *AlbumHandler) GetAlbum(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	a, err := h.albumService.GetAlbum(c.Request.Context(), tripID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get album")
+		return
+	}
+
+	c.JSON(http.StatusOK, a)
+}
+
+// UploadPhoto adds a photo, sent as the "file" field of a multipart form, to
+// a trip's album. The form can also carry its "caption" and "visibility".
+func (h *AlbumHandler) UploadPhoto(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	// Leave room for the multipart framing and the other fields
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, album.MaxPhotoSize+1<<20)
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.respondError(c, album.ErrPhotoTooLarge, "Failed to upload photo")
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "No photo file provided",
+		})
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, album.MaxPhotoSize+1))
+	if err != nil {
+		h.respondError(c, err, "Failed to upload photo")
+		return
+	}
+
+	photo, err := h.albumService.UploadPhoto(c.Request.Context(), tripID, userID,
+		c.PostForm("caption"), album.Visibility(c.PostForm("visibility")), content)
+	if err != nil {
+		h.respondError(c, err, "Failed to upload photo")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"photo": photo,
+	})
+}
+
+// UpdatePhoto changes a photo's caption or visibility
+func (h *AlbumHandler) UpdatePhoto(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, photoID, ok := parsePhotoParams(c)
+	if !ok {
+		return
+	}
+
+	var req UpdatePhotoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	photo, err := h.albumService.UpdatePhoto(c.Request.Context(), tripID, photoID, userID, req.Caption, req.Visibility)
+	if err != nil {
+		h.respondError(c, err, "Failed to update photo")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"photo": photo,
+	})
+}
+
+// ReorderPhotos places the photos of a trip's album in a new order
+func (h *AlbumHandler) ReorderPhotos(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var req ReorderPhotosRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	a, err := h.albumService.ReorderPhotos(c.Request.Context(), tripID, userID, req.PhotoIDs)
+	if err != nil {
+		h.respondError(c, err, "Failed to reorder photos")
+		return
+	}
+
+	c.JSON(http.StatusOK, a)
+}
+
+// SetCover makes a photo the cover of its trip's album
+func (h *AlbumHandler) SetCover(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, photoID, ok := parsePhotoParams(c)
+	if !ok {
+		return
+	}
+
+	a, err := h.albumService.SetCover(c.Request.Context(), tripID, photoID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to set album cover")
+		return
+	}
+
+	c.JSON(http.StatusOK, a)
+}
+
+// DeletePhoto removes a photo from a trip's album
+func (h *AlbumHandler) DeletePhoto(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, photoID, ok := parsePhotoParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.albumService.DeletePhoto(c.Request.Context(), tripID, photoID, userID); err != nil {
+		h.respondError(c, err, "Failed to delete photo")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Photo deleted",
+	})
+}
+
+// DownloadAlbum streams the photos of a trip's album the current user can
+// see as a zip file. Once the archive has started, errors can only cut it
+// short, which clients notice as a broken download.
+func (h *AlbumHandler) DownloadAlbum(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	a, err := h.albumService.GetArchive(c.Request.Context(), tripID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to download album")
+		return
+	}
+
+	// The server's WriteTimeout would cut a large album off; give each
+	// write its own deadline instead
+	w, err := newStreamWriter(c.Writer, archiveWriteTimeout)
+	if err != nil {
+		h.respondError(c, err, "Failed to download album")
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Content-Type", archive.ContentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": archive.Filename(a.TripTitle),
+	}))
+	c.Status(http.StatusOK)
+
+	if err := archive.WriteZip(c.Request.Context(), w, a.Photos, a); err != nil {
+		h.logger.WithError(err).Error("Failed to write album archive")
+		c.Abort()
+	}
+}
+
+// parsePhotoParams parses the trip and photo IDs from the path
+func parsePhotoParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	photoID, ok := parseUUIDParam(c, "photo_id", "Invalid photo ID")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tripID, photoID, true
+}
+
+// respondError maps album and trip domain errors to HTTP responses
+func (h *AlbumHandler) respondError(c *gin.Context, err error, logMessage string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, album.ErrPhotoNotFound),
+		errors.Is(err, trip.ErrTripNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, album.ErrNotTripMember),
+		errors.Is(err, album.ErrNotPhotoOwner),
+		errors.Is(err, album.ErrNotAlbumOrganizer):
+		status = http.StatusForbidden
+	case errors.Is(err, album.ErrAlbumFull):
+		status = http.StatusConflict
+	case errors.Is(err, album.ErrPhotoTooLarge):
+		status = http.StatusRequestEntityTooLarge
+	case errors.Is(err, album.ErrInvalidPhoto),
+		errors.Is(err, album.ErrInvalidCaption),
+		errors.Is(err, album.ErrInvalidVisibility),
+		errors.Is(err, album.ErrInvalidOrder):
+		status = http.StatusUnprocessableEntity
+	}
+
+	if status == http.StatusInternalServerError {
+		h.logger.WithError(err).Error(logMessage)
+		c.JSON(status, gin.H{
+			"error": logMessage,
+		})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
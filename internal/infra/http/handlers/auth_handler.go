@@ -1,14 +1,16 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"path/filepath"
-	"strings"
+	"strconv"
 
 	"jointrip/internal/app/auth"
+	appNotification "jointrip/internal/app/notification"
+	"jointrip/internal/domain/currency"
+	"jointrip/internal/domain/notification"
 	"jointrip/internal/domain/user"
 	"jointrip/internal/infra/http/middleware"
 
@@ -19,15 +21,17 @@ import (
 
 // AuthHandler handles authentication-related HTTP requests
 type AuthHandler struct {
-	authService *auth.Service
-	logger      *logrus.Logger
+	authService         *auth.Service
+	notificationService *appNotification.Service
+	logger              *logrus.Logger
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService *auth.Service, logger *logrus.Logger) *AuthHandler {
+func NewAuthHandler(authService *auth.Service, notificationService *appNotification.Service, logger *logrus.Logger) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		logger:      logger,
+		authService:         authService,
+		notificationService: notificationService,
+		logger:              logger,
 	}
 }
 
@@ -42,7 +46,9 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
-// UpdateProfileRequest represents a profile update request
+// UpdateProfileRequest represents a profile update request. The notification
+// switches turn a channel on or off for every notification type; finer choices
+// are made through the notification preferences.
 type UpdateProfileRequest struct {
 	FirstName          *string  `json:"first_name,omitempty"`
 	LastName           *string  `json:"last_name,omitempty"`
@@ -54,10 +60,29 @@ type UpdateProfileRequest struct {
 	Interests          []string `json:"interests,omitempty"`
 	TravelStyle        *string  `json:"travel_style,omitempty"`
 	ProfileVisibility  *string  `json:"profile_visibility,omitempty"`
+	PreferredCurrency  *string  `json:"preferred_currency,omitempty"`
 	EmailNotifications *bool    `json:"email_notifications,omitempty"`
 	PushNotifications  *bool    `json:"push_notifications,omitempty"`
 }
 
+// profileResponse is a user profile with the account-wide notification
+// switches, derived from the notification preferences
+type profileResponse struct {
+	*user.User
+	EmailNotifications bool `json:"email_notifications"`
+	PushNotifications  bool `json:"push_notifications"`
+}
+
+// newProfileResponse reports a channel as on while any notification type uses it
+func newProfileResponse(u *user.User, prefs *notification.Preferences) profileResponse {
+	return profileResponse{
+		User: u,
+		EmailNotifications: prefs.ChannelEnabled(notification.ChannelEmail) ||
+			prefs.ChannelEnabled(notification.ChannelDigest),
+		PushNotifications: prefs.ChannelEnabled(notification.ChannelPush),
+	}
+}
+
 // GetGoogleAuthURL returns the Google OAuth authorization URL
 func (h *AuthHandler) GetGoogleAuthURL(c *gin.Context) {
 	state := c.Query("state")
@@ -97,10 +122,19 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	prefs, err := h.notificationService.GetPreferences(c.Request.Context(), response.User.ID)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get notification preferences")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get profile",
+		})
+		return
+	}
+
 	h.logger.WithField("user_id", response.User.ID).Info("User logged in successfully")
 
 	c.JSON(http.StatusOK, gin.H{
-		"user":         response.User,
+		"user":         newProfileResponse(response.User, prefs),
 		"accessToken":  response.AccessToken,
 		"refreshToken": response.RefreshToken,
 		"expiresAt":    response.ExpiresAt,
@@ -206,8 +240,17 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
+	prefs, err := h.notificationService.GetPreferences(c.Request.Context(), user.ID)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get notification preferences")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get profile",
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"user": user,
+		"user": newProfileResponse(user, prefs),
 	})
 }
 
@@ -290,13 +333,30 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		profileData["profile_visibility"] = *req.ProfileVisibility
 		currentUser.ProfileVisibility = user.PrivacyLevel(*req.ProfileVisibility)
 	}
+	if req.PreferredCurrency != nil {
+		// An empty currency clears the preference
+		code := ""
+		if *req.PreferredCurrency != "" {
+			code, err = currency.Normalize(*req.PreferredCurrency)
+			if err != nil {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+		}
+		profileData["preferred_currency"] = code
+		currentUser.PreferredCurrency = code
+	}
+
+	// Email covers digests too, as it did before notifications had channels
+	channels := make(map[notification.Channel]bool)
 	if req.EmailNotifications != nil {
-		profileData["email_notifications"] = *req.EmailNotifications
-		currentUser.EmailNotifications = *req.EmailNotifications
+		channels[notification.ChannelEmail] = *req.EmailNotifications
+		channels[notification.ChannelDigest] = *req.EmailNotifications
 	}
 	if req.PushNotifications != nil {
-		profileData["push_notifications"] = *req.PushNotifications
-		currentUser.PushNotifications = *req.PushNotifications
+		channels[notification.ChannelPush] = *req.PushNotifications
 	}
 
 	// Update the user profile in the database using the new method
@@ -309,11 +369,25 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
+	var prefs *notification.Preferences
+	if len(channels) > 0 {
+		prefs, err = h.notificationService.SetChannelEnabled(c.Request.Context(), currentUser.ID, channels)
+	} else {
+		prefs, err = h.notificationService.GetPreferences(c.Request.Context(), currentUser.ID)
+	}
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to update notification preferences")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update profile",
+		})
+		return
+	}
+
 	h.logger.WithField("user_id", currentUser.ID).Info("User profile updated successfully")
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Profile updated successfully",
-		"user":    currentUser,
+		"user":    newProfileResponse(currentUser, prefs),
 	})
 }
 
@@ -346,78 +420,99 @@ func (h *AuthHandler) UploadProfilePhoto(c *gin.Context) {
 	}
 	defer file.Close()
 
-	// Validate file type
-	contentType := header.Header.Get("Content-Type")
-	if !strings.HasPrefix(contentType, "image/") {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "File must be an image",
+	// Validate file size (5MB max); the type is checked by its content
+	if header.Size > user.MaxProfilePhotoSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": user.ErrProfilePhotoTooLarge.Error(),
 		})
 		return
 	}
 
-	// Validate file size (5MB max)
-	if header.Size > 5<<20 {
+	content, err := io.ReadAll(io.LimitReader(file, user.MaxProfilePhotoSize+1))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to read uploaded photo")
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "File size must be less than 5MB",
+			"error": "Failed to read photo",
 		})
 		return
 	}
 
-	// Create uploads directory if it doesn't exist
-	uploadsDir := "uploads/profile_photos"
-	if err := os.MkdirAll(uploadsDir, 0755); err != nil {
-		h.logger.WithError(err).Error("Failed to create uploads directory")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to save photo",
-		})
+	// Re-encode and store the photo and save its URLs
+	err = h.authService.UploadProfilePhoto(c.Request.Context(), currentUser, content)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrProfilePhotoTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": err.Error(),
+			})
+		case errors.Is(err, user.ErrInvalidProfilePhoto):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			h.logger.WithError(err).Error("Failed to update user profile photo")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to update profile photo",
+			})
+		}
 		return
 	}
 
-	// Generate unique filename
-	ext := filepath.Ext(header.Filename)
-	filename := fmt.Sprintf("%s%s", currentUser.ID.String(), ext)
-	filePath := filepath.Join(uploadsDir, filename)
-
-	// Create destination file
-	dst, err := os.Create(filePath)
+	prefs, err := h.notificationService.GetPreferences(c.Request.Context(), currentUser.ID)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to create destination file")
+		h.logger.WithError(err).Error("Failed to get notification preferences")
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to save photo",
+			"error": "Failed to get profile",
 		})
 		return
 	}
-	defer dst.Close()
 
-	// Copy uploaded file to destination
-	_, err = io.Copy(dst, file)
-	if err != nil {
-		h.logger.WithError(err).Error("Failed to copy file")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to save photo",
-		})
+	h.logger.WithField("user_id", currentUser.ID).Info("Profile photo updated successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Profile photo updated successfully",
+		"photo_url": currentUser.ProfilePhotoURL,
+		"user":      newProfileResponse(currentUser, prefs),
+	})
+}
+
+// GetProfilePhoto redirects to a short-lived link to a user's uploaded
+// profile photo, in the size given by the size query parameter. It needs
+// no authentication, so that image tags can load it.
+func (h *AuthHandler) GetProfilePhoto(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "user_id", "Invalid user ID")
+	if !ok {
 		return
 	}
 
-	// Update user's profile photo URL
-	photoURL := fmt.Sprintf("/uploads/profile_photos/%s", filename)
-	currentUser.ProfilePhotoURL = photoURL
+	size := user.DefaultProfilePhotoSize
+	if value := c.Query("size"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid photo size",
+			})
+			return
+		}
+		size = parsed
+	}
 
-	// Save to database
-	err = h.authService.UpdateUser(c.Request.Context(), currentUser)
+	link, err := h.authService.ProfilePhotoLink(c.Request.Context(), userID, size)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to update user profile photo")
+		if errors.Is(err, user.ErrUserNotFound) || errors.Is(err, user.ErrNoProfilePhoto) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Profile photo not found",
+			})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to get profile photo link")
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update profile photo",
+			"error": "Failed to get profile photo",
 		})
 		return
 	}
 
-	h.logger.WithField("user_id", currentUser.ID).Info("Profile photo updated successfully")
-
-	c.JSON(http.StatusOK, gin.H{
-		"message":   "Profile photo updated successfully",
-		"photo_url": photoURL,
-		"user":      currentUser,
-	})
+	// Browsers may reuse the redirect for a while, but not beyond the link
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int((auth.ProfilePhotoLinkTTL/2).Seconds())))
+	c.Redirect(http.StatusFound, link)
 }
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	appCalendar "jointrip/internal/app/calendar"
+	"jointrip/internal/domain/calendar"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/infra/http/middleware"
+	"jointrip/internal/infra/ical"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	calendarProdID = "-//JoinTrip//Trips//EN"
+	calendarDomain = "jointrip"
+
+	// feedPath is the public route serving calendar feeds, relative to the API root
+	feedPath = "/api/v1/calendar/feeds/"
+)
+
+// CalendarHandler handles iCalendar export HTTP requests
+type CalendarHandler struct {
+	calendarService *appCalendar.Service
+	logger          *logrus.Logger
+}
+
+// NewCalendarHandler creates a new calendar handler
+func NewCalendarHandler(calendarService *appCalendar.Service, logger *logrus.Logger) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+		logger:          logger,
+	}
+}
+
+// ExportTrip returns a single trip and its itinerary as an .ics file
+func (h *CalendarHandler) ExportTrip(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	schedule, err := h.calendarService.ExportTrip(c.Request.Context(), tripID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to export trip")
+		return
+	}
+
+	cal := buildCalendar(schedule.Trip.Title, []*appCalendar.TripSchedule{schedule})
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="trip-%s.ics"`, tripID))
+	h.writeCalendar(c, cal)
+}
+
+// GetFeed serves a user's calendar feed. It is authenticated by the secret
+// token in the URL because calendar clients cannot send Authorization headers.
+func (h *CalendarHandler) GetFeed(c *gin.Context) {
+	secret := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	schedules, err := h.calendarService.GetFeed(c.Request.Context(), secret)
+	if err != nil {
+		h.respondError(c, err, "Failed to load calendar feed")
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-cache")
+	h.writeCalendar(c, buildCalendar("JoinTrip", schedules))
+}
+
+// GetFeedToken returns the current user's feed token details (the secret is never shown again)
+func (h *CalendarHandler) GetFeedToken(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	token, err := h.calendarService.GetFeedToken(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get calendar feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"feed": token,
+	})
+}
+
+// RotateFeedToken creates a new feed URL for the current user, revoking the previous one
+func (h *CalendarHandler) RotateFeedToken(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	token, secret, err := h.calendarService.RotateFeedToken(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to create calendar feed")
+		return
+	}
+
+	h.logger.WithField("user_id", userID).Info("Calendar feed token rotated")
+
+	host := c.Request.Host
+	c.JSON(http.StatusCreated, gin.H{
+		"feed":       token,
+		"feed_url":   requestScheme(c) + "://" + host + feedPath + secret + ".ics",
+		"webcal_url": "webcal://" + host + feedPath + secret + ".ics",
+	})
+}
+
+// RevokeFeedToken disables the current user's feed URL
+func (h *CalendarHandler) RevokeFeedToken(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	if err := h.calendarService.RevokeFeedToken(c.Request.Context(), userID); err != nil {
+		h.respondError(c, err, "Failed to revoke calendar feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Calendar feed revoked",
+	})
+}
+
+// writeCalendar encodes a calendar into the response
+func (h *CalendarHandler) writeCalendar(c *gin.Context, cal *ical.Calendar) {
+	c.Header("Content-Type", ical.ContentType)
+	c.Status(http.StatusOK)
+	if err := cal.Encode(c.Writer); err != nil {
+		h.logger.WithError(err).Error("Failed to write calendar")
+	}
+}
+
+// respondError maps calendar and trip domain errors to HTTP responses
+func (h *CalendarHandler) respondError(c *gin.Context, err error, logMessage string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, trip.ErrTripNotFound),
+		errors.Is(err, calendar.ErrFeedTokenNotFound):
+		status = http.StatusNotFound
+	}
+
+	if status == http.StatusInternalServerError {
+		h.logger.WithError(err).Error(logMessage)
+		c.JSON(status, gin.H{
+			"error": logMessage,
+		})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
+
+// buildCalendar turns trip schedules into calendar events: one all-day event
+// spanning each trip and one event per itinerary item
+func buildCalendar(name string, schedules []*appCalendar.TripSchedule) *ical.Calendar {
+	now := time.Now()
+	cal := &ical.Calendar{
+		ProdID: calendarProdID,
+		Name:   name,
+	}
+
+	for _, schedule := range schedules {
+		t := schedule.Trip
+
+		status := ical.StatusConfirmed
+		if t.Status == trip.StatusCanceled {
+			status = ical.StatusCancelled
+		}
+
+		location := t.DestinationCountry
+		if t.DestinationCity != "" {
+			location = t.DestinationCity + ", " + t.DestinationCountry
+		}
+
+		// The trip's end date is inclusive, DTEND is not
+		end := t.EndDate.AddDate(0, 0, 1)
+		cal.Events = append(cal.Events, ical.Event{
+			UID:          fmt.Sprintf("trip-%s@%s", t.ID, calendarDomain),
+			Summary:      t.Title,
+			Description:  t.Description,
+			Location:     location,
+			Status:       status,
+			Start:        t.StartDate,
+			End:          &end,
+			AllDay:       true,
+			Stamp:        now,
+			LastModified: t.UpdatedAt,
+		})
+
+		for _, item := range schedule.Items {
+			cal.Events = append(cal.Events, ical.Event{
+				UID:          fmt.Sprintf("itinerary-%s@%s", item.ID, calendarDomain),
+				Summary:      item.Title,
+				Description:  item.Description,
+				Location:     item.Location,
+				Status:       status,
+				Start:        item.StartsAt,
+				End:          item.EndsAt,
+				Stamp:        now,
+				LastModified: item.UpdatedAt,
+			})
+		}
+	}
+
+	return cal
+}
+
+// requestScheme returns the scheme the client used, honouring reverse proxies
+func requestScheme(c *gin.Context) string {
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		return proto
+	}
+	if c.Request.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
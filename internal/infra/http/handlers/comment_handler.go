@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	appComment "jointrip/internal/app/comment"
+	"jointrip/internal/domain/comment"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/infra/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// CommentHandler handles trip comment HTTP requests
+type CommentHandler struct {
+	commentService *appComment.Service
+	logger         *logrus.Logger
+}
+
+// NewCommentHandler creates a new comment handler
+func NewCommentHandler(commentService *appComment.Service, logger *logrus.Logger) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+		logger:         logger,
+	}
+}
+
+// CreateCommentRequest represents a request to comment on a trip or reply to a comment
+type CreateCommentRequest struct {
+	Content         string     `json:"content" binding:"required"`
+	ParentCommentID *uuid.UUID `json:"parent_comment_id"`
+}
+
+// EditCommentRequest represents a request to change a comment's content
+type EditCommentRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// ListComments returns a page of a trip's top-level comments, newest first
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	page, err := h.commentService.ListComments(c.Request.Context(), tripID, userID, c.Query("cursor"), limit)
+	if err != nil {
+		h.respondError(c, err, "Failed to list comments")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// ListReplies returns a page of the replies to a comment, oldest first
+func (h *CommentHandler) ListReplies(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, commentID, ok := parseCommentParams(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	page, err := h.commentService.ListReplies(c.Request.Context(), tripID, commentID, userID, c.Query("cursor"), limit)
+	if err != nil {
+		h.respondError(c, err, "Failed to list replies")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// ListRevisions returns the edit history of a comment
+func (h *CommentHandler) ListRevisions(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, commentID, ok := parseCommentParams(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.commentService.ListRevisions(c.Request.Context(), tripID, commentID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get comment history")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"revisions": revisions,
+	})
+}
+
+// CreateComment posts a comment or a reply on a trip
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	created, err := h.commentService.CreateComment(c.Request.Context(), tripID, userID, req.Content, req.ParentCommentID)
+	if err != nil {
+		h.respondError(c, err, "Failed to create comment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"comment": created,
+	})
+}
+
+// EditComment changes the content of the current user's comment
+func (h *CommentHandler) EditComment(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, commentID, ok := parseCommentParams(c)
+	if !ok {
+		return
+	}
+
+	var req EditCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	edited, err := h.commentService.EditComment(c.Request.Context(), tripID, commentID, userID, req.Content)
+	if err != nil {
+		h.respondError(c, err, "Failed to edit comment")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comment": edited,
+	})
+}
+
+// DeleteComment deletes the current user's comment
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, commentID, ok := parseCommentParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.commentService.DeleteComment(c.Request.Context(), tripID, commentID, userID); err != nil {
+		h.respondError(c, err, "Failed to delete comment")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Comment deleted",
+	})
+}
+
+// HideComment hides a comment from other users (organizer only)
+func (h *CommentHandler) HideComment(c *gin.Context) {
+	h.setHidden(c, true, "Failed to hide comment")
+}
+
+// UnhideComment shows a hidden comment again (organizer only)
+func (h *CommentHandler) UnhideComment(c *gin.Context) {
+	h.setHidden(c, false, "Failed to unhide comment")
+}
+
+// setHidden handles hiding and unhiding a comment
+func (h *CommentHandler) setHidden(c *gin.Context, hidden bool, logMessage string) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, commentID, ok := parseCommentParams(c)
+	if !ok {
+		return
+	}
+
+	moderated, err := h.commentService.SetCommentHidden(c.Request.Context(), tripID, commentID, userID, hidden)
+	if err != nil {
+		h.respondError(c, err, logMessage)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comment": moderated,
+	})
+}
+
+// parseCommentParams reads the trip and comment IDs from the path
+func parseCommentParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	commentID, ok := parseUUIDParam(c, "comment_id", "Invalid comment ID")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tripID, commentID, true
+}
+
+// respondError maps comment and trip domain errors to HTTP responses
+func (h *CommentHandler) respondError(c *gin.Context, err error, logMessage string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, comment.ErrInvalidCursor):
+		status = http.StatusBadRequest
+	case errors.Is(err, comment.ErrCommentNotFound),
+		errors.Is(err, trip.ErrTripNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, comment.ErrNotCommentAuthor),
+		errors.Is(err, trip.ErrNotOrganizer):
+		status = http.StatusForbidden
+	case errors.Is(err, comment.ErrCommentDeleted):
+		status = http.StatusConflict
+	case errors.Is(err, comment.ErrInvalidContent),
+		errors.Is(err, comment.ErrThreadTooDeep),
+		errors.Is(err, comment.ErrTooManyMentions):
+		status = http.StatusUnprocessableEntity
+	}
+
+	if status == http.StatusInternalServerError {
+		h.logger.WithError(err).Error(logMessage)
+		c.JSON(status, gin.H{
+			"error": logMessage,
+		})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,1071 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	appExpense "jointrip/internal/app/expense"
+	"jointrip/internal/domain/blob"
+	"jointrip/internal/domain/currency"
+	"jointrip/internal/domain/expense"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/infra/http/middleware"
+	"jointrip/internal/infra/report"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// expenseDateLayout is the format of expense dates in requests
+	expenseDateLayout = "2006-01-02"
+
+	// reportPath is the public route serving expense reports, relative to the API root
+	reportPath = "/api/v1/expense-reports/"
+)
+
+// ExpenseHandler handles shared expense HTTP requests
+type ExpenseHandler struct {
+	expenseService *appExpense.Service
+	logger         *logrus.Logger
+}
+
+// NewExpenseHandler creates a new expense handler
+func NewExpenseHandler(expenseService *appExpense.Service, logger *logrus.Logger) *ExpenseHandler {
+	return &ExpenseHandler{
+		expenseService: expenseService,
+		logger:         logger,
+	}
+}
+
+// ExpenseRequest represents an expense creation or update request. Amounts
+// are integer minor units (cents); split values are minor units for exact
+// splits, basis points for percentages and share counts for shares.
+type ExpenseRequest struct {
+	PayerID     *uuid.UUID        `json:"payer_id"`
+	Title       string            `json:"title" binding:"required"`
+	Amount      int64             `json:"amount" binding:"required"`
+	Currency    string            `json:"currency"`
+	Category    string            `json:"category"`
+	Date        string            `json:"date"`
+	Notes       string            `json:"notes"`
+	SplitMethod string            `json:"split_method"`
+	Split       []expense.Portion `json:"split"`
+}
+
+// SettleShareRequest represents a request to mark a share as paid back
+type SettleShareRequest struct {
+	Notes string `json:"notes"`
+}
+
+// SettlementRequest represents a payment that squared up two members
+type SettlementRequest struct {
+	FromUserID uuid.UUID `json:"from_user_id" binding:"required"`
+	ToUserID   uuid.UUID `json:"to_user_id" binding:"required"`
+	Currency   string    `json:"currency"`
+	Notes      string    `json:"notes"`
+}
+
+// RecurringExpenseRequest represents a recurring expense creation or update
+// request. Amount is the cost of one full period in minor units; dates are
+// the first and last nights charged.
+type RecurringExpenseRequest struct {
+	PayerID   *uuid.UUID `json:"payer_id"`
+	Title     string     `json:"title" binding:"required"`
+	Amount    int64      `json:"amount" binding:"required"`
+	Currency  string     `json:"currency"`
+	Category  string     `json:"category"`
+	Notes     string     `json:"notes"`
+	Frequency string     `json:"frequency"`
+	StartDate string     `json:"start_date"`
+	EndDate   string     `json:"end_date"`
+}
+
+// BudgetPlanRequest represents a budget plan replacing the current one.
+// Amounts are integer minor units of the trip's currency.
+type BudgetPlanRequest struct {
+	Lines []BudgetLineRequest `json:"lines"`
+}
+
+// BudgetLineRequest represents the planned amount of one category
+type BudgetLineRequest struct {
+	Category     string `json:"category" binding:"required"`
+	Amount       int64  `json:"amount" binding:"required"`
+	AlertPercent int    `json:"alert_percent"`
+}
+
+// ListExpenses returns a trip's expenses, optionally filtered by category and date range
+func (h *ExpenseHandler) ListExpenses(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	filter, ok := parseExpenseFilter(c)
+	if !ok {
+		return
+	}
+
+	expenses, err := h.expenseService.ListExpenses(c.Request.Context(), tripID, userID, filter)
+	if err != nil {
+		h.respondError(c, err, "Failed to list expenses")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"expenses": expenses,
+	})
+}
+
+// GetExpense returns an expense with its split
+func (h *ExpenseHandler) GetExpense(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, expenseID, ok := parseExpenseParams(c)
+	if !ok {
+		return
+	}
+
+	e, err := h.expenseService.GetExpense(c.Request.Context(), tripID, expenseID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get expense")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"expense": e,
+	})
+}
+
+// CreateExpense records an expense on a trip
+func (h *ExpenseHandler) CreateExpense(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	input, ok := bindExpenseRequest(c)
+	if !ok {
+		return
+	}
+
+	e, err := h.expenseService.CreateExpense(c.Request.Context(), tripID, userID, input)
+	if err != nil {
+		h.respondError(c, err, "Failed to create expense")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"expense_id": e.ID,
+		"trip_id":    tripID,
+		"user_id":    userID,
+	}).Info("Expense recorded")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"expense": e,
+	})
+}
+
+// UpdateExpense changes an expense and its split
+func (h *ExpenseHandler) UpdateExpense(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, expenseID, ok := parseExpenseParams(c)
+	if !ok {
+		return
+	}
+
+	input, ok := bindExpenseRequest(c)
+	if !ok {
+		return
+	}
+
+	e, err := h.expenseService.UpdateExpense(c.Request.Context(), tripID, expenseID, userID, input)
+	if err != nil {
+		h.respondError(c, err, "Failed to update expense")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"expense": e,
+	})
+}
+
+// DeleteExpense deletes an expense
+func (h *ExpenseHandler) DeleteExpense(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, expenseID, ok := parseExpenseParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.expenseService.DeleteExpense(c.Request.Context(), tripID, expenseID, userID); err != nil {
+		h.respondError(c, err, "Failed to delete expense")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Expense deleted",
+	})
+}
+
+// GetBalances returns the trip members' balances and a settle-up plan. The
+// currency query picks the amounts: "original" per currency paid in (the
+// default), "base" in the trip's currency, or "preferred" in the trip's
+// currency with each amount also converted into the member's own currency.
+func (h *ExpenseHandler) GetBalances(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	basis := expense.BasisOriginal
+	preferred := false
+	switch c.DefaultQuery("currency", "original") {
+	case "original":
+	case "base":
+		basis = expense.BasisBase
+	case "preferred":
+		basis = expense.BasisBase
+		preferred = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid currency, expected original, base or preferred",
+		})
+		return
+	}
+
+	sheet, err := h.expenseService.GetBalances(c.Request.Context(), tripID, userID, basis, preferred)
+	if err != nil {
+		h.respondError(c, err, "Failed to compute balances")
+		return
+	}
+
+	c.JSON(http.StatusOK, sheet)
+}
+
+// SettleShare marks a share of an expense as paid back
+func (h *ExpenseHandler) SettleShare(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, expenseID, ok := parseExpenseParams(c)
+	if !ok {
+		return
+	}
+
+	shareID, ok := parseUUIDParam(c, "share_id", "Invalid share ID")
+	if !ok {
+		return
+	}
+
+	var req SettleShareRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request format",
+			})
+			return
+		}
+	}
+
+	e, err := h.expenseService.SettleShare(c.Request.Context(), tripID, expenseID, shareID, userID, req.Notes)
+	if err != nil {
+		h.respondError(c, err, "Failed to settle share")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"expense": e,
+	})
+}
+
+// UnsettleShare reopens a share that was settled by mistake
+func (h *ExpenseHandler) UnsettleShare(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, expenseID, ok := parseExpenseParams(c)
+	if !ok {
+		return
+	}
+
+	shareID, ok := parseUUIDParam(c, "share_id", "Invalid share ID")
+	if !ok {
+		return
+	}
+
+	e, err := h.expenseService.UnsettleShare(c.Request.Context(), tripID, expenseID, shareID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to reopen share")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"expense": e,
+	})
+}
+
+// RecordSettlement settles every open share between two members in a currency
+func (h *ExpenseHandler) RecordSettlement(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var req SettlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	settlement, err := h.expenseService.SettleBetween(c.Request.Context(), tripID, userID, req.FromUserID, req.ToUserID, req.Currency, req.Notes)
+	if err != nil {
+		h.respondError(c, err, "Failed to record settlement")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"trip_id":      tripID,
+		"from_user_id": settlement.Transfer.FromUserID,
+		"to_user_id":   settlement.Transfer.ToUserID,
+		"shares":       len(settlement.Shares),
+	}).Info("Settlement recorded")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"settlement": settlement,
+	})
+}
+
+// bindExpenseRequest reads an expense request body into service input
+func bindExpenseRequest(c *gin.Context) (appExpense.ExpenseInput, bool) {
+	var req ExpenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return appExpense.ExpenseInput{}, false
+	}
+
+	input := appExpense.ExpenseInput{
+		PayerID:     req.PayerID,
+		Title:       req.Title,
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Category:    expense.Category(req.Category),
+		Notes:       req.Notes,
+		SplitMethod: expense.SplitMethod(req.SplitMethod),
+		Split:       req.Split,
+	}
+
+	if req.Date != "" {
+		date, err := time.Parse(expenseDateLayout, req.Date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid date, expected YYYY-MM-DD",
+			})
+			return appExpense.ExpenseInput{}, false
+		}
+		input.Date = &date
+	}
+
+	return input, true
+}
+
+// parseDateQuery reads an optional YYYY-MM-DD query parameter
+func parseDateQuery(c *gin.Context, name string) (*time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+
+	date, err := time.Parse(expenseDateLayout, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid " + name + " date, expected YYYY-MM-DD",
+		})
+		return nil, false
+	}
+	return &date, true
+}
+
+// parseExpenseFilter reads the category and date range filter from the query
+func parseExpenseFilter(c *gin.Context) (expense.ListFilter, bool) {
+	var ok bool
+	filter := expense.ListFilter{Category: expense.Category(c.Query("category"))}
+	if filter.From, ok = parseDateQuery(c, "from"); !ok {
+		return expense.ListFilter{}, false
+	}
+	if filter.To, ok = parseDateQuery(c, "to"); !ok {
+		return expense.ListFilter{}, false
+	}
+	return filter, true
+}
+
+// parseExpenseParams reads the trip and expense IDs from the path
+func parseExpenseParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	expenseID, ok := parseUUIDParam(c, "expense_id", "Invalid expense ID")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tripID, expenseID, true
+}
+
+// CreateReportLink issues a short-lived download link for the trip's expense
+// report. The format query is csv (the default) or pdf, and the report can be
+// filtered by category and date range like the expense list.
+func (h *ExpenseHandler) CreateReportLink(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	filter, ok := parseExpenseFilter(c)
+	if !ok {
+		return
+	}
+	format := expense.ReportFormat(c.DefaultQuery("format", string(expense.ReportCSV)))
+
+	link, err := h.expenseService.CreateReportLink(c.Request.Context(), tripID, userID, format, filter)
+	if err != nil {
+		h.respondError(c, err, "Failed to create report link")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"url":        requestScheme(c) + "://" + c.Request.Host + reportPath + link.Token,
+		"format":     link.Format,
+		"expires_at": link.ExpiresAt,
+	})
+}
+
+// DownloadReport serves the expense report a signed link grants
+func (h *ExpenseHandler) DownloadReport(c *gin.Context) {
+	r, format, err := h.expenseService.GetReport(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		h.respondError(c, err, "Failed to produce report")
+		return
+	}
+
+	var buf bytes.Buffer
+	if format == expense.ReportPDF {
+		err = report.WritePDF(&buf, r)
+	} else {
+		err = report.WriteCSV(&buf, r)
+	}
+	if err != nil {
+		h.respondError(c, err, "Failed to produce report")
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": report.Filename(r, format),
+	}))
+	c.Data(http.StatusOK, report.ContentType(format), buf.Bytes())
+}
+
+// ListReceipts returns the receipts attached to an expense
+func (h *ExpenseHandler) ListReceipts(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, expenseID, ok := parseExpenseParams(c)
+	if !ok {
+		return
+	}
+
+	receipts, err := h.expenseService.ListReceipts(c.Request.Context(), tripID, expenseID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to list receipts")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"receipts": receipts,
+	})
+}
+
+// UploadReceipt attaches a receipt, sent as the "file" field of a multipart
+// form, to an expense
+func (h *ExpenseHandler) UploadReceipt(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, expenseID, ok := parseExpenseParams(c)
+	if !ok {
+		return
+	}
+
+	// Leave room for the multipart framing around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, expense.MaxReceiptSize+1<<20)
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.respondError(c, expense.ErrReceiptTooLarge, "Failed to upload receipt")
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "No receipt file provided",
+		})
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, expense.MaxReceiptSize+1))
+	if err != nil {
+		h.respondError(c, err, "Failed to upload receipt")
+		return
+	}
+
+	receipt, err := h.expenseService.UploadReceipt(c.Request.Context(), tripID, expenseID, userID, header.Filename, content)
+	if err != nil {
+		h.respondError(c, err, "Failed to upload receipt")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"receipt": receipt,
+	})
+}
+
+// GetReceipt serves a receipt file
+func (h *ExpenseHandler) GetReceipt(c *gin.Context) {
+	h.serveReceipt(c, false)
+}
+
+// GetReceiptThumbnail serves the preview of a receipt image
+func (h *ExpenseHandler) GetReceiptThumbnail(c *gin.Context) {
+	h.serveReceipt(c, true)
+}
+
+// DeleteReceipt removes a receipt from an expense
+func (h *ExpenseHandler) DeleteReceipt(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, expenseID, ok := parseExpenseParams(c)
+	if !ok {
+		return
+	}
+	receiptID, ok := parseUUIDParam(c, "receipt_id", "Invalid receipt ID")
+	if !ok {
+		return
+	}
+
+	if err := h.expenseService.DeleteReceipt(c.Request.Context(), tripID, expenseID, receiptID, userID); err != nil {
+		h.respondError(c, err, "Failed to delete receipt")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Receipt deleted",
+	})
+}
+
+// serveReceipt streams a receipt file or its thumbnail. Images are shown
+// inline; documents are always downloaded rather than opened in the page.
+func (h *ExpenseHandler) serveReceipt(c *gin.Context, thumbnail bool) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, expenseID, ok := parseExpenseParams(c)
+	if !ok {
+		return
+	}
+	receiptID, ok := parseUUIDParam(c, "receipt_id", "Invalid receipt ID")
+	if !ok {
+		return
+	}
+
+	receipt, file, err := h.expenseService.OpenReceipt(c.Request.Context(), tripID, expenseID, receiptID, userID, thumbnail)
+	if err != nil {
+		h.respondError(c, err, "Failed to open receipt")
+		return
+	}
+	defer file.Close()
+
+	contentType, size, disposition := receipt.ContentType, receipt.Size, "attachment"
+	if thumbnail {
+		contentType, size = blob.ContentTypeJPEG, -1
+	}
+	if receipt.IsImage() {
+		disposition = "inline"
+	}
+
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{
+		"filename": receipt.Filename,
+	}))
+	c.DataFromReader(http.StatusOK, size, contentType, file, nil)
+}
+
+// ListRecurringExpenses returns a trip's recurring expenses
+func (h *ExpenseHandler) ListRecurringExpenses(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	recurring, err := h.expenseService.ListRecurringExpenses(c.Request.Context(), tripID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to list recurring expenses")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recurring_expenses": recurring,
+	})
+}
+
+// CreateRecurringExpense sets up a cost recorded as an expense every period
+func (h *ExpenseHandler) CreateRecurringExpense(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	input, ok := bindRecurringExpenseRequest(c)
+	if !ok {
+		return
+	}
+
+	recurring, err := h.expenseService.CreateRecurringExpense(c.Request.Context(), tripID, userID, input)
+	if err != nil {
+		h.respondError(c, err, "Failed to create recurring expense")
+		return
+	}
+
+	c.JSON(http.StatusCreated, recurring)
+}
+
+// UpdateRecurringExpense changes a recurring expense from its next period on
+func (h *ExpenseHandler) UpdateRecurringExpense(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, recurringID, ok := parseRecurringExpenseParams(c)
+	if !ok {
+		return
+	}
+
+	input, ok := bindRecurringExpenseRequest(c)
+	if !ok {
+		return
+	}
+
+	recurring, err := h.expenseService.UpdateRecurringExpense(c.Request.Context(), tripID, recurringID, userID, input)
+	if err != nil {
+		h.respondError(c, err, "Failed to update recurring expense")
+		return
+	}
+
+	c.JSON(http.StatusOK, recurring)
+}
+
+// DeleteRecurringExpense stops a recurring expense, keeping what it recorded
+func (h *ExpenseHandler) DeleteRecurringExpense(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, recurringID, ok := parseRecurringExpenseParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.expenseService.DeleteRecurringExpense(c.Request.Context(), tripID, recurringID, userID); err != nil {
+		h.respondError(c, err, "Failed to delete recurring expense")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Recurring expense deleted",
+	})
+}
+
+// bindRecurringExpenseRequest reads a recurring expense request into service input
+func bindRecurringExpenseRequest(c *gin.Context) (appExpense.RecurringInput, bool) {
+	var req RecurringExpenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return appExpense.RecurringInput{}, false
+	}
+
+	input := appExpense.RecurringInput{
+		PayerID:   req.PayerID,
+		Title:     req.Title,
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+		Category:  expense.Category(req.Category),
+		Notes:     req.Notes,
+		Frequency: expense.Frequency(req.Frequency),
+	}
+
+	var ok bool
+	if input.StartDate, ok = parseRequestDate(c, req.StartDate); !ok {
+		return appExpense.RecurringInput{}, false
+	}
+	if input.EndDate, ok = parseRequestDate(c, req.EndDate); !ok {
+		return appExpense.RecurringInput{}, false
+	}
+
+	return input, true
+}
+
+// parseRequestDate reads an optional YYYY-MM-DD date of a request body
+func parseRequestDate(c *gin.Context, value string) (*time.Time, bool) {
+	if value == "" {
+		return nil, true
+	}
+
+	date, err := time.Parse(expenseDateLayout, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid date, expected YYYY-MM-DD",
+		})
+		return nil, false
+	}
+	return &date, true
+}
+
+// parseRecurringExpenseParams reads the trip and recurring expense IDs from the path
+func parseRecurringExpenseParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	recurringID, ok := parseUUIDParam(c, "recurring_id", "Invalid recurring expense ID")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tripID, recurringID, true
+}
+
+// GetBudgetPlan returns a trip's budget plan. The scope query picks the
+// trip's plan ("trip", the default) or the member's own ("personal").
+func (h *ExpenseHandler) GetBudgetPlan(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	personal, ok := parseBudgetScope(c)
+	if !ok {
+		return
+	}
+
+	lines, err := h.expenseService.GetBudgetPlan(c.Request.Context(), tripID, userID, personal)
+	if err != nil {
+		h.respondError(c, err, "Failed to get budget plan")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"lines": lines,
+	})
+}
+
+// SetBudgetPlan replaces a trip's budget plan, or the member's own plan with
+// scope=personal
+func (h *ExpenseHandler) SetBudgetPlan(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	personal, ok := parseBudgetScope(c)
+	if !ok {
+		return
+	}
+
+	var req BudgetPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	inputs := make([]expense.BudgetLineInput, len(req.Lines))
+	for i, line := range req.Lines {
+		inputs[i] = expense.BudgetLineInput{
+			Category:     expense.Category(line.Category),
+			Amount:       line.Amount,
+			AlertPercent: line.AlertPercent,
+		}
+	}
+
+	lines, err := h.expenseService.SetBudgetPlan(c.Request.Context(), tripID, userID, personal, inputs)
+	if err != nil {
+		h.respondError(c, err, "Failed to save budget plan")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"lines": lines,
+	})
+}
+
+// CompareBudget returns a trip's budget plan, or the member's own plan with
+// scope=personal, against the recorded expenses, with a burn-down over the
+// trip's dates
+func (h *ExpenseHandler) CompareBudget(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	personal, ok := parseBudgetScope(c)
+	if !ok {
+		return
+	}
+
+	comparison, err := h.expenseService.CompareBudget(c.Request.Context(), tripID, userID, personal)
+	if err != nil {
+		h.respondError(c, err, "Failed to compare budget")
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// parseBudgetScope reads the scope query: whether the member's own plan is
+// meant rather than the trip's
+func parseBudgetScope(c *gin.Context) (bool, bool) {
+	switch c.DefaultQuery("scope", "trip") {
+	case "trip":
+		return false, true
+	case "personal":
+		return true, true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scope, expected trip or personal",
+		})
+		return false, false
+	}
+}
+
+// ListCurrencies returns the supported ISO 4217 currencies and their minor units
+func (h *ExpenseHandler) ListCurrencies(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"currencies": currency.All(),
+	})
+}
+
+// respondError maps expense, currency and trip domain errors to HTTP responses
+func (h *ExpenseHandler) respondError(c *gin.Context, err error, logMessage string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, expense.ErrExpenseNotFound),
+		errors.Is(err, expense.ErrShareNotFound),
+		errors.Is(err, expense.ErrReceiptNotFound),
+		errors.Is(err, expense.ErrRecurringExpenseNotFound),
+		errors.Is(err, blob.ErrNotFound),
+		errors.Is(err, trip.ErrTripNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, expense.ErrNotTripMember),
+		errors.Is(err, expense.ErrNotExpenseOwner),
+		errors.Is(err, expense.ErrNotSettlementParty),
+		errors.Is(err, expense.ErrInvalidReportLink),
+		errors.Is(err, expense.ErrNotReceiptOwner),
+		errors.Is(err, expense.ErrNotBudgetOwner),
+		errors.Is(err, expense.ErrNotRecurringOwner):
+		status = http.StatusForbidden
+	case errors.Is(err, expense.ErrReportLinkExpired):
+		status = http.StatusGone
+	case errors.Is(err, expense.ErrExpenseSettled),
+		errors.Is(err, expense.ErrAlreadySettled),
+		errors.Is(err, expense.ErrNotSettled),
+		errors.Is(err, expense.ErrNothingToSettle),
+		errors.Is(err, expense.ErrTooManyReceipts),
+		errors.Is(err, expense.ErrRecurringStarted):
+		status = http.StatusConflict
+	case errors.Is(err, expense.ErrReceiptTooLarge):
+		status = http.StatusRequestEntityTooLarge
+	case errors.Is(err, expense.ErrInvalidExpense),
+		errors.Is(err, expense.ErrInvalidAmount),
+		errors.Is(err, currency.ErrInvalidCurrency),
+		errors.Is(err, currency.ErrRateNotFound),
+		errors.Is(err, currency.ErrConversionOverflow),
+		errors.Is(err, expense.ErrInvalidCategory),
+		errors.Is(err, expense.ErrInvalidSplitMethod),
+		errors.Is(err, expense.ErrInvalidSplit),
+		errors.Is(err, expense.ErrSplitMismatch),
+		errors.Is(err, expense.ErrInvalidSettlement),
+		errors.Is(err, expense.ErrInvalidReport),
+		errors.Is(err, expense.ErrInvalidReceipt),
+		errors.Is(err, expense.ErrInvalidBudget),
+		errors.Is(err, expense.ErrInvalidRecurringExpense):
+		status = http.StatusUnprocessableEntity
+	}
+
+	if status == http.StatusInternalServerError {
+		h.logger.WithError(err).Error(logMessage)
+		c.JSON(status, gin.H{
+			"error": logMessage,
+		})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
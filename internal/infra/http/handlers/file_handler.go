@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"bufio"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"jointrip/internal/domain/blob"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// FileHandler serves the signed links of file stores that do not serve
+// files themselves, such as the local disk store
+type FileHandler struct {
+	fileStore blob.Store
+	logger    *logrus.Logger
+}
+
+// NewFileHandler creates a new file handler
+func NewFileHandler(fileStore blob.Store, logger *logrus.Logger) *FileHandler {
+	return &FileHandler{
+		fileStore: fileStore,
+		logger:    logger,
+	}
+}
+
+// ServeFile streams the file a signed link grants. The link itself is the
+// authorization, so no session is needed.
+func (h *FileHandler) ServeFile(c *gin.Context) {
+	server, ok := h.fileStore.(blob.LinkServer)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": blob.ErrNotFound.Error(),
+		})
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	file, err := server.OpenLink(c.Request.Context(), key, c.Request.URL.Query(), time.Now())
+	if err != nil {
+		h.respondError(c, err, "Failed to open file")
+		return
+	}
+	defer file.Close()
+
+	// Only content recognized by its magic bytes is shown inline
+	content := bufio.NewReaderSize(file, blob.SniffLength)
+	head, _ := content.Peek(blob.SniffLength)
+	contentType := blob.DetectContentType(head)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+		c.Header("Content-Disposition", "attachment")
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.DataFromReader(http.StatusOK, -1, contentType, content, nil)
+}
+
+// respondError maps file errors to HTTP responses
+func (h *FileHandler) respondError(c *gin.Context, err error, logMessage string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, blob.ErrNotFound),
+		errors.Is(err, blob.ErrInvalidKey):
+		status = http.StatusNotFound
+	case errors.Is(err, blob.ErrInvalidLink):
+		status = http.StatusForbidden
+	case errors.Is(err, blob.ErrLinkExpired):
+		status = http.StatusGone
+	}
+
+	if status == http.StatusInternalServerError {
+		h.logger.WithError(err).Error(logMessage)
+		c.JSON(status, gin.H{
+			"error": logMessage,
+		})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,448 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	appTrip "jointrip/internal/app/trip"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/infra/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// invitePath is the web route that opens an invite link, relative to the site root
+const invitePath = "/invites/"
+
+// InviteHandler handles trip invite link and invitation HTTP requests
+type InviteHandler struct {
+	tripService *appTrip.Service
+	logger      *logrus.Logger
+}
+
+// NewInviteHandler creates a new invite handler
+func NewInviteHandler(tripService *appTrip.Service, logger *logrus.Logger) *InviteHandler {
+	return &InviteHandler{
+		tripService: tripService,
+		logger:      logger,
+	}
+}
+
+// CreateInviteLinkRequest represents a request to issue an invite link
+type CreateInviteLinkRequest struct {
+	ExpiresInHours int  `json:"expires_in_hours"`
+	MaxUses        *int `json:"max_uses"`
+	PreApproved    bool `json:"pre_approved"`
+}
+
+// InviteUserRequest represents a direct invitation request
+type InviteUserRequest struct {
+	UserID      uuid.UUID `json:"user_id" binding:"required"`
+	Message     string    `json:"message"`
+	PreApproved bool      `json:"pre_approved"`
+}
+
+// InviteLinkResponse is an invite link with its shareable URL
+type InviteLinkResponse struct {
+	*appTrip.IssuedInviteLink
+	URL string `json:"url"`
+}
+
+// CreateInviteLink issues a shareable invite link (organizer only)
+func (h *InviteHandler) CreateInviteLink(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var req CreateInviteLinkRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request format",
+			})
+			return
+		}
+	}
+
+	link, err := h.tripService.CreateInviteLink(c.Request.Context(), tripID, userID, appTrip.CreateInviteLinkInput{
+		TTL:         time.Duration(req.ExpiresInHours) * time.Hour,
+		MaxUses:     req.MaxUses,
+		PreApproved: req.PreApproved,
+	})
+	if err != nil {
+		h.respondError(c, err, "Failed to create invite link")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"trip_id": tripID,
+		"link_id": link.ID,
+	}).Info("Invite link created")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"invite_link": h.linkResponse(c, link),
+	})
+}
+
+// ListInviteLinks returns a trip's invite links (organizer only)
+func (h *InviteHandler) ListInviteLinks(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	links, err := h.tripService.ListInviteLinks(c.Request.Context(), tripID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to list invite links")
+		return
+	}
+
+	responses := make([]*InviteLinkResponse, 0, len(links))
+	for _, link := range links {
+		responses = append(responses, h.linkResponse(c, link))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trip_id":      tripID,
+		"invite_links": responses,
+	})
+}
+
+// RevokeInviteLink disables an invite link (organizer only)
+func (h *InviteHandler) RevokeInviteLink(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	linkID, ok := parseUUIDParam(c, "link_id", "Invalid invite link ID")
+	if !ok {
+		return
+	}
+
+	if err := h.tripService.RevokeInviteLink(c.Request.Context(), tripID, userID, linkID); err != nil {
+		h.respondError(c, err, "Failed to revoke invite link")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Invite link revoked",
+	})
+}
+
+// PreviewInvite returns the trip behind an invite link
+func (h *InviteHandler) PreviewInvite(c *gin.Context) {
+	preview, err := h.tripService.PreviewInvite(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		h.respondError(c, err, "Failed to load invite")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invite": preview,
+	})
+}
+
+// RedeemInvite joins the trip behind an invite link
+func (h *InviteHandler) RedeemInvite(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	var req JoinTripRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request format",
+			})
+			return
+		}
+	}
+
+	result, err := h.tripService.RedeemInvite(c.Request.Context(), c.Param("token"), userID, req.Notes)
+	if err != nil {
+		h.respondError(c, err, "Failed to redeem invite")
+		return
+	}
+
+	h.respondJoin(c, result)
+}
+
+// InviteUser sends a direct invitation to a user (organizer only)
+func (h *InviteHandler) InviteUser(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var req InviteUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	invitation, err := h.tripService.InviteUser(c.Request.Context(), tripID, userID, req.UserID, req.Message, req.PreApproved)
+	if err != nil {
+		h.respondError(c, err, "Failed to invite user")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"trip_id":    tripID,
+		"invitee_id": req.UserID,
+	}).Info("Trip invitation sent")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"invitation": invitation,
+	})
+}
+
+// ListTripInvitations returns a trip's direct invitations (organizer only)
+func (h *InviteHandler) ListTripInvitations(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	invitations, err := h.tripService.ListTripInvitations(c.Request.Context(), tripID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to list invitations")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trip_id":     tripID,
+		"invitations": invitations,
+	})
+}
+
+// CancelInvitation withdraws a pending invitation (organizer only)
+func (h *InviteHandler) CancelInvitation(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	invitationID, ok := parseUUIDParam(c, "invitation_id", "Invalid invitation ID")
+	if !ok {
+		return
+	}
+
+	if err := h.tripService.CancelInvitation(c.Request.Context(), tripID, userID, invitationID); err != nil {
+		h.respondError(c, err, "Failed to cancel invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Invitation canceled",
+	})
+}
+
+// ListMyInvitations returns the current user's pending invitations
+func (h *InviteHandler) ListMyInvitations(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	invitations, err := h.tripService.ListMyInvitations(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to list invitations")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invitations": invitations,
+	})
+}
+
+// AcceptInvitation joins the trip of a pending invitation
+func (h *InviteHandler) AcceptInvitation(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	invitationID, ok := parseUUIDParam(c, "invitation_id", "Invalid invitation ID")
+	if !ok {
+		return
+	}
+
+	var req JoinTripRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request format",
+			})
+			return
+		}
+	}
+
+	result, err := h.tripService.AcceptInvitation(c.Request.Context(), invitationID, userID, req.Notes)
+	if err != nil {
+		h.respondError(c, err, "Failed to accept invitation")
+		return
+	}
+
+	h.respondJoin(c, result)
+}
+
+// DeclineInvitation turns down a pending invitation
+func (h *InviteHandler) DeclineInvitation(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	invitationID, ok := parseUUIDParam(c, "invitation_id", "Invalid invitation ID")
+	if !ok {
+		return
+	}
+
+	if err := h.tripService.DeclineInvitation(c.Request.Context(), invitationID, userID); err != nil {
+		h.respondError(c, err, "Failed to decline invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Invitation declined",
+	})
+}
+
+// respondJoin writes the outcome of joining a trip through an invite
+func (h *InviteHandler) respondJoin(c *gin.Context, result *appTrip.JoinResult) {
+	if result.Waitlisted() {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":        "Trip is full, you have been added to the waitlist",
+			"waitlist_entry": result.WaitlistEntry,
+		})
+		return
+	}
+
+	message := "Join request sent"
+	if result.Participant.Status == trip.ParticipantStatusApproved {
+		message = "You have joined the trip"
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     message,
+		"participant": result.Participant,
+	})
+}
+
+// linkResponse attaches the shareable URL to an invite link
+func (h *InviteHandler) linkResponse(c *gin.Context, link *appTrip.IssuedInviteLink) *InviteLinkResponse {
+	return &InviteLinkResponse{
+		IssuedInviteLink: link,
+		URL:              requestScheme(c) + "://" + c.Request.Host + invitePath + link.Token,
+	}
+}
+
+// respondError maps invite and trip domain errors to HTTP responses
+func (h *InviteHandler) respondError(c *gin.Context, err error, logMessage string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, trip.ErrInvalidInviteToken):
+		status = http.StatusBadRequest
+	case errors.Is(err, trip.ErrTripNotFound),
+		errors.Is(err, trip.ErrInviteLinkNotFound),
+		errors.Is(err, trip.ErrInvitationNotFound),
+		errors.Is(err, trip.ErrInviteeNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, trip.ErrNotOrganizer):
+		status = http.StatusForbidden
+	case errors.Is(err, trip.ErrAlreadyParticipant),
+		errors.Is(err, trip.ErrAlreadyWaitlisted),
+		errors.Is(err, trip.ErrAlreadyInvited),
+		errors.Is(err, trip.ErrInvalidInvitationState),
+		errors.Is(err, trip.ErrInvalidParticipantState):
+		status = http.StatusConflict
+	case errors.Is(err, trip.ErrInviteRevoked),
+		errors.Is(err, trip.ErrInviteExpired),
+		errors.Is(err, trip.ErrInviteExhausted):
+		status = http.StatusGone
+	case errors.Is(err, trip.ErrTripNotJoinable),
+		errors.Is(err, trip.ErrInvalidInviteOptions),
+		errors.Is(err, trip.ErrCannotInviteSelf):
+		status = http.StatusUnprocessableEntity
+	}
+
+	if status == http.StatusInternalServerError {
+		h.logger.WithError(err).Error(logMessage)
+		c.JSON(status, gin.H{
+			"error": logMessage,
+		})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
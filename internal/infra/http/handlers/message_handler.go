@@ -0,0 +1,442 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	appMessaging "jointrip/internal/app/messaging"
+	"jointrip/internal/domain/messaging"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/domain/user"
+	"jointrip/internal/infra/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// MessageHandler handles direct messaging, trip chat and user blocking HTTP requests
+type MessageHandler struct {
+	messagingService *appMessaging.Service
+	logger           *logrus.Logger
+}
+
+// NewMessageHandler creates a new message handler
+func NewMessageHandler(messagingService *appMessaging.Service, logger *logrus.Logger) *MessageHandler {
+	return &MessageHandler{
+		messagingService: messagingService,
+		logger:           logger,
+	}
+}
+
+// StartConversationRequest represents a request to open a direct conversation
+type StartConversationRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	Body   string    `json:"body"`
+}
+
+// SendMessageRequest represents a request to post a message
+type SendMessageRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// MarkReadRequest represents a request to move the read marker
+type MarkReadRequest struct {
+	MessageID *uuid.UUID `json:"message_id"`
+}
+
+// StartConversation opens (or reopens) a direct conversation with another user
+func (h *MessageHandler) StartConversation(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	var req StartConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	conversation, message, created, err := h.messagingService.StartConversation(c.Request.Context(), userID, req.UserID, req.Body)
+	if err != nil {
+		h.respondError(c, err, "Failed to start conversation")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+		h.logger.WithFields(logrus.Fields{
+			"conversation_id": conversation.ID,
+			"user_id":         userID,
+		}).Info("Conversation started")
+	}
+
+	response := gin.H{
+		"conversation": conversation,
+	}
+	if message != nil {
+		response["message"] = message
+	}
+	c.JSON(status, response)
+}
+
+// ListConversations returns a page of the current user's conversations
+func (h *MessageHandler) ListConversations(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	page, err := h.messagingService.ListConversations(c.Request.Context(), userID, c.Query("cursor"), limit)
+	if err != nil {
+		h.respondError(c, err, "Failed to list conversations")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetConversation returns a conversation with its members and read markers
+func (h *MessageHandler) GetConversation(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	conversationID, ok := parseUUIDParam(c, "conversation_id", "Invalid conversation ID")
+	if !ok {
+		return
+	}
+
+	conversation, err := h.messagingService.GetConversation(c.Request.Context(), conversationID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get conversation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"conversation": conversation,
+	})
+}
+
+// ListMessages returns a page of a conversation's messages, newest first
+func (h *MessageHandler) ListMessages(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	conversationID, ok := parseUUIDParam(c, "conversation_id", "Invalid conversation ID")
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	page, err := h.messagingService.ListMessages(c.Request.Context(), conversationID, userID, c.Query("cursor"), limit)
+	if err != nil {
+		h.respondError(c, err, "Failed to list messages")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// SendMessage posts a message to a conversation
+func (h *MessageHandler) SendMessage(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	conversationID, ok := parseUUIDParam(c, "conversation_id", "Invalid conversation ID")
+	if !ok {
+		return
+	}
+
+	var req SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	message, err := h.messagingService.SendMessage(c.Request.Context(), conversationID, userID, req.Body)
+	if err != nil {
+		h.respondError(c, err, "Failed to send message")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": message,
+	})
+}
+
+// MarkRead moves the current user's read marker in a conversation
+func (h *MessageHandler) MarkRead(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	conversationID, ok := parseUUIDParam(c, "conversation_id", "Invalid conversation ID")
+	if !ok {
+		return
+	}
+
+	var req MarkReadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request format",
+			})
+			return
+		}
+	}
+
+	member, err := h.messagingService.MarkRead(c.Request.Context(), conversationID, userID, req.MessageID)
+	if err != nil {
+		h.respondError(c, err, "Failed to mark conversation as read")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"read_marker": member,
+	})
+}
+
+// GetTripConversation returns the group chat of a trip
+func (h *MessageHandler) GetTripConversation(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	conversation, err := h.messagingService.GetTripConversation(c.Request.Context(), tripID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get trip chat")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"conversation": conversation,
+	})
+}
+
+// ListPinnedMessages returns the pinned messages of a trip chat
+func (h *MessageHandler) ListPinnedMessages(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	conversationID, ok := parseUUIDParam(c, "conversation_id", "Invalid conversation ID")
+	if !ok {
+		return
+	}
+
+	messages, err := h.messagingService.ListPinnedMessages(c.Request.Context(), conversationID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to list pinned messages")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messages": messages,
+	})
+}
+
+// PinMessage pins a trip chat message (organizer only)
+func (h *MessageHandler) PinMessage(c *gin.Context) {
+	h.updatePin(c, h.messagingService.PinMessage, "Failed to pin message")
+}
+
+// UnpinMessage unpins a trip chat message (organizer only)
+func (h *MessageHandler) UnpinMessage(c *gin.Context) {
+	h.updatePin(c, h.messagingService.UnpinMessage, "Failed to unpin message")
+}
+
+// ListBlockedUsers returns the users the current user has blocked
+func (h *MessageHandler) ListBlockedUsers(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	blocks, err := h.messagingService.ListBlockedUsers(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to list blocked users")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"blocks": blocks,
+	})
+}
+
+// BlockUser blocks another user
+func (h *MessageHandler) BlockUser(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	blockedID, ok := parseUUIDParam(c, "user_id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	block, err := h.messagingService.BlockUser(c.Request.Context(), userID, blockedID)
+	if err != nil {
+		h.respondError(c, err, "Failed to block user")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"block": block,
+	})
+}
+
+// UnblockUser lifts a block on another user
+func (h *MessageHandler) UnblockUser(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	blockedID, ok := parseUUIDParam(c, "user_id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	if err := h.messagingService.UnblockUser(c.Request.Context(), userID, blockedID); err != nil {
+		h.respondError(c, err, "Failed to unblock user")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User unblocked",
+	})
+}
+
+// updatePin handles pinning and unpinning a message
+func (h *MessageHandler) updatePin(
+	c *gin.Context,
+	update func(ctx context.Context, conversationID, messageID, userID uuid.UUID) (*messaging.Message, error),
+	logMessage string,
+) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	conversationID, ok := parseUUIDParam(c, "conversation_id", "Invalid conversation ID")
+	if !ok {
+		return
+	}
+
+	messageID, ok := parseUUIDParam(c, "message_id", "Invalid message ID")
+	if !ok {
+		return
+	}
+
+	message, err := update(c.Request.Context(), conversationID, messageID, userID)
+	if err != nil {
+		h.respondError(c, err, logMessage)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+	})
+}
+
+// respondError maps messaging and user domain errors to HTTP responses
+func (h *MessageHandler) respondError(c *gin.Context, err error, logMessage string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, messaging.ErrInvalidCursor):
+		status = http.StatusBadRequest
+	case errors.Is(err, messaging.ErrConversationNotFound),
+		errors.Is(err, messaging.ErrMessageNotFound),
+		errors.Is(err, trip.ErrTripNotFound),
+		errors.Is(err, user.ErrUserNotFound),
+		errors.Is(err, user.ErrBlockNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, messaging.ErrMessagingNotAllowed),
+		errors.Is(err, messaging.ErrUserBlocked),
+		errors.Is(err, messaging.ErrNotCurrentMember),
+		errors.Is(err, messaging.ErrNotTripMember),
+		errors.Is(err, messaging.ErrCannotPinMessage):
+		status = http.StatusForbidden
+	case errors.Is(err, user.ErrAlreadyBlocked):
+		status = http.StatusConflict
+	case errors.Is(err, messaging.ErrCannotMessageSelf),
+		errors.Is(err, messaging.ErrNotTripChat),
+		errors.Is(err, messaging.ErrInvalidMessageBody),
+		errors.Is(err, user.ErrCannotBlockSelf):
+		status = http.StatusUnprocessableEntity
+	}
+
+	if status == http.StatusInternalServerError {
+		h.logger.WithError(err).Error(logMessage)
+		c.JSON(status, gin.H{
+			"error": logMessage,
+		})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	appNotification "jointrip/internal/app/notification"
+	"jointrip/internal/domain/notification"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/infra/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// NotificationHandler handles notification center HTTP requests
+type NotificationHandler struct {
+	notificationService *appNotification.Service
+	logger              *logrus.Logger
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService *appNotification.Service, logger *logrus.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// MarkNotificationsReadRequest represents a request to mark notifications as read.
+// Either specific IDs or all notifications are marked.
+type MarkNotificationsReadRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"max=100"`
+	All bool        `json:"all"`
+}
+
+// UpdateNotificationPreferencesRequest represents a request to change which
+// channels each notification type is delivered on, how emails are batched and
+// the quiet hours. Types, channels and settings that are left out keep their
+// current value. Email is the email channel of each type, as sent by older
+// clients; empty quiet hours turn them off.
+type UpdateNotificationPreferencesRequest struct {
+	Channels   map[notification.Type]map[notification.Channel]bool `json:"channels"`
+	Email      map[notification.Type]bool                          `json:"email"`
+	Digest     *notification.DigestFrequency                       `json:"digest"`
+	Timezone   *string                                             `json:"timezone"`
+	QuietHours *QuietHoursRequest                                  `json:"quiet_hours"`
+}
+
+// QuietHoursRequest represents quiet hours written as "15:04"
+type QuietHoursRequest struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// ListNotifications returns a page of the current user's notifications
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	var filter notification.ListFilter
+	if value := c.Query("unread"); value != "" {
+		unread, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid unread filter",
+			})
+			return
+		}
+		filter.UnreadOnly = unread
+	}
+	filter.Limit, _ = strconv.Atoi(c.Query("limit"))
+	filter.Offset, _ = strconv.Atoi(c.Query("offset"))
+
+	page, err := h.notificationService.List(c.Request.Context(), userID, filter)
+	if err != nil {
+		h.respondError(c, err, "Failed to list notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetUnreadCount returns the number of unread notifications of the current user
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	count, err := h.notificationService.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to count unread notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"unread_count": count,
+	})
+}
+
+// MarkRead marks notifications of the current user as read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	var req MarkNotificationsReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	if len(req.IDs) == 0 && !req.All {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Provide notification ids or set all to true",
+		})
+		return
+	}
+
+	var ids []uuid.UUID
+	if !req.All {
+		ids = req.IDs
+	}
+
+	updated, err := h.notificationService.MarkRead(c.Request.Context(), userID, ids)
+	if err != nil {
+		h.respondError(c, err, "Failed to mark notifications as read")
+		return
+	}
+
+	unread, err := h.notificationService.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to count unread notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"updated":      updated,
+		"unread_count": unread,
+	})
+}
+
+// GetPreferences returns the current user's notification preferences
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	prefs, err := h.notificationService.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, preferencesResponse(prefs))
+}
+
+// UpdatePreferences changes the current user's notification preferences
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	var req UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	update := appNotification.PreferencesUpdate{
+		Channels: req.Channels,
+		Email:    req.Email,
+		Digest:   req.Digest,
+		Timezone: req.Timezone,
+	}
+	if req.QuietHours != nil {
+		update.QuietHours = &appNotification.QuietHoursUpdate{
+			Start: req.QuietHours.Start,
+			End:   req.QuietHours.End,
+		}
+	}
+
+	prefs, err := h.notificationService.UpdatePreferences(c.Request.Context(), userID, update)
+	if err != nil {
+		h.respondError(c, err, "Failed to update notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, preferencesResponse(prefs))
+}
+
+// MuteTrip stops notifications about a trip reaching the current user
+func (h *NotificationHandler) MuteTrip(c *gin.Context) {
+	h.setTripMuted(c, true)
+}
+
+// UnmuteTrip lets notifications about a trip reach the current user again
+func (h *NotificationHandler) UnmuteTrip(c *gin.Context) {
+	h.setTripMuted(c, false)
+}
+
+// setTripMuted mutes or unmutes the trip in the path for the current user
+func (h *NotificationHandler) setTripMuted(c *gin.Context, muted bool) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, err := uuid.Parse(c.Param("trip_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid trip ID",
+		})
+		return
+	}
+
+	if muted {
+		err = h.notificationService.MuteTrip(c.Request.Context(), userID, tripID)
+	} else {
+		err = h.notificationService.UnmuteTrip(c.Request.Context(), userID, tripID)
+	}
+	if err != nil {
+		h.respondError(c, err, "Failed to change trip notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trip_id": tripID,
+		"muted":   muted,
+	})
+}
+
+// preferencesResponse lists the channels of every notification type,
+// including the ones the user never changed, as they are delivered once the
+// account-wide switches apply. Email repeats the email channel for older
+// clients.
+func preferencesResponse(prefs *notification.Preferences) gin.H {
+	channels := make(map[notification.Type]map[notification.Channel]bool)
+	email := make(map[notification.Type]bool)
+	for _, notificationType := range notification.Types() {
+		channels[notificationType] = make(map[notification.Channel]bool)
+		for _, channel := range notification.Channels() {
+			channels[notificationType][channel] = prefs.Enabled(notificationType, channel)
+		}
+		email[notificationType] = prefs.Enabled(notificationType, notification.ChannelEmail)
+	}
+
+	return gin.H{
+		"channels":          channels,
+		"disabled_channels": prefs.DisabledChannels,
+		"email":             email,
+		"digest":            prefs.Digest,
+		"timezone":          prefs.Timezone,
+		"quiet_hours":       prefs.QuietHours,
+		"muted_trips":       prefs.MutedTrips,
+		"next_digest_at":    prefs.NextDigestAt,
+	}
+}
+
+// respondError maps notification errors to HTTP responses
+func (h *NotificationHandler) respondError(c *gin.Context, err error, logMessage string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, trip.ErrTripNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, notification.ErrInvalidType),
+		errors.Is(err, notification.ErrInvalidChannel),
+		errors.Is(err, notification.ErrInvalidDigestFrequency),
+		errors.Is(err, notification.ErrInvalidTimezone),
+		errors.Is(err, notification.ErrInvalidQuietHours):
+		status = http.StatusUnprocessableEntity
+	}
+
+	if status == http.StatusInternalServerError {
+		h.logger.WithError(err).Error(logMessage)
+		c.JSON(status, gin.H{
+			"error": logMessage,
+		})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	appPush "jointrip/internal/app/push"
+	"jointrip/internal/domain/push"
+	"jointrip/internal/infra/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// PushHandler handles Web Push subscription HTTP requests
+type PushHandler struct {
+	pushService *appPush.Service
+	logger      *logrus.Logger
+}
+
+// NewPushHandler creates a new push handler
+func NewPushHandler(pushService *appPush.Service, logger *logrus.Logger) *PushHandler {
+	return &PushHandler{
+		pushService: pushService,
+		logger:      logger,
+	}
+}
+
+// SubscribePushRequest represents a browser push subscription, in the shape
+// of PushSubscription.toJSON()
+type SubscribePushRequest struct {
+	Endpoint string `json:"endpoint" binding:"required,max=2048"`
+	Keys     struct {
+		P256dh string `json:"p256dh" binding:"required"`
+		Auth   string `json:"auth" binding:"required"`
+	} `json:"keys" binding:"required"`
+}
+
+// GetPublicKey returns the VAPID public key to subscribe browsers with
+func (h *PushHandler) GetPublicKey(c *gin.Context) {
+	publicKey, err := h.pushService.PublicKey()
+	if err != nil {
+		h.respondError(c, err, "Failed to get push public key")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"public_key": publicKey,
+	})
+}
+
+// Subscribe registers a push subscription for the current user's device
+func (h *PushHandler) Subscribe(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	var req SubscribePushRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	sub, err := h.pushService.Subscribe(c.Request.Context(), userID, req.Endpoint, req.Keys.P256dh, req.Keys.Auth, c.Request.UserAgent())
+	if err != nil {
+		h.respondError(c, err, "Failed to register push subscription")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"subscription": sub,
+	})
+}
+
+// ListSubscriptions returns the current user's registered devices
+func (h *PushHandler) ListSubscriptions(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	subs, err := h.pushService.ListSubscriptions(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to list push subscriptions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"subscriptions": subs,
+	})
+}
+
+// Unsubscribe removes one of the current user's devices
+func (h *PushHandler) Unsubscribe(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	subscriptionID, err := uuid.Parse(c.Param("subscription_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid subscription ID",
+		})
+		return
+	}
+
+	if err := h.pushService.Unsubscribe(c.Request.Context(), userID, subscriptionID); err != nil {
+		h.respondError(c, err, "Failed to remove push subscription")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondError maps push errors to HTTP responses
+func (h *PushHandler) respondError(c *gin.Context, err error, logMessage string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, push.ErrPushNotConfigured):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, push.ErrSubscriptionNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, push.ErrInvalidEndpoint),
+		errors.Is(err, push.ErrInvalidKeys):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, push.ErrTooManySubscriptions):
+		status = http.StatusConflict
+	}
+
+	if status == http.StatusInternalServerError {
+		h.logger.WithError(err).Error(logMessage)
+		c.JSON(status, gin.H{
+			"error": logMessage,
+		})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
+	"jointrip/internal/app/rating"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/domain/user"
 	"jointrip/internal/infra/http/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -12,22 +16,24 @@ import (
 
 // RatingHandler handles user rating-related HTTP requests
 type RatingHandler struct {
-	logger *logrus.Logger
+	ratingService *rating.Service
+	logger        *logrus.Logger
 }
 
 // NewRatingHandler creates a new rating handler
-func NewRatingHandler(logger *logrus.Logger) *RatingHandler {
+func NewRatingHandler(ratingService *rating.Service, logger *logrus.Logger) *RatingHandler {
 	return &RatingHandler{
-		logger: logger,
+		ratingService: ratingService,
+		logger:        logger,
 	}
 }
 
 // CreateRatingRequest represents a rating creation request
 type CreateRatingRequest struct {
 	RatedUserID uuid.UUID `json:"rated_user_id" binding:"required"`
+	TripID      uuid.UUID `json:"trip_id" binding:"required"`
 	Rating      int       `json:"rating" binding:"required,min=1,max=5"`
 	Review      string    `json:"review,omitempty"`
-	TripID      *uuid.UUID `json:"trip_id,omitempty"`
 }
 
 // CreateRating creates a new user rating
@@ -48,31 +54,21 @@ func (h *RatingHandler) CreateRating(c *gin.Context) {
 		return
 	}
 
-	// Validate that user is not rating themselves
-	if req.RatedUserID == currentUser.ID {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Cannot rate yourself",
-		})
+	created, err := h.ratingService.RateUser(c.Request.Context(), currentUser.ID, req.RatedUserID, req.TripID, req.Rating, req.Review)
+	if err != nil {
+		h.respondError(c, err, "Failed to create rating")
 		return
 	}
 
-	// TODO: Implement rating creation logic
-	// For now, return success response
 	h.logger.WithFields(logrus.Fields{
-		"rater_id":     currentUser.ID,
+		"rater_id":      currentUser.ID,
 		"rated_user_id": req.RatedUserID,
-		"rating":       req.Rating,
+		"rating":        req.Rating,
 	}).Info("Rating created successfully")
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Rating created successfully",
-		"rating": gin.H{
-			"rater_id":     currentUser.ID,
-			"rated_user_id": req.RatedUserID,
-			"rating":       req.Rating,
-			"review":       req.Review,
-			"trip_id":      req.TripID,
-		},
+		"rating":  created,
 	})
 }
 
@@ -87,30 +83,17 @@ func (h *RatingHandler) GetUserRatings(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement get ratings logic
-	// For now, return mock data
-	h.logger.WithField("user_id", userID).Info("Fetching user ratings")
+	ratings, err := h.ratingService.ListReceived(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get ratings")
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"user_id": userID,
-		"ratings": []gin.H{
-			{
-				"id":         uuid.New(),
-				"rater_id":   uuid.New(),
-				"rating":     5,
-				"review":     "Great travel companion!",
-				"created_at": "2024-01-01T00:00:00Z",
-			},
-			{
-				"id":         uuid.New(),
-				"rater_id":   uuid.New(),
-				"rating":     4,
-				"review":     "Very organized and friendly",
-				"created_at": "2024-01-02T00:00:00Z",
-			},
-		},
-		"average_rating": 4.5,
-		"total_ratings":  2,
+		"user_id":        userID,
+		"ratings":        ratings,
+		"average_rating": user.AverageRating(ratings),
+		"total_ratings":  len(ratings),
 	})
 }
 
@@ -124,21 +107,44 @@ func (h *RatingHandler) GetMyRatings(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement get my ratings logic
-	// For now, return mock data
-	h.logger.WithField("user_id", currentUser.ID).Info("Fetching user's given ratings")
+	ratings, err := h.ratingService.ListGiven(c.Request.Context(), currentUser.ID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get ratings")
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"user_id": currentUser.ID,
-		"ratings": []gin.H{
-			{
-				"id":           uuid.New(),
-				"rated_user_id": uuid.New(),
-				"rating":       4,
-				"review":       "Good experience traveling together",
-				"created_at":   "2024-01-01T00:00:00Z",
-			},
-		},
-		"total_given": 1,
+		"user_id":     currentUser.ID,
+		"ratings":     ratings,
+		"total_given": len(ratings),
+	})
+}
+
+// respondError maps user rating errors to HTTP responses
+func (h *RatingHandler) respondError(c *gin.Context, err error, logMessage string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, user.ErrUserNotFound),
+		errors.Is(err, trip.ErrTripNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, user.ErrNotTripCompanion):
+		status = http.StatusForbidden
+	case errors.Is(err, user.ErrAlreadyRated):
+		status = http.StatusConflict
+	case errors.Is(err, user.ErrCannotRateSelf),
+		errors.Is(err, user.ErrInvalidRating):
+		status = http.StatusBadRequest
+	}
+
+	if status == http.StatusInternalServerError {
+		h.logger.WithError(err).Error(logMessage)
+		c.JSON(status, gin.H{
+			"error": logMessage,
+		})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
 	})
 }
@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"jointrip/internal/app/auth"
+	appMessaging "jointrip/internal/app/messaging"
+	appRealtime "jointrip/internal/app/realtime"
+	"jointrip/internal/domain/realtime"
+	"jointrip/internal/infra/http/middleware"
+	infraRealtime "jointrip/internal/infra/realtime"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+)
+
+// Real-time connection settings
+const (
+	streamWriteTimeout       = 10 * time.Second
+	streamHeartbeatInterval  = 25 * time.Second
+	streamRevalidateInterval = time.Minute
+	streamReplayBatchSize    = 100
+	sseRetryMillis           = 5000
+	wsTypingThrottle         = 3 * time.Second
+	wsMaxClientFrame         = 4 << 10
+)
+
+// Frame types sent by clients
+const (
+	clientFrameTyping = "typing"
+)
+
+// clientFrame is a message sent by a client over the WebSocket
+type clientFrame struct {
+	Type           string    `json:"type"`
+	ConversationID uuid.UUID `json:"conversation_id"`
+}
+
+// RealtimeHandler serves the WebSocket and Server-Sent Events endpoints that
+// push real-time updates
+type RealtimeHandler struct {
+	authService      *auth.Service
+	messagingService *appMessaging.Service
+	realtimeService  *appRealtime.Service
+	hub              *infraRealtime.Hub
+	logger           *logrus.Logger
+}
+
+// NewRealtimeHandler creates a new real-time handler
+func NewRealtimeHandler(
+	authService *auth.Service,
+	messagingService *appMessaging.Service,
+	realtimeService *appRealtime.Service,
+	hub *infraRealtime.Hub,
+	logger *logrus.Logger,
+) *RealtimeHandler {
+	return &RealtimeHandler{
+		authService:      authService,
+		messagingService: messagingService,
+		realtimeService:  realtimeService,
+		hub:              hub,
+		logger:           logger,
+	}
+}
+
+// Connect upgrades the request to a WebSocket that receives the user's new
+// messages, notifications and typing indicators. Browsers cannot set headers
+// on WebSockets, so the access token is usually passed as the token query
+// parameter.
+func (h *RealtimeHandler) Connect(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+	token := middleware.ExtractToken(c)
+
+	server := websocket.Server{
+		// Access tokens are not sent automatically by browsers, so connections
+		// from any origin are as trustworthy as the token they carry
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			h.serveWebSocket(c.Request.Context(), ws, userID, token)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// Stream sends the same events as the WebSocket as Server-Sent Events, for
+// clients behind proxies that break WebSockets. Clients resume after a
+// reconnect with the Last-Event-ID header (or the last_event_id query
+// parameter) and receive the replayable events they missed.
+func (h *RealtimeHandler) Stream(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+	token := middleware.ExtractToken(c)
+
+	lastEventID, err := parseLastEventID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid Last-Event-ID",
+		})
+		return
+	}
+
+	// The server's WriteTimeout would cut the stream off; give each write
+	// its own deadline instead
+	w, err := newStreamWriter(c.Writer, streamWriteTimeout)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to clear write deadline for event stream")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Streaming is not supported",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// Subscribe before replaying so that nothing logged in between is lost
+	sub := h.hub.Subscribe(userID)
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	send := func(e realtime.Event) error {
+		if err := writeServerSentEvent(w, e); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", sseRetryMillis); err != nil {
+		return
+	}
+
+	for {
+		missed, err := h.realtimeService.EventsSince(ctx, userID, lastEventID, streamReplayBatchSize)
+		if err != nil {
+			h.logger.WithError(err).WithField("user_id", userID).Error("Failed to replay events")
+			return
+		}
+		for _, e := range missed {
+			if err := send(e); err != nil {
+				return
+			}
+			lastEventID = e.ID
+		}
+		if len(missed) < streamReplayBatchSize {
+			break
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return
+	}
+
+	h.pump(ctx, sub, userID, token, ctx.Done(), func(e realtime.Event) error {
+		// Skip live events that were already replayed
+		if e.ID != 0 && e.ID <= lastEventID {
+			return nil
+		}
+		return send(e)
+	})
+}
+
+// serveWebSocket pushes events to the connection until either side closes it
+func (h *RealtimeHandler) serveWebSocket(ctx context.Context, ws *websocket.Conn, userID uuid.UUID, token string) {
+	defer ws.Close()
+
+	// The server's WriteTimeout is still set on the hijacked connection;
+	// clear it and apply a deadline to each write instead
+	if err := ws.SetDeadline(time.Time{}); err != nil {
+		h.logger.WithError(err).Warn("Failed to clear WebSocket deadline")
+		return
+	}
+	ws.MaxPayloadBytes = wsMaxClientFrame
+
+	sub := h.hub.Subscribe(userID)
+	defer sub.Close()
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		h.readFrames(ctx, ws, userID)
+	}()
+
+	h.pump(ctx, sub, userID, token, closed, func(e realtime.Event) error {
+		if err := ws.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+			return err
+		}
+		return websocket.JSON.Send(ws, e)
+	})
+}
+
+// pump sends the subscription's events and periodic heartbeats until the
+// client goes away, a write fails or the access token stops being valid
+func (h *RealtimeHandler) pump(ctx context.Context, sub *infraRealtime.Subscription, userID uuid.UUID, token string, closed <-chan struct{}, send func(realtime.Event) error) {
+	logger := h.logger.WithField("user_id", userID)
+	logger.Debug("Real-time client connected")
+	defer logger.Debug("Real-time client disconnected")
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+	revalidate := time.NewTicker(streamRevalidateInterval)
+	defer revalidate.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case e, ok := <-sub.Events():
+			if !ok {
+				// Dropped by the hub for falling behind; the client reconnects
+				return
+			}
+			if err := send(e); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := send(realtime.Event{Type: realtime.TypeHeartbeat}); err != nil {
+				return
+			}
+		case <-revalidate.C:
+			if _, err := h.authService.ValidateToken(ctx, token); err != nil {
+				// Signed out or expired; the client reconnects with a fresh token
+				return
+			}
+		}
+	}
+}
+
+// readFrames handles frames sent by the client until the connection closes
+func (h *RealtimeHandler) readFrames(ctx context.Context, ws *websocket.Conn, userID uuid.UUID) {
+	lastTyping := make(map[uuid.UUID]time.Time)
+
+	for {
+		var frame clientFrame
+		if err := websocket.JSON.Receive(ws, &frame); err != nil {
+			return
+		}
+
+		switch frame.Type {
+		case clientFrameTyping:
+			if time.Since(lastTyping[frame.ConversationID]) < wsTypingThrottle {
+				continue
+			}
+			lastTyping[frame.ConversationID] = time.Now()
+
+			if err := h.messagingService.NotifyTyping(ctx, frame.ConversationID, userID); err != nil {
+				h.logger.WithError(err).WithFields(logrus.Fields{
+					"user_id":         userID,
+					"conversation_id": frame.ConversationID,
+				}).Debug("Ignored typing indicator")
+			}
+		}
+	}
+}
+
+// parseLastEventID reads the ID of the last event a reconnecting client received
+func parseLastEventID(c *gin.Context) (int64, error) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, realtime.ErrInvalidLastEventID
+	}
+	return id, nil
+}
+
+// writeServerSentEvent writes an event in the text/event-stream format. Event
+// data is single-line JSON, so it fits in one data field.
+func writeServerSentEvent(w io.Writer, e realtime.Event) error {
+	data := e.Data
+	if len(data) == 0 {
+		data = []byte("{}")
+	}
+
+	var err error
+	if e.ID != 0 {
+		_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	} else {
+		_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	return nil
+}
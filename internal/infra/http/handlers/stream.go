@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+)
+
+// streamWriter gives every write to a long-running HTTP response its own
+// deadline, for responses such as event streams and large downloads that can
+// take longer to send than the server's WriteTimeout allows. A client that
+// stops reading is still dropped after the timeout.
+type streamWriter struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	timeout time.Duration
+}
+
+// newStreamWriter replaces the response's overall write deadline with one
+// per write. It fails if the response does not support deadlines.
+func newStreamWriter(w http.ResponseWriter, timeout time.Duration) (*streamWriter, error) {
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	return &streamWriter{w: w, rc: rc, timeout: timeout}, nil
+}
+
+// Write extends the deadline before passing p on to the response
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if err := s.rc.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil {
+		return 0, err
+	}
+	return s.w.Write(p)
+}
+
+// Flush sends buffered data to the client
+func (s *streamWriter) Flush() error {
+	if err := s.rc.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
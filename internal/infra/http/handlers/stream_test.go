@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamWriter_OutlastsServerWriteTimeout(t *testing.T) {
+	var chunks []string
+	for i := 0; i < 4; i++ {
+		chunks = append(chunks, strings.Repeat(string(rune('a'+i)), 64<<10))
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw, err := newStreamWriter(w, time.Second)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, chunk := range chunks {
+			// Like photos fetched from storage far away
+			time.Sleep(100 * time.Millisecond)
+			if _, err := io.WriteString(sw, chunk); err != nil {
+				return
+			}
+			if err := sw.Flush(); err != nil {
+				return
+			}
+		}
+	}))
+	// Sending every chunk takes longer than the server allows a response
+	server.Config.WriteTimeout = 250 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join(chunks, ""), string(body))
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	appTag "jointrip/internal/app/tag"
+	"jointrip/internal/domain/tag"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/infra/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// TagHandler handles trip tag HTTP requests
+type TagHandler struct {
+	tagService *appTag.Service
+	logger     *logrus.Logger
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(tagService *appTag.Service, logger *logrus.Logger) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+		logger:     logger,
+	}
+}
+
+// TagRequest represents a tag creation or update request
+type TagRequest struct {
+	Name        string       `json:"name" binding:"required"`
+	Category    tag.Category `json:"category" binding:"required"`
+	Description string       `json:"description"`
+	Color       string       `json:"color"`
+}
+
+// SetTagActiveRequest represents a request to activate or retire a tag
+type SetTagActiveRequest struct {
+	IsActive *bool `json:"is_active" binding:"required"`
+}
+
+// SetTripTagsRequest represents a request to replace a trip's tags
+type SetTripTagsRequest struct {
+	TagIDs []uuid.UUID `json:"tag_ids"`
+}
+
+// ListTags returns the active tag taxonomy
+func (h *TagHandler) ListTags(c *gin.Context) {
+	filter := tag.ListFilter{}
+	if value := c.Query("category"); value != "" {
+		category := tag.Category(value)
+		filter.Category = &category
+	}
+
+	tags, err := h.tagService.ListTags(c.Request.Context(), filter)
+	if err != nil {
+		h.respondError(c, err, "Failed to list tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tags": tags,
+	})
+}
+
+// ListPopularTags returns the most used tags with their usage counts
+func (h *TagHandler) ListPopularTags(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	usages, err := h.tagService.ListPopularTags(c.Request.Context(), limit)
+	if err != nil {
+		h.respondError(c, err, "Failed to list popular tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tags": usages,
+	})
+}
+
+// AdminListTags returns every tag including retired ones
+func (h *TagHandler) AdminListTags(c *gin.Context) {
+	filter := tag.ListFilter{IncludeInactive: true}
+	if value := c.Query("category"); value != "" {
+		category := tag.Category(value)
+		filter.Category = &category
+	}
+
+	tags, err := h.tagService.ListTags(c.Request.Context(), filter)
+	if err != nil {
+		h.respondError(c, err, "Failed to list tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tags": tags,
+	})
+}
+
+// CreateTag adds a tag to the taxonomy (admin only)
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	t, err := h.tagService.CreateTag(c.Request.Context(), appTag.TagInput{
+		Name:        req.Name,
+		Category:    req.Category,
+		Description: req.Description,
+		Color:       req.Color,
+	})
+	if err != nil {
+		h.respondError(c, err, "Failed to create tag")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"tag_id":   t.ID,
+		"tag_name": t.Name,
+	}).Info("Tag created successfully")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"tag": t,
+	})
+}
+
+// UpdateTag changes a tag in the taxonomy (admin only)
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	tagID, ok := parseUUIDParam(c, "tag_id", "Invalid tag ID")
+	if !ok {
+		return
+	}
+
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	t, err := h.tagService.UpdateTag(c.Request.Context(), tagID, appTag.TagInput{
+		Name:        req.Name,
+		Category:    req.Category,
+		Description: req.Description,
+		Color:       req.Color,
+	})
+	if err != nil {
+		h.respondError(c, err, "Failed to update tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tag": t,
+	})
+}
+
+// SetTagActive activates or retires a tag (admin only)
+func (h *TagHandler) SetTagActive(c *gin.Context) {
+	tagID, ok := parseUUIDParam(c, "tag_id", "Invalid tag ID")
+	if !ok {
+		return
+	}
+
+	var req SetTagActiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	t, err := h.tagService.SetTagActive(c.Request.Context(), tagID, *req.IsActive)
+	if err != nil {
+		h.respondError(c, err, "Failed to update tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tag": t,
+	})
+}
+
+// GetTripTags returns the tags attached to a trip
+func (h *TagHandler) GetTripTags(c *gin.Context) {
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	tags, err := h.tagService.GetTripTags(c.Request.Context(), tripID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get trip tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trip_id": tripID,
+		"tags":    tags,
+	})
+}
+
+// SetTripTags replaces the tags attached to a trip (creator only)
+func (h *TagHandler) SetTripTags(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var req SetTripTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	tags, err := h.tagService.SetTripTags(c.Request.Context(), tripID, userID, req.TagIDs)
+	if err != nil {
+		h.respondError(c, err, "Failed to set trip tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trip_id": tripID,
+		"tags":    tags,
+	})
+}
+
+// RemoveTripTag detaches a tag from a trip (creator only)
+func (h *TagHandler) RemoveTripTag(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	tagID, ok := parseUUIDParam(c, "tag_id", "Invalid tag ID")
+	if !ok {
+		return
+	}
+
+	if err := h.tagService.RemoveTripTag(c.Request.Context(), tripID, userID, tagID); err != nil {
+		h.respondError(c, err, "Failed to remove trip tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Tag removed from trip",
+	})
+}
+
+// respondError maps tag and trip domain errors to HTTP responses
+func (h *TagHandler) respondError(c *gin.Context, err error, logMessage string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, tag.ErrTagNotFound),
+		errors.Is(err, trip.ErrTripNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, trip.ErrNotOrganizer):
+		status = http.StatusForbidden
+	case errors.Is(err, tag.ErrTagAlreadyExists):
+		status = http.StatusConflict
+	case errors.Is(err, tag.ErrTagInactive),
+		errors.Is(err, tag.ErrTooManyTags),
+		errors.Is(err, tag.ErrInvalidCategory),
+		errors.Is(err, tag.ErrUnknownTravelStyle),
+		errors.Is(err, tag.ErrInvalidTagName),
+		errors.Is(err, tag.ErrInvalidTagColor):
+		status = http.StatusUnprocessableEntity
+	}
+
+	if status == http.StatusInternalServerError {
+		h.logger.WithError(err).Error(logMessage)
+		c.JSON(status, gin.H{
+			"error": logMessage,
+		})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	appTemplate "jointrip/internal/app/template"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/infra/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// TemplateHandler handles trip cloning and template HTTP requests
+type TemplateHandler struct {
+	templateService *appTemplate.Service
+	logger          *logrus.Logger
+}
+
+// NewTemplateHandler creates a new template handler
+func NewTemplateHandler(templateService *appTemplate.Service, logger *logrus.Logger) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+		logger:          logger,
+	}
+}
+
+// CloneTripRequest represents a request to clone a trip
+type CloneTripRequest struct {
+	OffsetDays int `json:"offset_days"`
+}
+
+// SaveTemplateRequest represents a request to save a trip as a template
+type SaveTemplateRequest struct {
+	Name string `json:"name"`
+}
+
+// TemplateVisibilityRequest represents a request to publish or unpublish a template
+type TemplateVisibilityRequest struct {
+	IsPublic *bool `json:"is_public" binding:"required"`
+}
+
+// UseTemplateRequest represents a request to start a trip from a template
+type UseTemplateRequest struct {
+	StartDate string `json:"start_date" binding:"required"`
+}
+
+// CloneTrip copies a trip into a new draft with dates shifted by an offset (organizer only)
+func (h *TemplateHandler) CloneTrip(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var req CloneTripRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	clone, err := h.templateService.CloneTrip(c.Request.Context(), tripID, userID, req.OffsetDays)
+	if err != nil {
+		h.respondError(c, err, "Failed to clone trip")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"source_trip_id": tripID,
+		"trip_id":        clone.ID,
+	}).Info("Trip cloned")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"trip": clone,
+	})
+}
+
+// SaveTemplate saves a trip as a private template (organizer only)
+func (h *TemplateHandler) SaveTemplate(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var req SaveTemplateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request format",
+			})
+			return
+		}
+	}
+
+	tpl, err := h.templateService.SaveTemplate(c.Request.Context(), tripID, userID, req.Name)
+	if err != nil {
+		h.respondError(c, err, "Failed to save template")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"template": tpl,
+	})
+}
+
+// ListPublicTemplates returns published templates
+func (h *TemplateHandler) ListPublicTemplates(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+
+	templates, err := h.templateService.ListPublicTemplates(c.Request.Context(), limit, offset)
+	if err != nil {
+		h.respondError(c, err, "Failed to list templates")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"templates": templates,
+	})
+}
+
+// ListMyTemplates returns the current user's templates
+func (h *TemplateHandler) ListMyTemplates(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	templates, err := h.templateService.ListMyTemplates(c.Request.Context(), userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to list templates")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"templates": templates,
+	})
+}
+
+// GetTemplate returns a single template
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	templateID, ok := parseUUIDParam(c, "template_id", "Invalid template ID")
+	if !ok {
+		return
+	}
+
+	tpl, err := h.templateService.GetTemplate(c.Request.Context(), templateID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get template")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"template": tpl,
+	})
+}
+
+// SetTemplateVisibility publishes or unpublishes a template (owner only)
+func (h *TemplateHandler) SetTemplateVisibility(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	templateID, ok := parseUUIDParam(c, "template_id", "Invalid template ID")
+	if !ok {
+		return
+	}
+
+	var req TemplateVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	tpl, err := h.templateService.SetTemplatePublic(c.Request.Context(), templateID, userID, *req.IsPublic)
+	if err != nil {
+		h.respondError(c, err, "Failed to update template")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"template": tpl,
+	})
+}
+
+// DeleteTemplate removes a template (owner only)
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	templateID, ok := parseUUIDParam(c, "template_id", "Invalid template ID")
+	if !ok {
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(c.Request.Context(), templateID, userID); err != nil {
+		h.respondError(c, err, "Failed to delete template")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Template deleted",
+	})
+}
+
+// UseTemplate starts a new draft trip from a template
+func (h *TemplateHandler) UseTemplate(c *gin.Context) {
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	if !currentUser.CanCreateTrips() {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "User cannot create trips",
+		})
+		return
+	}
+
+	templateID, ok := parseUUIDParam(c, "template_id", "Invalid template ID")
+	if !ok {
+		return
+	}
+
+	var req UseTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid start date, expected YYYY-MM-DD",
+		})
+		return
+	}
+
+	t, err := h.templateService.CreateTripFromTemplate(c.Request.Context(), templateID, currentUser.ID, startDate)
+	if err != nil {
+		h.respondError(c, err, "Failed to create trip from template")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"trip": t,
+	})
+}
+
+// respondError maps template and trip domain errors to HTTP responses
+func (h *TemplateHandler) respondError(c *gin.Context, err error, logMessage string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, trip.ErrTripNotFound),
+		errors.Is(err, trip.ErrTemplateNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, trip.ErrNotOrganizer),
+		errors.Is(err, trip.ErrNotTemplateOwner):
+		status = http.StatusForbidden
+	}
+
+	if status == http.StatusInternalServerError {
+		h.logger.WithError(err).Error(logMessage)
+		c.JSON(status, gin.H{
+			"error": logMessage,
+		})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,824 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	appTrip "jointrip/internal/app/trip"
+	"jointrip/internal/domain/tag"
+	"jointrip/internal/domain/trip"
+	"jointrip/internal/infra/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// TripHandler handles trip and participation HTTP requests
+type TripHandler struct {
+	tripService *appTrip.Service
+	logger      *logrus.Logger
+}
+
+// NewTripHandler creates a new trip handler
+func NewTripHandler(tripService *appTrip.Service, logger *logrus.Logger) *TripHandler {
+	return &TripHandler{
+		tripService: tripService,
+		logger:      logger,
+	}
+}
+
+// CreateTripRequest represents a trip creation request
+type CreateTripRequest struct {
+	Title              string  `json:"title" binding:"required"`
+	Description        string  `json:"description"`
+	DestinationCountry string  `json:"destination_country" binding:"required"`
+	DestinationCity    string  `json:"destination_city"`
+	StartDate          string  `json:"start_date" binding:"required"`
+	EndDate            string  `json:"end_date" binding:"required"`
+	MaxParticipants    int     `json:"max_participants" binding:"required,min=2"`
+	EstimatedBudget    float64 `json:"estimated_budget"`
+	Currency           string  `json:"currency"`
+	TripType           string  `json:"trip_type"`
+	IsPublic           *bool   `json:"is_public,omitempty"`
+	Draft              bool    `json:"draft"`
+}
+
+// UpdateTripRequest represents a trip update request
+type UpdateTripRequest struct {
+	Title              string  `json:"title" binding:"required"`
+	Description        string  `json:"description"`
+	DestinationCountry string  `json:"destination_country" binding:"required"`
+	DestinationCity    string  `json:"destination_city"`
+	StartDate          string  `json:"start_date" binding:"required"`
+	EndDate            string  `json:"end_date" binding:"required"`
+	EstimatedBudget    float64 `json:"estimated_budget"`
+	TripType           string  `json:"trip_type"`
+	IsPublic           *bool   `json:"is_public,omitempty"`
+}
+
+// ItineraryItemRequest represents an itinerary item creation or update request
+type ItineraryItemRequest struct {
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	Location    string     `json:"location"`
+	StartsAt    time.Time  `json:"starts_at" binding:"required"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+}
+
+// JoinTripRequest represents a request to join a trip
+type JoinTripRequest struct {
+	Notes string `json:"notes"`
+}
+
+// CreateTrip publishes a new trip
+func (h *TripHandler) CreateTrip(c *gin.Context) {
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	if !currentUser.CanCreateTrips() {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "User cannot create trips",
+		})
+		return
+	}
+
+	var req CreateTripRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid start date, expected YYYY-MM-DD",
+		})
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid end date, expected YYYY-MM-DD",
+		})
+		return
+	}
+
+	isPublic := true
+	if req.IsPublic != nil {
+		isPublic = *req.IsPublic
+	}
+
+	newTrip, err := h.tripService.CreateTrip(c.Request.Context(), currentUser.ID, appTrip.CreateTripInput{
+		Title:              req.Title,
+		Description:        req.Description,
+		DestinationCountry: req.DestinationCountry,
+		DestinationCity:    req.DestinationCity,
+		StartDate:          startDate,
+		EndDate:            endDate,
+		MaxParticipants:    req.MaxParticipants,
+		EstimatedBudget:    req.EstimatedBudget,
+		Currency:           req.Currency,
+		TripType:           req.TripType,
+		IsPublic:           isPublic,
+		Draft:              req.Draft,
+	})
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to create trip")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"trip_id":    newTrip.ID,
+		"creator_id": currentUser.ID,
+	}).Info("Trip created successfully")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"trip": newTrip,
+	})
+}
+
+// GetTrip returns a single trip
+func (h *TripHandler) GetTrip(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	t, err := h.tripService.GetTrip(c.Request.Context(), tripID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get trip")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trip": t,
+	})
+}
+
+// UpdateTrip changes a trip's details (organizer only)
+func (h *TripHandler) UpdateTrip(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var req UpdateTripRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid start date, expected YYYY-MM-DD",
+		})
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid end date, expected YYYY-MM-DD",
+		})
+		return
+	}
+
+	isPublic := true
+	if req.IsPublic != nil {
+		isPublic = *req.IsPublic
+	}
+
+	t, err := h.tripService.UpdateTrip(c.Request.Context(), tripID, userID, appTrip.UpdateTripInput{
+		Title:              req.Title,
+		Description:        req.Description,
+		DestinationCountry: req.DestinationCountry,
+		DestinationCity:    req.DestinationCity,
+		StartDate:          startDate,
+		EndDate:            endDate,
+		EstimatedBudget:    req.EstimatedBudget,
+		TripType:           req.TripType,
+		IsPublic:           isPublic,
+	})
+	if err != nil {
+		h.respondError(c, err, "Failed to update trip")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trip": t,
+	})
+}
+
+// PublishTrip publishes a draft trip (organizer only)
+func (h *TripHandler) PublishTrip(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	t, err := h.tripService.PublishTrip(c.Request.Context(), tripID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to publish trip")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trip": t,
+	})
+}
+
+// SearchTrips lists public trips filtered by destination, dates, budget, type and tags
+func (h *TripHandler) SearchTrips(c *gin.Context) {
+	filter := trip.SearchFilter{
+		Destination: strings.TrimSpace(c.Query("destination")),
+		TripType:    c.Query("trip_type"),
+		TagMatch:    trip.TagMatch(c.DefaultQuery("tag_match", string(trip.TagMatchAny))),
+	}
+
+	if filter.TagMatch != trip.TagMatchAny && filter.TagMatch != trip.TagMatchAll {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid tag_match, expected any or all",
+		})
+		return
+	}
+
+	if value := c.Query("starts_after"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid starts_after date, expected YYYY-MM-DD",
+			})
+			return
+		}
+		filter.StartsAfter = &date
+	}
+	if value := c.Query("ends_before"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid ends_before date, expected YYYY-MM-DD",
+			})
+			return
+		}
+		filter.EndsBefore = &date
+	}
+	if value := c.Query("max_budget"); value != "" {
+		budget, err := strconv.ParseFloat(value, 64)
+		if err != nil || budget < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid max_budget",
+			})
+			return
+		}
+		filter.MaxBudget = &budget
+	}
+	if value := c.Query("tags"); value != "" {
+		for _, name := range strings.Split(value, ",") {
+			if name = tag.NormalizeName(name); name != "" {
+				filter.Tags = append(filter.Tags, name)
+			}
+		}
+	}
+
+	filter.Limit, _ = strconv.Atoi(c.Query("limit"))
+	filter.Offset, _ = strconv.Atoi(c.Query("offset"))
+	filter.Normalize()
+
+	trips, err := h.tripService.SearchTrips(c.Request.Context(), filter)
+	if err != nil {
+		h.respondError(c, err, "Failed to search trips")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trips":  trips,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
+// ListParticipants returns the participants of a trip
+func (h *TripHandler) ListParticipants(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	participants, err := h.tripService.ListParticipants(c.Request.Context(), tripID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to list participants")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trip_id":      tripID,
+		"participants": participants,
+	})
+}
+
+// JoinTrip requests to join a trip, queueing on the waitlist when it is full
+func (h *TripHandler) JoinTrip(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var req JoinTripRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request format",
+			})
+			return
+		}
+	}
+
+	result, err := h.tripService.RequestJoin(c.Request.Context(), tripID, userID, req.Notes)
+	if err != nil {
+		h.respondError(c, err, "Failed to request trip join")
+		return
+	}
+
+	if result.Waitlisted() {
+		h.logger.WithFields(logrus.Fields{
+			"trip_id": tripID,
+			"user_id": userID,
+		}).Info("Join request added to trip waitlist")
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":        "Trip is full, you have been added to the waitlist",
+			"waitlist_entry": result.WaitlistEntry,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     "Join request sent",
+		"participant": result.Participant,
+	})
+}
+
+// ApproveParticipant approves a pending join request
+func (h *TripHandler) ApproveParticipant(c *gin.Context) {
+	h.reviewParticipant(c, h.tripService.ApproveParticipant, "Participant approved")
+}
+
+// RejectParticipant rejects a pending join request
+func (h *TripHandler) RejectParticipant(c *gin.Context) {
+	h.reviewParticipant(c, h.tripService.RejectParticipant, "Participant rejected")
+}
+
+// RemoveParticipant takes an approved participant off the trip
+func (h *TripHandler) RemoveParticipant(c *gin.Context) {
+	h.reviewParticipant(c, h.tripService.RemoveParticipant, "Participant removed")
+}
+
+// LeaveTrip withdraws the current user from a trip
+func (h *TripHandler) LeaveTrip(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	if err := h.tripService.LeaveTrip(c.Request.Context(), tripID, userID); err != nil {
+		h.respondError(c, err, "Failed to leave trip")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "You have left the trip",
+	})
+}
+
+// GetWaitlist returns the trip's waitlist (organizer only)
+func (h *TripHandler) GetWaitlist(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	entries, err := h.tripService.GetWaitlist(c.Request.Context(), tripID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get waitlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trip_id":  tripID,
+		"waitlist": entries,
+	})
+}
+
+// GetMyWaitlistEntry returns the current user's waitlist entry for a trip
+func (h *TripHandler) GetMyWaitlistEntry(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	entry, err := h.tripService.GetWaitlistEntry(c.Request.Context(), tripID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get waitlist entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"waitlist_entry": entry,
+	})
+}
+
+// LeaveWaitlist removes the current user from a trip's waitlist
+func (h *TripHandler) LeaveWaitlist(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	if err := h.tripService.LeaveWaitlist(c.Request.Context(), tripID, userID); err != nil {
+		h.respondError(c, err, "Failed to leave waitlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "You have left the waitlist",
+	})
+}
+
+// AcceptWaitlistOffer accepts the seat offered to the current user
+func (h *TripHandler) AcceptWaitlistOffer(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	participant, err := h.tripService.AcceptWaitlistOffer(c.Request.Context(), tripID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to accept waitlist offer")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"trip_id": tripID,
+		"user_id": userID,
+	}).Info("Waitlist offer accepted")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Seat accepted",
+		"participant": participant,
+	})
+}
+
+// DeclineWaitlistOffer declines the seat offered to the current user
+func (h *TripHandler) DeclineWaitlistOffer(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	if err := h.tripService.DeclineWaitlistOffer(c.Request.Context(), tripID, userID); err != nil {
+		h.respondError(c, err, "Failed to decline waitlist offer")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Seat offer declined",
+	})
+}
+
+// ListItinerary returns a trip's itinerary
+func (h *TripHandler) ListItinerary(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	items, err := h.tripService.ListItinerary(c.Request.Context(), tripID, userID)
+	if err != nil {
+		h.respondError(c, err, "Failed to list itinerary")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trip_id":   tripID,
+		"itinerary": items,
+	})
+}
+
+// AddItineraryItem adds an item to a trip's itinerary (organizer only)
+func (h *TripHandler) AddItineraryItem(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	var req ItineraryItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	item, err := h.tripService.AddItineraryItem(c.Request.Context(), tripID, userID, appTrip.ItineraryItemInput{
+		Title:       req.Title,
+		Description: req.Description,
+		Location:    req.Location,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+	})
+	if err != nil {
+		h.respondError(c, err, "Failed to add itinerary item")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"item": item,
+	})
+}
+
+// UpdateItineraryItem changes an itinerary item (organizer only)
+func (h *TripHandler) UpdateItineraryItem(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	itemID, ok := parseUUIDParam(c, "item_id", "Invalid itinerary item ID")
+	if !ok {
+		return
+	}
+
+	var req ItineraryItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	item, err := h.tripService.UpdateItineraryItem(c.Request.Context(), tripID, userID, itemID, appTrip.ItineraryItemInput{
+		Title:       req.Title,
+		Description: req.Description,
+		Location:    req.Location,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+	})
+	if err != nil {
+		h.respondError(c, err, "Failed to update itinerary item")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"item": item,
+	})
+}
+
+// DeleteItineraryItem removes an itinerary item (organizer only)
+func (h *TripHandler) DeleteItineraryItem(c *gin.Context) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	itemID, ok := parseUUIDParam(c, "item_id", "Invalid itinerary item ID")
+	if !ok {
+		return
+	}
+
+	if err := h.tripService.DeleteItineraryItem(c.Request.Context(), tripID, userID, itemID); err != nil {
+		h.respondError(c, err, "Failed to delete itinerary item")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Itinerary item deleted",
+	})
+}
+
+// reviewParticipant runs an organizer decision on a join request
+func (h *TripHandler) reviewParticipant(
+	c *gin.Context,
+	review func(ctx context.Context, tripID, organizerID, participantID uuid.UUID) (*trip.Participant, error),
+	message string,
+) {
+	userID, err := middleware.GetCurrentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication required",
+		})
+		return
+	}
+
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID")
+	if !ok {
+		return
+	}
+
+	participantID, ok := parseUUIDParam(c, "participant_id", "Invalid participant ID")
+	if !ok {
+		return
+	}
+
+	participant, err := review(c.Request.Context(), tripID, userID, participantID)
+	if err != nil {
+		h.respondError(c, err, "Failed to review participant")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     message,
+		"participant": participant,
+	})
+}
+
+// respondError maps trip domain errors to HTTP responses
+func (h *TripHandler) respondError(c *gin.Context, err error, logMessage string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, trip.ErrTripNotFound),
+		errors.Is(err, trip.ErrParticipantNotFound),
+		errors.Is(err, trip.ErrWaitlistEntryNotFound),
+		errors.Is(err, trip.ErrItineraryItemNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, trip.ErrNotOrganizer),
+		errors.Is(err, trip.ErrInviteRequired):
+		status = http.StatusForbidden
+	case errors.Is(err, trip.ErrTripFull),
+		errors.Is(err, trip.ErrAlreadyParticipant),
+		errors.Is(err, trip.ErrAlreadyWaitlisted),
+		errors.Is(err, trip.ErrInvalidParticipantState),
+		errors.Is(err, trip.ErrInvalidWaitlistState),
+		errors.Is(err, trip.ErrNoPendingOffer),
+		errors.Is(err, trip.ErrTripNotDraft):
+		status = http.StatusConflict
+	case errors.Is(err, trip.ErrOfferExpired):
+		status = http.StatusGone
+	case errors.Is(err, trip.ErrTripNotJoinable),
+		errors.Is(err, trip.ErrInvalidTripDetails),
+		errors.Is(err, trip.ErrCannotRemoveCreator),
+		errors.Is(err, trip.ErrItemOutsideTrip),
+		errors.Is(err, trip.ErrInvalidItemTimes):
+		status = http.StatusUnprocessableEntity
+	}
+
+	if status == http.StatusInternalServerError {
+		h.logger.WithError(err).Error(logMessage)
+		c.JSON(status, gin.H{
+			"error": logMessage,
+		})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
+
+// parseUUIDParam parses a UUID path parameter, writing a 400 response on failure
+func parseUUIDParam(c *gin.Context, name, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": message,
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware restricts routes to configured administrators
+type AdminMiddleware struct {
+	emails map[string]bool
+}
+
+// NewAdminMiddleware creates a new admin middleware from a list of admin emails
+func NewAdminMiddleware(emails []string) *AdminMiddleware {
+	m := &AdminMiddleware{emails: make(map[string]bool, len(emails))}
+	for _, email := range emails {
+		m.emails[strings.ToLower(strings.TrimSpace(email))] = true
+	}
+	return m
+}
+
+// RequireAdmin middleware that requires an authenticated administrator.
+// It must run after RequireAuth.
+func (m *AdminMiddleware) RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentUser, err := GetCurrentUser(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "User not authenticated",
+			})
+			c.Abort()
+			return
+		}
+
+		if !m.emails[strings.ToLower(currentUser.Email)] {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Administrator access required",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
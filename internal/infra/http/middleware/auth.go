@@ -67,8 +67,19 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	}
 }
 
+// TokenQueryParam is the query parameter that carries the access token for
+// clients that cannot set headers
+const TokenQueryParam = "token"
+
 // extractToken extracts the bearer token from the request
 func (m *AuthMiddleware) extractToken(c *gin.Context) string {
+	return ExtractToken(c)
+}
+
+// ExtractToken returns the access token from the Authorization header or the
+// token query parameter, which clients that cannot set headers (such as
+// browser WebSockets) use instead
+func ExtractToken(c *gin.Context) string {
 	// Try Authorization header first
 	authHeader := c.GetHeader("Authorization")
 	if authHeader != "" {
@@ -79,7 +90,7 @@ func (m *AuthMiddleware) extractToken(c *gin.Context) string {
 	}
 
 	// Try query parameter as fallback
-	return c.Query("token")
+	return c.Query(TokenQueryParam)
 }
 
 // GetCurrentUser helper function to get current user from context
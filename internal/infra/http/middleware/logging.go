@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"net/url"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -57,8 +58,8 @@ func (m *LoggingMiddleware) RequestLogger() gin.HandlerFunc {
 		logEntry := m.logger.WithFields(logrus.Fields{
 			"request_id":   requestID,
 			"method":       c.Request.Method,
-			"path":         c.Request.URL.Path,
-			"query":        c.Request.URL.RawQuery,
+			"path":         loggedPath(c),
+			"query":        loggedQuery(c.Request.URL),
 			"status_code":  statusCode,
 			"latency_ms":   latency.Milliseconds(),
 			"client_ip":    clientIP,
@@ -85,6 +86,49 @@ func (m *LoggingMiddleware) RequestLogger() gin.HandlerFunc {
 	}
 }
 
+// secretQueryParams are the query parameters that must never end up in the
+// logs: the access token, and the signature and expiry of a signed file link
+var secretQueryParams = []string{TokenQueryParam, "signature", "expires"}
+
+// loggedQuery returns the query string of a request without its secret
+// parameters. Pairs that cannot be parsed are dropped too, as there is no
+// telling what they hold.
+func loggedQuery(u *url.URL) string {
+	query, err := url.ParseQuery(u.RawQuery)
+	if err == nil && !hasAny(query, secretQueryParams) {
+		return u.RawQuery
+	}
+
+	for _, param := range secretQueryParams {
+		query.Del(param)
+	}
+	return query.Encode()
+}
+
+// hasAny returns true if the query holds any of the parameters
+func hasAny(query url.Values, params []string) bool {
+	for _, param := range params {
+		if query.Has(param) {
+			return true
+		}
+	}
+	return false
+}
+
+// secretPathParam is the route parameter that carries a bearer secret, such
+// as a calendar feed token, an invite token or a signed report link.
+const secretPathParam = "token"
+
+// loggedPath returns the request path, or the route template when the path
+// holds a secret. Calendar feed and invite tokens stay valid until revoked,
+// so logging them would hand out working credentials.
+func loggedPath(c *gin.Context) string {
+	if _, ok := c.Params.Get(secretPathParam); ok {
+		return c.FullPath()
+	}
+	return c.Request.URL.Path
+}
+
 // ErrorLogger middleware that logs errors
 func (m *LoggingMiddleware) ErrorLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -102,7 +146,7 @@ func (m *LoggingMiddleware) ErrorLogger() gin.HandlerFunc {
 			logEntry := m.logger.WithFields(logrus.Fields{
 				"request_id": requestID,
 				"method":     c.Request.Method,
-				"path":       c.Request.URL.Path,
+				"path":       loggedPath(c),
 				"error_type": err.Type,
 			})
 
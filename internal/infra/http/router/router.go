@@ -3,10 +3,24 @@ package router
 import (
 	"io"
 	"io/fs"
+	appAlbum "jointrip/internal/app/album"
 	"jointrip/internal/app/auth"
+	"jointrip/internal/app/calendar"
+	"jointrip/internal/app/comment"
+	appExpense "jointrip/internal/app/expense"
+	"jointrip/internal/app/messaging"
+	"jointrip/internal/app/notification"
+	appPush "jointrip/internal/app/push"
+	"jointrip/internal/app/rating"
+	appRealtime "jointrip/internal/app/realtime"
+	"jointrip/internal/app/tag"
+	"jointrip/internal/app/template"
+	"jointrip/internal/app/trip"
+	"jointrip/internal/domain/blob"
 	"jointrip/internal/infra/config"
 	"jointrip/internal/infra/http/handlers"
 	"jointrip/internal/infra/http/middleware"
+	"jointrip/internal/infra/realtime"
 	"net/http"
 	"strings"
 	"time"
@@ -17,17 +31,45 @@ import (
 
 // Router wraps the Gin router with our application routes
 type Router struct {
-	engine         *gin.Engine
-	authHandler    *handlers.AuthHandler
-	ratingHandler  *handlers.RatingHandler
-	authMiddleware *middleware.AuthMiddleware
-	webFS          fs.FS
+	engine              *gin.Engine
+	authHandler         *handlers.AuthHandler
+	ratingHandler       *handlers.RatingHandler
+	tripHandler         *handlers.TripHandler
+	tagHandler          *handlers.TagHandler
+	calendarHandler     *handlers.CalendarHandler
+	templateHandler     *handlers.TemplateHandler
+	inviteHandler       *handlers.InviteHandler
+	messageHandler      *handlers.MessageHandler
+	commentHandler      *handlers.CommentHandler
+	expenseHandler      *handlers.ExpenseHandler
+	albumHandler        *handlers.AlbumHandler
+	realtimeHandler     *handlers.RealtimeHandler
+	notificationHandler *handlers.NotificationHandler
+	pushHandler         *handlers.PushHandler
+	fileHandler         *handlers.FileHandler
+	authMiddleware      *middleware.AuthMiddleware
+	adminMiddleware     *middleware.AdminMiddleware
+	webFS               fs.FS
 }
 
 // NewRouter creates a new router with all routes configured
 func NewRouter(
 	cfg *config.Config,
 	authService *auth.Service,
+	tripService *trip.Service,
+	tagService *tag.Service,
+	calendarService *calendar.Service,
+	templateService *template.Service,
+	messagingService *messaging.Service,
+	commentService *comment.Service,
+	ratingService *rating.Service,
+	expenseService *appExpense.Service,
+	albumService *appAlbum.Service,
+	notificationService *notification.Service,
+	pushService *appPush.Service,
+	realtimeService *appRealtime.Service,
+	realtimeHub *realtime.Hub,
+	fileStore blob.Store,
 	logger *logrus.Logger,
 	webFS fs.FS,
 ) *Router {
@@ -43,6 +85,7 @@ func NewRouter(
 	// Create middleware
 	loggingMiddleware := middleware.NewLoggingMiddleware(logger)
 	authMiddleware := middleware.NewAuthMiddleware(authService)
+	adminMiddleware := middleware.NewAdminMiddleware(cfg.Admin.Emails)
 
 	// Add middleware
 	engine.Use(gin.Recovery())
@@ -64,15 +107,42 @@ func NewRouter(
 	})
 
 	// Create handlers
-	authHandler := handlers.NewAuthHandler(authService, logger)
-	ratingHandler := handlers.NewRatingHandler(logger)
+	authHandler := handlers.NewAuthHandler(authService, notificationService, logger)
+	ratingHandler := handlers.NewRatingHandler(ratingService, logger)
+	tripHandler := handlers.NewTripHandler(tripService, logger)
+	tagHandler := handlers.NewTagHandler(tagService, logger)
+	calendarHandler := handlers.NewCalendarHandler(calendarService, logger)
+	templateHandler := handlers.NewTemplateHandler(templateService, logger)
+	inviteHandler := handlers.NewInviteHandler(tripService, logger)
+	messageHandler := handlers.NewMessageHandler(messagingService, logger)
+	commentHandler := handlers.NewCommentHandler(commentService, logger)
+	expenseHandler := handlers.NewExpenseHandler(expenseService, logger)
+	albumHandler := handlers.NewAlbumHandler(albumService, logger)
+	notificationHandler := handlers.NewNotificationHandler(notificationService, logger)
+	pushHandler := handlers.NewPushHandler(pushService, logger)
+	realtimeHandler := handlers.NewRealtimeHandler(authService, messagingService, realtimeService, realtimeHub, logger)
+	fileHandler := handlers.NewFileHandler(fileStore, logger)
 
 	router := &Router{
-		engine:         engine,
-		authHandler:    authHandler,
-		ratingHandler:  ratingHandler,
-		authMiddleware: authMiddleware,
-		webFS:          webFS,
+		engine:              engine,
+		authHandler:         authHandler,
+		ratingHandler:       ratingHandler,
+		tripHandler:         tripHandler,
+		tagHandler:          tagHandler,
+		calendarHandler:     calendarHandler,
+		templateHandler:     templateHandler,
+		inviteHandler:       inviteHandler,
+		messageHandler:      messageHandler,
+		commentHandler:      commentHandler,
+		expenseHandler:      expenseHandler,
+		albumHandler:        albumHandler,
+		realtimeHandler:     realtimeHandler,
+		notificationHandler: notificationHandler,
+		pushHandler:         pushHandler,
+		fileHandler:         fileHandler,
+		authMiddleware:      authMiddleware,
+		adminMiddleware:     adminMiddleware,
+		webFS:               webFS,
 	}
 
 	router.setupRoutes()
@@ -101,6 +171,18 @@ func (r *Router) setupRoutes() {
 		auth.POST("/logout", r.authHandler.Logout)
 	}
 
+	// Calendar feed (public, authenticated by the secret token in the URL)
+	v1.GET("/calendar/feeds/:token", r.calendarHandler.GetFeed)
+
+	// Expense report downloads (public, authenticated by the signed token in the URL)
+	v1.GET("/expense-reports/:token", r.expenseHandler.DownloadReport)
+
+	// Stored files (public, authenticated by the signed link)
+	v1.GET("/files/*key", r.fileHandler.ServeFile)
+
+	// Profile photos (public, so that image tags can load them)
+	v1.GET("/users/:user_id/photo", r.authHandler.GetProfilePhoto)
+
 	// Protected routes (require authentication)
 	protected := v1.Group("/")
 	protected.Use(r.authMiddleware.RequireAuth())
@@ -116,6 +198,161 @@ func (r *Router) setupRoutes() {
 		protected.POST("/ratings", r.ratingHandler.CreateRating)
 		protected.GET("/ratings/my", r.ratingHandler.GetMyRatings)
 		protected.GET("/users/:user_id/ratings", r.ratingHandler.GetUserRatings)
+
+		// Trip routes
+		protected.GET("/trips", r.tripHandler.SearchTrips)
+		protected.POST("/trips", r.tripHandler.CreateTrip)
+		protected.GET("/trips/:id", r.tripHandler.GetTrip)
+		protected.PUT("/trips/:id", r.tripHandler.UpdateTrip)
+		protected.POST("/trips/:id/publish", r.tripHandler.PublishTrip)
+		protected.GET("/trips/:id/participants", r.tripHandler.ListParticipants)
+		protected.POST("/trips/:id/join", r.tripHandler.JoinTrip)
+		protected.POST("/trips/:id/leave", r.tripHandler.LeaveTrip)
+		protected.POST("/trips/:id/participants/:participant_id/approve", r.tripHandler.ApproveParticipant)
+		protected.POST("/trips/:id/participants/:participant_id/reject", r.tripHandler.RejectParticipant)
+		protected.POST("/trips/:id/participants/:participant_id/remove", r.tripHandler.RemoveParticipant)
+
+		// Trip waitlist routes
+		protected.GET("/trips/:id/waitlist", r.tripHandler.GetWaitlist)
+		protected.GET("/trips/:id/waitlist/me", r.tripHandler.GetMyWaitlistEntry)
+		protected.DELETE("/trips/:id/waitlist/me", r.tripHandler.LeaveWaitlist)
+		protected.POST("/trips/:id/waitlist/accept", r.tripHandler.AcceptWaitlistOffer)
+		protected.POST("/trips/:id/waitlist/decline", r.tripHandler.DeclineWaitlistOffer)
+
+		// Trip itinerary routes
+		protected.GET("/trips/:id/itinerary", r.tripHandler.ListItinerary)
+		protected.POST("/trips/:id/itinerary", r.tripHandler.AddItineraryItem)
+		protected.PUT("/trips/:id/itinerary/:item_id", r.tripHandler.UpdateItineraryItem)
+		protected.DELETE("/trips/:id/itinerary/:item_id", r.tripHandler.DeleteItineraryItem)
+
+		// Trip invite routes
+		protected.GET("/trips/:id/invite-links", r.inviteHandler.ListInviteLinks)
+		protected.POST("/trips/:id/invite-links", r.inviteHandler.CreateInviteLink)
+		protected.DELETE("/trips/:id/invite-links/:link_id", r.inviteHandler.RevokeInviteLink)
+		protected.GET("/trips/:id/invitations", r.inviteHandler.ListTripInvitations)
+		protected.POST("/trips/:id/invitations", r.inviteHandler.InviteUser)
+		protected.DELETE("/trips/:id/invitations/:invitation_id", r.inviteHandler.CancelInvitation)
+		protected.GET("/invites/:token", r.inviteHandler.PreviewInvite)
+		protected.POST("/invites/:token/redeem", r.inviteHandler.RedeemInvite)
+		protected.GET("/invitations", r.inviteHandler.ListMyInvitations)
+		protected.POST("/invitations/:invitation_id/accept", r.inviteHandler.AcceptInvitation)
+		protected.POST("/invitations/:invitation_id/decline", r.inviteHandler.DeclineInvitation)
+
+		// Trip cloning and template routes
+		protected.POST("/trips/:id/clone", r.templateHandler.CloneTrip)
+		protected.POST("/trips/:id/templates", r.templateHandler.SaveTemplate)
+		protected.GET("/templates", r.templateHandler.ListPublicTemplates)
+		protected.GET("/templates/mine", r.templateHandler.ListMyTemplates)
+		protected.GET("/templates/:template_id", r.templateHandler.GetTemplate)
+		protected.PUT("/templates/:template_id/visibility", r.templateHandler.SetTemplateVisibility)
+		protected.DELETE("/templates/:template_id", r.templateHandler.DeleteTemplate)
+		protected.POST("/templates/:template_id/trips", r.templateHandler.UseTemplate)
+
+		// Calendar routes
+		protected.GET("/trips/:id/calendar.ics", r.calendarHandler.ExportTrip)
+		protected.GET("/calendar/feed", r.calendarHandler.GetFeedToken)
+		protected.POST("/calendar/feed", r.calendarHandler.RotateFeedToken)
+		protected.DELETE("/calendar/feed", r.calendarHandler.RevokeFeedToken)
+
+		// Direct messaging routes
+		protected.GET("/conversations", r.messageHandler.ListConversations)
+		protected.POST("/conversations", r.messageHandler.StartConversation)
+		protected.GET("/conversations/:conversation_id", r.messageHandler.GetConversation)
+		protected.GET("/conversations/:conversation_id/messages", r.messageHandler.ListMessages)
+		protected.POST("/conversations/:conversation_id/messages", r.messageHandler.SendMessage)
+		protected.POST("/conversations/:conversation_id/read", r.messageHandler.MarkRead)
+
+		// Trip group chat routes
+		protected.GET("/trips/:id/chat", r.messageHandler.GetTripConversation)
+		protected.GET("/conversations/:conversation_id/pins", r.messageHandler.ListPinnedMessages)
+		protected.PUT("/conversations/:conversation_id/messages/:message_id/pin", r.messageHandler.PinMessage)
+		protected.DELETE("/conversations/:conversation_id/messages/:message_id/pin", r.messageHandler.UnpinMessage)
+
+		// Trip comment routes
+		protected.GET("/trips/:id/comments", r.commentHandler.ListComments)
+		protected.POST("/trips/:id/comments", r.commentHandler.CreateComment)
+		protected.PUT("/trips/:id/comments/:comment_id", r.commentHandler.EditComment)
+		protected.DELETE("/trips/:id/comments/:comment_id", r.commentHandler.DeleteComment)
+		protected.GET("/trips/:id/comments/:comment_id/replies", r.commentHandler.ListReplies)
+		protected.GET("/trips/:id/comments/:comment_id/history", r.commentHandler.ListRevisions)
+		protected.PUT("/trips/:id/comments/:comment_id/hidden", r.commentHandler.HideComment)
+		protected.DELETE("/trips/:id/comments/:comment_id/hidden", r.commentHandler.UnhideComment)
+
+		// Shared expense routes
+		protected.GET("/trips/:id/expenses", r.expenseHandler.ListExpenses)
+		protected.POST("/trips/:id/expenses", r.expenseHandler.CreateExpense)
+		protected.GET("/trips/:id/expenses/:expense_id", r.expenseHandler.GetExpense)
+		protected.PUT("/trips/:id/expenses/:expense_id", r.expenseHandler.UpdateExpense)
+		protected.DELETE("/trips/:id/expenses/:expense_id", r.expenseHandler.DeleteExpense)
+		protected.GET("/trips/:id/expenses/:expense_id/receipts", r.expenseHandler.ListReceipts)
+		protected.POST("/trips/:id/expenses/:expense_id/receipts", r.expenseHandler.UploadReceipt)
+		protected.GET("/trips/:id/expenses/:expense_id/receipts/:receipt_id", r.expenseHandler.GetReceipt)
+		protected.GET("/trips/:id/expenses/:expense_id/receipts/:receipt_id/thumbnail", r.expenseHandler.GetReceiptThumbnail)
+		protected.DELETE("/trips/:id/expenses/:expense_id/receipts/:receipt_id", r.expenseHandler.DeleteReceipt)
+		protected.PUT("/trips/:id/expenses/:expense_id/shares/:share_id/settlement", r.expenseHandler.SettleShare)
+		protected.DELETE("/trips/:id/expenses/:expense_id/shares/:share_id/settlement", r.expenseHandler.UnsettleShare)
+		protected.GET("/trips/:id/balances", r.expenseHandler.GetBalances)
+		protected.POST("/trips/:id/settlements", r.expenseHandler.RecordSettlement)
+		protected.POST("/trips/:id/expense-reports", r.expenseHandler.CreateReportLink)
+		protected.GET("/trips/:id/recurring-expenses", r.expenseHandler.ListRecurringExpenses)
+		protected.POST("/trips/:id/recurring-expenses", r.expenseHandler.CreateRecurringExpense)
+		protected.PUT("/trips/:id/recurring-expenses/:recurring_id", r.expenseHandler.UpdateRecurringExpense)
+		protected.DELETE("/trips/:id/recurring-expenses/:recurring_id", r.expenseHandler.DeleteRecurringExpense)
+		protected.GET("/trips/:id/budget", r.expenseHandler.GetBudgetPlan)
+		protected.PUT("/trips/:id/budget", r.expenseHandler.SetBudgetPlan)
+		protected.GET("/trips/:id/budget/comparison", r.expenseHandler.CompareBudget)
+		protected.GET("/currencies", r.expenseHandler.ListCurrencies)
+
+		// Trip photo album routes
+		protected.GET("/trips/:id/photos", r.albumHandler.GetAlbum)
+		protected.POST("/trips/:id/photos", r.albumHandler.UploadPhoto)
+		protected.GET("/trips/:id/photos/archive", r.albumHandler.DownloadAlbum)
+		protected.PUT("/trips/:id/photos/order", r.albumHandler.ReorderPhotos)
+		protected.PUT("/trips/:id/photos/:photo_id", r.albumHandler.UpdatePhoto)
+		protected.DELETE("/trips/:id/photos/:photo_id", r.albumHandler.DeletePhoto)
+		protected.PUT("/trips/:id/photos/:photo_id/cover", r.albumHandler.SetCover)
+
+		// User blocking routes
+		protected.GET("/blocks", r.messageHandler.ListBlockedUsers)
+		protected.POST("/users/:user_id/block", r.messageHandler.BlockUser)
+		protected.DELETE("/users/:user_id/block", r.messageHandler.UnblockUser)
+
+		// Notification center routes
+		protected.GET("/notifications", r.notificationHandler.ListNotifications)
+		protected.GET("/notifications/unread-count", r.notificationHandler.GetUnreadCount)
+		protected.POST("/notifications/read", r.notificationHandler.MarkRead)
+		protected.GET("/notifications/preferences", r.notificationHandler.GetPreferences)
+		protected.PUT("/notifications/preferences", r.notificationHandler.UpdatePreferences)
+		protected.PUT("/notifications/muted-trips/:trip_id", r.notificationHandler.MuteTrip)
+		protected.DELETE("/notifications/muted-trips/:trip_id", r.notificationHandler.UnmuteTrip)
+
+		// Web Push routes
+		protected.GET("/push/public-key", r.pushHandler.GetPublicKey)
+		protected.GET("/push/subscriptions", r.pushHandler.ListSubscriptions)
+		protected.POST("/push/subscriptions", r.pushHandler.Subscribe)
+		protected.DELETE("/push/subscriptions/:subscription_id", r.pushHandler.Unsubscribe)
+
+		// Real-time updates
+		protected.GET("/ws", r.realtimeHandler.Connect)
+		protected.GET("/events/stream", r.realtimeHandler.Stream)
+
+		// Tag routes
+		protected.GET("/tags", r.tagHandler.ListTags)
+		protected.GET("/tags/popular", r.tagHandler.ListPopularTags)
+		protected.GET("/trips/:id/tags", r.tagHandler.GetTripTags)
+		protected.PUT("/trips/:id/tags", r.tagHandler.SetTripTags)
+		protected.DELETE("/trips/:id/tags/:tag_id", r.tagHandler.RemoveTripTag)
+	}
+
+	// Admin routes (require an administrator)
+	admin := v1.Group("/admin")
+	admin.Use(r.authMiddleware.RequireAuth(), r.adminMiddleware.RequireAdmin())
+	{
+		// Tag taxonomy management
+		admin.GET("/tags", r.tagHandler.AdminListTags)
+		admin.POST("/tags", r.tagHandler.CreateTag)
+		admin.PUT("/tags/:tag_id", r.tagHandler.UpdateTag)
+		admin.PUT("/tags/:tag_id/active", r.tagHandler.SetTagActive)
 	}
 
 	// Optional auth routes (authentication optional)
@@ -131,9 +368,6 @@ func (r *Router) setupRoutes() {
 
 // setupStaticRoutes configures static file serving for React app
 func (r *Router) setupStaticRoutes() {
-	// Serve uploaded files
-	r.engine.Static("/uploads", "./uploads")
-
 	// Serve img files from web/public/img
 	r.engine.Static("/img", "./web/public/img")
 
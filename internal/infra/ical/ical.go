@@ -0,0 +1,149 @@
+package ical
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Content type and format layouts defined by RFC 5545
+const (
+	ContentType = "text/calendar; charset=utf-8"
+
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+
+	// maxLineOctets is the longest a content line may be before it must be folded
+	maxLineOctets = 75
+)
+
+// Event statuses
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+// Calendar is a VCALENDAR object
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Event is a VEVENT component. All-day events use only the date part of
+// Start and End, and End is exclusive as required by RFC 5545.
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Status       string
+	Start        time.Time
+	End          *time.Time
+	AllDay       bool
+	Stamp        time.Time
+	LastModified time.Time
+}
+
+// Encode writes the calendar to w
+func (c *Calendar) Encode(w io.Writer) error {
+	var buf bytes.Buffer
+
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:"+c.ProdID)
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&buf, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+
+	for _, e := range c.Events {
+		e.encode(&buf)
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// encode writes the event as a VEVENT component
+func (e *Event) encode(buf *bytes.Buffer) {
+	writeLine(buf, "BEGIN:VEVENT")
+	writeLine(buf, "UID:"+e.UID)
+	writeLine(buf, "DTSTAMP:"+formatDateTime(e.Stamp))
+
+	if e.AllDay {
+		writeLine(buf, "DTSTART;VALUE=DATE:"+e.Start.Format(dateLayout))
+		end := e.Start.AddDate(0, 0, 1)
+		if e.End != nil && e.End.After(e.Start) {
+			end = *e.End
+		}
+		writeLine(buf, "DTEND;VALUE=DATE:"+end.Format(dateLayout))
+	} else {
+		writeLine(buf, "DTSTART:"+formatDateTime(e.Start))
+		if e.End != nil {
+			writeLine(buf, "DTEND:"+formatDateTime(*e.End))
+		}
+	}
+
+	writeLine(buf, "SUMMARY:"+escapeText(e.Summary))
+	if e.Description != "" {
+		writeLine(buf, "DESCRIPTION:"+escapeText(e.Description))
+	}
+	if e.Location != "" {
+		writeLine(buf, "LOCATION:"+escapeText(e.Location))
+	}
+	if e.URL != "" {
+		writeLine(buf, "URL:"+e.URL)
+	}
+	if e.Status != "" {
+		writeLine(buf, "STATUS:"+e.Status)
+	}
+	if !e.LastModified.IsZero() {
+		writeLine(buf, "LAST-MODIFIED:"+formatDateTime(e.LastModified))
+	}
+
+	writeLine(buf, "END:VEVENT")
+}
+
+// formatDateTime formats a time in UTC form
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// escapeText escapes a TEXT value (RFC 5545 section 3.3.11)
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// writeLine writes a content line, folding it at 75 octets without splitting
+// multi-byte characters (RFC 5545 section 3.1)
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space that counts towards the limit
+		limit = maxLineOctets - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
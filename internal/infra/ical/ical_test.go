@@ -0,0 +1,116 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendar_Encode(t *testing.T) {
+	stamp := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	tripEnd := time.Date(2026, 6, 11, 0, 0, 0, 0, time.UTC)
+	itemEnd := time.Date(2026, 6, 2, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	cal := &Calendar{
+		ProdID: "-//JoinTrip//Trips//EN",
+		Name:   "My trips",
+		Events: []Event{
+			{
+				UID:     "trip-1@jointrip",
+				Summary: "Hiking in the Alps",
+				Start:   time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+				End:     &tripEnd,
+				AllDay:  true,
+				Stamp:   stamp,
+				Status:  StatusConfirmed,
+			},
+			{
+				UID:      "item-1@jointrip",
+				Summary:  "Cable car",
+				Location: "Zermatt, Switzerland",
+				Start:    time.Date(2026, 6, 2, 10, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+				End:      &itemEnd,
+				Stamp:    stamp,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, cal.Encode(&buf))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(out, "BEGIN:VEVENT\r\n"))
+
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20260601\r\n")
+	assert.Contains(t, out, "DTEND;VALUE=DATE:20260611\r\n")
+	assert.Contains(t, out, "DTSTAMP:20260301T093000Z\r\n")
+
+	// Timed events are converted to UTC
+	assert.Contains(t, out, "DTSTART:20260602T080000Z\r\n")
+	assert.Contains(t, out, "DTEND:20260602T100000Z\r\n")
+	assert.Contains(t, out, "LOCATION:Zermatt\\, Switzerland\r\n")
+
+	// No bare line feeds
+	assert.NotContains(t, strings.ReplaceAll(out, "\r\n", ""), "\n")
+}
+
+func TestCalendar_EncodeAllDayWithoutEnd(t *testing.T) {
+	cal := &Calendar{
+		ProdID: "-//JoinTrip//Trips//EN",
+		Events: []Event{{
+			UID:    "day@jointrip",
+			Start:  time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+			AllDay: true,
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, cal.Encode(&buf))
+
+	assert.Contains(t, buf.String(), "DTEND;VALUE=DATE:20270101\r\n")
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain", "Lisbon", "Lisbon"},
+		{"comma and semicolon", "Porto, Portugal; day 2", `Porto\, Portugal\; day 2`},
+		{"backslash", `C:\trips`, `C:\\trips`},
+		{"newlines", "line one\r\nline two\nline three", `line one\nline two\nline three`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, escapeText(tt.input))
+		})
+	}
+}
+
+func TestWriteLine_Folding(t *testing.T) {
+	var buf bytes.Buffer
+	line := "DESCRIPTION:" + strings.Repeat("añ", 80)
+	writeLine(&buf, line)
+
+	physical := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	require.Greater(t, len(physical), 1)
+
+	for i, l := range physical {
+		assert.LessOrEqual(t, len(l), maxLineOctets, "line %d too long", i)
+		if i > 0 {
+			assert.True(t, strings.HasPrefix(l, " "), "continuation line %d must start with a space", i)
+		}
+	}
+
+	// Unfolding restores the original line without breaking UTF-8
+	unfolded := strings.ReplaceAll(buf.String(), "\r\n ", "")
+	assert.Equal(t, line+"\r\n", unfolded)
+}
@@ -0,0 +1,228 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+
+	// Register the decoders of the formats uploads may come in
+	_ "image/gif"
+	_ "image/png"
+)
+
+// MaxPixels is the largest image, in pixels, that will be decoded. A small
+// compressed file can claim enormous dimensions and exhaust memory when
+// decoded, so the size in its header is checked first.
+const MaxPixels = 40_000_000
+
+// DefaultQuality is the JPEG quality of encoded images
+const DefaultQuality = 85
+
+// Errors returned for images that cannot be processed
+var (
+	ErrUnsupportedFormat = errors.New("image must be a JPEG, PNG or GIF")
+	ErrTooManyPixels     = errors.New("image dimensions are too large")
+)
+
+// Decode decodes a JPEG, PNG or GIF image, rejecting images larger than
+// MaxPixels before decoding them. JPEGs are turned upright according to their
+// EXIF orientation, as the metadata carrying it is not kept.
+func Decode(data []byte) (image.Image, string, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return nil, "", ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid image: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, "", ErrTooManyPixels
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid image: %w", err)
+	}
+
+	if format == "jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+	return img, format, nil
+}
+
+// Fit scales an image down to fit within a square of side pixels, keeping
+// its aspect ratio. Smaller images are returned as they are.
+func Fit(img image.Image, side int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= side && height <= side {
+		return img
+	}
+
+	if width >= height {
+		height = max(1, height*side/width)
+		width = side
+	} else {
+		width = max(1, width*side/height)
+		height = side
+	}
+	return Resize(img, width, height)
+}
+
+// Resize scales an image down to exactly width by height pixels, averaging
+// the source pixels that fall into every target pixel. It is meant for
+// shrinking; enlarging repeats pixels.
+func Resize(img image.Image, width, height int) *image.RGBA {
+	src := toRGBA(img)
+	srcWidth, srcHeight := src.Rect.Dx(), src.Rect.Dy()
+
+	sums := make([]uint64, width*height*4)
+	counts := make([]uint64, width*height)
+	for y := 0; y < srcHeight; y++ {
+		ty := y * height / srcHeight
+		row := src.Pix[y*src.Stride:]
+		for x := 0; x < srcWidth; x++ {
+			t := ty*width + x*width/srcWidth
+			p := row[x*4 : x*4+4]
+			sums[t*4] += uint64(p[0])
+			sums[t*4+1] += uint64(p[1])
+			sums[t*4+2] += uint64(p[2])
+			sums[t*4+3] += uint64(p[3])
+			counts[t]++
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for t, count := range counts {
+		if count == 0 {
+			// Only when enlarging: take the nearest source pixel
+			tx, ty := t%width, t/width
+			copy(dst.Pix[t*4:t*4+4], src.Pix[(ty*srcHeight/height)*src.Stride+(tx*srcWidth/width)*4:])
+			continue
+		}
+		for c := 0; c < 4; c++ {
+			dst.Pix[t*4+c] = uint8((sums[t*4+c] + count/2) / count)
+		}
+	}
+	return dst
+}
+
+// EncodeJPEG encodes an image as a baseline JPEG without any metadata.
+// Transparent areas are filled with white.
+func EncodeJPEG(img image.Image, quality int) ([]byte, error) {
+	flat := image.NewRGBA(img.Bounds())
+	draw.Draw(flat, flat.Rect, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Rect, img, img.Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Thumbnailer makes small JPEG previews of images
+type Thumbnailer struct {
+	side int
+}
+
+// NewThumbnailer creates a thumbnailer fitting previews in a square of side pixels
+func NewThumbnailer(side int) *Thumbnailer {
+	return &Thumbnailer{side: side}
+}
+
+// Thumbnail returns a JPEG preview of an image
+func (t *Thumbnailer) Thumbnail(data []byte) ([]byte, error) {
+	img, _, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeJPEG(Fit(img, t.side), DefaultQuality)
+}
+
+// CropSquare returns the centered square of an image
+func CropSquare(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	origin := bounds.Min.Add(image.Pt((bounds.Dx()-side)/2, (bounds.Dy()-side)/2))
+
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(square, square.Rect, img, origin, draw.Src)
+	return square
+}
+
+// AvatarMaker re-encodes uploaded photos as square JPEGs
+type AvatarMaker struct {
+	quality int
+}
+
+// NewAvatarMaker creates an avatar maker
+func NewAvatarMaker() *AvatarMaker {
+	return &AvatarMaker{quality: DefaultQuality}
+}
+
+// Avatars decodes an image and returns it cropped to a square and encoded
+// at each of the sizes. The JPEGs carry no metadata, so nothing such as the
+// location a photo was taken at survives.
+func (m *AvatarMaker) Avatars(data []byte, sizes []int) ([][]byte, error) {
+	img, _, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	square := CropSquare(img)
+	avatars := make([][]byte, 0, len(sizes))
+	for _, size := range sizes {
+		avatar, err := EncodeJPEG(Resize(square, size, size), m.quality)
+		if err != nil {
+			return nil, err
+		}
+		avatars = append(avatars, avatar)
+	}
+	return avatars, nil
+}
+
+// PhotoMaker re-encodes uploaded photos as JPEGs at several sizes
+type PhotoMaker struct {
+	quality int
+}
+
+// NewPhotoMaker creates a photo maker
+func NewPhotoMaker() *PhotoMaker {
+	return &PhotoMaker{quality: DefaultQuality}
+}
+
+// Photos decodes an image and returns it scaled down to fit within a square
+// of each of the sides and encoded as a JPEG without metadata, so that
+// nothing such as the location a photo was taken at survives
+func (m *PhotoMaker) Photos(data []byte, sides []int) ([][]byte, error) {
+	img, _, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	photos := make([][]byte, 0, len(sides))
+	for _, side := range sides {
+		photo, err := EncodeJPEG(Fit(img, side), m.quality)
+		if err != nil {
+			return nil, err
+		}
+		photos = append(photos, photo)
+	}
+	return photos, nil
+}
+
+// toRGBA returns the image as RGBA with its origin at zero
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Rect, img, bounds.Min, draw.Src)
+	return rgba
+}
@@ -0,0 +1,228 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func encodeTestJPEG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	return buf.Bytes()
+}
+
+// withOrientation inserts an EXIF segment with the given orientation, and a
+// GPS tag for good measure, right after the start of a JPEG
+func withOrientation(data []byte, orientation uint16) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("MM\x00\x2a")
+	binary.Write(&tiff, binary.BigEndian, uint32(8))
+	binary.Write(&tiff, binary.BigEndian, uint16(2))
+	// Orientation, SHORT, count 1, value
+	binary.Write(&tiff, binary.BigEndian, []uint16{0x0112, 3})
+	binary.Write(&tiff, binary.BigEndian, uint32(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{orientation, 0})
+	// GPS IFD pointer, LONG, count 1, offset (unused here)
+	binary.Write(&tiff, binary.BigEndian, []uint16{0x8825, 4})
+	binary.Write(&tiff, binary.BigEndian, []uint32{1, 0})
+	binary.Write(&tiff, binary.BigEndian, uint32(0))
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	app1 := []byte{0xff, 0xe1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, app1...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func TestDecode(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	decoded, format, err := Decode(encodePNG(t, img))
+	require.NoError(t, err)
+	assert.Equal(t, "png", format)
+	assert.Equal(t, image.Rect(0, 0, 40, 20), decoded.Bounds())
+
+	_, _, err = Decode([]byte("%PDF-1.7"))
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	_, _, err = Decode(encodePNG(t, img)[:60])
+	assert.Error(t, err)
+}
+
+func TestDecode_RejectsDecompressionBombs(t *testing.T) {
+	data := encodePNG(t, image.NewGray(image.Rect(0, 0, 1, 1)))
+
+	// Claim 100000 x 100000 pixels in the header and fix up its checksum
+	ihdr := data[8:]
+	binary.BigEndian.PutUint32(ihdr[8:], 100000)
+	binary.BigEndian.PutUint32(ihdr[12:], 100000)
+	binary.BigEndian.PutUint32(ihdr[21:], crc32.ChecksumIEEE(ihdr[4:21]))
+
+	_, _, err := Decode(data)
+	assert.ErrorIs(t, err, ErrTooManyPixels)
+}
+
+func TestDecode_AppliesEXIFOrientation(t *testing.T) {
+	// A wide image whose left half is black and right half white
+	img := image.NewGray(image.Rect(0, 0, 64, 32))
+	for y := 0; y < 32; y++ {
+		for x := 32; x < 64; x++ {
+			img.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+	data := encodeTestJPEG(t, img)
+
+	tests := []struct {
+		orientation  uint16
+		bounds       image.Rectangle
+		darkX, darkY int
+	}{
+		{1, image.Rect(0, 0, 64, 32), 8, 16},
+		{3, image.Rect(0, 0, 64, 32), 56, 16},
+		{6, image.Rect(0, 0, 32, 64), 16, 8},
+		{8, image.Rect(0, 0, 32, 64), 16, 56},
+	}
+
+	for _, tt := range tests {
+		decoded, _, err := Decode(withOrientation(data, tt.orientation))
+		require.NoError(t, err)
+		assert.Equal(t, tt.bounds, decoded.Bounds(), "orientation %d", tt.orientation)
+
+		r, _, _, _ := decoded.At(tt.darkX, tt.darkY).RGBA()
+		assert.Less(t, r, uint32(0x4000), "orientation %d", tt.orientation)
+	}
+}
+
+func TestJPEGOrientation_IgnoresGarbage(t *testing.T) {
+	assert.Equal(t, 1, jpegOrientation(nil))
+	assert.Equal(t, 1, jpegOrientation([]byte{0xff, 0xd8, 0xff, 0xe1, 0xff, 0xff}))
+	assert.Equal(t, 1, jpegOrientation([]byte{0xff, 0xd8, 0xff, 0xe1, 0x00, 0x0a, 'E', 'x', 'i', 'f', 0, 0, 'I', 'I'}))
+}
+
+func TestResize(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		img.Set(x, 0, color.RGBA{R: 200, A: 255})
+		img.Set(x, 1, color.RGBA{R: 100, A: 255})
+	}
+
+	resized := Resize(img, 2, 1)
+	assert.Equal(t, image.Rect(0, 0, 2, 1), resized.Bounds())
+	assert.Equal(t, color.RGBA{R: 150, A: 255}, resized.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{R: 150, A: 255}, resized.RGBAAt(1, 0))
+
+	enlarged := Resize(img, 8, 4)
+	assert.Equal(t, color.RGBA{R: 200, A: 255}, enlarged.RGBAAt(7, 0))
+}
+
+func TestFit(t *testing.T) {
+	wide := image.NewRGBA(image.Rect(0, 0, 1000, 250))
+	assert.Equal(t, image.Rect(0, 0, 128, 32), Fit(wide, 128).Bounds())
+
+	tall := image.NewRGBA(image.Rect(0, 0, 10, 5000))
+	assert.Equal(t, image.Rect(0, 0, 1, 128), Fit(tall, 128).Bounds())
+
+	small := image.NewRGBA(image.Rect(0, 0, 50, 60))
+	assert.Same(t, small, Fit(small, 128))
+}
+
+func TestThumbnailer(t *testing.T) {
+	// A transparent PNG becomes a white JPEG without any metadata
+	thumb, err := NewThumbnailer(100).Thumbnail(encodePNG(t, image.NewNRGBA(image.Rect(0, 0, 400, 300))))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(thumb, []byte{0xff, 0xd8, 0xff}))
+
+	decoded, err := jpeg.Decode(bytes.NewReader(thumb))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 100, 75), decoded.Bounds())
+	r, g, b, _ := decoded.At(50, 37).RGBA()
+	assert.Greater(t, r+g+b, uint32(3*0xf000))
+
+	photo := withOrientation(encodeTestJPEG(t, image.NewGray(image.Rect(0, 0, 200, 100))), 6)
+	require.Contains(t, string(photo), "Exif")
+	thumb, err = NewThumbnailer(100).Thumbnail(photo)
+	require.NoError(t, err)
+	assert.NotContains(t, string(thumb), "Exif")
+
+	_, err = NewThumbnailer(100).Thumbnail([]byte("not an image"))
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestCropSquare(t *testing.T) {
+	// A wide image whose middle third is red
+	img := image.NewRGBA(image.Rect(10, 10, 40, 20))
+	for y := 10; y < 20; y++ {
+		for x := 20; x < 30; x++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+
+	square := CropSquare(img)
+	assert.Equal(t, image.Rect(0, 0, 10, 10), square.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, square.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, square.RGBAAt(9, 9))
+}
+
+func TestAvatarMaker(t *testing.T) {
+	photo := withOrientation(encodeTestJPEG(t, image.NewGray(image.Rect(0, 0, 600, 300))), 6)
+	require.Contains(t, string(photo), "Exif")
+
+	avatars, err := NewAvatarMaker().Avatars(photo, []int{64, 128, 512})
+	require.NoError(t, err)
+	require.Len(t, avatars, 3)
+	for i, side := range []int{64, 128, 512} {
+		assert.NotContains(t, string(avatars[i]), "Exif")
+		decoded, err := jpeg.Decode(bytes.NewReader(avatars[i]))
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, side, side), decoded.Bounds())
+	}
+
+	_, err = NewAvatarMaker().Avatars([]byte("GIF89a not really"), []int{64})
+	assert.Error(t, err)
+	_, err = NewAvatarMaker().Avatars([]byte("%PDF-1.7"), []int{64})
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	bomb := encodePNG(t, image.NewGray(image.Rect(0, 0, 1, 1)))
+	ihdr := bomb[8:]
+	binary.BigEndian.PutUint32(ihdr[8:], 50000)
+	binary.BigEndian.PutUint32(ihdr[12:], 50000)
+	binary.BigEndian.PutUint32(ihdr[21:], crc32.ChecksumIEEE(ihdr[4:21]))
+	_, err = NewAvatarMaker().Avatars(bomb, []int{64})
+	assert.ErrorIs(t, err, ErrTooManyPixels)
+}
+
+func TestPhotoMaker(t *testing.T) {
+	// Turned upright by its orientation, the photo is 300 wide and 600 tall
+	photo := withOrientation(encodeTestJPEG(t, image.NewGray(image.Rect(0, 0, 600, 300))), 6)
+	require.Contains(t, string(photo), "Exif")
+
+	photos, err := NewPhotoMaker().Photos(photo, []int{100, 1000})
+	require.NoError(t, err)
+	require.Len(t, photos, 2)
+	for i, bounds := range []image.Rectangle{image.Rect(0, 0, 50, 100), image.Rect(0, 0, 300, 600)} {
+		assert.NotContains(t, string(photos[i]), "Exif")
+		decoded, err := jpeg.Decode(bytes.NewReader(photos[i]))
+		require.NoError(t, err)
+		assert.Equal(t, bounds, decoded.Bounds())
+	}
+
+	_, err = NewPhotoMaker().Photos([]byte("%PDF-1.7"), []int{100})
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"context"
+	"sync"
+
+	"jointrip/internal/domain/email"
+)
+
+// DefaultCaptureLimit is how many messages a capture mailer keeps
+const DefaultCaptureLimit = 100
+
+// CaptureMailer keeps sent messages in memory instead of delivering them, for
+// tests and local development without a mail server. Only the most recent
+// messages are kept.
+type CaptureMailer struct {
+	mu       sync.Mutex
+	limit    int
+	messages []email.Message
+}
+
+// NewCaptureMailer creates a mailer that keeps up to limit messages
+func NewCaptureMailer(limit int) *CaptureMailer {
+	return &CaptureMailer{limit: limit}
+}
+
+// Send records a message
+func (m *CaptureMailer) Send(ctx context.Context, message *email.Message) error {
+	if err := message.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, *message)
+	if len(m.messages) > m.limit {
+		m.messages = m.messages[len(m.messages)-m.limit:]
+	}
+	return nil
+}
+
+// Messages returns the captured messages, oldest first
+func (m *CaptureMailer) Messages() []email.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]email.Message(nil), m.messages...)
+}
+
+// Reset forgets the captured messages
+func (m *CaptureMailer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = nil
+}
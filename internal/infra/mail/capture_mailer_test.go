@@ -0,0 +1,35 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"jointrip/internal/domain/email"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureMailer(t *testing.T) {
+	mailer := NewCaptureMailer(2)
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, mailer.Send(context.Background(), &email.Message{
+			To:      "anna@example.com",
+			Subject: fmt.Sprintf("Message %d", i),
+			Text:    "Hello",
+		}))
+	}
+
+	messages := mailer.Messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, "Message 2", messages[0].Subject)
+	assert.Equal(t, "Message 3", messages[1].Subject)
+
+	err := mailer.Send(context.Background(), &email.Message{To: "nope", Subject: "Hi", Text: "Hello"})
+	assert.ErrorIs(t, err, email.ErrInvalidAddress)
+
+	mailer.Reset()
+	assert.Empty(t, mailer.Messages())
+}
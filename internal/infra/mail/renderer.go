@@ -0,0 +1,270 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"jointrip/internal/domain/email"
+	"jointrip/internal/domain/notification"
+	"jointrip/internal/domain/user"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// DefaultLocale is used for users without a supported language
+const DefaultLocale = "en"
+
+// digestTemplate names the template of digest emails
+const digestTemplate = "digest"
+
+// localeFormat holds how dates and times are written in a locale
+type localeFormat struct {
+	date string
+	time string
+}
+
+// localeFormats lists the supported locales
+var localeFormats = map[string]localeFormat{
+	"en": {date: "Jan 2, 2006", time: "Jan 2, 3:04 PM"},
+	"es": {date: "02/01/2006", time: "02/01 15:04"},
+	"fr": {date: "02/01/2006", time: "02/01 15:04"},
+}
+
+// templateData is what email templates are executed with
+type templateData struct {
+	Locale         string
+	RecipientName  string
+	Content        string
+	Data           map[string]string
+	ActionURL      string
+	PreferencesURL string
+
+	// Digest emails only
+	Frequency notification.DigestFrequency
+	Date      string
+	Items     []digestItem
+	More      bool
+}
+
+// digestItem is one notification listed in a digest
+type digestItem struct {
+	Title string
+	Time  string
+	URL   string
+}
+
+// templateSet holds the parsed templates of one notification type in one locale
+type templateSet struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// TemplateRenderer renders notification emails from the embedded templates,
+// in the first of the recipient's languages that has translations
+type TemplateRenderer struct {
+	baseURL string
+	sets    map[string]map[string]*templateSet
+}
+
+// NewTemplateRenderer parses the templates of every locale and notification
+// type. Links in emails point into the web app at baseURL.
+func NewTemplateRenderer(baseURL string) (*TemplateRenderer, error) {
+	r := &TemplateRenderer{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		sets:    make(map[string]map[string]*templateSet),
+	}
+
+	names := []string{digestTemplate}
+	for _, notificationType := range notification.Types() {
+		names = append(names, string(notificationType))
+	}
+
+	for locale := range localeFormats {
+		r.sets[locale] = make(map[string]*templateSet)
+		for _, name := range names {
+			set, err := parseTemplateSet(locale, name)
+			if err != nil {
+				return nil, err
+			}
+			r.sets[locale][name] = set
+		}
+	}
+
+	return r, nil
+}
+
+// RenderNotification renders the email about a notification for its recipient
+func (r *TemplateRenderer) RenderNotification(recipient *user.User, n *notification.Notification) (*email.Message, error) {
+	locale := Locale(recipient.Languages)
+	set, ok := r.sets[locale][string(n.Type)]
+	if !ok {
+		return nil, notification.ErrInvalidType
+	}
+
+	data := r.notificationData(locale, n)
+	data.RecipientName = recipientName(recipient)
+
+	return render(set, string(n.Type), recipient.Email, data)
+}
+
+// RenderDigest renders a digest of notifications, with times in the
+// recipient's timezone. When more is set, the digest says that further
+// notifications did not fit.
+func (r *TemplateRenderer) RenderDigest(recipient *user.User, prefs *notification.Preferences, notifications []*notification.Notification, more bool, now time.Time) (*email.Message, error) {
+	locale := Locale(recipient.Languages)
+	format := localeFormats[locale]
+	loc := prefs.Location()
+
+	data := templateData{
+		Locale:         locale,
+		RecipientName:  recipientName(recipient),
+		ActionURL:      r.baseURL + "/notifications",
+		PreferencesURL: r.preferencesURL(),
+		Frequency:      prefs.Digest,
+		Date:           now.In(loc).Format(format.date),
+		More:           more,
+	}
+
+	for _, n := range notifications {
+		set, ok := r.sets[locale][string(n.Type)]
+		if !ok {
+			return nil, notification.ErrInvalidType
+		}
+
+		var title bytes.Buffer
+		if err := set.text.ExecuteTemplate(&title, "subject", r.notificationData(locale, n)); err != nil {
+			return nil, fmt.Errorf("failed to render %s title: %w", n.Type, err)
+		}
+
+		data.Items = append(data.Items, digestItem{
+			Title: oneLine(title.String()),
+			Time:  n.CreatedAt.In(loc).Format(format.time),
+			URL:   r.entityURL(n.RelatedEntityType, n.RelatedEntityID.String()),
+		})
+	}
+
+	return render(r.sets[locale][digestTemplate], digestTemplate, recipient.Email, data)
+}
+
+// notificationData returns the template data describing a notification
+func (r *TemplateRenderer) notificationData(locale string, n *notification.Notification) templateData {
+	return templateData{
+		Locale:         locale,
+		Content:        n.Content,
+		Data:           n.Data,
+		ActionURL:      r.entityURL(n.RelatedEntityType, n.RelatedEntityID.String()),
+		PreferencesURL: r.preferencesURL(),
+	}
+}
+
+// preferencesURL links to the notification settings page
+func (r *TemplateRenderer) preferencesURL() string {
+	return r.baseURL + "/settings/notifications"
+}
+
+// render executes a template set into a message to the given address
+func render(set *templateSet, name, to string, data templateData) (*email.Message, error) {
+	var subject, text, html bytes.Buffer
+	if err := set.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s subject: %w", name, err)
+	}
+	if err := set.text.ExecuteTemplate(&text, "layout_text", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s text: %w", name, err)
+	}
+	if err := set.html.ExecuteTemplate(&html, "layout_html", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s html: %w", name, err)
+	}
+
+	return &email.Message{
+		To:      to,
+		Subject: oneLine(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+// oneLine collapses whitespace, so that rendered subjects fit a header
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Locale returns the first of the given language codes that emails are
+// translated to, or DefaultLocale. Regional codes such as "es-MX" match
+// their base language.
+func Locale(languages []string) string {
+	for _, language := range languages {
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(language)), "-")
+		base, _, _ = strings.Cut(base, "_")
+		if _, ok := localeFormats[base]; ok {
+			return base
+		}
+	}
+	return DefaultLocale
+}
+
+// entityURL links to the page of a notification's related entity
+func (r *TemplateRenderer) entityURL(entityType notification.EntityType, id string) string {
+	switch entityType {
+	case notification.EntityTrip:
+		return r.baseURL + "/trips/" + id
+	case notification.EntityConversation:
+		return r.baseURL + "/messages/" + id
+	case notification.EntityUser:
+		return r.baseURL + "/users/" + id
+	default:
+		return r.baseURL + "/"
+	}
+}
+
+// recipientName returns how an email greets its recipient
+func recipientName(u *user.User) string {
+	if u.FirstName != "" {
+		return u.FirstName
+	}
+	return u.Username
+}
+
+// parseTemplateSet parses the layout, the locale's shared phrases and the
+// named template into a text and an HTML template
+func parseTemplateSet(locale, name string) (*templateSet, error) {
+	files := []string{
+		"templates/layout.tmpl",
+		"templates/" + locale + "/common.tmpl",
+		"templates/" + locale + "/" + name + ".tmpl",
+	}
+	funcs := map[string]interface{}{
+		"date": dateFormatter(localeFormats[locale].date),
+	}
+
+	text, err := texttemplate.New(name).
+		Funcs(funcs).Option("missingkey=zero").ParseFS(templateFS, files...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s templates for %s: %w", name, locale, err)
+	}
+
+	html, err := htmltemplate.New(name).
+		Funcs(funcs).Option("missingkey=zero").ParseFS(templateFS, files...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s templates for %s: %w", name, locale, err)
+	}
+
+	return &templateSet{text: text, html: html}, nil
+}
+
+// dateFormatter formats notification data dates with the given layout,
+// leaving values that are not dates as they are
+func dateFormatter(layout string) func(string) string {
+	return func(value string) string {
+		date, err := time.Parse(notification.DataDateLayout, value)
+		if err != nil {
+			return value
+		}
+		return date.Format(layout)
+	}
+}
@@ -0,0 +1,178 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"jointrip/internal/domain/notification"
+	"jointrip/internal/domain/user"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRecipient(languages ...string) *user.User {
+	return &user.User{
+		ID:        uuid.New(),
+		Email:     "anna@example.com",
+		FirstName: "Anna",
+		Languages: languages,
+	}
+}
+
+func testNotification(t *testing.T, notificationType notification.Type, data map[string]string) *notification.Notification {
+	n, err := notification.NewNotification(uuid.New(), notificationType, "Title", "See you <soon> & bring snacks", notification.EntityTrip, uuid.New())
+	require.NoError(t, err)
+	n.Data = data
+	return n
+}
+
+func TestLocale(t *testing.T) {
+	tests := []struct {
+		name      string
+		languages []string
+		expected  string
+	}{
+		{"no languages", nil, "en"},
+		{"unsupported", []string{"ja", "ko"}, "en"},
+		{"first supported wins", []string{"ja", "fr", "es"}, "fr"},
+		{"regional code", []string{"es-MX"}, "es"},
+		{"underscore and case", []string{"FR_ca"}, "fr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Locale(tt.languages))
+		})
+	}
+}
+
+func TestTemplateRenderer_RendersEveryTypeInEveryLocale(t *testing.T) {
+	renderer, err := NewTemplateRenderer("https://jointrip.example/")
+	require.NoError(t, err)
+
+	data := map[string]string{
+		notification.DataActorName: "Ben",
+		notification.DataTripTitle: "Alps",
+		notification.DataRating:    "5",
+	}
+
+	for locale := range localeFormats {
+		for _, notificationType := range notification.Types() {
+			message, err := renderer.RenderNotification(testRecipient(locale), testNotification(t, notificationType, data))
+			require.NoError(t, err, "%s %s", locale, notificationType)
+
+			assert.Equal(t, "anna@example.com", message.To)
+			assert.NotEmpty(t, message.Subject)
+			assert.NotContains(t, message.Subject, "\n")
+			assert.NotContains(t, message.Text, "<no value>")
+			assert.Contains(t, message.Text, "Anna")
+			assert.Contains(t, message.HTML, `lang="`+locale+`"`)
+			assert.Contains(t, message.HTML, "https://jointrip.example/trips/")
+			assert.Contains(t, message.Text, "https://jointrip.example/settings/notifications")
+			assert.NoError(t, message.Validate())
+		}
+	}
+}
+
+func TestTemplateRenderer_Localizes(t *testing.T) {
+	renderer, err := NewTemplateRenderer("https://jointrip.example")
+	require.NoError(t, err)
+
+	n := testNotification(t, notification.TypeTripChanged, map[string]string{
+		notification.DataTripTitle: "Alps",
+		notification.DataStartDate: "2026-07-01",
+		notification.DataEndDate:   "2026-07-14",
+	})
+
+	english, err := renderer.RenderNotification(testRecipient(), n)
+	require.NoError(t, err)
+	assert.Equal(t, "Alps was updated", english.Subject)
+	assert.Contains(t, english.Text, "Jul 1, 2026")
+
+	spanish, err := renderer.RenderNotification(testRecipient("es"), n)
+	require.NoError(t, err)
+	assert.Equal(t, "Alps ha cambiado", spanish.Subject)
+	assert.Contains(t, spanish.Text, "Hola, Anna:")
+	assert.Contains(t, spanish.Text, "01/07/2026")
+}
+
+func TestTemplateRenderer_LocalizesBudgetCategories(t *testing.T) {
+	renderer, err := NewTemplateRenderer("https://jointrip.example")
+	require.NoError(t, err)
+
+	n := testNotification(t, notification.TypeBudgetAlert, map[string]string{
+		notification.DataTripTitle: "Alps",
+		notification.DataCategory:  "accommodation",
+		notification.DataSpent:     "850.00 EUR",
+		notification.DataBudget:    "1000.00 EUR",
+		notification.DataPercent:   "85",
+		notification.DataStatus:    "warning",
+	})
+
+	english, err := renderer.RenderNotification(testRecipient(), n)
+	require.NoError(t, err)
+	assert.Equal(t, "The accommodation budget for Alps is at 85%", english.Subject)
+	assert.Contains(t, english.Text, "850.00 EUR of the 1000.00 EUR planned")
+
+	n.Data[notification.DataStatus] = "exceeded"
+	n.Data[notification.DataPersonal] = "true"
+	french, err := renderer.RenderNotification(testRecipient("fr"), n)
+	require.NoError(t, err)
+	assert.Equal(t, "Votre budget hébergement de Alps est dépassé", french.Subject)
+	assert.Contains(t, french.Text, "pour votre part")
+}
+
+func TestTemplateRenderer_EscapesHTML(t *testing.T) {
+	renderer, err := NewTemplateRenderer("https://jointrip.example")
+	require.NoError(t, err)
+
+	n := testNotification(t, notification.TypeNewMessage, map[string]string{
+		notification.DataActorName: "<script>Ben</script>",
+	})
+
+	message, err := renderer.RenderNotification(testRecipient(), n)
+	require.NoError(t, err)
+
+	assert.Equal(t, "New message from <script>Ben</script>", message.Subject)
+	assert.Contains(t, message.Text, "See you <soon> & bring snacks")
+	assert.NotContains(t, message.HTML, "<script>")
+	assert.Contains(t, message.HTML, "&lt;script&gt;Ben&lt;/script&gt;")
+	assert.Contains(t, message.HTML, "See you &lt;soon&gt; &amp; bring snacks")
+	assert.True(t, strings.HasPrefix(message.HTML, "<!DOCTYPE html>"))
+}
+
+func TestTemplateRenderer_RenderDigest(t *testing.T) {
+	renderer, err := NewTemplateRenderer("https://jointrip.example")
+	require.NoError(t, err)
+
+	now := time.Date(2026, 5, 11, 6, 0, 0, 0, time.UTC)
+	prefs := notification.NewPreferences(uuid.New())
+	require.NoError(t, prefs.SetDigest(notification.DigestWeekly, "America/New_York", now))
+
+	message := testNotification(t, notification.TypeNewMessage, map[string]string{notification.DataActorName: "Ben"})
+	message.CreatedAt = time.Date(2026, 5, 8, 23, 30, 0, 0, time.UTC)
+	changed := testNotification(t, notification.TypeTripChanged, map[string]string{notification.DataTripTitle: "Alps"})
+	changed.CreatedAt = time.Date(2026, 5, 10, 14, 5, 0, 0, time.UTC)
+
+	digest, err := renderer.RenderDigest(testRecipient(), prefs, []*notification.Notification{message, changed}, true, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Your weekly JoinTrip digest for May 11, 2026", digest.Subject)
+	assert.Contains(t, digest.Text, "- New message from Ben (May 8, 7:30 PM)")
+	assert.Contains(t, digest.Text, "- Alps was updated (May 10, 10:05 AM)")
+	assert.Contains(t, digest.Text, "and more waiting for you")
+	assert.Contains(t, digest.Text, "https://jointrip.example/notifications")
+	assert.Contains(t, digest.HTML, "New message from Ben")
+	assert.NoError(t, digest.Validate())
+
+	require.NoError(t, prefs.SetDigest(notification.DigestDaily, "Europe/Paris", now))
+	daily, err := renderer.RenderDigest(testRecipient("fr"), prefs, []*notification.Notification{changed}, false, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Votre résumé JoinTrip du 11/05/2026", daily.Subject)
+	assert.Contains(t, daily.Text, "- Alps a été modifié (10/05 16:05)")
+	assert.NotContains(t, daily.Text, "bien plus")
+}
@@ -0,0 +1,189 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"jointrip/internal/domain/email"
+	"jointrip/internal/infra/config"
+)
+
+// SMTP timeouts
+const (
+	smtpDialTimeout    = 10 * time.Second
+	smtpSessionTimeout = 30 * time.Second
+)
+
+// SMTPMailer sends emails through an SMTP server. Connections on port 465
+// use implicit TLS; on other ports STARTTLS is used when the server offers it.
+type SMTPMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     mail.Address
+}
+
+// NewSMTPMailer creates a new SMTP mailer
+func NewSMTPMailer(cfg *config.Config) (*SMTPMailer, error) {
+	from, err := mail.ParseAddress(cfg.Mail.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", cfg.Mail.From, err)
+	}
+
+	return &SMTPMailer{
+		host:     cfg.Mail.SMTPHost,
+		port:     cfg.Mail.SMTPPort,
+		username: cfg.Mail.SMTPUser,
+		password: cfg.Mail.SMTPPassword,
+		from:     *from,
+	}, nil
+}
+
+// Send delivers a message in a single SMTP session
+func (m *SMTPMailer) Send(ctx context.Context, message *email.Message) error {
+	if err := message.Validate(); err != nil {
+		return err
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return email.ErrInvalidAddress
+	}
+
+	body, err := buildMessage(m.from, *to, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	dialer := &net.Dialer{Timeout: smtpDialTimeout}
+	address := net.JoinHostPort(m.host, m.port)
+	var conn net.Conn
+	if m.port == "465" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: m.host}}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+
+	deadline := time.Now().Add(smtpSessionTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if m.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.from.Address); err != nil {
+		return fmt.Errorf("sender rejected: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("recipient rejected: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to start message: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("message rejected: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// buildMessage encodes a message as a MIME multipart/alternative email with
+// quoted-printable text and HTML parts
+func buildMessage(from, to mail.Address, message *email.Message, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	parts := multipart.NewWriter(&buf)
+
+	headers := []struct{ name, value string }{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", message.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", messageID(from.Address)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + parts.Boundary()},
+	}
+	var head bytes.Buffer
+	for _, h := range headers {
+		fmt.Fprintf(&head, "%s: %s\r\n", h.name, h.value)
+	}
+	head.WriteString("\r\n")
+
+	bodies := []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	}
+	for _, b := range bodies {
+		if b.content == "" {
+			continue
+		}
+		part, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {b.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(part)
+		if _, err := qp.Write([]byte(b.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	return append(head.Bytes(), buf.Bytes()...), nil
+}
+
+// messageID returns a unique Message-ID in the sender's domain
+func messageID(sender string) string {
+	domain := "localhost"
+	if _, host, ok := strings.Cut(sender, "@"); ok {
+		domain = host
+	}
+
+	random := make([]byte, 16)
+	rand.Read(random)
+	return "<" + hex.EncodeToString(random) + "@" + domain + ">"
+}
@@ -0,0 +1,164 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"jointrip/internal/domain/email"
+	"jointrip/internal/infra/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts one SMTP session and returns the transaction it received
+func fakeSMTPServer(t *testing.T) (addr string, received <-chan smtpTransaction) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	done := make(chan smtpTransaction, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		var tx smtpTransaction
+		text.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch command {
+			case "EHLO", "HELO":
+				text.PrintfLine("250-localhost")
+				text.PrintfLine("250 8BITMIME")
+			case "MAIL":
+				tx.from = line
+				text.PrintfLine("250 OK")
+			case "RCPT":
+				tx.to = line
+				text.PrintfLine("250 OK")
+			case "DATA":
+				text.PrintfLine("354 Go ahead")
+				tx.data, _ = text.ReadDotBytes()
+				text.PrintfLine("250 Queued")
+			case "QUIT":
+				text.PrintfLine("221 Bye")
+				done <- tx
+				return
+			default:
+				text.PrintfLine("502 Not implemented")
+			}
+		}
+	}()
+
+	return listener.Addr().String(), done
+}
+
+type smtpTransaction struct {
+	from string
+	to   string
+	data []byte
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	addr, received := fakeSMTPServer(t)
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+
+	mailer, err := NewSMTPMailer(&config.Config{Mail: config.MailConfig{
+		SMTPHost: host,
+		SMTPPort: port,
+		From:     "JoinTrip <no-reply@jointrip.example>",
+	}})
+	require.NoError(t, err)
+
+	err = mailer.Send(context.Background(), &email.Message{
+		To:      "Anna <anna@example.com>",
+		Subject: "Nuevo mensaje de José",
+		Text:    "Hola",
+		HTML:    "<p>Hola</p>",
+	})
+	require.NoError(t, err)
+
+	select {
+	case tx := <-received:
+		assert.Equal(t, "MAIL FROM:<no-reply@jointrip.example> BODY=8BITMIME", tx.from)
+		assert.Equal(t, "RCPT TO:<anna@example.com>", tx.to)
+		assert.Contains(t, string(tx.data), "Subject: =?utf-8?q?Nuevo_mensaje_de_Jos=C3=A9?=")
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not delivered")
+	}
+}
+
+func TestNewSMTPMailer_InvalidSender(t *testing.T) {
+	_, err := NewSMTPMailer(&config.Config{Mail: config.MailConfig{From: "not an address"}})
+	assert.Error(t, err)
+}
+
+func TestBuildMessage(t *testing.T) {
+	from := mail.Address{Name: "JoinTrip", Address: "no-reply@jointrip.example"}
+	to := mail.Address{Name: "Anna", Address: "anna@example.com"}
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	raw, err := buildMessage(from, to, &email.Message{
+		To:      to.String(),
+		Subject: "Alps a été modifié",
+		Text:    "Le voyage a lieu du 01/07/2026 au 14/07/2026. " + strings.Repeat("long line ", 20),
+		HTML:    `<p style="margin:0">Le voyage</p>`,
+	}, now)
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Alps a été modifié", subject)
+	assert.Equal(t, `"JoinTrip" <no-reply@jointrip.example>`, msg.Header.Get("From"))
+	assert.Equal(t, `"Anna" <anna@example.com>`, msg.Header.Get("To"))
+	assert.Contains(t, msg.Header.Get("Message-ID"), "@jointrip.example>")
+	date, err := msg.Header.Date()
+	require.NoError(t, err)
+	assert.True(t, date.Equal(now))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	var contentTypes, bodies []string
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, "quoted-printable", part.Header.Get("Content-Transfer-Encoding"))
+
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		require.NoError(t, err)
+		contentTypes = append(contentTypes, part.Header.Get("Content-Type"))
+		bodies = append(bodies, string(body))
+	}
+
+	assert.Equal(t, []string{"text/plain; charset=utf-8", "text/html; charset=utf-8"}, contentTypes)
+	assert.True(t, strings.HasPrefix(bodies[0], "Le voyage a lieu du 01/07/2026"))
+	assert.Equal(t, `<p style="margin:0">Le voyage</p>`, bodies[1])
+}
@@ -0,0 +1,125 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+
+	"jointrip/internal/domain/realtime"
+
+	"github.com/google/uuid"
+)
+
+// DefaultSubscriptionBuffer is the number of events a subscription can queue
+// before it is considered too slow and dropped
+const DefaultSubscriptionBuffer = 64
+
+// Subscription receives the events addressed to one user on this server
+type Subscription struct {
+	UserID uuid.UUID
+	events chan realtime.Event
+	hub    *Hub
+}
+
+// Events returns the subscription's event stream. The channel is closed when
+// the subscription ends, either through Close or because it fell behind.
+func (s *Subscription) Events() <-chan realtime.Event {
+	return s.events
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() {
+	s.hub.remove(s)
+}
+
+// Hub keeps track of the clients connected to this server and delivers events
+// to them. On its own it is a complete broker for a single server; behind a
+// shared broker it handles the local end of the fan-out.
+type Hub struct {
+	mu            sync.RWMutex
+	subscriptions map[uuid.UUID]map[*Subscription]struct{}
+	bufferSize    int
+}
+
+// NewHub creates a new hub whose subscriptions buffer up to bufferSize events
+func NewHub(bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = DefaultSubscriptionBuffer
+	}
+
+	return &Hub{
+		subscriptions: make(map[uuid.UUID]map[*Subscription]struct{}),
+		bufferSize:    bufferSize,
+	}
+}
+
+// Subscribe registers a new client of the user
+func (h *Hub) Subscribe(userID uuid.UUID) *Subscription {
+	sub := &Subscription{
+		UserID: userID,
+		events: make(chan realtime.Event, h.bufferSize),
+		hub:    h,
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.subscriptions[userID] == nil {
+		h.subscriptions[userID] = make(map[*Subscription]struct{})
+	}
+	h.subscriptions[userID][sub] = struct{}{}
+
+	return sub
+}
+
+// Publish implements realtime.Broker for a single server
+func (h *Hub) Publish(ctx context.Context, delivery realtime.Delivery) error {
+	h.Deliver(delivery)
+	return nil
+}
+
+// Deliver hands an event to the local subscriptions of its users. Subscriptions
+// whose buffer is full are closed rather than allowed to hold up the others.
+func (h *Hub) Deliver(delivery realtime.Delivery) {
+	var slow []*Subscription
+
+	h.mu.RLock()
+	for _, userID := range delivery.UserIDs {
+		for sub := range h.subscriptions[userID] {
+			select {
+			case sub.events <- delivery.Event:
+			default:
+				slow = append(slow, sub)
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range slow {
+		h.remove(sub)
+	}
+}
+
+// Connected returns the number of subscriptions of a user on this server
+func (h *Hub) Connected(userID uuid.UUID) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.subscriptions[userID])
+}
+
+// remove unregisters a subscription and closes its stream
+func (h *Hub) remove(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subs := h.subscriptions[sub.UserID]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subscriptions, sub.UserID)
+	}
+	close(sub.events)
+}
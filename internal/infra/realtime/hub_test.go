@@ -0,0 +1,73 @@
+package realtime
+
+import (
+	"context"
+	"testing"
+
+	"jointrip/internal/domain/realtime"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_Deliver(t *testing.T) {
+	hub := NewHub(4)
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+
+	alicePhone := hub.Subscribe(alice)
+	aliceLaptop := hub.Subscribe(alice)
+	bobPhone := hub.Subscribe(bob)
+	assert.Equal(t, 2, hub.Connected(alice))
+
+	e, err := realtime.NewEvent(realtime.TypeTyping, map[string]string{"hello": "world"})
+	require.NoError(t, err)
+
+	require.NoError(t, hub.Publish(context.Background(), realtime.Delivery{
+		UserIDs: []uuid.UUID{alice, carol},
+		Event:   e,
+	}))
+
+	assert.Equal(t, e, <-alicePhone.Events())
+	assert.Equal(t, e, <-aliceLaptop.Events())
+	assert.Empty(t, bobPhone.Events())
+}
+
+func TestSubscription_Close(t *testing.T) {
+	hub := NewHub(4)
+	userID := uuid.New()
+
+	sub := hub.Subscribe(userID)
+	sub.Close()
+	sub.Close()
+
+	_, open := <-sub.Events()
+	assert.False(t, open)
+	assert.Zero(t, hub.Connected(userID))
+
+	// Delivering to a user without subscriptions is a no-op
+	hub.Deliver(realtime.Delivery{UserIDs: []uuid.UUID{userID}, Event: realtime.Event{Type: realtime.TypeHeartbeat}})
+}
+
+func TestHub_DropsSlowSubscriptions(t *testing.T) {
+	hub := NewHub(1)
+	userID := uuid.New()
+
+	slow := hub.Subscribe(userID)
+	delivery := realtime.Delivery{UserIDs: []uuid.UUID{userID}, Event: realtime.Event{Type: realtime.TypeHeartbeat}}
+
+	hub.Deliver(delivery)
+	hub.Deliver(delivery)
+
+	// The buffered event is still readable, then the stream ends
+	_, open := <-slow.Events()
+	assert.True(t, open)
+	_, open = <-slow.Events()
+	assert.False(t, open)
+	assert.Zero(t, hub.Connected(userID))
+
+	// A fresh subscription is unaffected
+	fresh := hub.Subscribe(userID)
+	hub.Deliver(delivery)
+	assert.Len(t, fresh.Events(), 1)
+}
@@ -0,0 +1,181 @@
+package realtime
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"jointrip/internal/domain/realtime"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// notifyChannel is the Postgres channel deliveries are announced on
+const notifyChannel = "realtime_deliveries"
+
+// Retention and listener settings of the Postgres broker
+const (
+	deliveryRetention    = time.Hour
+	pruneInterval        = 10 * time.Minute
+	minReconnectInterval = time.Second
+	maxReconnectInterval = time.Minute
+)
+
+// PostgresBroker fans deliveries out to every server through Postgres
+// LISTEN/NOTIFY. NOTIFY payloads are limited to 8000 bytes, so deliveries are
+// stored in a table and only their IDs are sent; each server loads the
+// delivery and hands it to its local hub.
+type PostgresBroker struct {
+	db     *sql.DB
+	dsn    string
+	hub    *Hub
+	logger *logrus.Logger
+
+	// lastID is the highest delivery ID handed to the hub, used to catch up
+	// after the listener reconnects. It is only touched by Run.
+	lastID int64
+}
+
+// NewPostgresBroker creates a broker that listens on the database at dsn and
+// delivers to the given hub
+func NewPostgresBroker(db *sql.DB, dsn string, hub *Hub, logger *logrus.Logger) *PostgresBroker {
+	return &PostgresBroker{
+		db:     db,
+		dsn:    dsn,
+		hub:    hub,
+		logger: logger,
+	}
+}
+
+// Publish stores the delivery and notifies every listening server, including
+// this one
+func (b *PostgresBroker) Publish(ctx context.Context, delivery realtime.Delivery) error {
+	payload, err := json.Marshal(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to encode delivery: %w", err)
+	}
+
+	query := `
+		WITH delivery AS (
+			INSERT INTO realtime_deliveries (payload) VALUES ($1) RETURNING id
+		)
+		SELECT pg_notify($2, id::text) FROM delivery`
+
+	if _, err := b.db.ExecContext(ctx, query, payload, notifyChannel); err != nil {
+		return fmt.Errorf("failed to publish delivery: %w", err)
+	}
+
+	return nil
+}
+
+// Run listens for deliveries until the context is canceled
+func (b *PostgresBroker) Run(ctx context.Context) error {
+	if err := b.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM realtime_deliveries`).Scan(&b.lastID); err != nil {
+		return fmt.Errorf("failed to load last delivery: %w", err)
+	}
+
+	listener := pq.NewListener(b.dsn, minReconnectInterval, maxReconnectInterval, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			b.logger.WithError(err).Warn("Realtime listener connection problem")
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(notifyChannel); err != nil {
+		return fmt.Errorf("failed to listen for deliveries: %w", err)
+	}
+
+	prune := time.NewTicker(pruneInterval)
+	defer prune.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.Notify:
+			if err := b.handleNotification(ctx, n); err != nil {
+				b.logger.WithError(err).Error("Failed to deliver realtime event")
+			}
+		case <-prune.C:
+			if err := b.prune(ctx); err != nil {
+				b.logger.WithError(err).Error("Failed to prune realtime deliveries")
+			}
+		}
+	}
+}
+
+// handleNotification delivers the announced delivery, or every delivery
+// missed while the listener was reconnecting
+func (b *PostgresBroker) handleNotification(ctx context.Context, n *pq.Notification) error {
+	if n == nil {
+		return b.catchUp(ctx)
+	}
+
+	id, err := strconv.ParseInt(n.Extra, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid delivery notification %q: %w", n.Extra, err)
+	}
+
+	var payload []byte
+	err = b.db.QueryRowContext(ctx, `SELECT payload FROM realtime_deliveries WHERE id = $1`, id).Scan(&payload)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load delivery: %w", err)
+	}
+
+	return b.deliver(id, payload)
+}
+
+// catchUp delivers the deliveries stored since the last one handed to the hub
+func (b *PostgresBroker) catchUp(ctx context.Context) error {
+	rows, err := b.db.QueryContext(ctx, `
+		SELECT id, payload FROM realtime_deliveries WHERE id > $1 ORDER BY id`, b.lastID)
+	if err != nil {
+		return fmt.Errorf("failed to load missed deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var payload []byte
+		if err := rows.Scan(&id, &payload); err != nil {
+			return fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		if err := b.deliver(id, payload); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// deliver decodes a stored delivery and hands it to the hub
+func (b *PostgresBroker) deliver(id int64, payload []byte) error {
+	var delivery realtime.Delivery
+	if err := json.Unmarshal(payload, &delivery); err != nil {
+		return fmt.Errorf("failed to decode delivery %d: %w", id, err)
+	}
+
+	b.hub.Deliver(delivery)
+	if id > b.lastID {
+		b.lastID = id
+	}
+	return nil
+}
+
+// prune removes deliveries that every listener has had time to load
+func (b *PostgresBroker) prune(ctx context.Context) error {
+	_, err := b.db.ExecContext(ctx, `DELETE FROM realtime_deliveries WHERE created_at < $1`,
+		time.Now().Add(-deliveryRetention))
+	if err != nil {
+		return fmt.Errorf("failed to delete old deliveries: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"jointrip/internal/domain/expense"
+)
+
+// WriteCSV writes a report as CSV in sections, each a title row, a header row
+// and the data, separated by blank rows: the expenses with one row per share,
+// the totals per participant, the balances and the settlement plan. Amounts
+// are in major units so spreadsheets read them as numbers.
+func WriteCSV(w io.Writer, r *expense.Report) error {
+	out := csv.NewWriter(w)
+
+	out.Write([]string{"Expense report", cell(r.TripTitle)})
+	out.Write([]string{"Generated", r.GeneratedAt.UTC().Format(timestampLayout)})
+	for _, detail := range filterDetails(r.Filter) {
+		out.Write([]string{detail[0], detail[1]})
+	}
+
+	out.Write(nil)
+	out.Write([]string{"Expenses"})
+	out.Write([]string{
+		"Date", "Title", "Category", "Paid by", "Currency", "Amount", "Base currency", "Base amount",
+		"Split method", "Participant", "Share", "Base share", "Settled", "Settled at", "Notes",
+	})
+	for _, e := range r.Expenses {
+		for _, share := range e.Shares {
+			settled, settledAt := "no", ""
+			if share.IsSettled {
+				settled = "yes"
+				if share.SettledAt != nil {
+					settledAt = share.SettledAt.UTC().Format(timestampLayout)
+				}
+			}
+			out.Write([]string{
+				e.Date.Format(dateLayout),
+				cell(e.Title),
+				string(e.Category),
+				cell(r.Name(e.PayerID)),
+				e.Currency,
+				formatDecimal(e.Amount, e.Currency),
+				e.BaseCurrency,
+				formatDecimal(e.BaseAmount, e.BaseCurrency),
+				string(e.SplitMethod),
+				cell(r.Name(share.UserID)),
+				formatDecimal(share.AmountOwed, e.Currency),
+				formatDecimal(share.BaseAmountOwed, e.BaseCurrency),
+				settled,
+				settledAt,
+				cell(e.Notes),
+			})
+		}
+	}
+
+	out.Write(nil)
+	out.Write([]string{"Split per participant"})
+	out.Write([]string{"Participant", "Currency", "Paid", "Share"})
+	for _, total := range r.Totals {
+		out.Write([]string{
+			cell(r.Name(total.UserID)),
+			total.Currency,
+			formatDecimal(total.Paid, total.Currency),
+			formatDecimal(total.Share, total.Currency),
+		})
+	}
+
+	out.Write(nil)
+	out.Write([]string{"Balances"})
+	out.Write([]string{"Participant", "Currency", "Paid for others", "Owed to others", "Net"})
+	for _, b := range r.Balances {
+		out.Write([]string{
+			cell(r.Name(b.UserID)),
+			b.Currency,
+			formatDecimal(b.Paid, b.Currency),
+			formatDecimal(b.Owed, b.Currency),
+			formatDecimal(b.Net, b.Currency),
+		})
+	}
+
+	out.Write(nil)
+	out.Write([]string{"Settlement plan"})
+	out.Write([]string{"From", "To", "Currency", "Amount"})
+	for _, t := range r.Transfers {
+		out.Write([]string{
+			cell(r.Name(t.FromUserID)),
+			cell(r.Name(t.ToUserID)),
+			t.Currency,
+			formatDecimal(t.Amount, t.Currency),
+		})
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// cell makes user-entered text safe for spreadsheets, which would run text
+// starting with a formula character as a formula
+func cell(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
@@ -0,0 +1,54 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, testReport(t)))
+
+	reader := csv.NewReader(&buf)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	require.NoError(t, err)
+
+	// Blank rows separate the sections and are skipped by the reader
+	assert.Equal(t, []string{"Expense report", "Lisbon Weekend!"}, records[0])
+	assert.Equal(t, []string{"Generated", "2026-10-18 09:30 UTC"}, records[1])
+	assert.Equal(t, []string{"Category", "All"}, records[2])
+	assert.Equal(t, []string{"From", "2026-10-16"}, records[3])
+	assert.Equal(t, []string{"To", ""}, records[4])
+
+	assert.Equal(t, []string{"Expenses"}, records[5])
+	assert.Equal(t, "Date", records[6][0])
+	assert.Equal(t, []string{
+		"2026-10-16", "'=Dinner (Tasca)", "food", "Anna Silva", "EUR", "90.00", "EUR", "90.00",
+		"equal", "Anna Silva", "30.00", "30.00", "no", "", "",
+	}, records[7])
+	assert.Equal(t, "00000000", records[9][9])
+	assert.Equal(t, []string{
+		"2026-10-16", "Museum", "activities", "Ben", "GBP", "20.00", "EUR", "24.00",
+		"equal", "Anna Silva", "10.00", "12.00", "yes", "2026-10-16 00:00 UTC", "Café ticket",
+	}, records[10])
+
+	assert.Equal(t, []string{"Split per participant"}, records[12])
+	assert.Equal(t, []string{"Participant", "Currency", "Paid", "Share"}, records[13])
+	assert.Equal(t, []string{"Anna Silva", "EUR", "90.00", "30.00"}, records[14])
+	assert.Equal(t, []string{"Ben", "GBP", "20.00", "10.00"}, records[18])
+
+	assert.Equal(t, []string{"Balances"}, records[19])
+	assert.Equal(t, []string{"Anna Silva", "EUR", "60.00", "0.00", "60.00"}, records[21])
+
+	// Anna's settled museum share no longer counts
+	assert.Equal(t, []string{"Settlement plan"}, records[24])
+	assert.Equal(t, []string{"From", "To", "Currency", "Amount"}, records[25])
+	assert.Equal(t, []string{"Ben", "Anna Silva", "EUR", "30.00"}, records[26])
+	assert.Equal(t, []string{"00000000", "Anna Silva", "EUR", "30.00"}, records[27])
+	assert.Len(t, records, 28)
+}
@@ -0,0 +1,300 @@
+package report
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Page geometry of an A4 page in points
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	pageMargin = 42.0
+)
+
+// pdfFont is one of the standard Type 1 fonts every PDF reader provides, so
+// nothing has to be embedded
+type pdfFont string
+
+const (
+	fontRegular pdfFont = "F1"
+	fontBold    pdfFont = "F2"
+)
+
+// pdfColumn is a table column; right aligned columns suit amounts
+type pdfColumn struct {
+	Width float64
+	Right bool
+}
+
+// pdfDocument lays out text on pages from top to bottom and encodes them as
+// a PDF 1.4 file. Text is set in Helvetica with the WinAnsi encoding, which
+// covers Western European languages; other characters become "?".
+type pdfDocument struct {
+	title string
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+}
+
+// newPDFDocument creates a document with one empty page
+func newPDFDocument(title string) *pdfDocument {
+	d := &pdfDocument{title: title}
+	d.newPage()
+	return d
+}
+
+// newPage starts a new page with the cursor at the top margin
+func (d *pdfDocument) newPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+	d.y = pageHeight - pageMargin
+}
+
+// ensure starts a new page unless height fits above the bottom margin, and
+// reports whether it did
+func (d *pdfDocument) ensure(height float64) bool {
+	// The footer sits in the bottom margin, so keep clear of it
+	if d.y-height >= pageMargin+12 {
+		return false
+	}
+	d.newPage()
+	return true
+}
+
+// line writes one line of text at the left margin and moves the cursor down
+func (d *pdfDocument) line(font pdfFont, size float64, text string) {
+	leading := size * 1.4
+	d.ensure(leading)
+	d.y -= leading
+	d.text(d.page, font, size, pageMargin, d.y, text)
+}
+
+// space moves the cursor down, without starting a new page
+func (d *pdfDocument) space(height float64) {
+	d.y -= height
+}
+
+// table writes rows under a bold header row, repeating the header at the
+// top of every page the table continues on. Cells are cut to fit their column.
+func (d *pdfDocument) table(columns []pdfColumn, header []string, rows []pdfRow) {
+	const size, leading = 8.5, 12.0
+
+	writeHeader := func() {
+		d.y -= leading
+		d.cells(columns, header, fontBold, size, 0)
+		d.rule(d.y - 3)
+		d.y -= 3
+	}
+
+	d.ensure(2 * leading)
+	writeHeader()
+	for _, row := range rows {
+		if d.ensure(leading) {
+			writeHeader()
+		}
+		d.y -= leading
+		d.cells(columns, row.Cells, fontRegular, size, row.Gray)
+	}
+}
+
+// pdfRow is a table row; Gray shades the text, 0 being black
+type pdfRow struct {
+	Cells []string
+	Gray  float64
+}
+
+// cells writes one table row at the cursor
+func (d *pdfDocument) cells(columns []pdfColumn, values []string, font pdfFont, size, gray float64) {
+	if gray > 0 {
+		fmt.Fprintf(d.page, "%s g\n", formatNumber(gray))
+	}
+
+	const padding = 4.0
+	x := pageMargin
+	for i, column := range columns {
+		if i < len(values) && values[i] != "" {
+			text := fitText(values[i], size, column.Width-padding)
+			textX := x
+			if column.Right {
+				textX = x + column.Width - padding - textWidth(text, size)
+			}
+			d.text(d.page, font, size, textX, d.y, text)
+		}
+		x += column.Width
+	}
+
+	if gray > 0 {
+		d.page.WriteString("0 g\n")
+	}
+}
+
+// rule draws a thin horizontal line across the page at the given height
+func (d *pdfDocument) rule(y float64) {
+	fmt.Fprintf(d.page, "0.5 w %s %s m %s %s l S\n",
+		formatNumber(pageMargin), formatNumber(y), formatNumber(pageWidth-pageMargin), formatNumber(y))
+}
+
+// text writes a text object to a content stream
+func (d *pdfDocument) text(page *bytes.Buffer, font pdfFont, size, x, y float64, text string) {
+	fmt.Fprintf(page, "BT /%s %s Tf %s %s Td %s Tj ET\n",
+		font, formatNumber(size), formatNumber(x), formatNumber(y), pdfString(text))
+}
+
+// Encode writes the document to w, numbering the pages in their footers
+func (d *pdfDocument) Encode(w io.Writer) error {
+	var buf bytes.Buffer
+	var offsets []int
+
+	// Objects are numbered from 1 in the order they are written
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1 catalog, 2 page tree, 3 and 4 fonts, 5 info, then a page and its
+	// content stream for every page
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title %s /Producer (JoinTrip) >>", pdfString(d.title)))
+
+	for i, page := range d.pages {
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(d.pages))
+		d.text(page, fontRegular, 8, pageWidth-pageMargin-textWidth(footer, 8), pageMargin/2, footer)
+
+		var stream bytes.Buffer
+		zw := zlib.NewWriter(&stream)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			formatNumber(pageWidth), formatNumber(pageHeight), firstPage+2*i+1,
+		))
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", stream.Len(), stream.Bytes()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// winAnsi maps the characters of the WinAnsi encoding outside Latin-1
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// encodeWinAnsi converts text to the WinAnsi encoding
+func encodeWinAnsi(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			encoded = append(encoded, byte(r))
+		case winAnsi[r] != 0:
+			encoded = append(encoded, winAnsi[r])
+		case r == '\t' || r == '\n' || r == '\r':
+			encoded = append(encoded, ' ')
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded
+}
+
+// pdfString encodes text as a PDF literal string
+func pdfString(text string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, c := range encodeWinAnsi(text) {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c >= 0x80:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// helveticaWidths are the Helvetica glyph widths of the printable ASCII
+// characters, in thousandths of the font size
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, // 0 to 9
+	278, 278, 584, 584, 584, 556, 1015, // : to @
+	667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, // A to M
+	722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, // N to Z
+	278, 278, 278, 469, 556, 333, // [ to `
+	556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, // a to m
+	556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, // n to z
+	334, 260, 334, 584, // { to ~
+}
+
+// textWidth estimates the width of text in points. Characters outside ASCII
+// count as wide as a digit, which is close enough for the accented letters
+// the encoding covers.
+func textWidth(text string, size float64) float64 {
+	total := 0
+	for _, r := range text {
+		if r >= 0x20 && r < 0x7f {
+			total += helveticaWidths[r-0x20]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// fitText cuts text to fit a width, ending it with an ellipsis when cut
+func fitText(text string, size, width float64) string {
+	if textWidth(text, size) <= width {
+		return text
+	}
+	for len(text) > 0 {
+		_, n := utf8.DecodeLastRuneInString(text)
+		text = text[:len(text)-n]
+		if textWidth(text+"…", size) <= width {
+			return text + "…"
+		}
+	}
+	return ""
+}
+
+// formatNumber formats a coordinate or size with at most two decimals
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
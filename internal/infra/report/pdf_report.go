@@ -0,0 +1,120 @@
+package report
+
+import (
+	"io"
+
+	"jointrip/internal/domain/expense"
+)
+
+// WritePDF writes a report as a PDF: the expenses with the share of every
+// participant below each, the totals per participant, the balances and the
+// settlement plan
+func WritePDF(w io.Writer, r *expense.Report) error {
+	doc := newPDFDocument("Expense report: " + r.TripTitle)
+
+	doc.line(fontBold, 16, "Expense report: "+r.TripTitle)
+	doc.space(4)
+	doc.line(fontRegular, 9, "Generated "+r.GeneratedAt.UTC().Format(timestampLayout))
+	filter := ""
+	for i, detail := range filterDetails(r.Filter) {
+		if detail[1] == "" {
+			continue
+		}
+		if i > 0 {
+			filter += "    "
+		}
+		filter += detail[0] + ": " + detail[1]
+	}
+	doc.line(fontRegular, 9, filter)
+
+	doc.space(10)
+	doc.line(fontBold, 12, "Expenses")
+	if len(r.Expenses) == 0 {
+		doc.line(fontRegular, 9, "No expenses match this report.")
+	} else {
+		var rows []pdfRow
+		for _, e := range r.Expenses {
+			rows = append(rows, pdfRow{Cells: []string{
+				e.Date.Format(dateLayout),
+				e.Title,
+				string(e.Category),
+				r.Name(e.PayerID),
+				formatAmount(e.Amount, e.Currency),
+				formatAmount(e.BaseAmount, e.BaseCurrency),
+			}})
+			for _, share := range e.Shares {
+				status := ""
+				if share.IsSettled {
+					status = "settled"
+				}
+				rows = append(rows, pdfRow{Gray: 0.4, Cells: []string{
+					"",
+					"    " + r.Name(share.UserID),
+					status,
+					"",
+					formatAmount(share.AmountOwed, e.Currency),
+					formatAmount(share.BaseAmountOwed, e.BaseCurrency),
+				}})
+			}
+		}
+		doc.table([]pdfColumn{
+			{Width: 58}, {Width: 150}, {Width: 66}, {Width: 87},
+			{Width: 75, Right: true}, {Width: 75.28, Right: true},
+		}, []string{"Date", "Title", "Category", "Paid by", "Amount", "Base amount"}, rows)
+	}
+
+	doc.space(10)
+	doc.line(fontBold, 12, "Split per participant")
+	if len(r.Totals) > 0 {
+		var rows []pdfRow
+		for _, total := range r.Totals {
+			rows = append(rows, pdfRow{Cells: []string{
+				r.Name(total.UserID),
+				formatAmount(total.Paid, total.Currency),
+				formatAmount(total.Share, total.Currency),
+			}})
+		}
+		doc.table([]pdfColumn{
+			{Width: 211.28}, {Width: 150, Right: true}, {Width: 150, Right: true},
+		}, []string{"Participant", "Paid", "Share"}, rows)
+	}
+
+	doc.space(10)
+	doc.line(fontBold, 12, "Balances")
+	if len(r.Balances) == 0 {
+		doc.line(fontRegular, 9, "Everyone is settled up.")
+	} else {
+		var rows []pdfRow
+		for _, b := range r.Balances {
+			rows = append(rows, pdfRow{Cells: []string{
+				r.Name(b.UserID),
+				formatAmount(b.Paid, b.Currency),
+				formatAmount(b.Owed, b.Currency),
+				formatAmount(b.Net, b.Currency),
+			}})
+		}
+		doc.table([]pdfColumn{
+			{Width: 151.28}, {Width: 120, Right: true}, {Width: 120, Right: true}, {Width: 120, Right: true},
+		}, []string{"Participant", "Paid for others", "Owed to others", "Net"}, rows)
+	}
+
+	doc.space(10)
+	doc.line(fontBold, 12, "Settlement plan")
+	if len(r.Transfers) == 0 {
+		doc.line(fontRegular, 9, "No payments are needed.")
+	} else {
+		var rows []pdfRow
+		for _, t := range r.Transfers {
+			rows = append(rows, pdfRow{Cells: []string{
+				r.Name(t.FromUserID),
+				r.Name(t.ToUserID),
+				formatAmount(t.Amount, t.Currency),
+			}})
+		}
+		doc.table([]pdfColumn{
+			{Width: 181.28}, {Width: 180}, {Width: 150, Right: true},
+		}, []string{"From", "To", "Amount"}, rows)
+	}
+
+	return doc.Encode(w)
+}
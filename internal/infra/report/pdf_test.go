@@ -0,0 +1,135 @@
+package report
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"jointrip/internal/domain/currency"
+	"jointrip/internal/domain/expense"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var streamPattern = regexp.MustCompile(`(?s)<< /Length (\d+) /Filter /FlateDecode >>\nstream\n`)
+
+// checkPDF verifies the cross-reference table of a PDF and returns the
+// decompressed content streams of its pages
+func checkPDF(t *testing.T, data []byte) []string {
+	t.Helper()
+	require.True(t, bytes.HasPrefix(data, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(data, []byte("%%EOF\n")))
+
+	trailer := data[bytes.LastIndex(data, []byte("startxref\n"))+len("startxref\n"):]
+	xref, err := strconv.Atoi(string(trailer[:bytes.IndexByte(trailer, '\n')]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(data[xref:], []byte("xref\n0 ")))
+
+	lines := strings.Split(string(data[xref:]), "\n")
+	count, err := strconv.Atoi(strings.Fields(lines[1])[1])
+	require.NoError(t, err)
+	for i := 1; i < count; i++ {
+		offset, err := strconv.Atoi(lines[2+i][:10])
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(data[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i))), "object %d", i)
+	}
+
+	var pages []string
+	for _, match := range streamPattern.FindAllSubmatchIndex(data, -1) {
+		length, err := strconv.Atoi(string(data[match[2]:match[3]]))
+		require.NoError(t, err)
+		stream := data[match[1] : match[1]+length]
+		require.True(t, bytes.HasPrefix(data[match[1]+length:], []byte("\nendstream")))
+
+		zr, err := zlib.NewReader(bytes.NewReader(stream))
+		require.NoError(t, err)
+		content, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		pages = append(pages, string(content))
+	}
+	return pages
+}
+
+func TestWritePDF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePDF(&buf, testReport(t)))
+
+	pages := checkPDF(t, buf.Bytes())
+	require.Len(t, pages, 1)
+	assert.Contains(t, buf.String(), "/Count 1")
+
+	content := pages[0]
+	assert.Contains(t, content, "(Expense report: Lisbon Weekend!) Tj")
+	assert.Contains(t, content, "(Category: All    From: 2026-10-16) Tj")
+	assert.Contains(t, content, `(=Dinner \(Tasca\)) Tj`)
+	assert.Contains(t, content, "(90.00 EUR) Tj")
+	assert.Contains(t, content, "(    Anna Silva) Tj")
+	assert.Contains(t, content, "(settled) Tj")
+	assert.Contains(t, content, "(24.00 EUR) Tj")
+	assert.Contains(t, content, "(Settlement plan) Tj")
+	assert.Contains(t, content, "(Page 1 of 1) Tj")
+}
+
+func TestWritePDF_ManyPages(t *testing.T) {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	var expenses []*expense.Expense
+	for i := 0; i < 120; i++ {
+		e, err := expense.NewExpense(uuid.New(), anna, expense.Details{
+			PayerID: anna, Title: strings.Repeat("Very long expense title ", 5), Amount: 1000,
+			Currency: "EUR", Category: expense.CategoryFood, Date: day,
+		}, expense.SplitEqual, []expense.Portion{{UserID: anna}, {UserID: ben}})
+		require.NoError(t, err)
+		require.NoError(t, e.ApplyRate(currency.Identity("EUR", day)))
+		expenses = append(expenses, e)
+	}
+	report := expense.NewReport("Long trip", expense.ListFilter{}, expenses, nil, day)
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePDF(&buf, report))
+
+	pages := checkPDF(t, buf.Bytes())
+	require.Greater(t, len(pages), 4)
+	last := fmt.Sprintf("Page %d of %d", len(pages), len(pages))
+	assert.Contains(t, pages[len(pages)-1], "("+last+") Tj")
+
+	// The table header is repeated on every page and titles are cut to fit
+	for _, page := range pages[:len(pages)-1] {
+		assert.Contains(t, page, "(Title) Tj")
+	}
+	assert.Contains(t, pages[0], `\205) Tj`)
+}
+
+func TestPDFString(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"plain", "(plain)"},
+		{`a(b)c\d`, `(a\(b\)c\\d)`},
+		{"Café €5 — ok", `(Caf\351 \2005 \227 ok)`},
+		{"東京\tline", "(?? line)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.expected, pdfString(tt.text))
+		})
+	}
+}
+
+func TestFitText(t *testing.T) {
+	assert.Equal(t, "Short", fitText("Short", 10, 100))
+
+	cut := fitText(strings.Repeat("W", 50), 10, 100)
+	assert.True(t, strings.HasSuffix(cut, "…"))
+	assert.LessOrEqual(t, textWidth(cut, 10), 100.0)
+	assert.Equal(t, "", fitText("Wide", 10, 1))
+}
@@ -0,0 +1,82 @@
+package report
+
+import (
+	"strings"
+	"time"
+
+	"jointrip/internal/domain/currency"
+	"jointrip/internal/domain/expense"
+)
+
+// Content types of the report formats
+const (
+	ContentTypeCSV = "text/csv; charset=utf-8"
+	ContentTypePDF = "application/pdf"
+)
+
+const (
+	dateLayout      = "2006-01-02"
+	timestampLayout = "2006-01-02 15:04 MST"
+)
+
+// ContentType returns the content type of a report format
+func ContentType(format expense.ReportFormat) string {
+	if format == expense.ReportPDF {
+		return ContentTypePDF
+	}
+	return ContentTypeCSV
+}
+
+// Filename returns the download name of a report, such as
+// "lisbon-weekend-expenses-2026-10-18.pdf"
+func Filename(r *expense.Report, format expense.ReportFormat) string {
+	var slug strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(r.TripTitle) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+			slug.WriteRune(c)
+			dash = false
+		case !dash && slug.Len() > 0:
+			slug.WriteByte('-')
+			dash = true
+		}
+	}
+
+	name := strings.TrimSuffix(slug.String(), "-")
+	if name == "" {
+		name = "trip"
+	}
+	return name + "-expenses-" + r.GeneratedAt.UTC().Format(dateLayout) + "." + string(format)
+}
+
+// filterDetails describes the filter a report was produced with
+func filterDetails(filter expense.ListFilter) [][2]string {
+	category := "All"
+	if filter.Category != "" {
+		category = string(filter.Category)
+	}
+	return [][2]string{
+		{"Category", category},
+		{"From", formatOptionalDate(filter.From)},
+		{"To", formatOptionalDate(filter.To)},
+	}
+}
+
+// formatOptionalDate formats a date, or returns an empty string without one
+func formatOptionalDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+// formatAmount formats an amount in minor units with its currency code
+func formatAmount(amount int64, code string) string {
+	return currency.Money{Currency: code, Amount: amount}.String()
+}
+
+// formatDecimal formats an amount in minor units in major units
+func formatDecimal(amount int64, code string) string {
+	return currency.Money{Currency: code, Amount: amount}.Decimal()
+}
@@ -0,0 +1,59 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"jointrip/internal/domain/currency"
+	"jointrip/internal/domain/expense"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	anna  = uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	ben   = uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	clara = uuid.MustParse("00000000-0000-0000-0000-00000000000c")
+)
+
+// testReport returns a report of two expenses, one of them in a foreign
+// currency with a settled share
+func testReport(t *testing.T) *expense.Report {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	tripID := uuid.New()
+
+	dinner, err := expense.NewExpense(tripID, anna, expense.Details{
+		PayerID: anna, Title: "=Dinner (Tasca)", Amount: 9000, Currency: "EUR",
+		Category: expense.CategoryFood, Date: day,
+	}, expense.SplitEqual, []expense.Portion{{UserID: anna}, {UserID: ben}, {UserID: clara}})
+	require.NoError(t, err)
+	require.NoError(t, dinner.ApplyRate(currency.Identity("EUR", day)))
+
+	rate, err := currency.NewRate("GBP", "EUR", "1.2", day, "test")
+	require.NoError(t, err)
+	museum, err := expense.NewExpense(tripID, ben, expense.Details{
+		PayerID: ben, Title: "Museum", Amount: 2000, Currency: "GBP",
+		Category: expense.CategoryActivities, Date: day, Notes: "Café ticket",
+	}, expense.SplitEqual, []expense.Portion{{UserID: anna}, {UserID: ben}})
+	require.NoError(t, err)
+	require.NoError(t, museum.ApplyRate(rate))
+	require.NoError(t, museum.SettleShare(museum.Shares[0], "", day))
+
+	return expense.NewReport("Lisbon Weekend!", expense.ListFilter{From: &day},
+		[]*expense.Expense{dinner, museum},
+		map[uuid.UUID]string{anna: "Anna Silva", ben: "Ben"},
+		time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC))
+}
+
+func TestFilename(t *testing.T) {
+	report := testReport(t)
+	assert.Equal(t, "lisbon-weekend-expenses-2026-10-18.csv", Filename(report, expense.ReportCSV))
+
+	report.TripTitle = "¡¡!!"
+	assert.Equal(t, "trip-expenses-2026-10-18.pdf", Filename(report, expense.ReportPDF))
+
+	assert.Equal(t, ContentTypePDF, ContentType(expense.ReportPDF))
+	assert.Equal(t, ContentTypeCSV, ContentType(expense.ReportCSV))
+}
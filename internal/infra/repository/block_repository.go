@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"jointrip/internal/domain/user"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// BlockRepository implements the user.BlockRepository interface
+type BlockRepository struct {
+	db *sql.DB
+}
+
+// NewBlockRepository creates a new user block repository
+func NewBlockRepository(db *sql.DB) *BlockRepository {
+	return &BlockRepository{db: db}
+}
+
+// Create stores a block
+func (r *BlockRepository) Create(ctx context.Context, b *user.Block) error {
+	query := `
+		INSERT INTO user_blocks (blocker_id, blocked_id, created_at)
+		VALUES ($1, $2, $3)`
+
+	if _, err := r.db.ExecContext(ctx, query, b.BlockerID, b.BlockedID, b.CreatedAt); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case "23505": // unique_violation
+				return user.ErrAlreadyBlocked
+			case "23503": // foreign_key_violation
+				return user.ErrUserNotFound
+			}
+		}
+		return fmt.Errorf("failed to create block: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a block
+func (r *BlockRepository) Delete(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	query := `DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("failed to delete block: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return user.ErrBlockNotFound
+	}
+
+	return nil
+}
+
+// ListByBlocker retrieves the blocks a user has placed, most recent first
+func (r *BlockRepository) ListByBlocker(ctx context.Context, blockerID uuid.UUID) ([]*user.Block, error) {
+	query := `
+		SELECT blocker_id, blocked_id, created_at
+		FROM user_blocks
+		WHERE blocker_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, blockerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blocks: %w", err)
+	}
+	defer rows.Close()
+
+	blocks := []*user.Block{}
+	for rows.Next() {
+		b := &user.Block{}
+		if err := rows.Scan(&b.BlockerID, &b.BlockedID, &b.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan block: %w", err)
+		}
+		blocks = append(blocks, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating blocks: %w", err)
+	}
+
+	return blocks, nil
+}
+
+// ExistsBetween checks if either user has blocked the other
+func (r *BlockRepository) ExistsBetween(ctx context.Context, userID, otherID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM user_blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2)
+			   OR (blocker_id = $2 AND blocked_id = $1)
+		)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, userID, otherID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check blocks: %w", err)
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"jointrip/internal/domain/calendar"
+
+	"github.com/google/uuid"
+)
+
+// CalendarRepository implements the calendar.Repository interface
+type CalendarRepository struct {
+	db *sql.DB
+}
+
+// NewCalendarRepository creates a new calendar feed token repository
+func NewCalendarRepository(db *sql.DB) *CalendarRepository {
+	return &CalendarRepository{db: db}
+}
+
+// Rotate revokes the user's active token, if any, and stores the new one
+func (r *CalendarRepository) Rotate(ctx context.Context, token *calendar.FeedToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	revoke := `
+		UPDATE calendar_feed_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND revoked_at IS NULL`
+
+	if _, err := tx.ExecContext(ctx, revoke, token.UserID); err != nil {
+		return fmt.Errorf("failed to revoke calendar feed token: %w", err)
+	}
+
+	insert := `
+		INSERT INTO calendar_feed_tokens (id, user_id, token_hash, created_at)
+		VALUES ($1, $2, $3, $4)`
+
+	if _, err := tx.ExecContext(ctx, insert, token.ID, token.UserID, token.TokenHash, token.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create calendar feed token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit calendar feed token: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveByUser retrieves the user's active token
+func (r *CalendarRepository) GetActiveByUser(ctx context.Context, userID uuid.UUID) (*calendar.FeedToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, created_at, last_used_at, revoked_at
+		FROM calendar_feed_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL`
+
+	return r.scanFeedToken(r.db.QueryRowContext(ctx, query, userID))
+}
+
+// GetActiveByHash retrieves an active token by the hash of its secret
+func (r *CalendarRepository) GetActiveByHash(ctx context.Context, tokenHash string) (*calendar.FeedToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, created_at, last_used_at, revoked_at
+		FROM calendar_feed_tokens
+		WHERE token_hash = $1 AND revoked_at IS NULL`
+
+	return r.scanFeedToken(r.db.QueryRowContext(ctx, query, tokenHash))
+}
+
+// RevokeByUser revokes the user's active token
+func (r *CalendarRepository) RevokeByUser(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE calendar_feed_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke calendar feed token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return calendar.ErrFeedTokenNotFound
+	}
+
+	return nil
+}
+
+// MarkUsed records that the token was used to fetch the feed
+func (r *CalendarRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE calendar_feed_tokens SET last_used_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to update calendar feed token: %w", err)
+	}
+
+	return nil
+}
+
+// scanFeedToken scans a feed token from a single row
+func (r *CalendarRepository) scanFeedToken(row *sql.Row) (*calendar.FeedToken, error) {
+	token := &calendar.FeedToken{}
+	err := row.Scan(
+		&token.ID, &token.UserID, &token.TokenHash, &token.CreatedAt, &token.LastUsedAt, &token.RevokedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, calendar.ErrFeedTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to scan calendar feed token: %w", err)
+	}
+
+	return token, nil
+}
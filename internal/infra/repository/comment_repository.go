@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"jointrip/internal/domain/comment"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// commentColumns selects a comment with its mentions and reply count
+const commentColumns = `
+	c.id, c.trip_id, c.user_id, c.parent_comment_id, c.depth, c.content, c.is_edited,
+	c.hidden_at, c.deleted_at, c.created_at, c.updated_at,
+	ARRAY(SELECT m.user_id::text FROM trip_comment_mentions m WHERE m.comment_id = c.id),
+	(SELECT COUNT(*) FROM trip_comments r WHERE r.parent_comment_id = c.id)`
+
+// CommentRepository implements the comment.Repository interface
+type CommentRepository struct {
+	db *sql.DB
+}
+
+// NewCommentRepository creates a new comment repository
+func NewCommentRepository(db *sql.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// Create stores a new comment with its mentions
+func (r *CommentRepository) Create(ctx context.Context, c *comment.Comment) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO trip_comments (
+			id, trip_id, user_id, parent_comment_id, depth, content, is_edited, hidden_at, deleted_at, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		)`
+
+	_, err = tx.ExecContext(ctx, query,
+		c.ID, c.TripID, c.UserID, c.ParentCommentID, c.Depth, c.Content, c.IsEdited, c.HiddenAt, c.DeletedAt, c.CreatedAt, c.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	if err := saveMentions(ctx, tx, c); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit comment: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a comment with its mentions and reply count
+func (r *CommentRepository) GetByID(ctx context.Context, id uuid.UUID) (*comment.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM trip_comments c WHERE c.id = $1`
+
+	c, err := scanComment(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, comment.ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	return c, nil
+}
+
+// List retrieves the trip's comments selected by the filter: top-level
+// comments newest first, or the replies to a comment oldest first
+func (r *CommentRepository) List(ctx context.Context, tripID uuid.UUID, filter comment.ListFilter) ([]*comment.Comment, error) {
+	var afterAt, afterID interface{}
+	if filter.After != nil {
+		afterAt, afterID = filter.After.At, filter.After.ID
+	}
+	args := []interface{}{tripID, afterAt, afterID, filter.Limit}
+
+	query := `SELECT ` + commentColumns + `
+		FROM trip_comments c
+		WHERE c.trip_id = $1 AND c.parent_comment_id IS NULL
+		  AND ($2::timestamptz IS NULL OR (c.created_at, c.id) < ($2::timestamptz, $3::uuid))
+		ORDER BY c.created_at DESC, c.id DESC
+		LIMIT $4`
+
+	if filter.ParentID != nil {
+		query = `SELECT ` + commentColumns + `
+			FROM trip_comments c
+			WHERE c.trip_id = $1 AND c.parent_comment_id = $5
+			  AND ($2::timestamptz IS NULL OR (c.created_at, c.id) > ($2::timestamptz, $3::uuid))
+			ORDER BY c.created_at, c.id
+			LIMIT $4`
+		args = append(args, *filter.ParentID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []*comment.Comment{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+// Update stores a comment's content, mentions and visibility together with
+// the revision of an edit, if any
+func (r *CommentRepository) Update(ctx context.Context, c *comment.Comment, revision *comment.Revision) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updateComment(ctx, tx, c); err != nil {
+		return err
+	}
+
+	if revision != nil {
+		query := `
+			INSERT INTO trip_comment_revisions (id, comment_id, content, edited_at)
+			VALUES ($1, $2, $3, $4)`
+
+		if _, err := tx.ExecContext(ctx, query, revision.ID, revision.CommentID, revision.Content, revision.EditedAt); err != nil {
+			return fmt.Errorf("failed to create comment revision: %w", err)
+		}
+	}
+
+	if err := saveMentions(ctx, tx, c); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit comment: %w", err)
+	}
+
+	return nil
+}
+
+// Delete stores a soft deletion and erases the comment's revisions and mentions
+func (r *CommentRepository) Delete(ctx context.Context, c *comment.Comment) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updateComment(ctx, tx, c); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM trip_comment_revisions WHERE comment_id = $1`, c.ID); err != nil {
+		return fmt.Errorf("failed to delete comment revisions: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM trip_comment_mentions WHERE comment_id = $1`, c.ID); err != nil {
+		return fmt.Errorf("failed to delete comment mentions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit comment deletion: %w", err)
+	}
+
+	return nil
+}
+
+// ListRevisions retrieves the earlier versions of a comment, newest first
+func (r *CommentRepository) ListRevisions(ctx context.Context, commentID uuid.UUID) ([]*comment.Revision, error) {
+	query := `
+		SELECT id, comment_id, content, edited_at
+		FROM trip_comment_revisions
+		WHERE comment_id = $1
+		ORDER BY edited_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comment revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []*comment.Revision{}
+	for rows.Next() {
+		revision := &comment.Revision{}
+		if err := rows.Scan(&revision.ID, &revision.CommentID, &revision.Content, &revision.EditedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan comment revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comment revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// updateComment stores the mutable fields of a comment
+func updateComment(ctx context.Context, tx *sql.Tx, c *comment.Comment) error {
+	query := `
+		UPDATE trip_comments SET
+			content = $2, is_edited = $3, hidden_at = $4, deleted_at = $5, updated_at = $6
+		WHERE id = $1`
+
+	result, err := tx.ExecContext(ctx, query, c.ID, c.Content, c.IsEdited, c.HiddenAt, c.DeletedAt, c.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return comment.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+// saveMentions replaces the users mentioned in a comment
+func saveMentions(ctx context.Context, tx *sql.Tx, c *comment.Comment) error {
+	query := `
+		DELETE FROM trip_comment_mentions
+		WHERE comment_id = $1 AND NOT (user_id = ANY($2::uuid[]))`
+
+	if _, err := tx.ExecContext(ctx, query, c.ID, pq.Array(uuidStrings(c.MentionedUserIDs))); err != nil {
+		return fmt.Errorf("failed to delete comment mentions: %w", err)
+	}
+
+	query = `
+		INSERT INTO trip_comment_mentions (comment_id, user_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT (comment_id, user_id) DO NOTHING`
+
+	if _, err := tx.ExecContext(ctx, query, c.ID, pq.Array(uuidStrings(c.MentionedUserIDs))); err != nil {
+		return fmt.Errorf("failed to save comment mentions: %w", err)
+	}
+
+	return nil
+}
+
+// scanComment reads a comment selected with commentColumns
+func scanComment(row rowScanner) (*comment.Comment, error) {
+	c := &comment.Comment{}
+	var mentions []string
+	err := row.Scan(
+		&c.ID, &c.TripID, &c.UserID, &c.ParentCommentID, &c.Depth, &c.Content, &c.IsEdited,
+		&c.HiddenAt, &c.DeletedAt, &c.CreatedAt, &c.UpdatedAt,
+		pq.Array(&mentions), &c.ReplyCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.MentionedUserIDs = make([]uuid.UUID, 0, len(mentions))
+	for _, mention := range mentions {
+		userID, err := uuid.Parse(mention)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mentioned user ID: %w", err)
+		}
+		c.MentionedUserIDs = append(c.MentionedUserIDs, userID)
+	}
+
+	return c, nil
+}
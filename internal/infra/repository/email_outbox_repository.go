@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"jointrip/internal/domain/email"
+)
+
+// EmailOutboxRepository implements the email.OutboxRepository interface
+type EmailOutboxRepository struct {
+	db *sql.DB
+}
+
+// NewEmailOutboxRepository creates a new email outbox repository
+func NewEmailOutboxRepository(db *sql.DB) *EmailOutboxRepository {
+	return &EmailOutboxRepository{db: db}
+}
+
+// Enqueue stores a new outbox entry
+func (r *EmailOutboxRepository) Enqueue(ctx context.Context, entry *email.OutboxEntry) error {
+	query := `
+		INSERT INTO email_outbox (
+			id, user_id, notification_id, to_address, subject, text_body, html_body,
+			status, attempts, next_attempt_at, last_error, sent_at, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+		)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		entry.ID, entry.UserID, entry.NotificationID, entry.Message.To, entry.Message.Subject, entry.Message.Text, entry.Message.HTML,
+		entry.Status, entry.Attempts, entry.NextAttemptAt, entry.LastError, entry.SentAt, entry.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue email: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDue returns up to limit pending entries whose next attempt is due and
+// pushes their next attempt past the lease. Rows locked by another claimer
+// are skipped.
+func (r *EmailOutboxRepository) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*email.OutboxEntry, error) {
+	query := `
+		UPDATE email_outbox SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id, notification_id, to_address, subject, text_body, html_body,
+			status, attempts, next_attempt_at, last_error, sent_at, created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due emails: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*email.OutboxEntry{}
+	for rows.Next() {
+		entry := &email.OutboxEntry{}
+		if err := rows.Scan(
+			&entry.ID, &entry.UserID, &entry.NotificationID, &entry.Message.To, &entry.Message.Subject, &entry.Message.Text, &entry.Message.HTML,
+			&entry.Status, &entry.Attempts, &entry.NextAttemptAt, &entry.LastError, &entry.SentAt, &entry.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate outbox entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Update stores the delivery state of an entry
+func (r *EmailOutboxRepository) Update(ctx context.Context, entry *email.OutboxEntry) error {
+	query := `
+		UPDATE email_outbox SET
+			status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, sent_at = $6
+		WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query,
+		entry.ID, entry.Status, entry.Attempts, entry.NextAttemptAt, entry.LastError, entry.SentAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update outbox entry: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"jointrip/internal/domain/currency"
+)
+
+// ExchangeRateRepository implements the currency.RateRepository interface
+type ExchangeRateRepository struct {
+	db *sql.DB
+}
+
+// NewExchangeRateRepository creates a new exchange rate repository
+func NewExchangeRateRepository(db *sql.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+// SaveRates stores rates, replacing those already stored for the same pair and day
+func (r *ExchangeRateRepository) SaveRates(ctx context.Context, rates []currency.Rate) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO exchange_rates (base, quote, as_of, rate, source)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (base, quote, as_of) DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source`
+
+	for _, rate := range rates {
+		if _, err := tx.ExecContext(ctx, query, rate.Base, rate.Quote, rate.AsOf, rate.Value, rate.Source); err != nil {
+			return fmt.Errorf("failed to save exchange rate: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit exchange rates: %w", err)
+	}
+
+	return nil
+}
+
+// GetClosest retrieves the rate for a pair published closest to the given
+// day, preferring the latest one on or before it
+func (r *ExchangeRateRepository) GetClosest(ctx context.Context, base, quote string, on time.Time) (currency.Rate, error) {
+	query := `
+		SELECT base, quote, as_of, rate, source
+		FROM exchange_rates
+		WHERE base = $1 AND quote = $2
+		ORDER BY as_of > $3::date, ABS(as_of - $3::date)
+		LIMIT 1`
+
+	var rate currency.Rate
+	var value string
+	err := r.db.QueryRowContext(ctx, query, base, quote, on).Scan(&rate.Base, &rate.Quote, &rate.AsOf, &value, &rate.Source)
+	if err == sql.ErrNoRows {
+		return currency.Rate{}, currency.ErrRateNotFound
+	}
+	if err != nil {
+		return currency.Rate{}, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+
+	rate.Value = trimDecimal(value)
+	return rate, nil
+}
+
+// trimDecimal drops the trailing zeros NUMERIC columns pad decimals with
+func trimDecimal(value string) string {
+	if !strings.Contains(value, ".") {
+		return value
+	}
+	return strings.TrimSuffix(strings.TrimRight(value, "0"), ".")
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"jointrip/internal/domain/expense"
+
+	"github.com/google/uuid"
+)
+
+const budgetLineColumns = `id, trip_id, user_id, category, amount, currency, alert_percent, alert_level, created_at, updated_at`
+
+// ExpenseBudgetRepository implements the expense.BudgetRepository interface
+type ExpenseBudgetRepository struct {
+	db *sql.DB
+}
+
+// NewExpenseBudgetRepository creates a new expense budget repository
+func NewExpenseBudgetRepository(db *sql.DB) *ExpenseBudgetRepository {
+	return &ExpenseBudgetRepository{db: db}
+}
+
+// ListByTrip retrieves the budget lines of a trip, both the trip's plan and
+// its members' personal plans
+func (r *ExpenseBudgetRepository) ListByTrip(ctx context.Context, tripID uuid.UUID) ([]*expense.BudgetLine, error) {
+	query := `
+		SELECT ` + budgetLineColumns + `
+		FROM expense_budget_lines
+		WHERE trip_id = $1
+		ORDER BY user_id NULLS FIRST, category`
+
+	rows, err := r.db.QueryContext(ctx, query, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list budget lines: %w", err)
+	}
+	defer rows.Close()
+
+	lines := []*expense.BudgetLine{}
+	for rows.Next() {
+		line, err := scanBudgetLine(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan budget line: %w", err)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+// ReplacePlan replaces the lines of the trip's plan, or of the member's plan
+// when userID is set
+func (r *ExpenseBudgetRepository) ReplacePlan(ctx context.Context, tripID uuid.UUID, userID *uuid.UUID, lines []*expense.BudgetLine) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM expense_budget_lines WHERE trip_id = $1 AND user_id IS NOT DISTINCT FROM $2`,
+		tripID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to clear budget plan: %w", err)
+	}
+
+	query := `
+		INSERT INTO expense_budget_lines (` + budgetLineColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	for _, line := range lines {
+		_, err := tx.ExecContext(ctx, query,
+			line.ID, line.TripID, line.UserID, line.Category, line.Amount, line.Currency,
+			line.AlertPercent, line.AlertLevel, line.CreatedAt, line.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create budget line: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit budget plan: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateAlertLevels saves the alert level of the lines
+func (r *ExpenseBudgetRepository) UpdateAlertLevels(ctx context.Context, lines []*expense.BudgetLine) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE expense_budget_lines SET alert_level = $2 WHERE id = $1`
+
+	for _, line := range lines {
+		if _, err := tx.ExecContext(ctx, query, line.ID, line.AlertLevel); err != nil {
+			return fmt.Errorf("failed to update budget alert level: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit budget alert levels: %w", err)
+	}
+
+	return nil
+}
+
+// scanBudgetLine scans a budget line row
+func scanBudgetLine(row rowScanner) (*expense.BudgetLine, error) {
+	var line expense.BudgetLine
+	var userID uuid.NullUUID
+	err := row.Scan(
+		&line.ID, &line.TripID, &userID, &line.Category, &line.Amount, &line.Currency,
+		&line.AlertPercent, &line.AlertLevel, &line.CreatedAt, &line.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if userID.Valid {
+		line.UserID = &userID.UUID
+	}
+	return &line, nil
+}
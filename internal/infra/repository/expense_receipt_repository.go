@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"jointrip/internal/domain/expense"
+
+	"github.com/google/uuid"
+)
+
+const receiptColumns = `id, expense_id, uploaded_by, filename, content_type, size, storage_key, thumbnail_key, created_at`
+
+// ExpenseReceiptRepository implements the expense.ReceiptRepository interface
+type ExpenseReceiptRepository struct {
+	db *sql.DB
+}
+
+// NewExpenseReceiptRepository creates a new expense receipt repository
+func NewExpenseReceiptRepository(db *sql.DB) *ExpenseReceiptRepository {
+	return &ExpenseReceiptRepository{db: db}
+}
+
+// Create creates a receipt
+func (r *ExpenseReceiptRepository) Create(ctx context.Context, receipt *expense.Receipt) error {
+	query := `
+		INSERT INTO expense_receipts (` + receiptColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		receipt.ID, receipt.ExpenseID, receipt.UploadedBy, receipt.Filename, receipt.ContentType,
+		receipt.Size, receipt.StorageKey, receipt.ThumbnailKey, receipt.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create receipt: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a receipt
+func (r *ExpenseReceiptRepository) GetByID(ctx context.Context, id uuid.UUID) (*expense.Receipt, error) {
+	query := `SELECT ` + receiptColumns + ` FROM expense_receipts WHERE id = $1`
+
+	receipt, err := scanReceipt(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, expense.ErrReceiptNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt: %w", err)
+	}
+
+	return receipt, nil
+}
+
+// ListByExpense retrieves an expense's receipts, oldest first
+func (r *ExpenseReceiptRepository) ListByExpense(ctx context.Context, expenseID uuid.UUID) ([]*expense.Receipt, error) {
+	query := `
+		SELECT ` + receiptColumns + `
+		FROM expense_receipts
+		WHERE expense_id = $1
+		ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, expenseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list receipts: %w", err)
+	}
+	defer rows.Close()
+
+	receipts := []*expense.Receipt{}
+	for rows.Next() {
+		receipt, err := scanReceipt(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan receipt: %w", err)
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	return receipts, rows.Err()
+}
+
+// Delete deletes a receipt
+func (r *ExpenseReceiptRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM expense_receipts WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete receipt: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return expense.ErrReceiptNotFound
+	}
+
+	return nil
+}
+
+// scanReceipt scans a receipt row
+func scanReceipt(row rowScanner) (*expense.Receipt, error) {
+	var receipt expense.Receipt
+	err := row.Scan(
+		&receipt.ID, &receipt.ExpenseID, &receipt.UploadedBy, &receipt.Filename, &receipt.ContentType,
+		&receipt.Size, &receipt.StorageKey, &receipt.ThumbnailKey, &receipt.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt.HasThumbnail = receipt.ThumbnailKey != ""
+	return &receipt, nil
+}
@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"jointrip/internal/domain/expense"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// expenseColumns selects an expense without its shares
+const expenseColumns = `
+	id, trip_id, payer_id, title, amount, currency, category, date, split_method, notes,
+	base_currency, base_amount, exchange_rate, rate_as_of, rate_source,
+	created_by, created_at, updated_at`
+
+// ExpenseRepository implements the expense.Repository interface
+type ExpenseRepository struct {
+	db *sql.DB
+}
+
+// NewExpenseRepository creates a new expense repository
+func NewExpenseRepository(db *sql.DB) *ExpenseRepository {
+	return &ExpenseRepository{db: db}
+}
+
+// Create creates an expense with its shares
+func (r *ExpenseRepository) Create(ctx context.Context, e *expense.Expense) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertExpense(ctx, tx, e); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit expense: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an expense with its shares
+func (r *ExpenseRepository) GetByID(ctx context.Context, id uuid.UUID) (*expense.Expense, error) {
+	query := `SELECT ` + expenseColumns + ` FROM expenses WHERE id = $1`
+
+	e, err := scanExpense(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, expense.ErrExpenseNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expense: %w", err)
+	}
+
+	if err := r.loadShares(ctx, []*expense.Expense{e}); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// ListByTrip retrieves a trip's expenses matching the filter, most recent date first
+func (r *ExpenseRepository) ListByTrip(ctx context.Context, tripID uuid.UUID, filter expense.ListFilter) ([]*expense.Expense, error) {
+	query := `
+		SELECT ` + expenseColumns + `
+		FROM expenses
+		WHERE trip_id = $1
+			AND ($2 = '' OR category = $2)
+			AND ($3::date IS NULL OR date >= $3::date)
+			AND ($4::date IS NULL OR date <= $4::date)
+		ORDER BY date DESC, created_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, tripID, string(filter.Category), filter.From, filter.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expenses: %w", err)
+	}
+	defer rows.Close()
+
+	expenses := []*expense.Expense{}
+	for rows.Next() {
+		e, err := scanExpense(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan expense from rows: %w", err)
+		}
+		expenses = append(expenses, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expenses: %w", err)
+	}
+
+	if err := r.loadShares(ctx, expenses); err != nil {
+		return nil, err
+	}
+
+	return expenses, nil
+}
+
+// Update updates an expense and replaces its shares
+func (r *ExpenseRepository) Update(ctx context.Context, e *expense.Expense) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE expenses SET
+			payer_id = $2, title = $3, amount = $4, currency = $5, category = $6, date = $7,
+			split_method = $8, notes = $9, base_currency = $10, base_amount = $11,
+			exchange_rate = $12, rate_as_of = $13, rate_source = $14, updated_at = $15
+		WHERE id = $1`
+
+	result, err := tx.ExecContext(ctx, query,
+		e.ID, e.PayerID, e.Title, e.Amount, e.Currency, e.Category, e.Date, e.SplitMethod, e.Notes,
+		e.BaseCurrency, e.BaseAmount, e.ExchangeRate.Value, e.ExchangeRate.AsOf, e.ExchangeRate.Source, e.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update expense: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return expense.ErrExpenseNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM expense_shares WHERE expense_id = $1`, e.ID); err != nil {
+		return fmt.Errorf("failed to clear expense shares: %w", err)
+	}
+
+	if err := insertShares(ctx, tx, e); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit expense: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateSettlements saves the settlement state and notes of the shares
+func (r *ExpenseRepository) UpdateSettlements(ctx context.Context, shares []*expense.Share) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE expense_shares SET is_settled = $2, settled_at = $3, notes = $4 WHERE id = $1`
+
+	for _, share := range shares {
+		result, err := tx.ExecContext(ctx, query, share.ID, share.IsSettled, share.SettledAt, share.Notes)
+		if err != nil {
+			return fmt.Errorf("failed to update expense share: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return expense.ErrShareNotFound
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit settlements: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes an expense and its shares
+func (r *ExpenseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM expenses WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete expense: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return expense.ErrExpenseNotFound
+	}
+
+	return nil
+}
+
+// loadShares attaches their shares to the expenses
+func (r *ExpenseRepository) loadShares(ctx context.Context, expenses []*expense.Expense) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*expense.Expense, len(expenses))
+	ids := make([]uuid.UUID, len(expenses))
+	for i, e := range expenses {
+		e.Shares = []*expense.Share{}
+		byID[e.ID] = e
+		ids[i] = e.ID
+	}
+
+	query := `
+		SELECT id, expense_id, user_id, amount_owed, base_amount_owed, weight, is_settled, settled_at, notes
+		FROM expense_shares
+		WHERE expense_id = ANY($1::uuid[])
+		ORDER BY expense_id, user_id`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(uuidStrings(ids)))
+	if err != nil {
+		return fmt.Errorf("failed to list expense shares: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		share := &expense.Share{}
+		err := rows.Scan(
+			&share.ID, &share.ExpenseID, &share.UserID, &share.AmountOwed, &share.BaseAmountOwed, &share.Weight,
+			&share.IsSettled, &share.SettledAt, &share.Notes,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan expense share: %w", err)
+		}
+		byID[share.ExpenseID].Shares = append(byID[share.ExpenseID].Shares, share)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating expense shares: %w", err)
+	}
+
+	return nil
+}
+
+// insertExpense inserts an expense with its shares in a transaction
+func insertExpense(ctx context.Context, tx *sql.Tx, e *expense.Expense) error {
+	query := `
+		INSERT INTO expenses (` + expenseColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+
+	_, err := tx.ExecContext(ctx, query,
+		e.ID, e.TripID, e.PayerID, e.Title, e.Amount, e.Currency, e.Category, e.Date, e.SplitMethod, e.Notes,
+		e.BaseCurrency, e.BaseAmount, e.ExchangeRate.Value, e.ExchangeRate.AsOf, e.ExchangeRate.Source,
+		e.CreatedBy, e.CreatedAt, e.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create expense: %w", err)
+	}
+
+	return insertShares(ctx, tx, e)
+}
+
+// insertShares inserts the shares of an expense within a transaction
+func insertShares(ctx context.Context, tx *sql.Tx, e *expense.Expense) error {
+	query := `
+		INSERT INTO expense_shares (id, expense_id, user_id, amount_owed, base_amount_owed, weight, is_settled, settled_at, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	for _, share := range e.Shares {
+		_, err := tx.ExecContext(ctx, query,
+			share.ID, e.ID, share.UserID, share.AmountOwed, share.BaseAmountOwed, share.Weight,
+			share.IsSettled, share.SettledAt, share.Notes,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create expense share: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// scanExpense scans an expense row without its shares
+func scanExpense(row rowScanner) (*expense.Expense, error) {
+	e := &expense.Expense{}
+	var rate string
+	err := row.Scan(
+		&e.ID, &e.TripID, &e.PayerID, &e.Title, &e.Amount, &e.Currency, &e.Category, &e.Date, &e.SplitMethod, &e.Notes,
+		&e.BaseCurrency, &e.BaseAmount, &rate, &e.ExchangeRate.AsOf, &e.ExchangeRate.Source,
+		&e.CreatedBy, &e.CreatedAt, &e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	e.ExchangeRate.Base = e.Currency
+	e.ExchangeRate.Quote = e.BaseCurrency
+	e.ExchangeRate.Value = trimDecimal(rate)
+	return e, nil
+}
@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// InviteRepository implements the trip.InviteRepository interface
+type InviteRepository struct {
+	db *sql.DB
+}
+
+// NewInviteRepository creates a new invite repository
+func NewInviteRepository(db *sql.DB) *InviteRepository {
+	return &InviteRepository{db: db}
+}
+
+// CreateLink creates a new invite link
+func (r *InviteRepository) CreateLink(ctx context.Context, l *trip.InviteLink) error {
+	query := `
+		INSERT INTO trip_invite_links (
+			id, trip_id, created_by, expires_at, max_uses, uses, pre_approved, revoked_at, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		l.ID, l.TripID, l.CreatedBy, l.ExpiresAt, l.MaxUses, l.Uses, l.PreApproved, l.RevokedAt, l.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create invite link: %w", err)
+	}
+
+	return nil
+}
+
+// GetLink retrieves an invite link by ID
+func (r *InviteRepository) GetLink(ctx context.Context, id uuid.UUID) (*trip.InviteLink, error) {
+	query := `
+		SELECT id, trip_id, created_by, expires_at, max_uses, uses, pre_approved, revoked_at, created_at
+		FROM trip_invite_links
+		WHERE id = $1`
+
+	l := &trip.InviteLink{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&l.ID, &l.TripID, &l.CreatedBy, &l.ExpiresAt, &l.MaxUses, &l.Uses, &l.PreApproved, &l.RevokedAt, &l.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, trip.ErrInviteLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to scan invite link: %w", err)
+	}
+
+	return l, nil
+}
+
+// ListLinksByTrip retrieves a trip's invite links, most recent first
+func (r *InviteRepository) ListLinksByTrip(ctx context.Context, tripID uuid.UUID) ([]*trip.InviteLink, error) {
+	query := `
+		SELECT id, trip_id, created_by, expires_at, max_uses, uses, pre_approved, revoked_at, created_at
+		FROM trip_invite_links
+		WHERE trip_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invite links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []*trip.InviteLink
+	for rows.Next() {
+		l := &trip.InviteLink{}
+		err := rows.Scan(
+			&l.ID, &l.TripID, &l.CreatedBy, &l.ExpiresAt, &l.MaxUses, &l.Uses, &l.PreApproved, &l.RevokedAt, &l.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invite link from rows: %w", err)
+		}
+		links = append(links, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating invite links: %w", err)
+	}
+
+	return links, nil
+}
+
+// UpdateLink updates an existing invite link
+func (r *InviteRepository) UpdateLink(ctx context.Context, l *trip.InviteLink) error {
+	query := `
+		UPDATE trip_invite_links SET
+			expires_at = $2, max_uses = $3, pre_approved = $4, revoked_at = $5
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, l.ID, l.ExpiresAt, l.MaxUses, l.PreApproved, l.RevokedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update invite link: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return trip.ErrInviteLinkNotFound
+	}
+
+	return nil
+}
+
+// ClaimLinkUse atomically consumes one use of a link
+func (r *InviteRepository) ClaimLinkUse(ctx context.Context, id uuid.UUID, now time.Time) error {
+	query := `
+		UPDATE trip_invite_links SET uses = uses + 1
+		WHERE id = $1
+		  AND revoked_at IS NULL
+		  AND expires_at > $2
+		  AND (max_uses IS NULL OR uses < max_uses)`
+
+	result, err := r.db.ExecContext(ctx, query, id, now)
+	if err != nil {
+		return fmt.Errorf("failed to claim invite link use: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	// Work out why the link could not be used
+	l, err := r.GetLink(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := l.CheckUsable(now); err != nil {
+		return err
+	}
+	return trip.ErrInviteExhausted
+}
+
+// ReleaseLinkUse gives back a use claimed for a redemption that failed
+func (r *InviteRepository) ReleaseLinkUse(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE trip_invite_links SET uses = uses - 1 WHERE id = $1 AND uses > 0`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to release invite link use: %w", err)
+	}
+
+	return nil
+}
+
+// CreateInvitation creates a direct invitation
+func (r *InviteRepository) CreateInvitation(ctx context.Context, i *trip.Invitation) error {
+	query := `
+		INSERT INTO trip_invitations (
+			id, trip_id, inviter_id, invitee_id, status, message, pre_approved, created_at, responded_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		i.ID, i.TripID, i.InviterID, i.InviteeID, i.Status, i.Message, i.PreApproved, i.CreatedAt, i.RespondedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case "23505": // unique_violation
+				return trip.ErrAlreadyInvited
+			case "23503": // foreign_key_violation
+				if pqErr.Constraint == "trip_invitations_invitee_id_fkey" {
+					return trip.ErrInviteeNotFound
+				}
+			}
+		}
+		return fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	return nil
+}
+
+// GetInvitation retrieves an invitation by ID
+func (r *InviteRepository) GetInvitation(ctx context.Context, id uuid.UUID) (*trip.Invitation, error) {
+	query := `
+		SELECT id, trip_id, inviter_id, invitee_id, status, message, pre_approved, created_at, responded_at
+		FROM trip_invitations
+		WHERE id = $1`
+
+	i := &trip.Invitation{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&i.ID, &i.TripID, &i.InviterID, &i.InviteeID, &i.Status, &i.Message, &i.PreApproved, &i.CreatedAt, &i.RespondedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, trip.ErrInvitationNotFound
+		}
+		return nil, fmt.Errorf("failed to scan invitation: %w", err)
+	}
+
+	return i, nil
+}
+
+// ListPendingInvitationsByInvitee retrieves a user's pending invitations, most recent first
+func (r *InviteRepository) ListPendingInvitationsByInvitee(ctx context.Context, inviteeID uuid.UUID) ([]*trip.Invitation, error) {
+	query := `
+		SELECT id, trip_id, inviter_id, invitee_id, status, message, pre_approved, created_at, responded_at
+		FROM trip_invitations
+		WHERE invitee_id = $1 AND status = 'pending'
+		ORDER BY created_at DESC`
+
+	return r.queryInvitations(ctx, query, inviteeID)
+}
+
+// ListInvitationsByTrip retrieves a trip's invitations, most recent first
+func (r *InviteRepository) ListInvitationsByTrip(ctx context.Context, tripID uuid.UUID) ([]*trip.Invitation, error) {
+	query := `
+		SELECT id, trip_id, inviter_id, invitee_id, status, message, pre_approved, created_at, responded_at
+		FROM trip_invitations
+		WHERE trip_id = $1
+		ORDER BY created_at DESC`
+
+	return r.queryInvitations(ctx, query, tripID)
+}
+
+// UpdateInvitation updates an existing invitation
+func (r *InviteRepository) UpdateInvitation(ctx context.Context, i *trip.Invitation) error {
+	query := `
+		UPDATE trip_invitations SET
+			status = $2, message = $3, pre_approved = $4, responded_at = $5
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, i.ID, i.Status, i.Message, i.PreApproved, i.RespondedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update invitation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return trip.ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+// queryInvitations runs a query returning invitation rows
+func (r *InviteRepository) queryInvitations(ctx context.Context, query string, args ...interface{}) ([]*trip.Invitation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	defer rows.Close()
+
+	var invitations []*trip.Invitation
+	for rows.Next() {
+		i := &trip.Invitation{}
+		err := rows.Scan(
+			&i.ID, &i.TripID, &i.InviterID, &i.InviteeID, &i.Status, &i.Message, &i.PreApproved, &i.CreatedAt, &i.RespondedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invitation from rows: %w", err)
+		}
+		invitations = append(invitations, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating invitations: %w", err)
+	}
+
+	return invitations, nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ItineraryRepository implements the trip.ItineraryRepository interface
+type ItineraryRepository struct {
+	db *sql.DB
+}
+
+// NewItineraryRepository creates a new itinerary repository
+func NewItineraryRepository(db *sql.DB) *ItineraryRepository {
+	return &ItineraryRepository{db: db}
+}
+
+// Create creates a new itinerary item
+func (r *ItineraryRepository) Create(ctx context.Context, item *trip.ItineraryItem) error {
+	return insertItineraryItem(ctx, r.db, item)
+}
+
+// GetByID retrieves an itinerary item by ID
+func (r *ItineraryRepository) GetByID(ctx context.Context, id uuid.UUID) (*trip.ItineraryItem, error) {
+	query := `
+		SELECT id, trip_id, title, description, location, starts_at, ends_at, created_by, created_at, updated_at
+		FROM trip_itinerary_items
+		WHERE id = $1`
+
+	item := &trip.ItineraryItem{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&item.ID, &item.TripID, &item.Title, &item.Description, &item.Location,
+		&item.StartsAt, &item.EndsAt, &item.CreatedBy, &item.CreatedAt, &item.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, trip.ErrItineraryItemNotFound
+		}
+		return nil, fmt.Errorf("failed to scan itinerary item: %w", err)
+	}
+
+	return item, nil
+}
+
+// ListByTrip retrieves a trip's itinerary in chronological order
+func (r *ItineraryRepository) ListByTrip(ctx context.Context, tripID uuid.UUID) ([]*trip.ItineraryItem, error) {
+	query := `
+		SELECT id, trip_id, title, description, location, starts_at, ends_at, created_by, created_at, updated_at
+		FROM trip_itinerary_items
+		WHERE trip_id = $1
+		ORDER BY starts_at ASC, created_at ASC`
+
+	return r.queryItems(ctx, query, tripID)
+}
+
+// ListByTrips retrieves the itineraries of several trips in chronological order
+func (r *ItineraryRepository) ListByTrips(ctx context.Context, tripIDs []uuid.UUID) ([]*trip.ItineraryItem, error) {
+	query := `
+		SELECT id, trip_id, title, description, location, starts_at, ends_at, created_by, created_at, updated_at
+		FROM trip_itinerary_items
+		WHERE trip_id = ANY($1::uuid[])
+		ORDER BY starts_at ASC, created_at ASC`
+
+	return r.queryItems(ctx, query, pq.Array(uuidStrings(tripIDs)))
+}
+
+// Update updates an existing itinerary item
+func (r *ItineraryRepository) Update(ctx context.Context, item *trip.ItineraryItem) error {
+	query := `
+		UPDATE trip_itinerary_items SET
+			title = $2, description = $3, location = $4, starts_at = $5, ends_at = $6, updated_at = $7
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query,
+		item.ID, item.Title, item.Description, item.Location, item.StartsAt, item.EndsAt, item.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update itinerary item: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return trip.ErrItineraryItemNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes an itinerary item
+func (r *ItineraryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM trip_itinerary_items WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete itinerary item: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return trip.ErrItineraryItemNotFound
+	}
+
+	return nil
+}
+
+// queryItems runs a query returning itinerary item rows
+func (r *ItineraryRepository) queryItems(ctx context.Context, query string, args ...interface{}) ([]*trip.ItineraryItem, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list itinerary items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*trip.ItineraryItem
+	for rows.Next() {
+		item := &trip.ItineraryItem{}
+		err := rows.Scan(
+			&item.ID, &item.TripID, &item.Title, &item.Description, &item.Location,
+			&item.StartsAt, &item.EndsAt, &item.CreatedBy, &item.CreatedAt, &item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan itinerary item from rows: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating itinerary items: %w", err)
+	}
+
+	return items, nil
+}
+
+// insertItineraryItem inserts an itinerary item using the given executor
+func insertItineraryItem(ctx context.Context, db execer, item *trip.ItineraryItem) error {
+	query := `
+		INSERT INTO trip_itinerary_items (
+			id, trip_id, title, description, location, starts_at, ends_at, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		)`
+
+	_, err := db.ExecContext(ctx, query,
+		item.ID, item.TripID, item.Title, item.Description, item.Location,
+		item.StartsAt, item.EndsAt, item.CreatedBy, item.CreatedAt, item.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create itinerary item: %w", err)
+	}
+
+	return nil
+}
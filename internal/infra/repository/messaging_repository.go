@@ -0,0 +1,484 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"jointrip/internal/domain/messaging"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// MessagingRepository implements the messaging.Repository interface
+type MessagingRepository struct {
+	db *sql.DB
+}
+
+// NewMessagingRepository creates a new conversation and message repository
+func NewMessagingRepository(db *sql.DB) *MessagingRepository {
+	return &MessagingRepository{db: db}
+}
+
+// CreateConversation creates a conversation with its members
+func (r *MessagingRepository) CreateConversation(ctx context.Context, c *messaging.Conversation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO conversations (id, direct_key, trip_id, last_activity_at, created_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5)`
+
+	if _, err := tx.ExecContext(ctx, query, c.ID, c.DirectKey, c.TripID, c.LastActivityAt, c.CreatedAt); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return messaging.ErrConversationExists
+		}
+		return fmt.Errorf("failed to create conversation: %w", err)
+	}
+
+	for _, m := range c.Members {
+		if err := insertConversationMember(ctx, tx, m); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit conversation: %w", err)
+	}
+
+	return nil
+}
+
+// GetConversation retrieves a conversation with its members
+func (r *MessagingRepository) GetConversation(ctx context.Context, id uuid.UUID) (*messaging.Conversation, error) {
+	query := `
+		SELECT id, COALESCE(direct_key, ''), trip_id, last_activity_at, created_at
+		FROM conversations
+		WHERE id = $1`
+
+	return r.getConversation(ctx, query, id)
+}
+
+// GetDirectConversation retrieves the direct conversation with the given key
+func (r *MessagingRepository) GetDirectConversation(ctx context.Context, directKey string) (*messaging.Conversation, error) {
+	query := `
+		SELECT id, COALESCE(direct_key, ''), trip_id, last_activity_at, created_at
+		FROM conversations
+		WHERE direct_key = $1`
+
+	return r.getConversation(ctx, query, directKey)
+}
+
+// GetTripConversation retrieves the group chat of a trip
+func (r *MessagingRepository) GetTripConversation(ctx context.Context, tripID uuid.UUID) (*messaging.Conversation, error) {
+	query := `
+		SELECT id, COALESCE(direct_key, ''), trip_id, last_activity_at, created_at
+		FROM conversations
+		WHERE trip_id = $1`
+
+	return r.getConversation(ctx, query, tripID)
+}
+
+// AddMember adds a member to a conversation, restoring them if they had left
+// and recording their absences
+func (r *MessagingRepository) AddMember(ctx context.Context, m *messaging.Member) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO conversation_members (conversation_id, user_id, last_read_message_id, last_read_at, joined_at, left_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (conversation_id, user_id) DO UPDATE SET left_at = EXCLUDED.left_at`
+
+	_, err = tx.ExecContext(ctx, query,
+		m.ConversationID, m.UserID, m.LastReadMessageID, m.LastReadAt, m.JoinedAt, m.LeftAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return messaging.ErrConversationNotFound
+		}
+		return fmt.Errorf("failed to add conversation member: %w", err)
+	}
+
+	absence := `
+		INSERT INTO conversation_member_absences (conversation_id, user_id, left_at, returned_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (conversation_id, user_id, left_at) DO NOTHING`
+
+	for _, a := range m.Absences {
+		if _, err := tx.ExecContext(ctx, absence, m.ConversationID, m.UserID, a.LeftAt, a.ReturnedAt); err != nil {
+			return fmt.Errorf("failed to record conversation member absence: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit conversation member: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateMember stores a member's read marker and departure
+func (r *MessagingRepository) UpdateMember(ctx context.Context, m *messaging.Member) error {
+	query := `
+		UPDATE conversation_members SET last_read_message_id = $3, last_read_at = $4, left_at = $5
+		WHERE conversation_id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, m.ConversationID, m.UserID, m.LastReadMessageID, m.LastReadAt, m.LeftAt)
+	if err != nil {
+		return fmt.Errorf("failed to update conversation member: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return messaging.ErrConversationNotFound
+	}
+
+	return nil
+}
+
+// ListConversations retrieves a user's conversations by latest activity, newest first
+func (r *MessagingRepository) ListConversations(ctx context.Context, userID uuid.UUID, after *messaging.Cursor, limit int) ([]*messaging.ConversationSummary, error) {
+	query := `
+		SELECT c.id, COALESCE(c.direct_key, ''), c.trip_id, c.last_activity_at, c.created_at,
+		       lm.id, lm.kind, lm.sender_id, lm.body, lm.pinned_at, lm.pinned_by, lm.created_at,
+		       (SELECT COUNT(*) FROM messages m
+		        WHERE m.conversation_id = c.id AND m.sender_id IS DISTINCT FROM $1
+		          AND (me.last_read_at IS NULL OR m.created_at > me.last_read_at)
+		          AND (me.left_at IS NULL OR m.created_at <= me.left_at)
+		          AND NOT EXISTS (
+		              SELECT 1 FROM conversation_member_absences a
+		              WHERE a.conversation_id = c.id AND a.user_id = $1
+		                AND m.created_at > a.left_at AND m.created_at < a.returned_at
+		          )) AS unread_count
+		FROM conversations c
+		JOIN conversation_members me ON me.conversation_id = c.id AND me.user_id = $1
+		LEFT JOIN LATERAL (
+			SELECT id, kind, sender_id, body, pinned_at, pinned_by, created_at FROM messages m
+			WHERE m.conversation_id = c.id
+			  AND (me.left_at IS NULL OR m.created_at <= me.left_at)
+			  AND NOT EXISTS (
+			      SELECT 1 FROM conversation_member_absences a
+			      WHERE a.conversation_id = c.id AND a.user_id = $1
+			        AND m.created_at > a.left_at AND m.created_at < a.returned_at
+			  )
+			ORDER BY created_at DESC, id DESC
+			LIMIT 1
+		) lm ON true
+		WHERE $2::timestamptz IS NULL OR (c.last_activity_at, c.id) < ($2::timestamptz, $3::uuid)
+		ORDER BY c.last_activity_at DESC, c.id DESC
+		LIMIT $4`
+
+	afterAt, afterID := cursorArgs(after)
+	rows, err := r.db.QueryContext(ctx, query, userID, afterAt, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := []*messaging.ConversationSummary{}
+	byID := make(map[uuid.UUID]*messaging.Conversation)
+	for rows.Next() {
+		c := &messaging.Conversation{}
+		var (
+			lastID   uuid.NullUUID
+			lastKind sql.NullString
+			lastBody sql.NullString
+			lastAt   sql.NullTime
+			last     messaging.Message
+			unread   int
+		)
+		if err := rows.Scan(
+			&c.ID, &c.DirectKey, &c.TripID, &c.LastActivityAt, &c.CreatedAt,
+			&lastID, &lastKind, &last.SenderID, &lastBody, &last.PinnedAt, &last.PinnedBy, &lastAt,
+			&unread,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan conversation: %w", err)
+		}
+
+		summary := &messaging.ConversationSummary{Conversation: c, UnreadCount: unread}
+		if lastID.Valid {
+			last.ID = lastID.UUID
+			last.ConversationID = c.ID
+			last.Kind = messaging.MessageKind(lastKind.String)
+			last.Body = lastBody.String
+			last.CreatedAt = lastAt.Time
+			summary.LastMessage = &last
+		}
+		summaries = append(summaries, summary)
+		byID[c.ID] = c
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating conversations: %w", err)
+	}
+
+	if err := r.loadMembers(ctx, byID); err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+// CreateMessage stores a message, bumps the conversation's activity and moves
+// the sender's read marker to it
+func (r *MessagingRepository) CreateMessage(ctx context.Context, m *messaging.Message) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	insert := `
+		INSERT INTO messages (id, conversation_id, kind, sender_id, body, pinned_at, pinned_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err = tx.ExecContext(ctx, insert,
+		m.ID, m.ConversationID, m.Kind, m.SenderID, m.Body, m.PinnedAt, m.PinnedBy, m.CreatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return messaging.ErrConversationNotFound
+		}
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+
+	touch := `
+		UPDATE conversations SET last_activity_at = GREATEST(last_activity_at, $2)
+		WHERE id = $1`
+
+	if _, err := tx.ExecContext(ctx, touch, m.ConversationID, m.CreatedAt); err != nil {
+		return fmt.Errorf("failed to update conversation activity: %w", err)
+	}
+
+	if m.SenderID != nil {
+		read := `
+			UPDATE conversation_members SET last_read_message_id = $3, last_read_at = $4
+			WHERE conversation_id = $1 AND user_id = $2
+			  AND (last_read_at IS NULL OR last_read_at < $4)`
+
+		if _, err := tx.ExecContext(ctx, read, m.ConversationID, *m.SenderID, m.ID, m.CreatedAt); err != nil {
+			return fmt.Errorf("failed to update read marker: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit message: %w", err)
+	}
+
+	return nil
+}
+
+// GetMessage retrieves a message by ID
+func (r *MessagingRepository) GetMessage(ctx context.Context, id uuid.UUID) (*messaging.Message, error) {
+	query := `
+		SELECT id, conversation_id, kind, sender_id, body, pinned_at, pinned_by, created_at
+		FROM messages
+		WHERE id = $1`
+
+	m, err := scanMessage(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, messaging.ErrMessageNotFound
+		}
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	return m, nil
+}
+
+// ListMessages retrieves the conversation's messages selected by the filter
+func (r *MessagingRepository) ListMessages(ctx context.Context, conversationID uuid.UUID, filter messaging.MessageFilter) ([]*messaging.Message, error) {
+	query := `
+		SELECT id, conversation_id, kind, sender_id, body, pinned_at, pinned_by, created_at
+		FROM messages m
+		WHERE conversation_id = $1
+		  AND ($2::timestamptz IS NULL OR (created_at, id) < ($2::timestamptz, $3::uuid))
+		  AND ($4::timestamptz IS NULL OR created_at <= $4::timestamptz)
+		  AND (NOT $5 OR pinned_at IS NOT NULL)
+		  AND ($7::uuid IS NULL OR NOT EXISTS (
+		      SELECT 1 FROM conversation_member_absences a
+		      WHERE a.conversation_id = m.conversation_id AND a.user_id = $7::uuid
+		        AND m.created_at > a.left_at AND m.created_at < a.returned_at
+		  ))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $6`
+
+	afterAt, afterID := cursorArgs(filter.After)
+	viewer := uuid.NullUUID{UUID: filter.Viewer, Valid: filter.Viewer != uuid.Nil}
+	rows, err := r.db.QueryContext(ctx, query,
+		conversationID, afterAt, afterID, filter.Until, filter.PinnedOnly, filter.Limit, viewer,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []*messaging.Message{}
+	for rows.Next() {
+		m, err := scanMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+		messages = append(messages, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// UpdatePin stores whether a message is pinned
+func (r *MessagingRepository) UpdatePin(ctx context.Context, m *messaging.Message) error {
+	query := `UPDATE messages SET pinned_at = $2, pinned_by = $3 WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, m.ID, m.PinnedAt, m.PinnedBy)
+	if err != nil {
+		return fmt.Errorf("failed to update message pin: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return messaging.ErrMessageNotFound
+	}
+
+	return nil
+}
+
+// getConversation loads a single conversation and its members
+func (r *MessagingRepository) getConversation(ctx context.Context, query string, arg interface{}) (*messaging.Conversation, error) {
+	c := &messaging.Conversation{}
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(&c.ID, &c.DirectKey, &c.TripID, &c.LastActivityAt, &c.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, messaging.ErrConversationNotFound
+		}
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+
+	if err := r.loadMembers(ctx, map[uuid.UUID]*messaging.Conversation{c.ID: c}); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// loadMembers attaches members to the given conversations in a single query
+func (r *MessagingRepository) loadMembers(ctx context.Context, conversations map[uuid.UUID]*messaging.Conversation) error {
+	if len(conversations) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(conversations))
+	for id := range conversations {
+		ids = append(ids, id)
+	}
+
+	query := `
+		SELECT conversation_id, user_id, last_read_message_id, last_read_at, joined_at, left_at
+		FROM conversation_members
+		WHERE conversation_id = ANY($1::uuid[])
+		ORDER BY joined_at ASC, user_id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(uuidStrings(ids)))
+	if err != nil {
+		return fmt.Errorf("failed to list conversation members: %w", err)
+	}
+	defer rows.Close()
+
+	type memberKey struct{ conversationID, userID uuid.UUID }
+	members := make(map[memberKey]*messaging.Member)
+	for rows.Next() {
+		m := &messaging.Member{}
+		if err := rows.Scan(&m.ConversationID, &m.UserID, &m.LastReadMessageID, &m.LastReadAt, &m.JoinedAt, &m.LeftAt); err != nil {
+			return fmt.Errorf("failed to scan conversation member: %w", err)
+		}
+		c := conversations[m.ConversationID]
+		c.Members = append(c.Members, m)
+		members[memberKey{m.ConversationID, m.UserID}] = m
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating conversation members: %w", err)
+	}
+
+	absences := `
+		SELECT conversation_id, user_id, left_at, returned_at
+		FROM conversation_member_absences
+		WHERE conversation_id = ANY($1::uuid[])
+		ORDER BY left_at ASC`
+
+	absenceRows, err := r.db.QueryContext(ctx, absences, pq.Array(uuidStrings(ids)))
+	if err != nil {
+		return fmt.Errorf("failed to list conversation member absences: %w", err)
+	}
+	defer absenceRows.Close()
+
+	for absenceRows.Next() {
+		var (
+			key memberKey
+			a   messaging.Absence
+		)
+		if err := absenceRows.Scan(&key.conversationID, &key.userID, &a.LeftAt, &a.ReturnedAt); err != nil {
+			return fmt.Errorf("failed to scan conversation member absence: %w", err)
+		}
+		if m, ok := members[key]; ok {
+			m.Absences = append(m.Absences, a)
+		}
+	}
+
+	if err := absenceRows.Err(); err != nil {
+		return fmt.Errorf("error iterating conversation member absences: %w", err)
+	}
+
+	return nil
+}
+
+// insertConversationMember inserts a conversation member using either a connection or a transaction
+func insertConversationMember(ctx context.Context, db execer, m *messaging.Member) error {
+	query := `
+		INSERT INTO conversation_members (conversation_id, user_id, last_read_message_id, last_read_at, joined_at, left_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	if _, err := db.ExecContext(ctx, query, m.ConversationID, m.UserID, m.LastReadMessageID, m.LastReadAt, m.JoinedAt, m.LeftAt); err != nil {
+		return fmt.Errorf("failed to add conversation member: %w", err)
+	}
+
+	return nil
+}
+
+// scanMessage scans a message from a row
+func scanMessage(row rowScanner) (*messaging.Message, error) {
+	m := &messaging.Message{}
+	err := row.Scan(&m.ID, &m.ConversationID, &m.Kind, &m.SenderID, &m.Body, &m.PinnedAt, &m.PinnedBy, &m.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// cursorArgs converts an optional cursor into nullable query arguments
+func cursorArgs(cursor *messaging.Cursor) (interface{}, interface{}) {
+	if cursor == nil {
+		return nil, nil
+	}
+	return cursor.At, cursor.ID
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"jointrip/internal/domain/notification"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// NotificationPreferenceRepository implements the notification.PreferenceRepository interface
+type NotificationPreferenceRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationPreferenceRepository creates a new notification preference repository
+func NewNotificationPreferenceRepository(db *sql.DB) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{db: db}
+}
+
+// GetPreferences retrieves the user's preferences, with every channel enabled
+// when the user never chose
+func (r *NotificationPreferenceRepository) GetPreferences(ctx context.Context, userID uuid.UUID) (*notification.Preferences, error) {
+	prefs := notification.NewPreferences(userID)
+
+	settingsQuery := `
+		SELECT digest_frequency, timezone, quiet_hours_start, quiet_hours_end, last_digest_at, next_digest_at, disabled_channels
+		FROM notification_settings
+		WHERE user_id = $1`
+
+	var quietStart, quietEnd sql.NullInt32
+	var disabledChannels []string
+	err := r.db.QueryRowContext(ctx, settingsQuery, userID).Scan(
+		&prefs.Digest, &prefs.Timezone, &quietStart, &quietEnd, &prefs.LastDigestAt, &prefs.NextDigestAt,
+		pq.Array(&disabledChannels),
+	)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+	for _, channel := range disabledChannels {
+		prefs.DisabledChannels = append(prefs.DisabledChannels, notification.Channel(channel))
+	}
+	if quietStart.Valid && quietEnd.Valid {
+		prefs.QuietHours = &notification.QuietHours{
+			Start: notification.ClockTime(quietStart.Int32),
+			End:   notification.ClockTime(quietEnd.Int32),
+		}
+	}
+
+	if err := r.loadChannels(ctx, prefs); err != nil {
+		return nil, err
+	}
+	if err := r.loadMutedTrips(ctx, prefs); err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+// loadChannels reads the user's per-type channel choices
+func (r *NotificationPreferenceRepository) loadChannels(ctx context.Context, prefs *notification.Preferences) error {
+	query := `SELECT type, channel, enabled FROM notification_channel_preferences WHERE user_id = $1`
+
+	rows, err := r.db.QueryContext(ctx, query, prefs.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var notificationType notification.Type
+		var channel notification.Channel
+		var enabled bool
+		if err := rows.Scan(&notificationType, &channel, &enabled); err != nil {
+			return fmt.Errorf("failed to scan notification preference: %w", err)
+		}
+		if prefs.Channels[notificationType] == nil {
+			prefs.Channels[notificationType] = map[notification.Channel]bool{}
+		}
+		prefs.Channels[notificationType][channel] = enabled
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate notification preferences: %w", err)
+	}
+
+	return nil
+}
+
+// loadMutedTrips reads the trips the user muted
+func (r *NotificationPreferenceRepository) loadMutedTrips(ctx context.Context, prefs *notification.Preferences) error {
+	query := `SELECT trip_id FROM notification_trip_mutes WHERE user_id = $1 ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, prefs.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get muted trips: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tripID uuid.UUID
+		if err := rows.Scan(&tripID); err != nil {
+			return fmt.Errorf("failed to scan muted trip: %w", err)
+		}
+		prefs.MutedTrips = append(prefs.MutedTrips, tripID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate muted trips: %w", err)
+	}
+
+	return nil
+}
+
+// SavePreferences stores the user's preferences, except muted trips
+func (r *NotificationPreferenceRepository) SavePreferences(ctx context.Context, prefs *notification.Preferences) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	settingsQuery := `
+		INSERT INTO notification_settings (
+			user_id, digest_frequency, timezone, quiet_hours_start, quiet_hours_end, last_digest_at, next_digest_at,
+			disabled_channels, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			digest_frequency = EXCLUDED.digest_frequency,
+			timezone = EXCLUDED.timezone,
+			quiet_hours_start = EXCLUDED.quiet_hours_start,
+			quiet_hours_end = EXCLUDED.quiet_hours_end,
+			last_digest_at = EXCLUDED.last_digest_at,
+			next_digest_at = EXCLUDED.next_digest_at,
+			disabled_channels = EXCLUDED.disabled_channels,
+			updated_at = EXCLUDED.updated_at`
+
+	var quietStart, quietEnd sql.NullInt32
+	if prefs.QuietHours != nil {
+		quietStart = sql.NullInt32{Int32: int32(prefs.QuietHours.Start), Valid: true}
+		quietEnd = sql.NullInt32{Int32: int32(prefs.QuietHours.End), Valid: true}
+	}
+
+	_, err = tx.ExecContext(ctx, settingsQuery,
+		prefs.UserID, prefs.Digest, prefs.Timezone, quietStart, quietEnd, prefs.LastDigestAt, prefs.NextDigestAt,
+		pq.Array(channelStrings(prefs.DisabledChannels)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save notification settings: %w", err)
+	}
+
+	query := `
+		INSERT INTO notification_channel_preferences (user_id, type, channel, enabled, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (user_id, type, channel) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			updated_at = EXCLUDED.updated_at`
+
+	for notificationType, channels := range prefs.Channels {
+		for channel, enabled := range channels {
+			if _, err := tx.ExecContext(ctx, query, prefs.UserID, notificationType, channel, enabled); err != nil {
+				return fmt.Errorf("failed to save notification preference: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit notification preferences: %w", err)
+	}
+
+	return nil
+}
+
+// MuteTrip stops notifications about the trip reaching the user
+func (r *NotificationPreferenceRepository) MuteTrip(ctx context.Context, userID, tripID uuid.UUID) error {
+	query := `
+		INSERT INTO notification_trip_mutes (user_id, trip_id, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id, trip_id) DO NOTHING`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, tripID); err != nil {
+		return fmt.Errorf("failed to mute trip: %w", err)
+	}
+
+	return nil
+}
+
+// UnmuteTrip lets notifications about the trip reach the user again
+func (r *NotificationPreferenceRepository) UnmuteTrip(ctx context.Context, userID, tripID uuid.UUID) error {
+	query := `DELETE FROM notification_trip_mutes WHERE user_id = $1 AND trip_id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, tripID); err != nil {
+		return fmt.Errorf("failed to unmute trip: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDueDigests returns up to limit users whose digest is due and pushes
+// their next digest past the lease. Rows locked by another claimer are skipped.
+func (r *NotificationPreferenceRepository) ClaimDueDigests(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]uuid.UUID, error) {
+	query := `
+		UPDATE notification_settings SET next_digest_at = $2
+		WHERE user_id IN (
+			SELECT user_id FROM notification_settings
+			WHERE next_digest_at <= $1
+			ORDER BY next_digest_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING user_id`
+
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due digests: %w", err)
+	}
+	defer rows.Close()
+
+	userIDs := []uuid.UUID{}
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan digest user: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate digest users: %w", err)
+	}
+
+	return userIDs, nil
+}
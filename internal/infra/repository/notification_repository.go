@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"jointrip/internal/domain/notification"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// NotificationRepository implements the notification.Repository interface
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create stores a new notification
+func (r *NotificationRepository) Create(ctx context.Context, n *notification.Notification) error {
+	query := `
+		INSERT INTO notifications (
+			id, user_id, type, title, content, related_entity_type, related_entity_id, data, channels, is_read, read_at, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+		)`
+
+	data, err := json.Marshal(n.Data)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification data: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query,
+		n.ID, n.UserID, n.Type, n.Title, n.Content, n.RelatedEntityType, n.RelatedEntityID, data, pq.Array(channelStrings(n.Channels)), n.IsRead, n.ReadAt, n.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves a page of the user's in-app notifications, newest first
+func (r *NotificationRepository) List(ctx context.Context, userID uuid.UUID, filter notification.ListFilter) ([]*notification.Notification, error) {
+	query := `
+		SELECT id, user_id, type, title, content, related_entity_type, related_entity_id, data, channels, is_read, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND 'in_app' = ANY(channels) AND (NOT $2 OR NOT is_read)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, filter.UnreadOnly, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer rows.Close()
+
+	return scanNotifications(rows)
+}
+
+// ListUnreadSince retrieves up to limit of the user's unread digest
+// notifications created after the given time, oldest first
+func (r *NotificationRepository) ListUnreadSince(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]*notification.Notification, error) {
+	query := `
+		SELECT id, user_id, type, title, content, related_entity_type, related_entity_id, data, channels, is_read, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND 'digest' = ANY(channels) AND NOT is_read AND created_at > $2
+		ORDER BY created_at, id
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unread notifications: %w", err)
+	}
+	defer rows.Close()
+
+	return scanNotifications(rows)
+}
+
+// scanNotifications reads notification rows
+func scanNotifications(rows *sql.Rows) ([]*notification.Notification, error) {
+	notifications := []*notification.Notification{}
+	for rows.Next() {
+		n := &notification.Notification{}
+		var data []byte
+		var channels []string
+		if err := rows.Scan(
+			&n.ID, &n.UserID, &n.Type, &n.Title, &n.Content, &n.RelatedEntityType, &n.RelatedEntityID, &data, pq.Array(&channels), &n.IsRead, &n.ReadAt, &n.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		if err := json.Unmarshal(data, &n.Data); err != nil {
+			return nil, fmt.Errorf("failed to decode notification data: %w", err)
+		}
+		for _, channel := range channels {
+			n.Channels = append(n.Channels, notification.Channel(channel))
+		}
+		notifications = append(notifications, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// CountUnread returns the number of unread in-app notifications of the user
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND 'in_app' = ANY(channels) AND NOT is_read`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+// HasUnread returns true if the user has an unread notification of the given
+// type about the entity
+func (r *NotificationRepository) HasUnread(ctx context.Context, userID uuid.UUID, notificationType notification.Type, entityType notification.EntityType, entityID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM notifications
+			WHERE user_id = $1 AND type = $2 AND related_entity_type = $3 AND related_entity_id = $4 AND NOT is_read
+		)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, userID, notificationType, entityType, entityID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check unread notifications: %w", err)
+	}
+
+	return exists, nil
+}
+
+// MarkRead marks the given notifications of the user as read, or all of them
+// when no IDs are given, and returns how many changed
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, readAt time.Time) (int, error) {
+	query := `
+		UPDATE notifications SET is_read = true, read_at = $2
+		WHERE user_id = $1 AND NOT is_read`
+	args := []interface{}{userID, readAt}
+
+	if len(ids) > 0 {
+		query += ` AND id = ANY($3::uuid[])`
+		args = append(args, pq.Array(uuidStrings(ids)))
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// channelStrings converts notification channels to strings for use with pq.Array
+func channelStrings(channels []notification.Channel) []string {
+	out := make([]string, len(channels))
+	for i, channel := range channels {
+		out[i] = string(channel)
+	}
+	return out
+}
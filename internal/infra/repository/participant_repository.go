@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ParticipantRepository implements the trip.ParticipantRepository interface
+type ParticipantRepository struct {
+	db *sql.DB
+}
+
+// NewParticipantRepository creates a new participant repository
+func NewParticipantRepository(db *sql.DB) *ParticipantRepository {
+	return &ParticipantRepository{db: db}
+}
+
+// Create creates a new participation request
+func (r *ParticipantRepository) Create(ctx context.Context, p *trip.Participant) error {
+	return insertParticipant(ctx, r.db, p)
+}
+
+// GetByID retrieves a participant by ID
+func (r *ParticipantRepository) GetByID(ctx context.Context, id uuid.UUID) (*trip.Participant, error) {
+	query := `
+		SELECT id, trip_id, user_id, status, role, join_date, left_date, notes, created_at
+		FROM trip_participants
+		WHERE id = $1`
+
+	return r.scanParticipant(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByTripAndUser retrieves a user's participation in a trip
+func (r *ParticipantRepository) GetByTripAndUser(ctx context.Context, tripID, userID uuid.UUID) (*trip.Participant, error) {
+	query := `
+		SELECT id, trip_id, user_id, status, role, join_date, left_date, notes, created_at
+		FROM trip_participants
+		WHERE trip_id = $1 AND user_id = $2`
+
+	return r.scanParticipant(r.db.QueryRowContext(ctx, query, tripID, userID))
+}
+
+// ListByTrip retrieves all participants of a trip
+func (r *ParticipantRepository) ListByTrip(ctx context.Context, tripID uuid.UUID) ([]*trip.Participant, error) {
+	query := `
+		SELECT id, trip_id, user_id, status, role, join_date, left_date, notes, created_at
+		FROM trip_participants
+		WHERE trip_id = $1
+		ORDER BY created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list participants: %w", err)
+	}
+	defer rows.Close()
+
+	var participants []*trip.Participant
+	for rows.Next() {
+		p, err := r.scanParticipantFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		participants = append(participants, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating participants: %w", err)
+	}
+
+	return participants, nil
+}
+
+// Update updates a participant without touching seat counts
+func (r *ParticipantRepository) Update(ctx context.Context, p *trip.Participant) error {
+	query := `
+		UPDATE trip_participants SET
+			status = $2, role = $3, join_date = $4, left_date = $5, notes = $6
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, p.ID, p.Status, p.Role, p.JoinDate, p.LeftDate, p.Notes)
+	if err != nil {
+		return fmt.Errorf("failed to update participant: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return trip.ErrParticipantNotFound
+	}
+
+	return nil
+}
+
+// Approve atomically approves a participant and takes a seat, failing with ErrTripFull
+func (r *ParticipantRepository) Approve(ctx context.Context, p *trip.Participant) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	maxParticipants, currentParticipants, err := lockTripSeats(ctx, tx, p.TripID)
+	if err != nil {
+		return err
+	}
+
+	// Seats offered to the waitlist are reserved until they are accepted or lapse
+	reserved, err := countOutstandingOffers(ctx, tx, p.TripID)
+	if err != nil {
+		return err
+	}
+
+	if currentParticipants+reserved >= maxParticipants {
+		return trip.ErrTripFull
+	}
+
+	query := `
+		UPDATE trip_participants SET status = $2, join_date = $3
+		WHERE id = $1 AND status = 'requested'`
+
+	result, err := tx.ExecContext(ctx, query, p.ID, p.Status, p.JoinDate)
+	if err != nil {
+		return fmt.Errorf("failed to approve participant: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return trip.ErrInvalidParticipantState
+	}
+
+	if err := adjustTripSeats(ctx, tx, p.TripID, 1); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit participant approval: %w", err)
+	}
+
+	return nil
+}
+
+// Leave atomically marks a participant as left (or removed) and frees their seat if they held one
+func (r *ParticipantRepository) Leave(ctx context.Context, p *trip.Participant) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, _, err := lockTripSeats(ctx, tx, p.TripID); err != nil {
+		return err
+	}
+
+	// Read the stored status under the trip lock so a double leave cannot free two seats
+	var previousStatus trip.ParticipantStatus
+	err = tx.QueryRowContext(ctx,
+		`SELECT status FROM trip_participants WHERE id = $1`, p.ID,
+	).Scan(&previousStatus)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return trip.ErrParticipantNotFound
+		}
+		return fmt.Errorf("failed to read participant status: %w", err)
+	}
+
+	if previousStatus == trip.ParticipantStatusLeft ||
+		previousStatus == trip.ParticipantStatusRejected ||
+		previousStatus == trip.ParticipantStatusRemoved {
+		return trip.ErrInvalidParticipantState
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE trip_participants SET status = $2, left_date = $3 WHERE id = $1`, p.ID, p.Status, p.LeftDate,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update participant: %w", err)
+	}
+
+	if previousStatus == trip.ParticipantStatusApproved {
+		if err := adjustTripSeats(ctx, tx, p.TripID, -1); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit participant departure: %w", err)
+	}
+
+	return nil
+}
+
+// SharesTrip checks if both users are approved participants of a common trip
+func (r *ParticipantRepository) SharesTrip(ctx context.Context, userID, otherID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM trip_participants a
+			JOIN trip_participants b ON b.trip_id = a.trip_id
+			WHERE a.user_id = $1 AND a.status = 'approved'
+			  AND b.user_id = $2 AND b.status = 'approved'
+		)`
+
+	var shares bool
+	if err := r.db.QueryRowContext(ctx, query, userID, otherID).Scan(&shares); err != nil {
+		return false, fmt.Errorf("failed to check shared trips: %w", err)
+	}
+
+	return shares, nil
+}
+
+// scanParticipant scans a participant from a single row
+func (r *ParticipantRepository) scanParticipant(row *sql.Row) (*trip.Participant, error) {
+	p := &trip.Participant{}
+	err := row.Scan(
+		&p.ID, &p.TripID, &p.UserID, &p.Status, &p.Role, &p.JoinDate, &p.LeftDate, &p.Notes, &p.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, trip.ErrParticipantNotFound
+		}
+		return nil, fmt.Errorf("failed to scan participant: %w", err)
+	}
+
+	return p, nil
+}
+
+// scanParticipantFromRows scans a participant from multiple rows
+func (r *ParticipantRepository) scanParticipantFromRows(rows *sql.Rows) (*trip.Participant, error) {
+	p := &trip.Participant{}
+	err := rows.Scan(
+		&p.ID, &p.TripID, &p.UserID, &p.Status, &p.Role, &p.JoinDate, &p.LeftDate, &p.Notes, &p.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan participant from rows: %w", err)
+	}
+
+	return p, nil
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertParticipant inserts a participant row using either a connection or a transaction
+func insertParticipant(ctx context.Context, db execer, p *trip.Participant) error {
+	query := `
+		INSERT INTO trip_participants (
+			id, trip_id, user_id, status, role, join_date, left_date, notes, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)`
+
+	_, err := db.ExecContext(ctx, query,
+		p.ID, p.TripID, p.UserID, p.Status, p.Role, p.JoinDate, p.LeftDate, p.Notes, p.CreatedAt,
+	)
+
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case "23505": // unique_violation
+				return trip.ErrAlreadyParticipant
+			}
+		}
+		return fmt.Errorf("failed to create participant: %w", err)
+	}
+
+	return nil
+}
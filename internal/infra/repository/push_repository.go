@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"jointrip/internal/domain/push"
+
+	"github.com/google/uuid"
+)
+
+// PushSubscriptionRepository implements the push.SubscriptionRepository interface
+type PushSubscriptionRepository struct {
+	db *sql.DB
+}
+
+// NewPushSubscriptionRepository creates a new push subscription repository
+func NewPushSubscriptionRepository(db *sql.DB) *PushSubscriptionRepository {
+	return &PushSubscriptionRepository{db: db}
+}
+
+// Save stores a subscription, replacing one with the same endpoint
+func (r *PushSubscriptionRepository) Save(ctx context.Context, sub *push.Subscription) error {
+	query := `
+		INSERT INTO push_subscriptions (id, user_id, endpoint, p256dh, auth, user_agent, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (endpoint) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			p256dh = EXCLUDED.p256dh,
+			auth = EXCLUDED.auth,
+			user_agent = EXCLUDED.user_agent
+		RETURNING id, created_at, last_used_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		sub.ID, sub.UserID, sub.Endpoint, sub.P256dh, sub.Auth, sub.UserAgent, sub.CreatedAt,
+	).Scan(&sub.ID, &sub.CreatedAt, &sub.LastUsedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save push subscription: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a subscription by ID
+func (r *PushSubscriptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*push.Subscription, error) {
+	query := `
+		SELECT id, user_id, endpoint, p256dh, auth, user_agent, created_at, last_used_at
+		FROM push_subscriptions
+		WHERE id = $1`
+
+	sub := &push.Subscription{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&sub.ID, &sub.UserID, &sub.Endpoint, &sub.P256dh, &sub.Auth, &sub.UserAgent, &sub.CreatedAt, &sub.LastUsedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, push.ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get push subscription: %w", err)
+	}
+
+	return sub, nil
+}
+
+// ListByUser retrieves the user's subscriptions, newest first
+func (r *PushSubscriptionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*push.Subscription, error) {
+	query := `
+		SELECT id, user_id, endpoint, p256dh, auth, user_agent, created_at, last_used_at
+		FROM push_subscriptions
+		WHERE user_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list push subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := []*push.Subscription{}
+	for rows.Next() {
+		sub := &push.Subscription{}
+		if err := rows.Scan(
+			&sub.ID, &sub.UserID, &sub.Endpoint, &sub.P256dh, &sub.Auth, &sub.UserAgent, &sub.CreatedAt, &sub.LastUsedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan push subscription: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate push subscriptions: %w", err)
+	}
+
+	return subs, nil
+}
+
+// Delete removes one of the user's subscriptions
+func (r *PushSubscriptionRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	query := `DELETE FROM push_subscriptions WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete push subscription: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return push.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// DeleteByID removes a subscription together with its queued messages
+func (r *PushSubscriptionRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM push_subscriptions WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete push subscription: %w", err)
+	}
+
+	return nil
+}
+
+// Touch records that a message was delivered to the subscription
+func (r *PushSubscriptionRepository) Touch(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	query := `UPDATE push_subscriptions SET last_used_at = $2 WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id, usedAt); err != nil {
+		return fmt.Errorf("failed to touch push subscription: %w", err)
+	}
+
+	return nil
+}
+
+// PushDeliveryRepository implements the push.DeliveryRepository interface
+type PushDeliveryRepository struct {
+	db *sql.DB
+}
+
+// NewPushDeliveryRepository creates a new push delivery repository
+func NewPushDeliveryRepository(db *sql.DB) *PushDeliveryRepository {
+	return &PushDeliveryRepository{db: db}
+}
+
+// Enqueue stores a new delivery
+func (r *PushDeliveryRepository) Enqueue(ctx context.Context, delivery *push.Delivery) error {
+	query := `
+		INSERT INTO push_deliveries (
+			id, subscription_id, payload, urgency, status, attempts, next_attempt_at, expires_at, last_error, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		delivery.ID, delivery.SubscriptionID, delivery.Payload, delivery.Urgency, delivery.Status,
+		delivery.Attempts, delivery.NextAttemptAt, delivery.ExpiresAt, delivery.LastError, delivery.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue push delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDue returns up to limit pending, unexpired deliveries whose next
+// attempt is due and pushes their next attempt past the lease. Rows locked by
+// another claimer are skipped.
+func (r *PushDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*push.Delivery, error) {
+	query := `
+		UPDATE push_deliveries SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM push_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1 AND expires_at > $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, subscription_id, payload, urgency, status, attempts, next_attempt_at, expires_at, last_error, created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due push deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*push.Delivery{}
+	for rows.Next() {
+		delivery := &push.Delivery{}
+		if err := rows.Scan(
+			&delivery.ID, &delivery.SubscriptionID, &delivery.Payload, &delivery.Urgency, &delivery.Status,
+			&delivery.Attempts, &delivery.NextAttemptAt, &delivery.ExpiresAt, &delivery.LastError, &delivery.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan push delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate push deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Update stores the state of a delivery
+func (r *PushDeliveryRepository) Update(ctx context.Context, delivery *push.Delivery) error {
+	query := `
+		UPDATE push_deliveries SET
+			status = $2, attempts = $3, next_attempt_at = $4, last_error = $5
+		WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query,
+		delivery.ID, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastError,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update push delivery: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteFinishedBefore removes sent, failed and expired deliveries created
+// before the given time
+func (r *PushDeliveryRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) error {
+	query := `
+		DELETE FROM push_deliveries
+		WHERE created_at < $1 AND (status <> 'pending' OR expires_at < $1)`
+
+	if _, err := r.db.ExecContext(ctx, query, before); err != nil {
+		return fmt.Errorf("failed to prune push deliveries: %w", err)
+	}
+
+	return nil
+}
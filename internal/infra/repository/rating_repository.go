@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"jointrip/internal/domain/user"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// RatingRepository implements the user.RatingRepository interface
+type RatingRepository struct {
+	db *sql.DB
+}
+
+// NewRatingRepository creates a new user rating repository
+func NewRatingRepository(db *sql.DB) *RatingRepository {
+	return &RatingRepository{db: db}
+}
+
+// Create stores a rating
+func (r *RatingRepository) Create(ctx context.Context, rating *user.Rating) error {
+	query := `
+		INSERT INTO user_ratings (id, rater_id, rated_id, rating, review, trip_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $7)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		rating.ID, rating.RaterID, rating.RatedUserID, rating.Rating, rating.Review, rating.TripID, rating.CreatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case "23505": // unique_violation
+				return user.ErrAlreadyRated
+			case "23503": // foreign_key_violation
+				return user.ErrUserNotFound
+			}
+		}
+		return fmt.Errorf("failed to create rating: %w", err)
+	}
+
+	return nil
+}
+
+// ListByRatedUser retrieves the ratings a user received, newest first
+func (r *RatingRepository) ListByRatedUser(ctx context.Context, ratedUserID uuid.UUID) ([]*user.Rating, error) {
+	return r.list(ctx, "rated_id", ratedUserID)
+}
+
+// ListByRater retrieves the ratings a user gave, newest first
+func (r *RatingRepository) ListByRater(ctx context.Context, raterID uuid.UUID) ([]*user.Rating, error) {
+	return r.list(ctx, "rater_id", raterID)
+}
+
+// list retrieves the ratings whose given user column matches the user
+func (r *RatingRepository) list(ctx context.Context, column string, userID uuid.UUID) ([]*user.Rating, error) {
+	query := fmt.Sprintf(`
+		SELECT id, rater_id, rated_id, trip_id, rating, COALESCE(review, ''), created_at
+		FROM user_ratings
+		WHERE %s = $1
+		ORDER BY created_at DESC`, column)
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ratings: %w", err)
+	}
+	defer rows.Close()
+
+	ratings := []*user.Rating{}
+	for rows.Next() {
+		rating := &user.Rating{}
+		if err := rows.Scan(
+			&rating.ID, &rating.RaterID, &rating.RatedUserID, &rating.TripID,
+			&rating.Rating, &rating.Review, &rating.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan rating: %w", err)
+		}
+		ratings = append(ratings, rating)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ratings: %w", err)
+	}
+
+	return ratings, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"jointrip/internal/domain/realtime"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// RealtimeEventRepository implements the realtime.EventLog interface
+type RealtimeEventRepository struct {
+	db *sql.DB
+}
+
+// NewRealtimeEventRepository creates a new real-time event log repository
+func NewRealtimeEventRepository(db *sql.DB) *RealtimeEventRepository {
+	return &RealtimeEventRepository{db: db}
+}
+
+// Append stores the delivery's event and assigns its ID
+func (r *RealtimeEventRepository) Append(ctx context.Context, delivery *realtime.Delivery) error {
+	query := `
+		INSERT INTO realtime_events (user_ids, type, data)
+		VALUES ($1, $2, $3)
+		RETURNING id`
+
+	data := []byte(delivery.Event.Data)
+	if data == nil {
+		data = []byte("null")
+	}
+
+	err := r.db.QueryRowContext(ctx, query,
+		pq.Array(uuidStrings(delivery.UserIDs)), delivery.Event.Type, data,
+	).Scan(&delivery.Event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to append realtime event: %w", err)
+	}
+
+	return nil
+}
+
+// ListSince returns the user's events logged after the given ID, oldest first
+func (r *RealtimeEventRepository) ListSince(ctx context.Context, userID uuid.UUID, afterID int64, limit int) ([]realtime.Event, error) {
+	query := `
+		SELECT id, type, data
+		FROM realtime_events
+		WHERE id > $1 AND user_ids @> ARRAY[$2]::uuid[]
+		ORDER BY id
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, afterID, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list realtime events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []realtime.Event
+	for rows.Next() {
+		var e realtime.Event
+		var data []byte
+		if err := rows.Scan(&e.ID, &e.Type, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan realtime event: %w", err)
+		}
+		e.Data = data
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate realtime events: %w", err)
+	}
+
+	return events, nil
+}
+
+// DeleteBefore removes events logged before the given time
+func (r *RealtimeEventRepository) DeleteBefore(ctx context.Context, before time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM realtime_events WHERE created_at < $1`, before); err != nil {
+		return fmt.Errorf("failed to delete realtime events: %w", err)
+	}
+	return nil
+}
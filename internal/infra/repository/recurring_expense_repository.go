@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"jointrip/internal/domain/expense"
+
+	"github.com/google/uuid"
+)
+
+const recurringExpenseColumns = `id, trip_id, payer_id, title, amount, currency, category, notes, frequency,
+	start_date, end_date, next_date, created_by, created_at, updated_at`
+
+// RecurringExpenseRepository implements the expense.RecurringRepository interface
+type RecurringExpenseRepository struct {
+	db *sql.DB
+}
+
+// NewRecurringExpenseRepository creates a new recurring expense repository
+func NewRecurringExpenseRepository(db *sql.DB) *RecurringExpenseRepository {
+	return &RecurringExpenseRepository{db: db}
+}
+
+// Create creates a recurring expense
+func (r *RecurringExpenseRepository) Create(ctx context.Context, recurring *expense.RecurringExpense) error {
+	query := `
+		INSERT INTO recurring_expenses (` + recurringExpenseColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		recurring.ID, recurring.TripID, recurring.PayerID, recurring.Title, recurring.Amount, recurring.Currency,
+		recurring.Category, recurring.Notes, recurring.Frequency, recurring.StartDate, recurring.EndDate,
+		recurring.NextDate, recurring.CreatedBy, recurring.CreatedAt, recurring.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create recurring expense: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a recurring expense
+func (r *RecurringExpenseRepository) GetByID(ctx context.Context, id uuid.UUID) (*expense.RecurringExpense, error) {
+	query := `SELECT ` + recurringExpenseColumns + ` FROM recurring_expenses WHERE id = $1`
+
+	recurring, err := scanRecurringExpense(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, expense.ErrRecurringExpenseNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recurring expense: %w", err)
+	}
+
+	return recurring, nil
+}
+
+// ListByTrip retrieves a trip's recurring expenses, by start date
+func (r *RecurringExpenseRepository) ListByTrip(ctx context.Context, tripID uuid.UUID) ([]*expense.RecurringExpense, error) {
+	query := `
+		SELECT ` + recurringExpenseColumns + `
+		FROM recurring_expenses
+		WHERE trip_id = $1
+		ORDER BY start_date, created_at`
+
+	return r.list(ctx, query, tripID)
+}
+
+// ListDue retrieves the unfinished recurring expenses whose next period
+// started before the given day
+func (r *RecurringExpenseRepository) ListDue(ctx context.Context, today time.Time) ([]*expense.RecurringExpense, error) {
+	query := `
+		SELECT ` + recurringExpenseColumns + `
+		FROM recurring_expenses
+		WHERE next_date <= end_date AND next_date < $1
+		ORDER BY next_date, id`
+
+	return r.list(ctx, query, today)
+}
+
+// Update updates a recurring expense
+func (r *RecurringExpenseRepository) Update(ctx context.Context, recurring *expense.RecurringExpense) error {
+	query := `
+		UPDATE recurring_expenses SET
+			payer_id = $2, title = $3, amount = $4, currency = $5, category = $6, notes = $7,
+			frequency = $8, start_date = $9, end_date = $10, next_date = $11, updated_at = $12
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query,
+		recurring.ID, recurring.PayerID, recurring.Title, recurring.Amount, recurring.Currency, recurring.Category,
+		recurring.Notes, recurring.Frequency, recurring.StartDate, recurring.EndDate, recurring.NextDate,
+		recurring.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update recurring expense: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return expense.ErrRecurringExpenseNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a recurring expense; the expenses it recorded remain
+func (r *RecurringExpenseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM recurring_expenses WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete recurring expense: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return expense.ErrRecurringExpenseNotFound
+	}
+
+	return nil
+}
+
+// Record creates the expense of a period and saves the recurring expense's
+// next date together. The next date only moves on from the expected one, so
+// two runs cannot record the same period.
+func (r *RecurringExpenseRepository) Record(ctx context.Context, recurring *expense.RecurringExpense, previousNextDate time.Time, e *expense.Expense) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE recurring_expenses SET next_date = $2 WHERE id = $1 AND next_date = $3`,
+		recurring.ID, recurring.NextDate, previousNextDate,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update recurring expense: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return expense.ErrRecurringExpenseNotFound
+	}
+
+	if err := insertExpense(ctx, tx, e); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit recurring expense: %w", err)
+	}
+
+	return nil
+}
+
+// list runs a query returning recurring expenses
+func (r *RecurringExpenseRepository) list(ctx context.Context, query string, args ...interface{}) ([]*expense.RecurringExpense, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recurring expenses: %w", err)
+	}
+	defer rows.Close()
+
+	recurring := []*expense.RecurringExpense{}
+	for rows.Next() {
+		rule, err := scanRecurringExpense(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan recurring expense: %w", err)
+		}
+		recurring = append(recurring, rule)
+	}
+
+	return recurring, rows.Err()
+}
+
+// scanRecurringExpense scans a recurring expense row
+func scanRecurringExpense(row rowScanner) (*expense.RecurringExpense, error) {
+	var recurring expense.RecurringExpense
+	err := row.Scan(
+		&recurring.ID, &recurring.TripID, &recurring.PayerID, &recurring.Title, &recurring.Amount, &recurring.Currency,
+		&recurring.Category, &recurring.Notes, &recurring.Frequency, &recurring.StartDate, &recurring.EndDate,
+		&recurring.NextDate, &recurring.CreatedBy, &recurring.CreatedAt, &recurring.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recurring, nil
+}
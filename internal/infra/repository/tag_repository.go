@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"jointrip/internal/domain/tag"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// TagRepository implements the tag.Repository interface
+type TagRepository struct {
+	db *sql.DB
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *sql.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// Create creates a new tag
+func (r *TagRepository) Create(ctx context.Context, t *tag.Tag) error {
+	query := `
+		INSERT INTO trip_tags (
+			id, name, category, description, color, is_active, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		t.ID, t.Name, t.Category, t.Description, t.Color, t.IsActive, t.CreatedAt, t.UpdatedAt,
+	)
+
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case "23505": // unique_violation
+				return tag.ErrTagAlreadyExists
+			}
+		}
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a tag by ID
+func (r *TagRepository) GetByID(ctx context.Context, id uuid.UUID) (*tag.Tag, error) {
+	query := `
+		SELECT id, name, category, description, color, is_active, created_at, updated_at
+		FROM trip_tags
+		WHERE id = $1`
+
+	return r.scanTag(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByIDs retrieves all tags with the given IDs
+func (r *TagRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*tag.Tag, error) {
+	query := `
+		SELECT id, name, category, description, color, is_active, created_at, updated_at
+		FROM trip_tags
+		WHERE id = ANY($1::uuid[])
+		ORDER BY category, name`
+
+	return r.queryTags(ctx, query, pq.Array(uuidStrings(ids)))
+}
+
+// List retrieves tags ordered by category and name
+func (r *TagRepository) List(ctx context.Context, filter tag.ListFilter) ([]*tag.Tag, error) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if !filter.IncludeInactive {
+		conditions = append(conditions, "is_active = true")
+	}
+	if filter.Category != nil {
+		args = append(args, *filter.Category)
+		conditions = append(conditions, fmt.Sprintf("category = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, name, category, description, color, is_active, created_at, updated_at
+		FROM trip_tags
+		%s
+		ORDER BY category, name`, where)
+
+	return r.queryTags(ctx, query, args...)
+}
+
+// Update updates an existing tag
+func (r *TagRepository) Update(ctx context.Context, t *tag.Tag) error {
+	query := `
+		UPDATE trip_tags SET
+			name = $2, category = $3, description = $4, color = $5, is_active = $6, updated_at = $7
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query,
+		t.ID, t.Name, t.Category, t.Description, t.Color, t.IsActive, t.UpdatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case "23505": // unique_violation
+				return tag.ErrTagAlreadyExists
+			}
+		}
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return tag.ErrTagNotFound
+	}
+
+	return nil
+}
+
+// ListByTrip retrieves the tags assigned to a trip
+func (r *TagRepository) ListByTrip(ctx context.Context, tripID uuid.UUID) ([]*tag.Tag, error) {
+	query := `
+		SELECT t.id, t.name, t.category, t.description, t.color, t.is_active, t.created_at, t.updated_at
+		FROM trip_tags t
+		JOIN trip_tag_assignments a ON a.tag_id = t.id
+		WHERE a.trip_id = $1 AND t.is_active = true
+		ORDER BY t.category, t.name`
+
+	return r.queryTags(ctx, query, tripID)
+}
+
+// ReplaceTripTags replaces every tag assignment of a trip
+func (r *TagRepository) ReplaceTripTags(ctx context.Context, tripID uuid.UUID, assignments []*tag.Assignment) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM trip_tag_assignments WHERE trip_id = $1`, tripID); err != nil {
+		return fmt.Errorf("failed to clear trip tags: %w", err)
+	}
+
+	for _, a := range assignments {
+		if err := insertTagAssignment(ctx, tx, a); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit trip tags: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveFromTrip removes a single tag from a trip
+func (r *TagRepository) RemoveFromTrip(ctx context.Context, tripID, tagID uuid.UUID) error {
+	query := `DELETE FROM trip_tag_assignments WHERE trip_id = $1 AND tag_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, tripID, tagID)
+	if err != nil {
+		return fmt.Errorf("failed to remove trip tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return tag.ErrTagNotFound
+	}
+
+	return nil
+}
+
+// ListPopular retrieves active tags ordered by the number of visible trips using them
+func (r *TagRepository) ListPopular(ctx context.Context, limit int) ([]*tag.Usage, error) {
+	query := `
+		SELECT t.id, t.name, t.category, t.description, t.color, t.is_active, t.created_at, t.updated_at,
+			   COUNT(tr.id) AS usage_count
+		FROM trip_tags t
+		JOIN trip_tag_assignments a ON a.tag_id = t.id
+		JOIN trips tr ON tr.id = a.trip_id AND tr.is_public = true AND tr.status IN ('active', 'full')
+		WHERE t.is_active = true
+		GROUP BY t.id
+		ORDER BY usage_count DESC, t.name ASC
+		LIMIT $1`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list popular tags: %w", err)
+	}
+	defer rows.Close()
+
+	var usages []*tag.Usage
+	for rows.Next() {
+		t := &tag.Tag{}
+		u := &tag.Usage{Tag: t}
+		err := rows.Scan(
+			&t.ID, &t.Name, &t.Category, &t.Description, &t.Color, &t.IsActive, &t.CreatedAt, &t.UpdatedAt,
+			&u.UsageCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag usage: %w", err)
+		}
+		usages = append(usages, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tag usage: %w", err)
+	}
+
+	return usages, nil
+}
+
+// queryTags runs a query returning tag rows
+func (r *TagRepository) queryTags(ctx context.Context, query string, args ...interface{}) ([]*tag.Tag, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []*tag.Tag
+	for rows.Next() {
+		t := &tag.Tag{}
+		err := rows.Scan(
+			&t.ID, &t.Name, &t.Category, &t.Description, &t.Color, &t.IsActive, &t.CreatedAt, &t.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag from rows: %w", err)
+		}
+		tags = append(tags, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// scanTag scans a tag from a single row
+func (r *TagRepository) scanTag(row *sql.Row) (*tag.Tag, error) {
+	t := &tag.Tag{}
+	err := row.Scan(
+		&t.ID, &t.Name, &t.Category, &t.Description, &t.Color, &t.IsActive, &t.CreatedAt, &t.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, tag.ErrTagNotFound
+		}
+		return nil, fmt.Errorf("failed to scan tag: %w", err)
+	}
+
+	return t, nil
+}
+
+// uuidStrings converts UUIDs to strings for use with pq.Array
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.String()
+	}
+	return out
+}
+
+// insertTagAssignment assigns a tag to a trip unless it already is
+func insertTagAssignment(ctx context.Context, db execer, a *tag.Assignment) error {
+	query := `
+		INSERT INTO trip_tag_assignments (id, trip_id, tag_id, assigned_by, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (trip_id, tag_id) DO NOTHING`
+
+	if _, err := db.ExecContext(ctx, query, a.ID, a.TripID, a.TagID, a.AssignedBy, a.CreatedAt); err != nil {
+		return fmt.Errorf("failed to assign tag: %w", err)
+	}
+
+	return nil
+}
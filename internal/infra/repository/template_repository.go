@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// TemplateRepository implements the trip.TemplateRepository interface
+type TemplateRepository struct {
+	db *sql.DB
+}
+
+// NewTemplateRepository creates a new trip template repository
+func NewTemplateRepository(db *sql.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+// Create creates a new template
+func (r *TemplateRepository) Create(ctx context.Context, tpl *trip.Template) error {
+	itinerary, err := json.Marshal(tpl.Itinerary)
+	if err != nil {
+		return fmt.Errorf("failed to encode template itinerary: %w", err)
+	}
+
+	query := `
+		INSERT INTO trip_templates (
+			id, owner_id, source_trip_id, name, title, description, destination_country, destination_city,
+			duration_days, max_participants, estimated_budget, currency, trip_type,
+			tag_ids, itinerary, is_public, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
+		)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		tpl.ID, tpl.OwnerID, tpl.SourceTripID, tpl.Name, tpl.Title, tpl.Description,
+		tpl.DestinationCountry, tpl.DestinationCity, tpl.DurationDays, tpl.MaxParticipants,
+		tpl.EstimatedBudget, tpl.Currency, tpl.TripType,
+		pq.Array(uuidStrings(tpl.TagIDs)), itinerary, tpl.IsPublic, tpl.CreatedAt, tpl.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create trip template: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a template by ID
+func (r *TemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*trip.Template, error) {
+	query := `
+		SELECT id, owner_id, source_trip_id, name, title, description, destination_country, destination_city,
+			   duration_days, max_participants, estimated_budget, currency, trip_type,
+			   tag_ids, itinerary, is_public, created_at, updated_at
+		FROM trip_templates
+		WHERE id = $1`
+
+	tpl, err := scanTemplate(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, trip.ErrTemplateNotFound
+	}
+	return tpl, err
+}
+
+// ListPublic retrieves published templates, most recent first
+func (r *TemplateRepository) ListPublic(ctx context.Context, limit, offset int) ([]*trip.Template, error) {
+	query := `
+		SELECT id, owner_id, source_trip_id, name, title, description, destination_country, destination_city,
+			   duration_days, max_participants, estimated_budget, currency, trip_type,
+			   tag_ids, itinerary, is_public, created_at, updated_at
+		FROM trip_templates
+		WHERE is_public = true
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2`
+
+	return r.queryTemplates(ctx, query, limit, offset)
+}
+
+// ListByOwner retrieves a user's templates, most recent first
+func (r *TemplateRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]*trip.Template, error) {
+	query := `
+		SELECT id, owner_id, source_trip_id, name, title, description, destination_country, destination_city,
+			   duration_days, max_participants, estimated_budget, currency, trip_type,
+			   tag_ids, itinerary, is_public, created_at, updated_at
+		FROM trip_templates
+		WHERE owner_id = $1
+		ORDER BY created_at DESC`
+
+	return r.queryTemplates(ctx, query, ownerID)
+}
+
+// Update updates an existing template
+func (r *TemplateRepository) Update(ctx context.Context, tpl *trip.Template) error {
+	itinerary, err := json.Marshal(tpl.Itinerary)
+	if err != nil {
+		return fmt.Errorf("failed to encode template itinerary: %w", err)
+	}
+
+	query := `
+		UPDATE trip_templates SET
+			name = $2, title = $3, description = $4, destination_country = $5, destination_city = $6,
+			duration_days = $7, max_participants = $8, estimated_budget = $9, currency = $10,
+			trip_type = $11, tag_ids = $12, itinerary = $13, is_public = $14, updated_at = $15
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query,
+		tpl.ID, tpl.Name, tpl.Title, tpl.Description, tpl.DestinationCountry, tpl.DestinationCity,
+		tpl.DurationDays, tpl.MaxParticipants, tpl.EstimatedBudget, tpl.Currency,
+		tpl.TripType, pq.Array(uuidStrings(tpl.TagIDs)), itinerary, tpl.IsPublic, tpl.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update trip template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return trip.ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a template
+func (r *TemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM trip_templates WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete trip template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return trip.ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+// queryTemplates runs a query returning template rows
+func (r *TemplateRepository) queryTemplates(ctx context.Context, query string, args ...interface{}) ([]*trip.Template, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trip templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*trip.Template
+	for rows.Next() {
+		tpl, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tpl)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trip templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTemplate scans a template, decoding its tag IDs and itinerary.
+// sql.ErrNoRows is returned unwrapped so callers can map it.
+func scanTemplate(row rowScanner) (*trip.Template, error) {
+	tpl := &trip.Template{}
+	var tagIDs []string
+	var itinerary []byte
+
+	err := row.Scan(
+		&tpl.ID, &tpl.OwnerID, &tpl.SourceTripID, &tpl.Name, &tpl.Title, &tpl.Description,
+		&tpl.DestinationCountry, &tpl.DestinationCity, &tpl.DurationDays, &tpl.MaxParticipants,
+		&tpl.EstimatedBudget, &tpl.Currency, &tpl.TripType,
+		pq.Array(&tagIDs), &itinerary, &tpl.IsPublic, &tpl.CreatedAt, &tpl.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan trip template: %w", err)
+	}
+
+	tpl.TagIDs = make([]uuid.UUID, 0, len(tagIDs))
+	for _, id := range tagIDs {
+		tagID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template tag ID: %w", err)
+		}
+		tpl.TagIDs = append(tpl.TagIDs, tagID)
+	}
+
+	if err := json.Unmarshal(itinerary, &tpl.Itinerary); err != nil {
+		return nil, fmt.Errorf("failed to decode template itinerary: %w", err)
+	}
+
+	return tpl, nil
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"jointrip/internal/domain/album"
+
+	"github.com/google/uuid"
+)
+
+const tripPhotoColumns = `id, trip_id, uploaded_by, caption, position, visibility, is_cover, size, storage_key, thumbnail_key, created_at, updated_at`
+
+// TripPhotoRepository implements the album.Repository interface
+type TripPhotoRepository struct {
+	db *sql.DB
+}
+
+// NewTripPhotoRepository creates a new trip photo repository
+func NewTripPhotoRepository(db *sql.DB) *TripPhotoRepository {
+	return &TripPhotoRepository{db: db}
+}
+
+// Create adds a photo at the end of its trip's album. The trip is locked
+// while the album is counted, so concurrent uploads can neither overfill
+// the album nor share a position.
+func (r *TripPhotoRepository) Create(ctx context.Context, photo *album.Photo) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockTrip(ctx, tx, photo.TripID); err != nil {
+		return err
+	}
+
+	var count, next int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COUNT(*), COALESCE(MAX(position) + 1, 0) FROM trip_photos WHERE trip_id = $1`, photo.TripID,
+	).Scan(&count, &next)
+	if err != nil {
+		return fmt.Errorf("failed to count photos: %w", err)
+	}
+	if count >= album.MaxPhotosPerTrip {
+		return album.ErrAlbumFull
+	}
+	photo.Position = next
+
+	query := `
+		INSERT INTO trip_photos (` + tripPhotoColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err = tx.ExecContext(ctx, query,
+		photo.ID, photo.TripID, photo.UploadedBy, photo.Caption, photo.Position, photo.Visibility,
+		photo.IsCover, photo.Size, photo.StorageKey, photo.ThumbnailKey, photo.CreatedAt, photo.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create photo: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit photo: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a photo
+func (r *TripPhotoRepository) GetByID(ctx context.Context, id uuid.UUID) (*album.Photo, error) {
+	query := `SELECT ` + tripPhotoColumns + ` FROM trip_photos WHERE id = $1`
+
+	photo, err := scanTripPhoto(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, album.ErrPhotoNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get photo: %w", err)
+	}
+
+	return photo, nil
+}
+
+// ListByTrip retrieves a trip's photos in album order
+func (r *TripPhotoRepository) ListByTrip(ctx context.Context, tripID uuid.UUID) ([]*album.Photo, error) {
+	query := `
+		SELECT ` + tripPhotoColumns + `
+		FROM trip_photos
+		WHERE trip_id = $1
+		ORDER BY position, created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list photos: %w", err)
+	}
+	defer rows.Close()
+
+	photos := []*album.Photo{}
+	for rows.Next() {
+		photo, err := scanTripPhoto(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan photo: %w", err)
+		}
+		photos = append(photos, photo)
+	}
+
+	return photos, rows.Err()
+}
+
+// Update stores a photo's caption and visibility
+func (r *TripPhotoRepository) Update(ctx context.Context, photo *album.Photo) error {
+	query := `
+		UPDATE trip_photos
+		SET caption = $2, visibility = $3, updated_at = $4
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, photo.ID, photo.Caption, photo.Visibility, photo.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update photo: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return album.ErrPhotoNotFound
+	}
+
+	return nil
+}
+
+// UpdateArrangement stores the positions and cover of all the photos of one
+// trip together. The previous cover is cleared first, as a trip cannot have
+// two at any point.
+func (r *TripPhotoRepository) UpdateArrangement(ctx context.Context, photos []*album.Photo) error {
+	if len(photos) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	clearCover := `UPDATE trip_photos SET is_cover = FALSE WHERE trip_id = $1 AND is_cover`
+	if _, err := tx.ExecContext(ctx, clearCover, photos[0].TripID); err != nil {
+		return fmt.Errorf("failed to clear album cover: %w", err)
+	}
+
+	query := `
+		UPDATE trip_photos
+		SET position = $3, is_cover = $4, updated_at = $5
+		WHERE id = $1 AND trip_id = $2`
+
+	for _, photo := range photos {
+		_, err := tx.ExecContext(ctx, query, photo.ID, photo.TripID, photo.Position, photo.IsCover, photo.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to arrange photo: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit album arrangement: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a photo
+func (r *TripPhotoRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM trip_photos WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete photo: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return album.ErrPhotoNotFound
+	}
+
+	return nil
+}
+
+// scanTripPhoto scans a trip photo row
+func scanTripPhoto(row rowScanner) (*album.Photo, error) {
+	var photo album.Photo
+	err := row.Scan(
+		&photo.ID, &photo.TripID, &photo.UploadedBy, &photo.Caption, &photo.Position, &photo.Visibility,
+		&photo.IsCover, &photo.Size, &photo.StorageKey, &photo.ThumbnailKey, &photo.CreatedAt, &photo.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &photo, nil
+}
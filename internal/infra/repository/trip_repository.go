@@ -0,0 +1,335 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"jointrip/internal/domain/tag"
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// TripRepository implements the trip.Repository interface
+type TripRepository struct {
+	db *sql.DB
+}
+
+// NewTripRepository creates a new trip repository
+func NewTripRepository(db *sql.DB) *TripRepository {
+	return &TripRepository{db: db}
+}
+
+// Create creates a new trip together with its creator's participation
+func (r *TripRepository) Create(ctx context.Context, t *trip.Trip, creator *trip.Participant) error {
+	return r.CreateWithItinerary(ctx, t, creator, nil, nil)
+}
+
+// CreateWithItinerary creates a new trip, its creator's participation, its itinerary
+// and its tags at once
+func (r *TripRepository) CreateWithItinerary(ctx context.Context, t *trip.Trip, creator *trip.Participant, items []*trip.ItineraryItem, tagIDs []uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO trips (
+			id, creator_id, title, description, destination_country, destination_city,
+			start_date, end_date, max_participants, current_participants,
+			estimated_budget, currency, trip_type, status, is_public, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+		)`
+
+	_, err = tx.ExecContext(ctx, query,
+		t.ID, t.CreatorID, t.Title, t.Description, t.DestinationCountry, t.DestinationCity,
+		t.StartDate, t.EndDate, t.MaxParticipants, t.CurrentParticipants,
+		t.EstimatedBudget, t.Currency, t.TripType, t.Status, t.IsPublic, t.CreatedAt, t.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create trip: %w", err)
+	}
+
+	if err := insertParticipant(ctx, tx, creator); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := insertItineraryItem(ctx, tx, item); err != nil {
+			return err
+		}
+	}
+
+	for _, tagID := range tagIDs {
+		if err := insertTagAssignment(ctx, tx, tag.NewAssignment(t.ID, tagID, creator.UserID)); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit trip creation: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a trip by ID
+func (r *TripRepository) GetByID(ctx context.Context, id uuid.UUID) (*trip.Trip, error) {
+	query := `
+		SELECT id, creator_id, title, description, destination_country, destination_city,
+			   start_date, end_date, max_participants, current_participants,
+			   estimated_budget, currency, trip_type, status, is_public, created_at, updated_at
+		FROM trips
+		WHERE id = $1`
+
+	return r.scanTrip(r.db.QueryRowContext(ctx, query, id))
+}
+
+// Update updates an existing trip
+func (r *TripRepository) Update(ctx context.Context, t *trip.Trip) error {
+	query := `
+		UPDATE trips SET
+			title = $2, description = $3, destination_country = $4, destination_city = $5,
+			start_date = $6, end_date = $7, max_participants = $8,
+			estimated_budget = $9, currency = $10, trip_type = $11, status = $12,
+			is_public = $13, updated_at = $14
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query,
+		t.ID, t.Title, t.Description, t.DestinationCountry, t.DestinationCity,
+		t.StartDate, t.EndDate, t.MaxParticipants,
+		t.EstimatedBudget, t.Currency, t.TripType, t.Status,
+		t.IsPublic, t.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return trip.ErrTripNotFound
+	}
+
+	return nil
+}
+
+// Search retrieves public trips matching the filter
+func (r *TripRepository) Search(ctx context.Context, filter trip.SearchFilter) ([]*trip.Trip, error) {
+	filter.Normalize()
+
+	conditions := []string{"t.is_public = true", "t.status IN ('active', 'full')"}
+	args := []interface{}{}
+	argIndex := 1
+
+	if filter.Destination != "" {
+		conditions = append(conditions, fmt.Sprintf(
+			"(t.destination_country ILIKE $%d OR t.destination_city ILIKE $%d)", argIndex, argIndex))
+		args = append(args, "%"+filter.Destination+"%")
+		argIndex++
+	}
+	if filter.StartsAfter != nil {
+		conditions = append(conditions, fmt.Sprintf("t.start_date >= $%d", argIndex))
+		args = append(args, *filter.StartsAfter)
+		argIndex++
+	}
+	if filter.EndsBefore != nil {
+		conditions = append(conditions, fmt.Sprintf("t.end_date <= $%d", argIndex))
+		args = append(args, *filter.EndsBefore)
+		argIndex++
+	}
+	if filter.MaxBudget != nil {
+		conditions = append(conditions, fmt.Sprintf("t.estimated_budget <= $%d", argIndex))
+		args = append(args, *filter.MaxBudget)
+		argIndex++
+	}
+	if filter.TripType != "" {
+		conditions = append(conditions, fmt.Sprintf("t.trip_type = $%d", argIndex))
+		args = append(args, filter.TripType)
+		argIndex++
+	}
+	if len(filter.Tags) > 0 {
+		matching := fmt.Sprintf(`
+			SELECT COUNT(DISTINCT tt.id)
+			FROM trip_tag_assignments a
+			JOIN trip_tags tt ON tt.id = a.tag_id
+			WHERE a.trip_id = t.id AND tt.is_active = true AND tt.name = ANY($%d)`, argIndex)
+		args = append(args, pq.Array(filter.Tags))
+		argIndex++
+
+		if filter.TagMatch == trip.TagMatchAll {
+			conditions = append(conditions, fmt.Sprintf("(%s) = $%d", matching, argIndex))
+			args = append(args, countDistinct(filter.Tags))
+			argIndex++
+		} else {
+			conditions = append(conditions, fmt.Sprintf("(%s) > 0", matching))
+		}
+	}
+
+	query := fmt.Sprintf(`
+		SELECT t.id, t.creator_id, t.title, t.description, t.destination_country, t.destination_city,
+			   t.start_date, t.end_date, t.max_participants, t.current_participants,
+			   t.estimated_budget, t.currency, t.trip_type, t.status, t.is_public, t.created_at, t.updated_at
+		FROM trips t
+		WHERE %s
+		ORDER BY t.start_date ASC, t.created_at DESC
+		LIMIT $%d OFFSET $%d`, strings.Join(conditions, " AND "), argIndex, argIndex+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search trips: %w", err)
+	}
+	defer rows.Close()
+
+	var trips []*trip.Trip
+	for rows.Next() {
+		t, err := r.scanTripFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		trips = append(trips, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trips: %w", err)
+	}
+
+	return trips, nil
+}
+
+// ListByParticipant retrieves the published trips a user is approved on, ordered by start date
+func (r *TripRepository) ListByParticipant(ctx context.Context, userID uuid.UUID) ([]*trip.Trip, error) {
+	query := `
+		SELECT t.id, t.creator_id, t.title, t.description, t.destination_country, t.destination_city,
+			   t.start_date, t.end_date, t.max_participants, t.current_participants,
+			   t.estimated_budget, t.currency, t.trip_type, t.status, t.is_public, t.created_at, t.updated_at
+		FROM trips t
+		JOIN trip_participants p ON p.trip_id = t.id
+		WHERE p.user_id = $1 AND p.status = 'approved' AND t.status <> 'draft'
+		ORDER BY t.start_date ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list participant trips: %w", err)
+	}
+	defer rows.Close()
+
+	var trips []*trip.Trip
+	for rows.Next() {
+		t, err := r.scanTripFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		trips = append(trips, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trips: %w", err)
+	}
+
+	return trips, nil
+}
+
+// scanTrip scans a trip from a single row
+func (r *TripRepository) scanTrip(row *sql.Row) (*trip.Trip, error) {
+	t := &trip.Trip{}
+	err := row.Scan(
+		&t.ID, &t.CreatorID, &t.Title, &t.Description, &t.DestinationCountry, &t.DestinationCity,
+		&t.StartDate, &t.EndDate, &t.MaxParticipants, &t.CurrentParticipants,
+		&t.EstimatedBudget, &t.Currency, &t.TripType, &t.Status, &t.IsPublic, &t.CreatedAt, &t.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, trip.ErrTripNotFound
+		}
+		return nil, fmt.Errorf("failed to scan trip: %w", err)
+	}
+
+	return t, nil
+}
+
+// scanTripFromRows scans a trip from multiple rows
+func (r *TripRepository) scanTripFromRows(rows *sql.Rows) (*trip.Trip, error) {
+	t := &trip.Trip{}
+	err := rows.Scan(
+		&t.ID, &t.CreatorID, &t.Title, &t.Description, &t.DestinationCountry, &t.DestinationCity,
+		&t.StartDate, &t.EndDate, &t.MaxParticipants, &t.CurrentParticipants,
+		&t.EstimatedBudget, &t.Currency, &t.TripType, &t.Status, &t.IsPublic, &t.CreatedAt, &t.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan trip from rows: %w", err)
+	}
+
+	return t, nil
+}
+
+// countDistinct counts the distinct values in a slice
+func countDistinct(values []string) int {
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		seen[v] = true
+	}
+	return len(seen)
+}
+
+// lockTripSeats locks a trip row for the rest of the transaction and returns its seat counts.
+// Every operation that changes current_participants goes through this lock so concurrent
+// joins, departures and waitlist promotions are serialized per trip.
+func lockTripSeats(ctx context.Context, tx *sql.Tx, tripID uuid.UUID) (maxParticipants, currentParticipants int, err error) {
+	query := `SELECT max_participants, current_participants FROM trips WHERE id = $1 FOR UPDATE`
+
+	err = tx.QueryRowContext(ctx, query, tripID).Scan(&maxParticipants, &currentParticipants)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, 0, trip.ErrTripNotFound
+		}
+		return 0, 0, fmt.Errorf("failed to lock trip: %w", err)
+	}
+
+	return maxParticipants, currentParticipants, nil
+}
+
+// lockTrip locks a trip's row, serializing changes to what belongs to the trip
+func lockTrip(ctx context.Context, tx *sql.Tx, tripID uuid.UUID) error {
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, `SELECT id FROM trips WHERE id = $1 FOR UPDATE`, tripID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return trip.ErrTripNotFound
+		}
+		return fmt.Errorf("failed to lock trip: %w", err)
+	}
+
+	return nil
+}
+
+// adjustTripSeats changes the participant count of a locked trip and keeps the full status in sync
+func adjustTripSeats(ctx context.Context, tx *sql.Tx, tripID uuid.UUID, delta int) error {
+	query := `
+		UPDATE trips SET
+			current_participants = current_participants + $2,
+			status = CASE
+				WHEN status = 'active' AND current_participants + $2 >= max_participants THEN 'full'
+				WHEN status = 'full' AND current_participants + $2 < max_participants THEN 'active'
+				ELSE status
+			END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+
+	if _, err := tx.ExecContext(ctx, query, tripID, delta); err != nil {
+		return fmt.Errorf("failed to update trip seats: %w", err)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -29,18 +30,23 @@ func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
 		INSERT INTO users (
 			id, google_id, email, username, first_name, last_name, phone,
 			date_of_birth, gender, bio, location, website, languages, interests,
-			travel_style, profile_visibility, email_notifications, push_notifications,
-			profile_photo_url, google_photo_url, reputation_score, privacy_level, is_active,
+			travel_style, profile_visibility, preferred_currency,
+			profile_photo_url, profile_photo_key, profile_photos, google_photo_url, reputation_score, privacy_level, is_active,
 			last_login, created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27
 		)`
 
-	_, err := r.db.ExecContext(ctx, query,
+	photos, err := encodeProfilePhotos(u.ProfilePhotos)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, query,
 		u.ID, u.GoogleID, u.Email, u.Username, u.FirstName, u.LastName, u.Phone,
 		u.DateOfBirth, u.Gender, u.Bio, u.Location, u.Website, pq.Array(u.Languages), pq.Array(u.Interests),
-		u.TravelStyle, u.ProfileVisibility, u.EmailNotifications, u.PushNotifications,
-		u.ProfilePhotoURL, u.GooglePhotoURL, u.ReputationScore, u.PrivacyLevel, u.IsActive,
+		u.TravelStyle, u.ProfileVisibility, u.PreferredCurrency,
+		u.ProfilePhotoURL, u.ProfilePhotoKey, photos, u.GooglePhotoURL, u.ReputationScore, u.PrivacyLevel, u.IsActive,
 		u.LastLogin, u.CreatedAt, u.UpdatedAt,
 	)
 
@@ -62,8 +68,8 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*user.User,
 	query := `
 		SELECT id, google_id, email, username, first_name, last_name, phone,
 			   date_of_birth, gender, bio, location, website, languages, interests,
-			   travel_style, profile_visibility, email_notifications, push_notifications,
-			   profile_photo_url, google_photo_url, reputation_score, privacy_level, is_active,
+			   travel_style, profile_visibility, preferred_currency,
+			   profile_photo_url, profile_photo_key, profile_photos, google_photo_url, reputation_score, privacy_level, is_active,
 			   last_login, created_at, updated_at
 		FROM users
 		WHERE id = $1 AND is_active = true`
@@ -76,8 +82,8 @@ func (r *UserRepository) GetByGoogleID(ctx context.Context, googleID string) (*u
 	query := `
 		SELECT id, google_id, email, username, first_name, last_name, phone,
 			   date_of_birth, gender, bio, location, website, languages, interests,
-			   travel_style, profile_visibility, email_notifications, push_notifications,
-			   profile_photo_url, google_photo_url, reputation_score, privacy_level, is_active,
+			   travel_style, profile_visibility, preferred_currency,
+			   profile_photo_url, profile_photo_key, profile_photos, google_photo_url, reputation_score, privacy_level, is_active,
 			   last_login, created_at, updated_at
 		FROM users
 		WHERE google_id = $1 AND is_active = true`
@@ -90,8 +96,8 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*user.Us
 	query := `
 		SELECT id, google_id, email, username, first_name, last_name, phone,
 			   date_of_birth, gender, bio, location, website, languages, interests,
-			   travel_style, profile_visibility, email_notifications, push_notifications,
-			   profile_photo_url, google_photo_url, reputation_score, privacy_level, is_active,
+			   travel_style, profile_visibility, preferred_currency,
+			   profile_photo_url, profile_photo_key, profile_photos, google_photo_url, reputation_score, privacy_level, is_active,
 			   last_login, created_at, updated_at
 		FROM users
 		WHERE email = $1 AND is_active = true`
@@ -104,8 +110,8 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*u
 	query := `
 		SELECT id, google_id, email, username, first_name, last_name, phone,
 			   date_of_birth, gender, bio, location, website, languages, interests,
-			   travel_style, profile_visibility, email_notifications, push_notifications,
-			   profile_photo_url, google_photo_url, reputation_score, privacy_level, is_active,
+			   travel_style, profile_visibility, preferred_currency,
+			   profile_photo_url, profile_photo_key, profile_photos, google_photo_url, reputation_score, privacy_level, is_active,
 			   last_login, created_at, updated_at
 		FROM users
 		WHERE username = $1 AND is_active = true`
@@ -120,18 +126,23 @@ func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
 			email = $2, username = $3, first_name = $4, last_name = $5, phone = $6,
 			date_of_birth = $7, gender = $8, bio = $9, location = $10, website = $11,
 			languages = $12, interests = $13, travel_style = $14, profile_visibility = $15,
-			email_notifications = $16, push_notifications = $17, profile_photo_url = $18,
-			reputation_score = $19, privacy_level = $20,
-			is_active = $21, last_login = $22, updated_at = $23
+			profile_photo_url = $16, reputation_score = $17, privacy_level = $18,
+			is_active = $19, last_login = $20, updated_at = $21, preferred_currency = $22,
+			profile_photo_key = $23, profile_photos = $24
 		WHERE id = $1`
 
+	photos, err := encodeProfilePhotos(u.ProfilePhotos)
+	if err != nil {
+		return err
+	}
+
 	result, err := r.db.ExecContext(ctx, query,
 		u.ID, u.Email, u.Username, u.FirstName, u.LastName, u.Phone,
 		u.DateOfBirth, u.Gender, u.Bio, u.Location, u.Website,
 		pq.Array(u.Languages), pq.Array(u.Interests), u.TravelStyle, u.ProfileVisibility,
-		u.EmailNotifications, u.PushNotifications, u.ProfilePhotoURL,
-		u.ReputationScore, u.PrivacyLevel,
-		u.IsActive, u.LastLogin, u.UpdatedAt,
+		u.ProfilePhotoURL, u.ReputationScore, u.PrivacyLevel,
+		u.IsActive, u.LastLogin, u.UpdatedAt, u.PreferredCurrency,
+		u.ProfilePhotoKey, photos,
 	)
 
 	if err != nil {
@@ -171,11 +182,7 @@ func (r *UserRepository) UpdateProfile(ctx context.Context, userID uuid.UUID, pr
 				args = append(args, pq.Array([]string{}))
 			}
 			argIndex++
-		case "travel_style", "profile_visibility":
-			setParts = append(setParts, fmt.Sprintf("%s = $%d", field, argIndex))
-			args = append(args, value)
-			argIndex++
-		case "email_notifications", "push_notifications":
+		case "travel_style", "profile_visibility", "preferred_currency":
 			setParts = append(setParts, fmt.Sprintf("%s = $%d", field, argIndex))
 			args = append(args, value)
 			argIndex++
@@ -235,8 +242,8 @@ func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*user.U
 	query := `
 		SELECT id, google_id, email, username, first_name, last_name, phone,
 			   date_of_birth, gender, bio, location, website, languages, interests,
-			   travel_style, profile_visibility, email_notifications, push_notifications,
-			   profile_photo_url, google_photo_url, reputation_score, privacy_level, is_active,
+			   travel_style, profile_visibility, preferred_currency,
+			   profile_photo_url, profile_photo_key, profile_photos, google_photo_url, reputation_score, privacy_level, is_active,
 			   last_login, created_at, updated_at
 		FROM users
 		WHERE is_active = true
@@ -294,11 +301,12 @@ func (r *UserRepository) ExistsByUsername(ctx context.Context, username string)
 // scanUser scans a user from a single row
 func (r *UserRepository) scanUser(row *sql.Row) (*user.User, error) {
 	u := &user.User{}
+	var photos []byte
 	err := row.Scan(
 		&u.ID, &u.GoogleID, &u.Email, &u.Username, &u.FirstName, &u.LastName, &u.Phone,
 		&u.DateOfBirth, &u.Gender, &u.Bio, &u.Location, &u.Website, pq.Array(&u.Languages), pq.Array(&u.Interests),
-		&u.TravelStyle, &u.ProfileVisibility, &u.EmailNotifications, &u.PushNotifications,
-		&u.ProfilePhotoURL, &u.GooglePhotoURL, &u.ReputationScore, &u.PrivacyLevel, &u.IsActive,
+		&u.TravelStyle, &u.ProfileVisibility, &u.PreferredCurrency,
+		&u.ProfilePhotoURL, &u.ProfilePhotoKey, &photos, &u.GooglePhotoURL, &u.ReputationScore, &u.PrivacyLevel, &u.IsActive,
 		&u.LastLogin, &u.CreatedAt, &u.UpdatedAt,
 	)
 
@@ -308,6 +316,9 @@ func (r *UserRepository) scanUser(row *sql.Row) (*user.User, error) {
 		}
 		return nil, fmt.Errorf("failed to scan user: %w", err)
 	}
+	if err := json.Unmarshal(photos, &u.ProfilePhotos); err != nil {
+		return nil, fmt.Errorf("failed to decode profile photos: %w", err)
+	}
 
 	return u, nil
 }
@@ -315,17 +326,34 @@ func (r *UserRepository) scanUser(row *sql.Row) (*user.User, error) {
 // scanUserFromRows scans a user from multiple rows
 func (r *UserRepository) scanUserFromRows(rows *sql.Rows) (*user.User, error) {
 	u := &user.User{}
+	var photos []byte
 	err := rows.Scan(
 		&u.ID, &u.GoogleID, &u.Email, &u.Username, &u.FirstName, &u.LastName, &u.Phone,
 		&u.DateOfBirth, &u.Gender, &u.Bio, &u.Location, &u.Website, pq.Array(&u.Languages), pq.Array(&u.Interests),
-		&u.TravelStyle, &u.ProfileVisibility, &u.EmailNotifications, &u.PushNotifications,
-		&u.ProfilePhotoURL, &u.GooglePhotoURL, &u.ReputationScore, &u.PrivacyLevel, &u.IsActive,
+		&u.TravelStyle, &u.ProfileVisibility, &u.PreferredCurrency,
+		&u.ProfilePhotoURL, &u.ProfilePhotoKey, &photos, &u.GooglePhotoURL, &u.ReputationScore, &u.PrivacyLevel, &u.IsActive,
 		&u.LastLogin, &u.CreatedAt, &u.UpdatedAt,
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan user from rows: %w", err)
 	}
+	if err := json.Unmarshal(photos, &u.ProfilePhotos); err != nil {
+		return nil, fmt.Errorf("failed to decode profile photos: %w", err)
+	}
 
 	return u, nil
 }
+
+// encodeProfilePhotos encodes the sizes of a profile photo for storage, as
+// an empty list when there are none
+func encodeProfilePhotos(photos []user.ProfilePhoto) ([]byte, error) {
+	if photos == nil {
+		photos = []user.ProfilePhoto{}
+	}
+	data, err := json.Marshal(photos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode profile photos: %w", err)
+	}
+	return data, nil
+}
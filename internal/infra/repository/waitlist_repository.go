@@ -0,0 +1,353 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"jointrip/internal/domain/trip"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// WaitlistRepository implements the trip.WaitlistRepository interface
+type WaitlistRepository struct {
+	db *sql.DB
+}
+
+// NewWaitlistRepository creates a new waitlist repository
+func NewWaitlistRepository(db *sql.DB) *WaitlistRepository {
+	return &WaitlistRepository{db: db}
+}
+
+// Create appends an entry to the end of the trip's waitlist
+func (r *WaitlistRepository) Create(ctx context.Context, w *trip.WaitlistEntry) error {
+	query := `
+		INSERT INTO trip_waitlist_entries (
+			id, trip_id, user_id, status, notes, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)
+		RETURNING position`
+
+	err := r.db.QueryRowContext(ctx, query,
+		w.ID, w.TripID, w.UserID, w.Status, w.Notes, w.CreatedAt, w.UpdatedAt,
+	).Scan(&w.Position)
+
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case "23505": // unique_violation
+				return trip.ErrAlreadyWaitlisted
+			}
+		}
+		return fmt.Errorf("failed to create waitlist entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveByTripAndUser retrieves a user's waiting or offered entry for a trip
+func (r *WaitlistRepository) GetActiveByTripAndUser(ctx context.Context, tripID, userID uuid.UUID) (*trip.WaitlistEntry, error) {
+	query := `
+		SELECT id, trip_id, user_id, position, status, notes, offered_at, offer_expires_at,
+			   created_at, updated_at
+		FROM trip_waitlist_entries
+		WHERE trip_id = $1 AND user_id = $2 AND status IN ('waiting', 'offered')`
+
+	return r.scanEntry(r.db.QueryRowContext(ctx, query, tripID, userID))
+}
+
+// ListActiveByTrip retrieves waiting and offered entries for a trip in queue order
+func (r *WaitlistRepository) ListActiveByTrip(ctx context.Context, tripID uuid.UUID) ([]*trip.WaitlistEntry, error) {
+	query := `
+		SELECT id, trip_id, user_id, position, status, notes, offered_at, offer_expires_at,
+			   created_at, updated_at
+		FROM trip_waitlist_entries
+		WHERE trip_id = $1 AND status IN ('waiting', 'offered')
+		ORDER BY position ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list waitlist entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*trip.WaitlistEntry
+	for rows.Next() {
+		w, err := r.scanEntryFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating waitlist entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// CountActiveByTrip counts waiting and offered entries for a trip
+func (r *WaitlistRepository) CountActiveByTrip(ctx context.Context, tripID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM trip_waitlist_entries
+		WHERE trip_id = $1 AND status IN ('waiting', 'offered')`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, tripID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count waitlist entries: %w", err)
+	}
+
+	return count, nil
+}
+
+// Update updates an existing entry
+func (r *WaitlistRepository) Update(ctx context.Context, w *trip.WaitlistEntry) error {
+	query := `
+		UPDATE trip_waitlist_entries SET
+			status = $2, notes = $3, offered_at = $4, offer_expires_at = $5, updated_at = $6
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query,
+		w.ID, w.Status, w.Notes, w.OfferedAt, w.OfferExpiresAt, w.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update waitlist entry: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return trip.ErrWaitlistEntryNotFound
+	}
+
+	return nil
+}
+
+// OfferNext atomically offers a free seat to the next waiting user.
+// The trip row lock serializes concurrent promotions, so two departures
+// always promote two different users and never the same one twice.
+func (r *WaitlistRepository) OfferNext(ctx context.Context, tripID uuid.UUID, now time.Time, window time.Duration) (*trip.WaitlistEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	maxParticipants, currentParticipants, err := lockTripSeats(ctx, tx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	reserved, err := countOutstandingOffers(ctx, tx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if currentParticipants+reserved >= maxParticipants {
+		return nil, trip.ErrNoSeatAvailable
+	}
+
+	query := `
+		SELECT id, trip_id, user_id, position, status, notes, offered_at, offer_expires_at,
+			   created_at, updated_at
+		FROM trip_waitlist_entries
+		WHERE trip_id = $1 AND status = 'waiting'
+		ORDER BY position ASC
+		LIMIT 1
+		FOR UPDATE`
+
+	w, err := r.scanEntry(tx.QueryRowContext(ctx, query, tripID))
+	if err != nil {
+		if err == trip.ErrWaitlistEntryNotFound {
+			return nil, trip.ErrWaitlistEmpty
+		}
+		return nil, err
+	}
+
+	if err := w.Offer(now, window); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE trip_waitlist_entries SET
+			status = $2, offered_at = $3, offer_expires_at = $4, updated_at = $5
+		WHERE id = $1`,
+		w.ID, w.Status, w.OfferedAt, w.OfferExpiresAt, w.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to offer seat: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit seat offer: %w", err)
+	}
+
+	return w, nil
+}
+
+// AcceptOffer atomically accepts an offer and turns it into an approved participant
+func (r *WaitlistRepository) AcceptOffer(ctx context.Context, w *trip.WaitlistEntry, p *trip.Participant) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	maxParticipants, currentParticipants, err := lockTripSeats(ctx, tx, w.TripID)
+	if err != nil {
+		return err
+	}
+
+	// Re-check the stored offer: the sweeper may have expired it concurrently
+	var (
+		status    trip.WaitlistStatus
+		expiresAt sql.NullTime
+	)
+	err = tx.QueryRowContext(ctx,
+		`SELECT status, offer_expires_at FROM trip_waitlist_entries WHERE id = $1 FOR UPDATE`, w.ID,
+	).Scan(&status, &expiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return trip.ErrWaitlistEntryNotFound
+		}
+		return fmt.Errorf("failed to lock waitlist entry: %w", err)
+	}
+
+	if status != trip.WaitlistStatusOffered {
+		return trip.ErrNoPendingOffer
+	}
+	// Accept stamped UpdatedAt with the acceptance time
+	if !expiresAt.Valid || !w.UpdatedAt.Before(expiresAt.Time) {
+		return trip.ErrOfferExpired
+	}
+	if currentParticipants >= maxParticipants {
+		return trip.ErrTripFull
+	}
+
+	// A user who left earlier already has a participation row; reuse it
+	query := `
+		INSERT INTO trip_participants (
+			id, trip_id, user_id, status, role, join_date, left_date, notes, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+		ON CONFLICT (trip_id, user_id) DO UPDATE SET
+			status = EXCLUDED.status, join_date = EXCLUDED.join_date, left_date = EXCLUDED.left_date, notes = EXCLUDED.notes
+		RETURNING id, created_at`
+
+	err = tx.QueryRowContext(ctx, query,
+		p.ID, p.TripID, p.UserID, p.Status, p.Role, p.JoinDate, p.LeftDate, p.Notes, p.CreatedAt,
+	).Scan(&p.ID, &p.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create participant from waitlist: %w", err)
+	}
+
+	if err := adjustTripSeats(ctx, tx, w.TripID, 1); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE trip_waitlist_entries SET status = $2, updated_at = $3 WHERE id = $1`,
+		w.ID, w.Status, w.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update waitlist entry: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit offer acceptance: %w", err)
+	}
+
+	return nil
+}
+
+// ExpireOffers expires lapsed offers and returns the IDs of the affected trips
+func (r *WaitlistRepository) ExpireOffers(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	query := `
+		UPDATE trip_waitlist_entries SET status = 'expired', updated_at = $1
+		WHERE status = 'offered' AND offer_expires_at <= $1
+		RETURNING trip_id`
+
+	rows, err := r.db.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire waitlist offers: %w", err)
+	}
+	defer rows.Close()
+
+	seen := make(map[uuid.UUID]bool)
+	var tripIDs []uuid.UUID
+	for rows.Next() {
+		var tripID uuid.UUID
+		if err := rows.Scan(&tripID); err != nil {
+			return nil, fmt.Errorf("failed to scan expired offer: %w", err)
+		}
+		if !seen[tripID] {
+			seen[tripID] = true
+			tripIDs = append(tripIDs, tripID)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expired offers: %w", err)
+	}
+
+	return tripIDs, nil
+}
+
+// scanEntry scans a waitlist entry from a single row
+func (r *WaitlistRepository) scanEntry(row *sql.Row) (*trip.WaitlistEntry, error) {
+	w := &trip.WaitlistEntry{}
+	err := row.Scan(
+		&w.ID, &w.TripID, &w.UserID, &w.Position, &w.Status, &w.Notes, &w.OfferedAt, &w.OfferExpiresAt,
+		&w.CreatedAt, &w.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, trip.ErrWaitlistEntryNotFound
+		}
+		return nil, fmt.Errorf("failed to scan waitlist entry: %w", err)
+	}
+
+	return w, nil
+}
+
+// scanEntryFromRows scans a waitlist entry from multiple rows
+func (r *WaitlistRepository) scanEntryFromRows(rows *sql.Rows) (*trip.WaitlistEntry, error) {
+	w := &trip.WaitlistEntry{}
+	err := rows.Scan(
+		&w.ID, &w.TripID, &w.UserID, &w.Position, &w.Status, &w.Notes, &w.OfferedAt, &w.OfferExpiresAt,
+		&w.CreatedAt, &w.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan waitlist entry from rows: %w", err)
+	}
+
+	return w, nil
+}
+
+// countOutstandingOffers counts unexpired seat offers for a trip inside a transaction
+func countOutstandingOffers(ctx context.Context, tx *sql.Tx, tripID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM trip_waitlist_entries
+		WHERE trip_id = $1 AND status = 'offered' AND offer_expires_at > CURRENT_TIMESTAMP`
+
+	var count int
+	if err := tx.QueryRowContext(ctx, query, tripID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count outstanding offers: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Job is periodic background work. It receives the scheduler clock's time,
+// so that time-dependent jobs can be tested with a FakeClock.
+type Job func(ctx context.Context, now time.Time) error
+
+// job is a registered job
+type job struct {
+	name     string
+	interval time.Duration
+	run      Job
+}
+
+// Scheduler runs jobs at fixed intervals. A failing run is logged and the
+// job runs again at its next interval.
+type Scheduler struct {
+	clock  Clock
+	logger *logrus.Logger
+	jobs   []job
+}
+
+// New creates a new scheduler
+func New(clock Clock, logger *logrus.Logger) *Scheduler {
+	return &Scheduler{
+		clock:  clock,
+		logger: logger,
+	}
+}
+
+// Every registers a job that runs once per interval. Jobs must be registered
+// before Run is called.
+func (s *Scheduler) Every(name string, interval time.Duration, run Job) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: run})
+}
+
+// Run runs the jobs until the context is cancelled and their current runs
+// have finished
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, j := range s.jobs {
+		wg.Add(1)
+		go func(j job) {
+			defer wg.Done()
+			s.loop(ctx, j)
+		}(j)
+	}
+	wg.Wait()
+}
+
+// loop runs a job at its interval
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-s.clock.After(j.interval):
+			if err := j.run(ctx, now); err != nil {
+				s.logger.WithError(err).WithField("job", j.name).Error("Scheduled job failed")
+			}
+		}
+	}
+}
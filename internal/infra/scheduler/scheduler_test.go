@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2026, 5, 6, 8, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	soon := clock.After(time.Minute)
+	later := clock.After(time.Hour)
+	assert.Equal(t, 2, clock.Waiters())
+
+	clock.Advance(30 * time.Second)
+	assert.Empty(t, soon)
+
+	clock.Advance(30 * time.Second)
+	assert.Equal(t, start.Add(time.Minute), <-soon)
+	assert.Empty(t, later)
+	assert.Equal(t, 1, clock.Waiters())
+
+	assert.Equal(t, start.Add(time.Minute), <-clock.After(0))
+}
+
+func TestScheduler_RunsJobsAtTheirInterval(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	start := time.Date(2026, 5, 6, 8, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := New(clock, logger)
+
+	minutely := make(chan time.Time, 10)
+	hourly := make(chan time.Time, 10)
+	s.Every("minutely", time.Minute, func(ctx context.Context, now time.Time) error {
+		minutely <- now
+		return errors.New("failing runs do not stop the job")
+	})
+	s.Every("hourly", time.Hour, func(ctx context.Context, now time.Time) error {
+		hourly <- now
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	waitForWaiters := func(n int) {
+		require.Eventually(t, func() bool { return clock.Waiters() == n }, time.Second, time.Millisecond)
+	}
+
+	waitForWaiters(2)
+	clock.Advance(time.Minute)
+	assert.Equal(t, start.Add(time.Minute), <-minutely)
+
+	waitForWaiters(2)
+	clock.Advance(time.Minute)
+	assert.Equal(t, start.Add(2*time.Minute), <-minutely)
+	assert.Empty(t, hourly)
+
+	waitForWaiters(2)
+	clock.Advance(time.Hour)
+	assert.Equal(t, start.Add(62*time.Minute), <-hourly)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop")
+	}
+}
@@ -0,0 +1,159 @@
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"jointrip/internal/domain/push"
+)
+
+// maxResponseMessage is how much of a push service's error response is kept
+const maxResponseMessage = 200
+
+// maxDrainedResponse is how much of a successful response is read so the
+// connection can be reused; push services answer with an empty body
+const maxDrainedResponse = 4 << 10
+
+// Client delivers encrypted messages to push services (RFC 8030), signed
+// with the application server's VAPID keys
+type Client struct {
+	keys       *VAPIDKeys
+	subject    string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewClient creates a new Web Push client. subject is the mailto: or https:
+// contact push services can reach the operator at.
+func NewClient(keys *VAPIDKeys, subject string, httpClient *http.Client) *Client {
+	return &Client{
+		keys:       keys,
+		subject:    subject,
+		httpClient: httpClient,
+		now:        time.Now,
+	}
+}
+
+// NewHTTPClient creates the HTTP client push messages are sent with. It only
+// connects to public addresses, so a subscription cannot make the server
+// post to services inside its own network, whatever its endpoint resolves
+// or redirects to. Proxies are not used, as they would hide the address.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: dialPublicOnly,
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// dialPublicOnly refuses connections to addresses that are not public
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("push endpoint address %q: %w", address, err)
+	}
+	if !push.IsPublicAddress(addrPort.Addr()) {
+		return fmt.Errorf("push endpoint address %s is not public", addrPort.Addr())
+	}
+	return nil
+}
+
+// PublicKey returns the VAPID public key that browsers subscribe with
+func (c *Client) PublicKey() string {
+	return c.keys.PublicKey()
+}
+
+// Send encrypts the payload for the subscription and hands it to its push
+// service. It returns push.ErrSubscriptionGone when the subscription no longer
+// exists and a *push.DeliveryError for other failures.
+func (c *Client) Send(ctx context.Context, sub *push.Subscription, payload []byte, ttl time.Duration, urgency push.Urgency) error {
+	uaPublic, err := push.DecodeKey(sub.P256dh)
+	if err != nil {
+		return push.ErrInvalidKeys
+	}
+	authSecret, err := push.DecodeKey(sub.Auth)
+	if err != nil {
+		return push.ErrInvalidKeys
+	}
+
+	body, err := Encrypt(payload, uaPublic, authSecret)
+	if err != nil {
+		return err
+	}
+
+	authorization, err := c.keys.authorization(sub.Endpoint, c.subject, c.now())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(ttl.Seconds())))
+	if urgency != "" {
+		req.Header.Set("Urgency", string(urgency))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &push.DeliveryError{Retryable: true, Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainedResponse))
+		return nil
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseMessage))
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusGone:
+		return push.ErrSubscriptionGone
+	case http.StatusRequestEntityTooLarge:
+		return push.ErrPayloadTooLarge
+	}
+
+	return &push.DeliveryError{
+		StatusCode: resp.StatusCode,
+		Retryable:  resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500,
+		RetryAfter: retryAfter(resp.Header.Get("Retry-After"), c.now()),
+		Message:    strings.TrimSpace(string(message)),
+	}
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,224 @@
+package webpush
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"jointrip/internal/domain/push"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePushService stands in for a browser vendor's push service. It answers
+// with the configured status and keeps what it received.
+type fakePushService struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	header   http.Header
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newFakePushService(t *testing.T) *fakePushService {
+	f := &fakePushService{status: http.StatusCreated, header: http.Header{}}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.requests = append(f.requests, r)
+		f.bodies = append(f.bodies, body)
+		for key, values := range f.header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(f.status)
+		if f.status >= 400 {
+			w.Write([]byte("push service says no"))
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakePushService) respond(status int, header http.Header) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status = status
+	f.header = header
+}
+
+// testSubscription returns a subscription to the fake service together with
+// the browser's private key
+func testSubscription(t *testing.T, endpoint string) (*push.Subscription, *ecdh.PrivateKey, []byte) {
+	uaPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	authSecret := make([]byte, 16)
+	_, err = rand.Read(authSecret)
+	require.NoError(t, err)
+
+	return &push.Subscription{
+		ID:       uuid.New(),
+		UserID:   uuid.New(),
+		Endpoint: endpoint,
+		P256dh:   base64.RawURLEncoding.EncodeToString(uaPrivate.PublicKey().Bytes()),
+		Auth:     base64.RawURLEncoding.EncodeToString(authSecret),
+	}, uaPrivate, authSecret
+}
+
+func testClient(t *testing.T) (*Client, *VAPIDKeys) {
+	publicKey, privateKey, err := GenerateVAPIDKeys()
+	require.NoError(t, err)
+	keys, err := ParseVAPIDKeys(publicKey, privateKey)
+	require.NoError(t, err)
+	return NewClient(keys, "mailto:ops@jointrip.example", http.DefaultClient), keys
+}
+
+func TestClient_Send(t *testing.T) {
+	service := newFakePushService(t)
+	client, keys := testClient(t)
+	sub, uaPrivate, authSecret := testSubscription(t, service.URL+"/push/abc123")
+
+	err := client.Send(context.Background(), sub, []byte(`{"title":"Hello"}`), 90*time.Minute, push.UrgencyHigh)
+	require.NoError(t, err)
+
+	require.Len(t, service.requests, 1)
+	req := service.requests[0]
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "/push/abc123", req.URL.Path)
+	assert.Equal(t, "aes128gcm", req.Header.Get("Content-Encoding"))
+	assert.Equal(t, "5400", req.Header.Get("TTL"))
+	assert.Equal(t, "high", req.Header.Get("Urgency"))
+
+	plaintext, err := decrypt(service.bodies[0], uaPrivate, authSecret)
+	require.NoError(t, err)
+	assert.Equal(t, `{"title":"Hello"}`, string(plaintext))
+
+	// The VAPID token is signed with the application server key and scoped
+	// to the push service's origin
+	authorization := req.Header.Get("Authorization")
+	require.True(t, strings.HasPrefix(authorization, "vapid t="))
+	tokenPart, keyPart, ok := strings.Cut(strings.TrimPrefix(authorization, "vapid t="), ", k=")
+	require.True(t, ok)
+	assert.Equal(t, keys.PublicKey(), keyPart)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(tokenPart, claims, func(token *jwt.Token) (interface{}, error) {
+		return &keys.private.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"ES256"}))
+	require.NoError(t, err)
+	assert.Equal(t, service.URL, claims["aud"])
+	assert.Equal(t, "mailto:ops@jointrip.example", claims["sub"])
+}
+
+func TestClient_SendFailures(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		header      http.Header
+		expectedErr error
+		retryable   bool
+		retryAfter  time.Duration
+	}{
+		{name: "gone", status: http.StatusGone, expectedErr: push.ErrSubscriptionGone},
+		{name: "not found", status: http.StatusNotFound, expectedErr: push.ErrSubscriptionGone},
+		{name: "too large", status: http.StatusRequestEntityTooLarge, expectedErr: push.ErrPayloadTooLarge},
+		{name: "rate limited", status: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"120"}}, retryable: true, retryAfter: 2 * time.Minute},
+		{name: "unavailable", status: http.StatusServiceUnavailable, retryable: true},
+		{name: "bad request", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newFakePushService(t)
+			service.respond(tt.status, tt.header)
+			client, _ := testClient(t)
+			sub, _, _ := testSubscription(t, service.URL+"/push/abc123")
+
+			err := client.Send(context.Background(), sub, []byte("hi"), time.Hour, push.UrgencyNormal)
+			require.Error(t, err)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			var deliveryErr *push.DeliveryError
+			require.True(t, errors.As(err, &deliveryErr))
+			assert.Equal(t, tt.status, deliveryErr.StatusCode)
+			assert.Equal(t, tt.retryable, deliveryErr.Retryable)
+			assert.Equal(t, tt.retryAfter, deliveryErr.RetryAfter)
+			assert.Equal(t, "push service says no", deliveryErr.Message)
+		})
+	}
+}
+
+func TestClient_SendUnreachable(t *testing.T) {
+	service := newFakePushService(t)
+	endpoint := service.URL + "/push/abc123"
+	service.Close()
+
+	client, _ := testClient(t)
+	sub, _, _ := testSubscription(t, endpoint)
+
+	err := client.Send(context.Background(), sub, []byte("hi"), time.Hour, push.UrgencyNormal)
+	var deliveryErr *push.DeliveryError
+	require.True(t, errors.As(err, &deliveryErr))
+	assert.True(t, deliveryErr.Retryable)
+}
+
+func TestNewHTTPClient_RefusesInternalAddresses(t *testing.T) {
+	service := newFakePushService(t)
+
+	keys, _ := testClient(t)
+	client := NewClient(keys.keys, "mailto:ops@jointrip.example", NewHTTPClient(time.Second))
+	sub, _, _ := testSubscription(t, service.URL+"/push/abc123")
+
+	err := client.Send(context.Background(), sub, []byte("hi"), time.Hour, push.UrgencyNormal)
+	var deliveryErr *push.DeliveryError
+	require.True(t, errors.As(err, &deliveryErr))
+	assert.Contains(t, deliveryErr.Message, "is not public")
+	assert.Empty(t, service.requests)
+}
+
+func TestParseVAPIDKeys(t *testing.T) {
+	publicKey, privateKey, err := GenerateVAPIDKeys()
+	require.NoError(t, err)
+
+	keys, err := ParseVAPIDKeys(publicKey, privateKey)
+	require.NoError(t, err)
+	assert.Equal(t, publicKey, keys.PublicKey())
+
+	_, err = ParseVAPIDKeys(publicKey+"==", privateKey)
+	assert.NoError(t, err, "padded keys are accepted")
+
+	otherPublic, _, err := GenerateVAPIDKeys()
+	require.NoError(t, err)
+	_, err = ParseVAPIDKeys(otherPublic, privateKey)
+	assert.Error(t, err)
+
+	_, err = ParseVAPIDKeys(publicKey, "not-a-key")
+	assert.Error(t, err)
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 5, 6, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Duration(0), retryAfter("", now))
+	assert.Equal(t, 30*time.Second, retryAfter("30", now))
+	assert.Equal(t, 90*time.Second, retryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), retryAfter("soon", now))
+}
@@ -0,0 +1,122 @@
+package webpush
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func b64(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+// decrypt is the user agent's side of RFC 8291, used to check what the push
+// service would hand to the browser
+func decrypt(body []byte, uaPrivate *ecdh.PrivateKey, authSecret []byte) ([]byte, error) {
+	if len(body) < 21 {
+		return nil, errors.New("short body")
+	}
+	salt := body[:16]
+	rs := binary.BigEndian.Uint32(body[16:20])
+	idLen := int(body[20])
+	if len(body) < 21+idLen || rs != recordSize {
+		return nil, errors.New("bad header")
+	}
+	asPublic, err := ecdh.P256().NewPublicKey(body[21 : 21+idLen])
+	if err != nil {
+		return nil, err
+	}
+
+	ecdhSecret, err := uaPrivate.ECDH(asPublic)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo := append([]byte("WebPush: info\x00"), uaPrivate.PublicKey().Bytes()...)
+	keyInfo = append(keyInfo, asPublic.Bytes()...)
+	ikm := hkdf(authSecret, ecdhSecret, keyInfo, 32)
+	cek := hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, nonce, body[21+idLen:], nil)
+	if err != nil {
+		return nil, err
+	}
+
+	end := bytes.LastIndexByte(plaintext, 0x02)
+	if end < 0 {
+		return nil, errors.New("missing record delimiter")
+	}
+	return plaintext[:end], nil
+}
+
+// TestEncrypt_RFC8291Example checks the example from RFC 8291 appendix A
+func TestEncrypt_RFC8291Example(t *testing.T) {
+	asPrivate, err := ecdh.P256().NewPrivateKey(b64(t, "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"))
+	require.NoError(t, err)
+
+	body, err := encrypt(
+		[]byte("When I grow up, I want to be a watermelon"),
+		b64(t, "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"),
+		b64(t, "BTBZMqHH6r4Tts7J_aSIgg"),
+		asPrivate,
+		b64(t, "DGv6ra1nlYgDCS1FRnbzlw"),
+	)
+	require.NoError(t, err)
+
+	expected := "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+	assert.Equal(t, expected, base64.RawURLEncoding.EncodeToString(body))
+
+	uaPrivate, err := ecdh.P256().NewPrivateKey(b64(t, "q1dXpw3UpT5VOmu_cf_v6ih07Aems3njxI-JWgLcM94"))
+	require.NoError(t, err)
+	plaintext, err := decrypt(body, uaPrivate, b64(t, "BTBZMqHH6r4Tts7J_aSIgg"))
+	require.NoError(t, err)
+	assert.Equal(t, "When I grow up, I want to be a watermelon", string(plaintext))
+}
+
+func TestEncrypt_RoundTrip(t *testing.T) {
+	uaPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	authSecret := make([]byte, 16)
+	_, err = rand.Read(authSecret)
+	require.NoError(t, err)
+
+	payload := bytes.Repeat([]byte("x"), 3993)
+	first, err := Encrypt(payload, uaPrivate.PublicKey().Bytes(), authSecret)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(first), 4096)
+
+	second, err := Encrypt(payload, uaPrivate.PublicKey().Bytes(), authSecret)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second, "each message uses a fresh key and salt")
+
+	plaintext, err := decrypt(first, uaPrivate, authSecret)
+	require.NoError(t, err)
+	assert.Equal(t, payload, plaintext)
+
+	_, err = Encrypt(payload, []byte("not a key"), authSecret)
+	assert.Error(t, err)
+	_, err = Encrypt(payload, uaPrivate.PublicKey().Bytes(), authSecret[:8])
+	assert.Error(t, err)
+	_, err = Encrypt(bytes.Repeat([]byte("x"), recordSize), uaPrivate.PublicKey().Bytes(), authSecret)
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+package webpush
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+
+	"jointrip/internal/domain/push"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// vapidTokenLifetime is how long a VAPID token is valid; push services reject
+// tokens valid for more than 24 hours (RFC 8292 section 2)
+const vapidTokenLifetime = 12 * time.Hour
+
+// VAPIDKeys is the application server's P-256 key pair that identifies it to
+// push services (RFC 8292). Browsers only accept messages signed with the key
+// whose public half was passed to PushManager.subscribe().
+type VAPIDKeys struct {
+	private *ecdsa.PrivateKey
+	public  []byte
+}
+
+// ParseVAPIDKeys parses a base64url encoded key pair: the uncompressed public
+// point and the private scalar, as printed by GenerateVAPIDKeys
+func ParseVAPIDKeys(publicKey, privateKey string) (*VAPIDKeys, error) {
+	rawPrivate, err := push.DecodeKey(privateKey)
+	if err != nil {
+		return nil, errors.New("invalid VAPID private key encoding")
+	}
+	key, err := ecdh.P256().NewPrivateKey(rawPrivate)
+	if err != nil {
+		return nil, errors.New("invalid VAPID private key")
+	}
+
+	rawPublic, err := push.DecodeKey(publicKey)
+	if err != nil {
+		return nil, errors.New("invalid VAPID public key encoding")
+	}
+	public := key.PublicKey().Bytes()
+	if string(rawPublic) != string(public) {
+		return nil, errors.New("VAPID public key does not match the private key")
+	}
+
+	return &VAPIDKeys{
+		private: &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(public[1:33]),
+				Y:     new(big.Int).SetBytes(public[33:]),
+			},
+			D: new(big.Int).SetBytes(rawPrivate),
+		},
+		public: public,
+	}, nil
+}
+
+// GenerateVAPIDKeys creates a new base64url encoded key pair
+func GenerateVAPIDKeys() (publicKey, privateKey string, err error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		base64.RawURLEncoding.EncodeToString(key.Bytes()), nil
+}
+
+// PublicKey returns the base64url encoded public key that browsers subscribe with
+func (k *VAPIDKeys) PublicKey() string {
+	return base64.RawURLEncoding.EncodeToString(k.public)
+}
+
+// authorization returns the Authorization header value for a request to the
+// push service at endpoint. subject is a mailto: or https: contact URL.
+func (k *VAPIDKeys) authorization(endpoint, subject string, now time.Time) (string, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	claims := jwt.MapClaims{
+		"aud": parsed.Scheme + "://" + parsed.Host,
+		"exp": now.Add(vapidTokenLifetime).Unix(),
+		"sub": subject,
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(k.private)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign VAPID token: %w", err)
+	}
+
+	return "vapid t=" + token + ", k=" + k.PublicKey(), nil
+}
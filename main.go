@@ -8,16 +8,44 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
+	appAlbum "jointrip/internal/app/album"
 	"jointrip/internal/app/auth"
+	"jointrip/internal/app/calendar"
+	"jointrip/internal/app/comment"
+	appEmail "jointrip/internal/app/email"
+	appExpense "jointrip/internal/app/expense"
+	"jointrip/internal/app/messaging"
+	"jointrip/internal/app/notification"
+	appPush "jointrip/internal/app/push"
+	"jointrip/internal/app/rating"
+	appRealtime "jointrip/internal/app/realtime"
+	"jointrip/internal/app/tag"
+	"jointrip/internal/app/template"
+	"jointrip/internal/app/trip"
+	"jointrip/internal/domain/blob"
+	"jointrip/internal/domain/realtime"
 	infraAuth "jointrip/internal/infra/auth"
+	"jointrip/internal/infra/blobstore"
 	"jointrip/internal/infra/config"
 	"jointrip/internal/infra/database"
+	"jointrip/internal/infra/events"
+	"jointrip/internal/infra/exchangerate"
 	"jointrip/internal/infra/http/router"
+	"jointrip/internal/infra/imaging"
 	"jointrip/internal/infra/logger"
+	"jointrip/internal/infra/mail"
+	infraRealtime "jointrip/internal/infra/realtime"
 	"jointrip/internal/infra/repository"
+	"jointrip/internal/infra/scheduler"
+	"jointrip/internal/infra/webpush"
 )
 
+// legacyProfilePhotoDir is where profile photos were written before they
+// moved to file storage
+const legacyProfilePhotoDir = "./uploads/profile_photos"
+
 func main() {
 	// Load configuration
 	cfg, err := config.Load()
@@ -45,10 +73,125 @@ func main() {
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db.DB)
 	sessionRepo := repository.NewSessionRepository(db.DB)
+	tripRepo := repository.NewTripRepository(db.DB)
+	participantRepo := repository.NewParticipantRepository(db.DB)
+	waitlistRepo := repository.NewWaitlistRepository(db.DB)
+	tagRepo := repository.NewTagRepository(db.DB)
+	itineraryRepo := repository.NewItineraryRepository(db.DB)
+	calendarRepo := repository.NewCalendarRepository(db.DB)
+	templateRepo := repository.NewTemplateRepository(db.DB)
+	inviteRepo := repository.NewInviteRepository(db.DB)
+	blockRepo := repository.NewBlockRepository(db.DB)
+	ratingRepo := repository.NewRatingRepository(db.DB)
+	messagingRepo := repository.NewMessagingRepository(db.DB)
+	notificationRepo := repository.NewNotificationRepository(db.DB)
+	notificationPreferenceRepo := repository.NewNotificationPreferenceRepository(db.DB)
+	emailOutboxRepo := repository.NewEmailOutboxRepository(db.DB)
+	realtimeEventRepo := repository.NewRealtimeEventRepository(db.DB)
+	pushSubscriptionRepo := repository.NewPushSubscriptionRepository(db.DB)
+	pushDeliveryRepo := repository.NewPushDeliveryRepository(db.DB)
+	commentRepo := repository.NewCommentRepository(db.DB)
+	expenseRepo := repository.NewExpenseRepository(db.DB)
+	expenseReceiptRepo := repository.NewExpenseReceiptRepository(db.DB)
+	expenseBudgetRepo := repository.NewExpenseBudgetRepository(db.DB)
+	recurringExpenseRepo := repository.NewRecurringExpenseRepository(db.DB)
+	exchangeRateRepo := repository.NewExchangeRateRepository(db.DB)
+	tripPhotoRepo := repository.NewTripPhotoRepository(db.DB)
 
 	// Initialize infrastructure services
 	jwtManager := infraAuth.NewJWTManager(cfg)
 	googleClient := infraAuth.NewGoogleOAuthClient(cfg)
+	inviteSigner := infraAuth.NewInviteSigner(cfg)
+	reportSigner := infraAuth.NewReportSigner(cfg)
+	eventBus := events.NewBus(log)
+	realtimeHub := infraRealtime.NewHub(infraRealtime.DefaultSubscriptionBuffer)
+
+	// Fan real-time updates out across replicas through Postgres when configured
+	var realtimeBroker realtime.Broker = realtimeHub
+	brokerCtx, stopBroker := context.WithCancel(context.Background())
+	defer stopBroker()
+	if cfg.Realtime.Broker == "postgres" {
+		pgBroker := infraRealtime.NewPostgresBroker(db.DB, cfg.GetDatabaseURL(), realtimeHub, log)
+		go func() {
+			if err := pgBroker.Run(brokerCtx); err != nil {
+				log.WithError(err).Fatal("Failed to run realtime broker")
+			}
+		}()
+		realtimeBroker = pgBroker
+	}
+
+	// Send email through SMTP, or keep it in memory when configured to capture
+	var mailer appEmail.Mailer
+	if cfg.Mail.Backend == "capture" {
+		mailer = mail.NewCaptureMailer(mail.DefaultCaptureLimit)
+	} else {
+		smtpMailer, err := mail.NewSMTPMailer(cfg)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to initialize mailer")
+		}
+		mailer = smtpMailer
+	}
+	emailRenderer, err := mail.NewTemplateRenderer(cfg.Mail.AppBaseURL)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to load email templates")
+	}
+
+	// Send Web Push messages signed with the configured VAPID keys. Development
+	// servers get a throwaway key pair so push works out of the box.
+	var pushSender appPush.Sender
+	vapidPublicKey, vapidPrivateKey := cfg.Push.VAPIDPublicKey, cfg.Push.VAPIDPrivateKey
+	if vapidPublicKey == "" && cfg.IsDevelopment() {
+		vapidPublicKey, vapidPrivateKey, err = webpush.GenerateVAPIDKeys()
+		if err != nil {
+			log.WithError(err).Fatal("Failed to generate VAPID keys")
+		}
+		log.WithField("vapid_public_key", vapidPublicKey).
+			Warn("VAPID keys not configured, using a temporary key pair; browser subscriptions will not survive a restart")
+	}
+	if vapidPublicKey != "" {
+		vapidKeys, err := webpush.ParseVAPIDKeys(vapidPublicKey, vapidPrivateKey)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to load VAPID keys")
+		}
+		pushSender = webpush.NewClient(vapidKeys, cfg.Push.VAPIDSubject, webpush.NewHTTPClient(10*time.Second))
+	} else {
+		log.Warn("VAPID keys not configured, Web Push is disabled")
+	}
+
+	// Keep uploaded files in an S3-compatible bucket, or on the local disk
+	// behind links the server signs and serves itself
+	var blobStore blob.Store
+	if cfg.Storage.Driver == "s3" {
+		s3Store, err := blobstore.NewS3Store(cfg, &http.Client{Timeout: time.Minute})
+		if err != nil {
+			log.WithError(err).Fatal("Failed to initialize file storage")
+		}
+		blobStore = s3Store
+	} else {
+		localStore, err := blobstore.NewLocalStore(cfg.Storage.Dir, "/api/v1/files", cfg.GetFileSigningSecret())
+		if err != nil {
+			log.WithError(err).Fatal("Failed to initialize file storage")
+		}
+		blobStore = localStore
+	}
+
+	// Convert expenses with the euro reference rates from the configured file
+	rateProvider := exchangerate.NewProvider(exchangeRateRepo, "EUR")
+	loadExchangeRates := func(ctx context.Context) error {
+		count, err := exchangerate.LoadFile(ctx, exchangeRateRepo, cfg.Rates.File)
+		if err != nil {
+			return err
+		}
+		log.WithField("rates", count).Info("Loaded exchange rates")
+		return nil
+	}
+	if cfg.Rates.File != "" {
+		if err := loadExchangeRates(context.Background()); err != nil {
+			log.WithError(err).Error("Failed to load exchange rates")
+		}
+	} else {
+		log.Warn("Exchange rates file not configured, only rates already stored can convert expenses")
+	}
 
 	// Initialize application services
 	authService := auth.NewService(
@@ -57,13 +200,111 @@ func main() {
 		googleClient,
 		jwtManager,
 		cfg.Session.MaxSessionsPerUser,
+		blobStore,
+		imaging.NewAvatarMaker(),
+	)
+	tripService := trip.NewService(
+		tripRepo,
+		participantRepo,
+		waitlistRepo,
+		itineraryRepo,
+		inviteRepo,
+		inviteSigner,
+		eventBus,
+		cfg.GetWaitlistOfferWindow(),
 	)
+	tagService := tag.NewService(tagRepo, tripRepo)
+	calendarService := calendar.NewService(calendarRepo, tripRepo, participantRepo, itineraryRepo)
+	templateService := template.NewService(tripRepo, itineraryRepo, templateRepo, tagRepo)
+	messagingService := messaging.NewService(messagingRepo, userRepo, blockRepo, tripRepo, participantRepo, eventBus)
+	commentService := comment.NewService(commentRepo, tripRepo, participantRepo, userRepo, eventBus)
+	ratingService := rating.NewService(ratingRepo, tripRepo, participantRepo, eventBus)
+	expenseService := appExpense.NewService(
+		expenseRepo,
+		expenseReceiptRepo,
+		expenseBudgetRepo,
+		recurringExpenseRepo,
+		tripRepo,
+		participantRepo,
+		userRepo,
+		rateProvider,
+		reportSigner,
+		blobStore,
+		imaging.NewThumbnailer(appExpense.ThumbnailSize),
+		eventBus,
+	)
+	albumService := appAlbum.NewService(tripPhotoRepo, tripRepo, participantRepo, blobStore, imaging.NewPhotoMaker())
+	notificationService := notification.NewService(notificationRepo, notificationPreferenceRepo, userRepo, tripRepo, participantRepo, eventBus)
+	realtimeService := appRealtime.NewService(realtimeBroker, realtimeEventRepo)
+	emailService := appEmail.NewService(emailOutboxRepo, notificationRepo, userRepo, notificationPreferenceRepo, emailRenderer, mailer)
+	pushService := appPush.NewService(pushSubscriptionRepo, pushDeliveryRepo, userRepo, notificationPreferenceRepo, pushSender)
+
+	// Move profile photos that were written to the old uploads directory
+	// into file storage
+	if _, err := os.Stat(legacyProfilePhotoDir); err == nil {
+		imported, err := authService.ImportLegacyProfilePhotos(context.Background(), os.DirFS(legacyProfilePhotoDir))
+		if err != nil {
+			log.WithError(err).Error("Failed to import profile photos")
+		}
+		if imported > 0 {
+			log.WithField("count", imported).Info("Imported profile photos into file storage")
+		}
+	}
+
+	// Subscribe services to domain events
+	messagingService.RegisterHandlers(eventBus)
+	notificationService.RegisterHandlers(eventBus)
+	realtimeService.RegisterHandlers(eventBus)
+	emailService.RegisterHandlers(eventBus)
+	pushService.RegisterHandlers(eventBus)
+
+	// Run background jobs
+	jobs := scheduler.New(scheduler.SystemClock{}, log)
+	// Pass lapsed waitlist offers on to the next users in line
+	jobs.Every("expire-waitlist-offers", time.Minute, func(ctx context.Context, now time.Time) error {
+		return tripService.ExpireWaitlistOffers(ctx)
+	})
+	// Drop real-time events that are too old to be replayed to reconnecting clients
+	jobs.Every("prune-realtime-events", time.Hour, func(ctx context.Context, now time.Time) error {
+		return realtimeService.PruneEventLog(ctx)
+	})
+	// Send queued emails and queue the digests that are due
+	jobs.Every("deliver-emails", 30*time.Second, func(ctx context.Context, now time.Time) error {
+		_, err := emailService.DeliverDue(ctx)
+		return err
+	})
+	jobs.Every("send-digests", 5*time.Minute, func(ctx context.Context, now time.Time) error {
+		_, err := emailService.SendDigests(ctx, now)
+		return err
+	})
+	// Send queued push messages and forget the ones that are done
+	jobs.Every("deliver-push", 5*time.Second, func(ctx context.Context, now time.Time) error {
+		_, err := pushService.DeliverDue(ctx, now)
+		return err
+	})
+	jobs.Every("prune-push-deliveries", time.Hour, func(ctx context.Context, now time.Time) error {
+		return pushService.PruneDeliveries(ctx, now)
+	})
+	// Record the periods of recurring expenses that are over
+	jobs.Every("record-recurring-expenses", time.Hour, func(ctx context.Context, now time.Time) error {
+		_, err := expenseService.RecordRecurringExpenses(ctx, now)
+		return err
+	})
+	// Pick up the rates file again once a day, as it is replaced
+	if cfg.Rates.File != "" {
+		jobs.Every("load-exchange-rates", 24*time.Hour, func(ctx context.Context, now time.Time) error {
+			return loadExchangeRates(ctx)
+		})
+	}
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go jobs.Run(jobsCtx)
 
 	// Get embedded web filesystem
 	webFS := GetWebFS()
 
 	// Initialize HTTP router
-	httpRouter := router.NewRouter(cfg, authService, log, webFS)
+	httpRouter := router.NewRouter(cfg, authService, tripService, tagService, calendarService, templateService, messagingService, commentService, ratingService, expenseService, albumService, notificationService, pushService, realtimeService, realtimeHub, blobStore, log, webFS)
 
 	// Create HTTP server
 	server := &http.Server{
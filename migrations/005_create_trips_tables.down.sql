@@ -0,0 +1,8 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_trip_waitlist_entries_updated_at ON trip_waitlist_entries;
+DROP TRIGGER IF EXISTS update_trips_updated_at ON trips;
+
+-- Drop tables (indexes are dropped with them)
+DROP TABLE IF EXISTS trip_waitlist_entries;
+DROP TABLE IF EXISTS trip_participants;
+DROP TABLE IF EXISTS trips;
@@ -0,0 +1,82 @@
+-- Create trips table
+CREATE TABLE IF NOT EXISTS trips (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    creator_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT DEFAULT '',
+    destination_country VARCHAR(100) NOT NULL,
+    destination_city VARCHAR(100) DEFAULT '',
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    max_participants INTEGER NOT NULL CHECK (max_participants >= 2),
+    current_participants INTEGER NOT NULL DEFAULT 1 CHECK (current_participants >= 0),
+    estimated_budget DECIMAL(12,2) DEFAULT 0.00,
+    currency VARCHAR(3) DEFAULT 'EUR',
+    trip_type VARCHAR(50) DEFAULT '',
+    status VARCHAR(20) DEFAULT 'active' CHECK (status IN ('active', 'completed', 'canceled', 'full')),
+    is_public BOOLEAN DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CHECK (end_date > start_date),
+    CHECK (current_participants <= max_participants)
+);
+
+-- Create indexes for trips table
+CREATE INDEX IF NOT EXISTS idx_trips_creator_id ON trips(creator_id);
+CREATE INDEX IF NOT EXISTS idx_trips_destination ON trips(destination_country, destination_city);
+CREATE INDEX IF NOT EXISTS idx_trips_dates ON trips(start_date, end_date);
+CREATE INDEX IF NOT EXISTS idx_trips_status ON trips(status);
+
+CREATE TRIGGER update_trips_updated_at
+    BEFORE UPDATE ON trips
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Create trip_participants table
+CREATE TABLE IF NOT EXISTS trip_participants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'requested' CHECK (status IN ('requested', 'approved', 'rejected', 'left')),
+    role VARCHAR(20) NOT NULL DEFAULT 'participant' CHECK (role IN ('creator', 'co-organizer', 'participant')),
+    join_date TIMESTAMP WITH TIME ZONE,
+    notes TEXT DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(trip_id, user_id)
+);
+
+-- Create indexes for trip_participants table
+CREATE INDEX IF NOT EXISTS idx_trip_participants_trip_id ON trip_participants(trip_id);
+CREATE INDEX IF NOT EXISTS idx_trip_participants_user_id ON trip_participants(user_id);
+CREATE INDEX IF NOT EXISTS idx_trip_participants_trip_status ON trip_participants(trip_id, status);
+
+-- Create trip_waitlist_entries table
+CREATE TABLE IF NOT EXISTS trip_waitlist_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    position BIGSERIAL NOT NULL, -- Global sequence; ordering within a trip is what matters
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'offered', 'accepted', 'declined', 'expired', 'canceled')),
+    notes TEXT DEFAULT '',
+    offered_at TIMESTAMP WITH TIME ZONE,
+    offer_expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CHECK (status <> 'offered' OR offer_expires_at IS NOT NULL)
+);
+
+-- A user can hold at most one active waitlist entry per trip
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trip_waitlist_entries_active_user
+    ON trip_waitlist_entries(trip_id, user_id)
+    WHERE status IN ('waiting', 'offered');
+
+CREATE INDEX IF NOT EXISTS idx_trip_waitlist_entries_trip_queue ON trip_waitlist_entries(trip_id, status, position);
+CREATE INDEX IF NOT EXISTS idx_trip_waitlist_entries_offer_expiry ON trip_waitlist_entries(offer_expires_at) WHERE status = 'offered';
+
+CREATE TRIGGER update_trip_waitlist_entries_updated_at
+    BEFORE UPDATE ON trip_waitlist_entries
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,6 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_trip_tags_updated_at ON trip_tags;
+
+-- Drop tables (indexes are dropped with them)
+DROP TABLE IF EXISTS trip_tag_assignments;
+DROP TABLE IF EXISTS trip_tags;
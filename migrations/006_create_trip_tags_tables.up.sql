@@ -0,0 +1,46 @@
+-- Create trip_tags table (curated tag taxonomy)
+CREATE TABLE IF NOT EXISTS trip_tags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(50) NOT NULL,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('activity', 'style', 'budget')),
+    description TEXT DEFAULT '',
+    color VARCHAR(7) DEFAULT '#6B7280',
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(category, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_tags_category ON trip_tags(category);
+CREATE INDEX IF NOT EXISTS idx_trip_tags_is_active ON trip_tags(is_active);
+
+CREATE TRIGGER update_trip_tags_updated_at
+    BEFORE UPDATE ON trip_tags
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Create trip_tag_assignments table
+CREATE TABLE IF NOT EXISTS trip_tag_assignments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES trip_tags(id) ON DELETE CASCADE,
+    assigned_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(trip_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_tag_assignments_trip_id ON trip_tag_assignments(trip_id);
+CREATE INDEX IF NOT EXISTS idx_trip_tag_assignments_tag_id ON trip_tag_assignments(tag_id);
+
+-- Seed style tags from the travel styles users pick on their profile
+INSERT INTO trip_tags (name, category, description, color) VALUES
+    ('budget', 'style', 'Keeping costs low', '#16A34A'),
+    ('mid-range', 'style', 'Comfortable without splurging', '#2563EB'),
+    ('luxury', 'style', 'High-end stays and experiences', '#9333EA'),
+    ('backpacker', 'style', 'Hostels, trains and a backpack', '#EA580C'),
+    ('adventure', 'style', 'Outdoor and adrenaline activities', '#DC2626'),
+    ('cultural', 'style', 'Museums, history and local culture', '#CA8A04'),
+    ('relaxation', 'style', 'Slow travel and downtime', '#0891B2')
+ON CONFLICT (category, name) DO NOTHING;
@@ -0,0 +1,6 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_trip_itinerary_items_updated_at ON trip_itinerary_items;
+
+-- Drop tables (indexes are dropped with them)
+DROP TABLE IF EXISTS calendar_feed_tokens;
+DROP TABLE IF EXISTS trip_itinerary_items;
@@ -0,0 +1,39 @@
+-- Create trip_itinerary_items table
+CREATE TABLE IF NOT EXISTS trip_itinerary_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT DEFAULT '',
+    location VARCHAR(255) DEFAULT '',
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_itinerary_items_trip_id ON trip_itinerary_items(trip_id, starts_at);
+
+CREATE TRIGGER update_trip_itinerary_items_updated_at
+    BEFORE UPDATE ON trip_itinerary_items
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Create calendar_feed_tokens table (secret-URL calendar subscriptions)
+CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_feed_tokens_user_id ON calendar_feed_tokens(user_id);
+
+-- A user has at most one live feed URL
+CREATE UNIQUE INDEX IF NOT EXISTS idx_calendar_feed_tokens_active_user
+    ON calendar_feed_tokens(user_id)
+    WHERE revoked_at IS NULL;
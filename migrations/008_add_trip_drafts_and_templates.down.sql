@@ -0,0 +1,11 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_trip_templates_updated_at ON trip_templates;
+
+-- Drop tables (indexes are dropped with them)
+DROP TABLE IF EXISTS trip_templates;
+
+-- Drafts cannot be represented without the new status
+DELETE FROM trips WHERE status = 'draft';
+ALTER TABLE trips DROP CONSTRAINT IF EXISTS trips_status_check;
+ALTER TABLE trips ADD CONSTRAINT trips_status_check
+    CHECK (status IN ('active', 'completed', 'canceled', 'full'));
@@ -0,0 +1,34 @@
+-- Allow trips to be saved as unpublished drafts
+ALTER TABLE trips DROP CONSTRAINT IF EXISTS trips_status_check;
+ALTER TABLE trips ADD CONSTRAINT trips_status_check
+    CHECK (status IN ('draft', 'active', 'completed', 'canceled', 'full'));
+
+-- Create trip_templates table
+CREATE TABLE IF NOT EXISTS trip_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_trip_id UUID REFERENCES trips(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT DEFAULT '',
+    destination_country VARCHAR(100) NOT NULL,
+    destination_city VARCHAR(100) DEFAULT '',
+    duration_days INTEGER NOT NULL CHECK (duration_days >= 1),
+    max_participants INTEGER NOT NULL CHECK (max_participants >= 2),
+    estimated_budget DECIMAL(12,2) DEFAULT 0.00,
+    currency VARCHAR(3) DEFAULT 'EUR',
+    trip_type VARCHAR(50) DEFAULT '',
+    tag_ids UUID[] NOT NULL DEFAULT '{}', -- Snapshot of the source trip's tags
+    itinerary JSONB NOT NULL DEFAULT '[]', -- Items positioned relative to the trip start
+    is_public BOOLEAN DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_templates_owner_id ON trip_templates(owner_id);
+CREATE INDEX IF NOT EXISTS idx_trip_templates_public ON trip_templates(created_at DESC) WHERE is_public = true;
+
+CREATE TRIGGER update_trip_templates_updated_at
+    BEFORE UPDATE ON trip_templates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
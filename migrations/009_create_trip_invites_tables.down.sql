@@ -0,0 +1,3 @@
+-- Drop tables (indexes are dropped with them)
+DROP TABLE IF EXISTS trip_invitations;
+DROP TABLE IF EXISTS trip_invite_links;
@@ -0,0 +1,35 @@
+-- Create trip_invite_links table (shareable signed links)
+CREATE TABLE IF NOT EXISTS trip_invite_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    max_uses INTEGER CHECK (max_uses IS NULL OR max_uses >= 1),
+    uses INTEGER NOT NULL DEFAULT 0 CHECK (uses >= 0),
+    pre_approved BOOLEAN NOT NULL DEFAULT false,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_invite_links_trip_id ON trip_invite_links(trip_id);
+
+-- Create trip_invitations table (direct invites to specific users)
+CREATE TABLE IF NOT EXISTS trip_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    inviter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    invitee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'canceled')),
+    message TEXT DEFAULT '',
+    pre_approved BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    responded_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_invitations_trip_id ON trip_invitations(trip_id);
+CREATE INDEX IF NOT EXISTS idx_trip_invitations_invitee_pending ON trip_invitations(invitee_id) WHERE status = 'pending';
+
+-- A user has at most one pending invitation per trip
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trip_invitations_pending_unique
+    ON trip_invitations(trip_id, invitee_id)
+    WHERE status = 'pending';
@@ -0,0 +1,20 @@
+DROP INDEX IF EXISTS idx_messages_pinned;
+
+-- System messages cannot be represented without a sender
+DELETE FROM messages WHERE kind = 'system';
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_sender_kind_check;
+ALTER TABLE messages DROP COLUMN IF EXISTS pinned_by;
+ALTER TABLE messages DROP COLUMN IF EXISTS pinned_at;
+ALTER TABLE messages ALTER COLUMN sender_id SET NOT NULL;
+ALTER TABLE messages DROP COLUMN IF EXISTS kind;
+
+DROP TABLE IF EXISTS conversation_member_absences;
+ALTER TABLE conversation_members DROP COLUMN IF EXISTS left_at;
+
+DELETE FROM conversations WHERE trip_id IS NOT NULL;
+ALTER TABLE conversations DROP COLUMN IF EXISTS trip_id;
+
+UPDATE trip_participants SET status = 'left' WHERE status = 'removed';
+ALTER TABLE trip_participants DROP CONSTRAINT IF EXISTS trip_participants_status_check;
+ALTER TABLE trip_participants ADD CONSTRAINT trip_participants_status_check
+    CHECK (status IN ('requested', 'approved', 'rejected', 'left'));
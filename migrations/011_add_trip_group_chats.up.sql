@@ -0,0 +1,31 @@
+-- Organizers can remove approved participants
+ALTER TABLE trip_participants DROP CONSTRAINT IF EXISTS trip_participants_status_check;
+ALTER TABLE trip_participants ADD CONSTRAINT trip_participants_status_check
+    CHECK (status IN ('requested', 'approved', 'rejected', 'left', 'removed'));
+
+-- Every trip has at most one group conversation
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS trip_id UUID UNIQUE REFERENCES trips(id) ON DELETE CASCADE;
+
+-- Members who leave a trip keep the history up to their departure
+ALTER TABLE conversation_members ADD COLUMN IF NOT EXISTS left_at TIMESTAMP WITH TIME ZONE;
+
+-- Periods during which a trip chat member was away. Messages posted while a
+-- member was gone stay hidden from them after they rejoin.
+CREATE TABLE IF NOT EXISTS conversation_member_absences (
+    conversation_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    left_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    returned_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (conversation_id, user_id, left_at),
+    FOREIGN KEY (conversation_id, user_id) REFERENCES conversation_members(conversation_id, user_id) ON DELETE CASCADE,
+    CHECK (returned_at >= left_at)
+);
+
+-- System messages have no sender; organizers can pin messages
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS kind VARCHAR(10) NOT NULL DEFAULT 'user' CHECK (kind IN ('user', 'system'));
+ALTER TABLE messages ALTER COLUMN sender_id DROP NOT NULL;
+ALTER TABLE messages ADD CONSTRAINT messages_sender_kind_check CHECK ((kind = 'user') = (sender_id IS NOT NULL));
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS pinned_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS pinned_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_messages_pinned ON messages(conversation_id, pinned_at) WHERE pinned_at IS NOT NULL;
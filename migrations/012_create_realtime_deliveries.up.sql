@@ -0,0 +1,9 @@
+-- Real-time deliveries shared between server instances. Servers are notified
+-- of new rows through LISTEN/NOTIFY and rows are pruned after an hour.
+CREATE TABLE IF NOT EXISTS realtime_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_realtime_deliveries_created_at ON realtime_deliveries(created_at);
@@ -0,0 +1,12 @@
+-- Log of replayable real-time events, used to resume event streams after a
+-- reconnect (Last-Event-ID). Events are pruned after a day.
+CREATE TABLE IF NOT EXISTS realtime_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_ids UUID[] NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    data JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_realtime_events_user_ids ON realtime_events USING GIN (user_ids);
+CREATE INDEX IF NOT EXISTS idx_realtime_events_created_at ON realtime_events(created_at);
@@ -0,0 +1,13 @@
+-- Per-user email batching: immediate emails or a daily or weekly digest,
+-- scheduled in the user's timezone
+CREATE TABLE IF NOT EXISTS notification_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    digest_frequency VARCHAR(20) NOT NULL DEFAULT 'immediate' CHECK (digest_frequency IN ('immediate', 'daily', 'weekly')),
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    last_digest_at TIMESTAMP WITH TIME ZONE,
+    next_digest_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_settings_next_digest ON notification_settings(next_digest_at) WHERE next_digest_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_notifications_user_unread_created ON notifications(user_id, created_at) WHERE NOT is_read;
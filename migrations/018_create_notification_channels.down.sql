@@ -0,0 +1,45 @@
+ALTER TABLE notifications DROP COLUMN IF EXISTS channels;
+
+DROP TABLE IF EXISTS notification_trip_mutes;
+
+ALTER TABLE notification_settings DROP COLUMN IF EXISTS quiet_hours_end;
+ALTER TABLE notification_settings DROP COLUMN IF EXISTS quiet_hours_start;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_notifications BOOLEAN DEFAULT true;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS push_notifications BOOLEAN DEFAULT true;
+
+-- A channel counts as off when it is switched off or off for every type
+UPDATE users SET email_notifications = false
+WHERE id IN (
+    SELECT user_id FROM notification_settings
+    WHERE 'email' = ANY(disabled_channels)
+    UNION
+    SELECT user_id FROM notification_channel_preferences
+    WHERE channel = 'email' AND NOT enabled
+    GROUP BY user_id HAVING COUNT(*) = 6
+);
+UPDATE users SET push_notifications = false
+WHERE id IN (
+    SELECT user_id FROM notification_settings
+    WHERE 'push' = ANY(disabled_channels)
+    UNION
+    SELECT user_id FROM notification_channel_preferences
+    WHERE channel = 'push' AND NOT enabled
+    GROUP BY user_id HAVING COUNT(*) = 6
+);
+
+ALTER TABLE notification_settings DROP COLUMN IF EXISTS disabled_channels;
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    email_enabled BOOLEAN NOT NULL DEFAULT true,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, type)
+);
+
+INSERT INTO notification_preferences (user_id, type, email_enabled)
+SELECT user_id, type, enabled FROM notification_channel_preferences
+WHERE channel = 'email';
+
+DROP TABLE IF EXISTS notification_channel_preferences;
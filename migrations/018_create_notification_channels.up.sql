@@ -0,0 +1,51 @@
+-- Per-type, per-channel notification preferences; channels without a row are enabled
+CREATE TABLE IF NOT EXISTS notification_channel_preferences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('in_app', 'email', 'push', 'digest')),
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, type, channel)
+);
+
+-- Per-type email choices become the email column of the matrix
+INSERT INTO notification_channel_preferences (user_id, type, channel, enabled)
+SELECT user_id, type, 'email', email_enabled FROM notification_preferences
+ON CONFLICT (user_id, type, channel) DO NOTHING;
+
+-- Account-wide channel switches, which hold for every type of notification,
+-- including types added after the user turned the channel off
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS disabled_channels TEXT[] NOT NULL DEFAULT '{}';
+
+-- Users who turned emails off get neither emails nor digests, and users who
+-- turned push off get no push
+INSERT INTO notification_settings (user_id, disabled_channels)
+SELECT id, array_remove(ARRAY[
+    CASE WHEN email_notifications = false THEN 'digest' END,
+    CASE WHEN email_notifications = false THEN 'email' END,
+    CASE WHEN push_notifications = false THEN 'push' END
+], NULL)
+FROM users
+WHERE email_notifications = false OR push_notifications = false
+ON CONFLICT (user_id) DO UPDATE SET disabled_channels = EXCLUDED.disabled_channels;
+
+DROP TABLE IF EXISTS notification_preferences;
+ALTER TABLE users DROP COLUMN IF EXISTS email_notifications;
+ALTER TABLE users DROP COLUMN IF EXISTS push_notifications;
+
+-- Quiet hours in minutes after midnight, in the user's timezone
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS quiet_hours_start SMALLINT CHECK (quiet_hours_start BETWEEN 0 AND 1439);
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS quiet_hours_end SMALLINT CHECK (quiet_hours_end BETWEEN 0 AND 1439);
+
+-- Trips whose notifications a user muted
+CREATE TABLE IF NOT EXISTS notification_trip_mutes (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, trip_id)
+);
+
+-- The channels each notification is delivered on. Existing notifications stay
+-- in the notification center and in pending digests.
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS channels TEXT[] NOT NULL DEFAULT '{in_app,digest}';
+ALTER TABLE notifications ALTER COLUMN channels DROP DEFAULT;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS trip_comment_mentions;
+DROP TABLE IF EXISTS trip_comment_revisions;
+DROP TABLE IF EXISTS trip_comments;
@@ -0,0 +1,35 @@
+-- Public comments on trips, threaded through parent_comment_id. Deleted
+-- comments keep their row, without content, so that replies keep their thread.
+CREATE TABLE IF NOT EXISTS trip_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_comment_id UUID REFERENCES trip_comments(id) ON DELETE CASCADE,
+    depth SMALLINT NOT NULL DEFAULT 0 CHECK (depth >= 0),
+    content TEXT NOT NULL DEFAULT '',
+    is_edited BOOLEAN NOT NULL DEFAULT false,
+    hidden_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_comments_top_level ON trip_comments(trip_id, created_at DESC, id DESC) WHERE parent_comment_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_trip_comments_replies ON trip_comments(parent_comment_id, created_at, id);
+
+-- Earlier versions of edited comments
+CREATE TABLE IF NOT EXISTS trip_comment_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    comment_id UUID NOT NULL REFERENCES trip_comments(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    edited_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_comment_revisions_comment ON trip_comment_revisions(comment_id, edited_at DESC);
+
+-- Users mentioned in a comment, so that edits only notify new mentions
+CREATE TABLE IF NOT EXISTS trip_comment_mentions (
+    comment_id UUID NOT NULL REFERENCES trip_comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (comment_id, user_id)
+);
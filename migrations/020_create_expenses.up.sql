@@ -0,0 +1,33 @@
+-- Shared trip expenses. Amounts are integer minor units of the currency.
+CREATE TABLE IF NOT EXISTS expenses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    payer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('food', 'transport', 'accommodation', 'activities', 'shopping', 'other')),
+    date DATE NOT NULL,
+    split_method VARCHAR(20) NOT NULL CHECK (split_method IN ('equal', 'exact', 'percentage', 'shares')),
+    notes TEXT NOT NULL DEFAULT '',
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_expenses_trip_date ON expenses(trip_id, date DESC, created_at DESC);
+
+-- What each user owes the payer of an expense
+CREATE TABLE IF NOT EXISTS expense_shares (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    expense_id UUID NOT NULL REFERENCES expenses(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount_owed BIGINT NOT NULL CHECK (amount_owed >= 0),
+    weight BIGINT NOT NULL CHECK (weight > 0),
+    is_settled BOOLEAN NOT NULL DEFAULT false,
+    settled_at TIMESTAMP WITH TIME ZONE,
+    notes TEXT NOT NULL DEFAULT '',
+    UNIQUE (expense_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_expense_shares_user ON expense_shares(user_id);
@@ -0,0 +1,12 @@
+ALTER TABLE users DROP COLUMN IF EXISTS preferred_currency;
+
+ALTER TABLE expense_shares DROP COLUMN IF EXISTS base_amount_owed;
+
+ALTER TABLE expenses
+    DROP COLUMN IF EXISTS rate_source,
+    DROP COLUMN IF EXISTS rate_as_of,
+    DROP COLUMN IF EXISTS exchange_rate,
+    DROP COLUMN IF EXISTS base_amount,
+    DROP COLUMN IF EXISTS base_currency;
+
+DROP TABLE IF EXISTS exchange_rates;
@@ -0,0 +1,38 @@
+-- Exchange rates loaded from reference rate files, one per pair and day.
+-- Rates are exact decimals: the price of one unit of base in quote.
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    base CHAR(3) NOT NULL,
+    quote CHAR(3) NOT NULL,
+    as_of DATE NOT NULL,
+    rate NUMERIC(24, 12) NOT NULL CHECK (rate > 0),
+    source VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (base, quote, as_of)
+);
+
+-- Each expense keeps the rate it was converted into the trip's currency with.
+-- Existing expenses are their own base, at a rate of 1.
+ALTER TABLE expenses
+    ADD COLUMN IF NOT EXISTS base_currency CHAR(3),
+    ADD COLUMN IF NOT EXISTS base_amount BIGINT,
+    ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(24, 12),
+    ADD COLUMN IF NOT EXISTS rate_as_of DATE,
+    ADD COLUMN IF NOT EXISTS rate_source VARCHAR(50);
+
+UPDATE expenses
+SET base_currency = currency, base_amount = amount, exchange_rate = 1, rate_as_of = date, rate_source = 'identity'
+WHERE base_currency IS NULL;
+
+ALTER TABLE expenses
+    ALTER COLUMN base_currency SET NOT NULL,
+    ALTER COLUMN base_amount SET NOT NULL,
+    ALTER COLUMN exchange_rate SET NOT NULL,
+    ALTER COLUMN rate_as_of SET NOT NULL,
+    ALTER COLUMN rate_source SET NOT NULL;
+
+ALTER TABLE expense_shares ADD COLUMN IF NOT EXISTS base_amount_owed BIGINT;
+UPDATE expense_shares SET base_amount_owed = amount_owed WHERE base_amount_owed IS NULL;
+ALTER TABLE expense_shares ALTER COLUMN base_amount_owed SET NOT NULL;
+
+-- The currency a user wants balances shown in; empty for the trip's own
+ALTER TABLE users ADD COLUMN IF NOT EXISTS preferred_currency VARCHAR(3) NOT NULL DEFAULT '';
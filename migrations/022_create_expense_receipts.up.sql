@@ -0,0 +1,15 @@
+-- Receipt files attached to expenses. The files are kept in blob storage
+-- under storage_key; images also get a preview under thumbnail_key.
+CREATE TABLE IF NOT EXISTS expense_receipts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    expense_id UUID NOT NULL REFERENCES expenses(id) ON DELETE CASCADE,
+    uploaded_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    storage_key VARCHAR(512) NOT NULL UNIQUE,
+    thumbnail_key VARCHAR(512) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_expense_receipts_expense ON expense_receipts(expense_id, created_at);
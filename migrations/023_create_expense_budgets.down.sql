@@ -0,0 +1,7 @@
+DELETE FROM notifications WHERE type = 'budget_alert';
+DELETE FROM notification_channel_preferences WHERE type = 'budget_alert';
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('join_request', 'join_approved', 'join_rejected', 'new_message', 'new_rating', 'trip_changed', 'mention'));
+
+DROP TABLE IF EXISTS expense_budget_lines;
@@ -0,0 +1,25 @@
+-- Category budget plans of trips. Lines without a user_id plan the whole
+-- trip; lines with one plan that member's own shares. alert_level is the
+-- highest threshold members were alerted about: 0 none, 1 warning, 2 exceeded.
+CREATE TABLE IF NOT EXISTS expense_budget_lines (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('food', 'transport', 'accommodation', 'activities', 'shopping', 'other')),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    alert_percent INTEGER NOT NULL DEFAULT 80 CHECK (alert_percent BETWEEN 1 AND 100),
+    alert_level SMALLINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_expense_budget_lines_trip_category
+    ON expense_budget_lines(trip_id, category) WHERE user_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_expense_budget_lines_member_category
+    ON expense_budget_lines(trip_id, user_id, category) WHERE user_id IS NOT NULL;
+
+-- Notifications about budget alerts; mentions were missing from the list too
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('join_request', 'join_approved', 'join_rejected', 'new_message', 'new_rating', 'trip_changed', 'mention', 'budget_alert'));
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS recurring_expenses;
+ALTER TABLE trip_participants DROP COLUMN IF EXISTS left_date;
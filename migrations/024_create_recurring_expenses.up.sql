@@ -0,0 +1,26 @@
+-- When approved participants left or were removed, so that recurring
+-- expenses can be split by the nights each member stayed
+ALTER TABLE trip_participants ADD COLUMN IF NOT EXISTS left_date TIMESTAMP WITH TIME ZONE;
+
+-- Costs that come back every period of a trip. Each period is recorded as an
+-- expense once it is over; next_date is the first night of the next one.
+CREATE TABLE IF NOT EXISTS recurring_expenses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    payer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('food', 'transport', 'accommodation', 'activities', 'shopping', 'other')),
+    notes TEXT NOT NULL DEFAULT '',
+    frequency VARCHAR(10) NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly')),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL CHECK (end_date >= start_date),
+    next_date DATE NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_expenses_trip ON recurring_expenses(trip_id, start_date);
+CREATE INDEX IF NOT EXISTS idx_recurring_expenses_due ON recurring_expenses(next_date) WHERE next_date <= end_date;
@@ -0,0 +1,3 @@
+-- Where an uploaded profile photo is kept in file storage. Photos uploaded
+-- to the local uploads directory before are moved there at startup.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS profile_photo_key TEXT NOT NULL DEFAULT '';
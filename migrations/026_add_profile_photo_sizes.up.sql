@@ -0,0 +1,4 @@
+-- The square sizes each uploaded profile photo is re-encoded to, with the
+-- URLs clients load them from. Their files are stored next to
+-- profile_photo_key.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS profile_photos JSONB NOT NULL DEFAULT '[]';
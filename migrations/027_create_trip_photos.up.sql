@@ -0,0 +1,20 @@
+-- Photos in trip albums. The re-encoded photo is kept in blob storage under
+-- storage_key and its preview under thumbnail_key. Each trip has at most one
+-- cover; without one, the first photo is shown.
+CREATE TABLE IF NOT EXISTS trip_photos (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    uploaded_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    caption TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    visibility VARCHAR(20) NOT NULL DEFAULT 'participants' CHECK (visibility IN ('participants', 'public')),
+    is_cover BOOLEAN NOT NULL DEFAULT FALSE,
+    size BIGINT NOT NULL CHECK (size > 0),
+    storage_key VARCHAR(512) NOT NULL UNIQUE,
+    thumbnail_key VARCHAR(512) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_photos_trip ON trip_photos(trip_id, position, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trip_photos_cover ON trip_photos(trip_id) WHERE is_cover;